@@ -0,0 +1,55 @@
+// Command fakebank posts a signed bank transfer notification to the payment
+// webhook, the way Casso or SePay would, for trying out auto-confirmation locally.
+//
+//	go run ./cmd/fakebank -secret dev-secret -amount 150000 -note "SB7K2Q9M tra tien an trua"
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/splitbill/backend/pkg/bankhook"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:8080/api/v1/webhooks/payments", "webhook URL")
+	secret := flag.String("secret", "", "payments.webhook_secret of the server")
+	provider := flag.String("provider", bankhook.ProviderCasso, "payload shape: casso or sepay")
+	id := flag.String("id", "", "provider transaction ID (default: current time); resend one to test de-duplication")
+	amount := flag.Float64("amount", 0, "transfer amount in VND")
+	note := flag.String("note", "", "transfer description, should contain the payment reference")
+	account := flag.String("account", "", "receiving account number")
+	bank := flag.String("bank", "VCB", "receiving bank")
+	outgoing := flag.Bool("out", false, "send an outgoing transfer instead of an incoming one")
+	flag.Parse()
+
+	if *secret == "" || *amount <= 0 {
+		flag.Usage()
+		log.Fatal("-secret and a positive -amount are required")
+	}
+
+	txID := *id
+	if txID == "" {
+		txID = fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+
+	sender := bankhook.NewFakeSender(*url, *secret, *provider)
+	status, body, err := sender.Send(context.Background(), bankhook.Transfer{
+		ID:            txID,
+		Incoming:      !*outgoing,
+		Amount:        *amount,
+		Description:   *note,
+		AccountNumber: *account,
+		Bank:          *bank,
+		BankReference: "FT" + txID,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		log.Fatalf("Failed to send notification: %v", err)
+	}
+
+	fmt.Printf("%d %s\n", status, body)
+}
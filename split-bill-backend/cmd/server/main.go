@@ -1,298 +1,510 @@
-// @title          Split Bill API
-// @version        4.0.0
-// @description    API server for Split Bill application - manage groups, bills, transactions, OCR receipt scanning, and payments
-// @termsOfService http://swagger.io/terms/
-
-// @contact.name  Split Bill Support
-// @contact.email support@splitbill.app
-
-// @license.name Apache 2.0
-// @license.url  http://www.apache.org/licenses/LICENSE-2.0.html
-
-// @host     localhost:8080
-// @BasePath /api/v1
-
-// @securityDefinitions.apikey BearerAuth
-// @in                         header
-// @name                       Authorization
-// @description                Firebase Bearer token. Format: "Bearer {token}"
-
-package main
-
-import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"os/signal"
-	"path/filepath"
-	"syscall"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/splitbill/backend/internal/config"
-	"github.com/splitbill/backend/internal/database"
-	"github.com/splitbill/backend/internal/handlers"
-	"github.com/splitbill/backend/internal/middleware"
-	"github.com/splitbill/backend/internal/repository"
-	"github.com/splitbill/backend/internal/services"
-	"github.com/splitbill/backend/pkg/visionapi"
-	"go.uber.org/zap"
-
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-)
-
-func main() {
-	// Initialize logger
-	logger, _ := zap.NewProduction()
-	defer logger.Sync()
-
-	// Load configuration
-	cfg := config.LoadConfig()
-
-	// Initialize database connections
-	mongoDB := database.NewMongoDB(&cfg.MongoDB)
-	defer mongoDB.Disconnect()
-
-	redisClient := database.NewRedisClient(&cfg.Redis)
-	defer redisClient.Close()
-
-	// Create MongoDB indexes for performance
-	database.EnsureIndexes(mongoDB)
-
-	// Initialize Vision API client
-	visionClient, err := visionapi.NewClient(
-		logger,
-		cfg.Google.VisionCredentials,
-		cfg.Google.VisionAPIKey,
-	)
-	if err != nil {
-		logger.Warn("Vision API client initialization failed", zap.Error(err))
-	}
-	if visionClient != nil {
-		defer visionClient.Close()
-	}
-
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(mongoDB)
-	groupRepo := repository.NewGroupRepository(mongoDB)
-	billRepo := repository.NewBillRepository(mongoDB)
-	transactionRepo := repository.NewTransactionRepository(mongoDB)
-	ocrRepo := repository.NewOCRRepository(mongoDB)
-	activityRepo := repository.NewActivityRepository(mongoDB)
-
-	// Initialize services
-	authService := services.NewAuthService(userRepo)
-	groupService := services.NewGroupService(groupRepo, userRepo)
-	billService := services.NewBillService(billRepo, groupRepo, userRepo)
-	debtService := services.NewDebtService(billRepo, transactionRepo, userRepo)
-	ocrService := services.NewOCRService(ocrRepo, billRepo, groupRepo, visionClient, logger)
-	notifService := services.NewNotificationService(userRepo, logger)
-	activityService := services.NewActivityService(activityRepo, userRepo, groupRepo, logger)
-	statsService := services.NewStatsService(billRepo, transactionRepo, groupRepo, userRepo)
-
-	// Log notification service status
-	_ = notifService // Will be used when FCM is configured
-
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	groupHandler := handlers.NewGroupHandler(groupService)
-	billHandler := handlers.NewBillHandler(billService, debtService)
-	transactionHandler := handlers.NewTransactionHandler(transactionRepo, userRepo)
-	ocrHandler := handlers.NewOCRHandler(ocrService)
-	paymentHandler := handlers.NewPaymentHandler(userRepo)
-	activityHandler := handlers.NewActivityHandler(activityService, userRepo)
-	statsHandler := handlers.NewStatsHandler(statsService, userRepo)
-
-	// Image upload handler
-	uploadDir := filepath.Join(".", "uploads")
-	baseURL := fmt.Sprintf("http://localhost%s", cfg.Server.Port)
-	imageHandler := handlers.NewImageHandler(uploadDir, baseURL)
-
-	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.Firebase.CredentialsFile)
-
-	// Setup Gin router
-	gin.SetMode(cfg.Server.Mode)
-	router := gin.New()
-
-	// Global middleware
-	router.Use(middleware.RecoveryWithLogger(logger))
-	router.Use(middleware.RequestLogger(logger))
-	router.Use(middleware.CORS())
-	router.Use(middleware.RateLimitMiddleware(100)) // 100 requests/second per IP
-
-	// Serve uploaded images statically
-	router.Static("/uploads", uploadDir)
-
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "split-bill-api",
-			"version": "4.0.0",
-		})
-	})
-
-	// Swagger documentation
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// API v1 routes
-	v1 := router.Group("/api/v1")
-
-	// Auth routes (require Firebase token)
-	auth := v1.Group("/auth")
-	auth.Use(authMiddleware.Authenticate())
-	{
-		auth.POST("/verify-token", authHandler.VerifyToken)
-		auth.GET("/me", authHandler.GetMe)
-		auth.PUT("/profile", authHandler.UpdateProfile)
-	}
-
-	// Group routes
-	groups := v1.Group("/groups")
-	groups.Use(authMiddleware.Authenticate())
-	{
-		groups.POST("", groupHandler.CreateGroup)
-		groups.GET("", groupHandler.ListGroups)
-		groups.POST("/join", groupHandler.JoinGroup)
-		groups.GET("/:id", groupHandler.GetGroup)
-		groups.PUT("/:id", groupHandler.UpdateGroup)
-		groups.DELETE("/:id", groupHandler.DeleteGroup)
-		groups.POST("/:id/members", groupHandler.AddMember)
-		groups.DELETE("/:id/members/:userId", groupHandler.RemoveMember)
-
-		// Bills within a group
-		groups.POST("/:id/bills", billHandler.CreateBill)
-		groups.GET("/:id/bills", billHandler.ListBills)
-
-		// Balances and settlements
-		groups.GET("/:id/balances", billHandler.GetGroupBalances)
-		groups.GET("/:id/settlements", billHandler.GetSettlements)
-
-		// Group activities (Phase 4)
-		groups.GET("/:id/activities", activityHandler.GetGroupActivities)
-
-		// Group stats & export (Phase 5)
-		groups.GET("/:id/stats", statsHandler.GetGroupStats)
-		groups.GET("/:id/stats/categories", statsHandler.GetGroupCategoryStats)
-		groups.GET("/:id/export", statsHandler.ExportGroupSummary)
-	}
-
-	// Bill routes (direct access)
-	bills := v1.Group("/bills")
-	bills.Use(authMiddleware.Authenticate())
-	{
-		bills.GET("/:id", billHandler.GetBill)
-		bills.PUT("/:id", billHandler.UpdateBill)
-		bills.DELETE("/:id", billHandler.DeleteBill)
-	}
-
-	// Transaction routes
-	transactions := v1.Group("/transactions")
-	transactions.Use(authMiddleware.Authenticate())
-	{
-		transactions.POST("", transactionHandler.CreateTransaction)
-		transactions.PUT("/:id/confirm", transactionHandler.ConfirmTransaction)
-	}
-
-	// User routes
-	users := v1.Group("/users")
-	users.Use(authMiddleware.Authenticate())
-	{
-		users.GET("/me/debts", transactionHandler.GetUserDebts)
-	}
-
-	// OCR routes (Phase 2) - with strict rate limit for expensive operations
-	ocr := v1.Group("/ocr")
-	ocr.Use(authMiddleware.Authenticate())
-	ocr.Use(middleware.RateLimitByUser(30)) // 30 OCR scans per minute per user
-	{
-		ocr.POST("/scan", ocrHandler.ScanReceipt)
-		ocr.POST("/scan-base64", ocrHandler.ScanReceiptBase64)
-		ocr.GET("/:id/result", ocrHandler.GetOCRResult)
-		ocr.POST("/:id/confirm", ocrHandler.ConfirmOCR)
-		ocr.GET("/pending", ocrHandler.GetPendingScans)
-	}
-
-	// Image upload routes (Phase 2)
-	upload := v1.Group("/upload")
-	upload.Use(authMiddleware.Authenticate())
-	upload.Use(middleware.RateLimitByUser(60)) // 60 uploads per minute per user
-	{
-		upload.POST("/image", imageHandler.UploadImage)
-		upload.POST("/image-base64", imageHandler.UploadBase64Image)
-	}
-
-	// Payment routes (Phase 4)
-	payment := v1.Group("/payment")
-	payment.Use(authMiddleware.Authenticate())
-	{
-		payment.POST("/deeplink", paymentHandler.GenerateDeeplink)
-		payment.POST("/vietqr", paymentHandler.GenerateVietQR)
-		payment.GET("/user/:userId", paymentHandler.GetUserPaymentInfo)
-		payment.GET("/banks", paymentHandler.GetSupportedBanks)
-	}
-
-	// Activity routes (Phase 4)
-	activities := v1.Group("/activities")
-	activities.Use(authMiddleware.Authenticate())
-	{
-		activities.GET("/me", activityHandler.GetUserActivities)
-	}
-
-	// Stats routes (Phase 5)
-	stats := v1.Group("/stats")
-	stats.Use(authMiddleware.Authenticate())
-	{
-		stats.GET("/me", statsHandler.GetUserStats)
-	}
-
-	// Categories route (Phase 5)
-	v1.GET("/categories", statsHandler.GetCategoryList)
-
-	// Create HTTP server with proper timeouts
-	srv := &http.Server{
-		Addr:         cfg.Server.Port,
-		Handler:      router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	// Start server in goroutine
-	go func() {
-		log.Printf("🚀 Split Bill API server starting on %s", cfg.Server.Port)
-		log.Printf("📚 Swagger UI: http://localhost%s/swagger/index.html", cfg.Server.Port)
-		log.Printf("📷 OCR endpoint: POST /api/v1/ocr/scan")
-		log.Printf("🖼️  Upload endpoint: POST /api/v1/upload/image")
-		log.Printf("💰 Payment endpoint: POST /api/v1/payment/deeplink")
-		log.Printf("📋 Activity endpoint: GET /api/v1/activities/me")
-		log.Printf("📊 Stats endpoint: GET /api/v1/stats/me")
-		log.Printf("📁 Categories endpoint: GET /api/v1/categories")
-
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Println("⏳ Shutting down server gracefully...")
-
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Attempt graceful shutdown
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("⚠️  Server forced to shutdown: %v", err)
-	}
-
-	log.Println("✅ Server exited gracefully")
-}
+// @title          Split Bill API
+// @version        4.0.0
+// @description    API server for Split Bill application - manage groups, bills, transactions, OCR receipt scanning, and payments
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name  Split Bill Support
+// @contact.email support@splitbill.app
+
+// @license.name Apache 2.0
+// @license.url  http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @host     localhost:8080
+// @BasePath /api/v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Firebase Bearer token. Format: "Bearer {token}"
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/cache"
+	"github.com/splitbill/backend/internal/config"
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/handlers"
+	"github.com/splitbill/backend/internal/middleware"
+	"github.com/splitbill/backend/internal/notify"
+	"github.com/splitbill/backend/internal/policy"
+	"github.com/splitbill/backend/internal/realtime"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/pkg/mailer"
+	"github.com/splitbill/backend/pkg/push"
+	"github.com/splitbill/backend/pkg/visionapi"
+	"github.com/splitbill/backend/pkg/zalo"
+	"go.uber.org/zap"
+
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+func main() {
+	// Initialize logger
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	// Load configuration
+	cfg := config.LoadConfig()
+
+	// Initialize database connections
+	mongoDB := database.NewMongoDB(&cfg.MongoDB)
+	defer mongoDB.Disconnect()
+
+	redisClient := database.NewRedisClient(&cfg.Redis)
+	defer redisClient.Close()
+
+	// Create MongoDB indexes for performance
+	database.EnsureIndexes(mongoDB)
+
+	// Initialize Vision API client
+	visionClient, err := visionapi.NewClient(
+		logger,
+		cfg.Google.VisionCredentials,
+		cfg.Google.VisionAPIKey,
+	)
+	if err != nil {
+		logger.Warn("Vision API client initialization failed", zap.Error(err))
+	}
+	if visionClient != nil {
+		defer visionClient.Close()
+	}
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(mongoDB)
+	groupRepo := repository.NewGroupRepository(mongoDB)
+	billRepo := repository.NewBillRepository(mongoDB)
+	transactionRepo := repository.NewTransactionRepository(mongoDB)
+	ocrRepo := repository.NewOCRRepository(mongoDB)
+	activityRepo := repository.NewActivityRepository(mongoDB)
+	statementRepo := repository.NewStatementRepository(mongoDB)
+	paymentReferenceRepo := repository.NewPaymentReferenceRepository(mongoDB)
+	paymentEventRepo := repository.NewPaymentEventRepository(mongoDB)
+	reminderRepo := repository.NewReminderRepository(mongoDB)
+	digestRepo := repository.NewDigestRepository(mongoDB)
+	notificationRepo := repository.NewNotificationRepository(mongoDB)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(mongoDB)
+	webhookRepo := repository.NewWebhookRepository(mongoDB)
+	inviteRepo := repository.NewInviteRepository(mongoDB)
+	joinRequestRepo := repository.NewJoinRequestRepository(mongoDB)
+	placeholderRepo := repository.NewPlaceholderRepository(mongoDB)
+
+	// Notification channels. Notifications always reach the in-app inbox;
+	// push needs a Firebase service account.
+	var channels []notify.Channel
+	if cfg.Firebase.CredentialsFile != "" {
+		fcmSender, err := push.NewFCMSender(context.Background(), cfg.Firebase.CredentialsFile)
+		if err != nil {
+			logger.Warn("FCM push initialization failed", zap.Error(err))
+		} else {
+			channels = append(channels, notify.NewPushChannel(fcmSender, userRepo.RemoveDeviceTokens))
+		}
+	}
+	if cfg.Notify.Email.Enabled {
+		emailCfg := cfg.Notify.Email
+		m, err := mailer.New(mailer.Config{
+			Host:     emailCfg.Host,
+			Port:     emailCfg.Port,
+			Username: emailCfg.Username,
+			Password: emailCfg.Password,
+			From:     emailCfg.From,
+		})
+		if err != nil {
+			logger.Warn("Email notifications disabled", zap.Error(err))
+		} else {
+			channels = append(channels, notify.NewEmailChannel(m))
+		}
+	}
+	if cfg.Notify.Zalo.Enabled {
+		zaloClient := zalo.NewClient(cfg.Notify.Zalo.AccessToken)
+		zaloClient.BaseURL = cfg.Notify.Zalo.BaseURL
+		channels = append(channels, notify.NewZaloChannel(zaloClient))
+	}
+	if cfg.Notify.Webhook.Enabled {
+		channels = append(channels, notify.NewWebhookChannel(cfg.Notify.Webhook.Timeout, cfg.Notify.Webhook.AllowPrivate))
+	}
+	notifyRouter := notify.NewRouter(logger, channels...)
+	logger.Info("Notification channels configured", zap.Any("channels", notifyRouter.Channels()))
+
+	// Realtime group events, shared between instances through Redis
+	realtimeHub := realtime.NewHub(redisClient.Client, logger)
+
+	// Domain events: services publish, side effects subscribe
+	var eventStream *events.Stream
+	if cfg.Events.Backend == "redis" {
+		consumer := cfg.Events.Consumer
+		if consumer == "" {
+			consumer, _ = os.Hostname()
+		}
+		eventStream = events.NewStream(redisClient.Client, consumer, logger)
+	}
+	eventBus := events.NewBus(eventStream, logger)
+	logger.Info("Event bus configured", zap.String("backend", cfg.Events.Backend))
+
+	// Initialize services
+	notifService := services.NewNotificationService(userRepo, groupRepo, notificationRepo, deferredNotificationRepo, notifyRouter, logger)
+	authService := services.NewAuthService(userRepo)
+	groupService := services.NewGroupService(groupRepo, userRepo, placeholderRepo, eventBus)
+	inviteService := services.NewInviteService(inviteRepo, joinRequestRepo, groupRepo, userRepo, placeholderRepo, eventBus)
+	billService := services.NewBillService(billRepo, groupRepo, userRepo, eventBus)
+	debtService := services.NewDebtService(billRepo, transactionRepo, userRepo)
+	paymentReferenceService := services.NewPaymentReferenceService(paymentReferenceRepo, transactionRepo, groupRepo)
+	transactionService := services.NewTransactionService(transactionRepo, groupRepo, userRepo, paymentReferenceService, eventBus)
+	reconciliationService := services.NewReconciliationService(statementRepo, transactionRepo, groupRepo, transactionService)
+	settlementPaymentService := services.NewSettlementPaymentService(debtService, groupRepo, userRepo, paymentReferenceService)
+	paymentWebhookService := services.NewPaymentWebhookService(paymentEventRepo, transactionRepo, userRepo, paymentReferenceService, transactionService)
+	ocrService := services.NewOCRService(ocrRepo, billRepo, groupRepo, visionClient, eventBus, logger)
+	activityService := services.NewActivityService(activityRepo, userRepo, groupRepo, logger)
+	statsService := services.NewStatsService(billRepo, transactionRepo, groupRepo, userRepo)
+	reminderService := services.NewReminderService(groupRepo, billRepo, transactionRepo, reminderRepo, debtService, notifService, logger)
+	digestService := services.NewDigestService(userRepo, groupRepo, transactionRepo, activityRepo, digestRepo, statsService, debtService, notifService, logger)
+	webhookService := services.NewWebhookService(webhookRepo, groupRepo, services.WebhookOptions{
+		Timeout:      cfg.Webhooks.Timeout,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		AllowPrivate: cfg.Webhooks.AllowPrivate,
+	}, logger)
+
+	// Groups created before the owner role existed get one
+	if assigned, err := groupService.AssignMissingOwners(context.Background()); err != nil {
+		logger.Warn("Assigning group owners failed", zap.Error(err))
+	} else if assigned > 0 {
+		logger.Info("Assigned owners to groups", zap.Int("groups", assigned))
+	}
+
+	// Side effects of group events
+	cache.NewCacheService(redisClient.Client).RegisterEventHandlers(eventBus)
+	realtimeHub.RegisterEventHandlers(eventBus)
+	activityService.RegisterEventHandlers(eventBus)
+	notifService.RegisterEventHandlers(eventBus)
+	if cfg.Webhooks.Enabled {
+		webhookService.RegisterEventHandlers(eventBus)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.Reminders.Enabled {
+		go reminderService.Start(jobsCtx, cfg.Reminders.CheckInterval)
+	}
+	if cfg.Digests.Enabled {
+		go digestService.Start(jobsCtx, cfg.Digests.CheckInterval)
+	}
+	if cfg.Webhooks.Enabled {
+		go webhookService.Start(jobsCtx, cfg.Webhooks.RetryInterval)
+	}
+	go notifService.StartDeferredDelivery(jobsCtx, cfg.Notify.DeferredCheckInterval)
+	go realtimeHub.Run(jobsCtx)
+	go eventBus.Run(jobsCtx)
+
+	// Public URLs for uploaded images and rendered QR codes
+	uploadDir := filepath.Join(".", "uploads")
+	baseURL := fmt.Sprintf("http://localhost%s", cfg.Server.Port)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService)
+	groupHandler := handlers.NewGroupHandler(groupService)
+	inviteHandler := handlers.NewInviteHandler(inviteService, groupService, userRepo)
+	billHandler := handlers.NewBillHandler(billService, debtService, paymentReferenceService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, userRepo)
+	statementHandler := handlers.NewStatementHandler(reconciliationService, userRepo)
+	ocrHandler := handlers.NewOCRHandler(ocrService, userRepo)
+	paymentHandler := handlers.NewPaymentHandler(userRepo, paymentReferenceService, settlementPaymentService, baseURL)
+	paymentWebhookHandler := handlers.NewPaymentWebhookHandler(paymentWebhookService, cfg.Payments.WebhookSecret)
+	activityHandler := handlers.NewActivityHandler(activityService, userRepo)
+	statsHandler := handlers.NewStatsHandler(statsService, userRepo)
+	reminderHandler := handlers.NewReminderHandler(reminderService, userRepo)
+	notificationHandler := handlers.NewNotificationHandler(notifService, userRepo)
+	digestHandler := handlers.NewDigestHandler(digestService, userRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, userRepo)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeHub, groupRepo, userRepo)
+
+	// Image upload handler
+	imageHandler := handlers.NewImageHandler(uploadDir, baseURL)
+
+	// Initialize auth middleware
+	authMiddleware := middleware.NewAuthMiddleware(cfg.Firebase.CredentialsFile)
+
+	// Group membership and role checks for every route
+	accessPolicy := policy.NewEnforcer(userRepo, groupRepo, billRepo, ocrRepo)
+
+	// Setup Gin router
+	gin.SetMode(cfg.Server.Mode)
+	router := gin.New()
+
+	// Global middleware
+	router.Use(middleware.RecoveryWithLogger(logger))
+	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.CORS())
+	router.Use(middleware.RateLimitMiddleware(100)) // 100 requests/second per IP
+
+	// Serve uploaded images statically
+	router.Static("/uploads", uploadDir)
+
+	// Health check
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":  "ok",
+			"service": "split-bill-api",
+			"version": "4.0.0",
+		})
+	})
+
+	// Swagger documentation
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+
+	// Auth routes (require Firebase token)
+	auth := v1.Group("/auth")
+	auth.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		auth.POST("/verify-token", authHandler.VerifyToken)
+		auth.GET("/me", authHandler.GetMe)
+		auth.PUT("/profile", authHandler.UpdateProfile)
+	}
+
+	// Group routes
+	groups := v1.Group("/groups")
+	groups.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		groups.POST("", groupHandler.CreateGroup)
+		groups.GET("", groupHandler.ListGroups)
+		groups.POST("/join", inviteHandler.JoinGroup)
+		groups.GET("/join-requests", inviteHandler.ListMyJoinRequests)
+		groups.GET("/permissions", groupHandler.GetPermissions)
+		groups.GET("/:id", groupHandler.GetGroup)
+		groups.PUT("/:id", groupHandler.UpdateGroup)
+		groups.DELETE("/:id", groupHandler.DeleteGroup)
+		groups.POST("/:id/members", groupHandler.AddMember)
+		groups.POST("/:id/placeholders", groupHandler.AddPlaceholder)
+		groups.DELETE("/:id/members/:userId", groupHandler.RemoveMember)
+		groups.POST("/:id/members/:userId/promote", groupHandler.PromoteMember)
+		groups.POST("/:id/members/:userId/demote", groupHandler.DemoteMember)
+		groups.POST("/:id/transfer-ownership", groupHandler.TransferOwnership)
+		groups.POST("/:id/members/:userId/remind", reminderHandler.RemindMember)
+
+		// Invite code and invite links
+		groups.POST("/:id/invite-code/regenerate", inviteHandler.RegenerateInviteCode)
+		groups.GET("/:id/invites", inviteHandler.ListInviteLinks)
+		groups.POST("/:id/invites", inviteHandler.CreateInviteLink)
+		groups.DELETE("/:id/invites/:inviteId", inviteHandler.RevokeInviteLink)
+
+		// Join requests to groups that require approval
+		groups.GET("/:id/join-requests", inviteHandler.ListJoinRequests)
+		groups.POST("/:id/join-requests/:requestId/approve", inviteHandler.ApproveJoinRequest)
+		groups.POST("/:id/join-requests/:requestId/deny", inviteHandler.DenyJoinRequest)
+
+		// Bills within a group
+		groups.POST("/:id/bills", billHandler.CreateBill)
+		groups.GET("/:id/bills", billHandler.ListBills)
+
+		// Balances and settlements
+		groups.GET("/:id/balances", billHandler.GetGroupBalances)
+		groups.GET("/:id/settlements", billHandler.GetSettlements)
+		groups.GET("/:id/settlements/:toUserId/pay", paymentHandler.PaySettlement)
+
+		// Settlement reminders
+		groups.GET("/:id/reminders", reminderHandler.GetSettings)
+		groups.PUT("/:id/reminders", reminderHandler.UpdateSettings)
+		groups.PUT("/:id/reminders/snooze", reminderHandler.Snooze)
+
+		// Transactions within a group
+		groups.GET("/:id/transactions", transactionHandler.ListGroupTransactions)
+
+		// Group activities (Phase 4)
+		groups.GET("/:id/activities", activityHandler.GetGroupActivities)
+
+		// Group stats & export (Phase 5)
+		groups.GET("/:id/stats", statsHandler.GetGroupStats)
+		groups.GET("/:id/stats/categories", statsHandler.GetGroupCategoryStats)
+		groups.GET("/:id/export", statsHandler.ExportGroupSummary)
+		groups.GET("/:id/digest", digestHandler.PreviewDigest)
+
+		// Realtime group events (server-sent events)
+		groups.GET("/:id/events", realtimeHandler.StreamGroupEvents)
+
+		// Outgoing group webhooks (admins only)
+		groups.GET("/:id/webhooks", webhookHandler.ListWebhooks)
+		groups.POST("/:id/webhooks", webhookHandler.CreateWebhook)
+		groups.PUT("/:id/webhooks/:webhookId", webhookHandler.UpdateWebhook)
+		groups.DELETE("/:id/webhooks/:webhookId", webhookHandler.DeleteWebhook)
+		groups.POST("/:id/webhooks/:webhookId/test", webhookHandler.TestWebhook)
+		groups.GET("/:id/webhooks/:webhookId/deliveries", webhookHandler.ListDeliveries)
+		groups.POST("/:id/webhooks/:webhookId/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+	}
+
+	// Bill routes (direct access)
+	bills := v1.Group("/bills")
+	bills.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		bills.GET("/:id", billHandler.GetBill)
+		bills.PUT("/:id", billHandler.UpdateBill)
+		bills.DELETE("/:id", billHandler.DeleteBill)
+	}
+
+	// Transaction routes
+	transactions := v1.Group("/transactions")
+	transactions.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		transactions.POST("", transactionHandler.CreateTransaction)
+		transactions.PUT("/:id", transactionHandler.UpdateTransaction)
+		transactions.PUT("/:id/cancel", transactionHandler.CancelTransaction)
+		transactions.PUT("/:id/confirm", transactionHandler.ConfirmTransaction)
+		transactions.PUT("/:id/reject", transactionHandler.RejectTransaction)
+		transactions.POST("/:id/reverse", transactionHandler.ReverseTransaction)
+	}
+
+	// Bank statement import and reconciliation routes
+	statements := v1.Group("/statements")
+	statements.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		statements.POST("/import", statementHandler.ImportStatement)
+		statements.GET("/imports", statementHandler.ListImports)
+		statements.GET("/lines", statementHandler.ListLines)
+		statements.POST("/lines/:id/link", statementHandler.LinkLine)
+		statements.PUT("/lines/:id/ignore", statementHandler.IgnoreLine)
+	}
+
+	// User routes
+	users := v1.Group("/users")
+	users.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		users.GET("/me/debts", transactionHandler.GetUserDebts)
+		users.POST("/me/devices", authHandler.RegisterDevice)
+		users.DELETE("/me/devices/:token", authHandler.UnregisterDevice)
+	}
+
+	// OCR routes (Phase 2) - with strict rate limit for expensive operations
+	ocr := v1.Group("/ocr")
+	ocr.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	ocr.Use(middleware.RateLimitByUser(30)) // 30 OCR scans per minute per user
+	{
+		ocr.POST("/scan", ocrHandler.ScanReceipt)
+		ocr.POST("/scan-base64", ocrHandler.ScanReceiptBase64)
+		ocr.GET("/:id/result", ocrHandler.GetOCRResult)
+		ocr.POST("/:id/confirm", ocrHandler.ConfirmOCR)
+		ocr.GET("/pending", ocrHandler.GetPendingScans)
+	}
+
+	// Image upload routes (Phase 2)
+	upload := v1.Group("/upload")
+	upload.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	upload.Use(middleware.RateLimitByUser(60)) // 60 uploads per minute per user
+	{
+		upload.POST("/image", imageHandler.UploadImage)
+		upload.POST("/image-base64", imageHandler.UploadBase64Image)
+	}
+
+	// Payment routes (Phase 4)
+	payment := v1.Group("/payment")
+	payment.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		payment.POST("/deeplink", paymentHandler.GenerateDeeplink)
+		payment.POST("/vietqr", paymentHandler.GenerateVietQR)
+		payment.GET("/user/:userId", paymentHandler.GetUserPaymentInfo)
+		payment.GET("/banks", paymentHandler.GetSupportedBanks)
+		payment.GET("/references/:code", paymentHandler.LookupReference)
+	}
+
+	// Activity routes (Phase 4)
+	activities := v1.Group("/activities")
+	activities.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		activities.GET("/me", activityHandler.GetUserActivities)
+		activities.GET("/me/own", activityHandler.GetOwnActivities)
+	}
+
+	// Notification inbox routes
+	notifications := v1.Group("/notifications")
+	notifications.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		notifications.GET("", notificationHandler.ListNotifications)
+		notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+		notifications.PUT("/read-all", notificationHandler.MarkAllRead)
+		notifications.PUT("/:id/read", notificationHandler.MarkRead)
+	}
+
+	// Stats routes (Phase 5)
+	stats := v1.Group("/stats")
+	stats.Use(authMiddleware.Authenticate(), accessPolicy.Enforce())
+	{
+		stats.GET("/me", statsHandler.GetUserStats)
+	}
+
+	// VietQR image rendering is public so image views can load it without a token
+	v1.GET("/payment/qr", paymentHandler.RenderQR)
+
+	// Payment aggregator webhooks authenticate with an HMAC signature instead of a user token
+	v1.POST("/webhooks/payments", paymentWebhookHandler.ReceivePayment)
+
+	// Categories route (Phase 5)
+	v1.GET("/categories", statsHandler.GetCategoryList)
+
+	// Refuse to start with a route nobody decided access for
+	if err := policy.Check(router.Routes()); err != nil {
+		logger.Fatal("Access policy incomplete", zap.Error(err))
+	}
+
+	// Create HTTP server with proper timeouts
+	srv := &http.Server{
+		Addr:         cfg.Server.Port,
+		Handler:      router,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	srv.RegisterOnShutdown(realtimeHub.Close)
+
+	// Start server in goroutine
+	go func() {
+		log.Printf("🚀 Split Bill API server starting on %s", cfg.Server.Port)
+		log.Printf("📚 Swagger UI: http://localhost%s/swagger/index.html", cfg.Server.Port)
+		log.Printf("📷 OCR endpoint: POST /api/v1/ocr/scan")
+		log.Printf("🖼️  Upload endpoint: POST /api/v1/upload/image")
+		log.Printf("💰 Payment endpoint: POST /api/v1/payment/deeplink")
+		log.Printf("📋 Activity endpoint: GET /api/v1/activities/me")
+		log.Printf("📊 Stats endpoint: GET /api/v1/stats/me")
+		log.Printf("📁 Categories endpoint: GET /api/v1/categories")
+
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("⏳ Shutting down server gracefully...")
+	stopJobs()
+
+	// Create shutdown context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Attempt graceful shutdown
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Server forced to shutdown: %v", err)
+	}
+
+	// Let the side effects of the last requests finish
+	eventBus.Wait()
+
+	log.Println("✅ Server exited gracefully")
+}
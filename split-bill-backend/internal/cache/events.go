@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/models"
+)
+
+// RegisterEventHandlers drops the cached data of a group whenever something
+// changes in it, and the stats of the users a bill or payment involves
+func (c *CacheService) RegisterEventHandlers(bus *events.Bus) {
+	bus.SubscribeAll("cache", false, func(ctx context.Context, event events.Event) error {
+		if err := c.InvalidateGroupCache(ctx, event.Group().Hex()); err != nil {
+			return err
+		}
+
+		switch e := event.(type) {
+		case events.BillCreated:
+			return c.invalidateBillUsers(ctx, e.Bill)
+		case events.BillUpdated:
+			return c.invalidateBillUsers(ctx, e.Bill, e.Previous)
+		case events.BillDeleted:
+			return c.invalidateBillUsers(ctx, e.Bill)
+		case events.TransactionConfirmed:
+			if err := c.InvalidateUserCache(ctx, e.Transaction.FromUser.Hex()); err != nil {
+				return err
+			}
+			return c.InvalidateUserCache(ctx, e.Transaction.ToUser.Hex())
+		case events.PlaceholderClaimed:
+			if err := c.InvalidateUserCache(ctx, e.PlaceholderID.Hex()); err != nil {
+				return err
+			}
+			return c.InvalidateUserCache(ctx, e.Member.UserID.Hex())
+		}
+		return nil
+	})
+}
+
+// invalidateBillUsers drops the stats of everyone who has a share of the bills
+func (c *CacheService) invalidateBillUsers(ctx context.Context, bills ...models.Bill) error {
+	for _, bill := range bills {
+		for _, split := range bill.Splits {
+			if err := c.InvalidateUserCache(ctx, split.UserID.Hex()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -1,73 +1,160 @@
-package config
-
-import (
-	"log"
-
-	"github.com/spf13/viper"
-)
-
-type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	MongoDB  MongoDBConfig  `mapstructure:"mongodb"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Firebase FirebaseConfig `mapstructure:"firebase"`
-	Google   GoogleConfig   `mapstructure:"google"`
-}
-
-type ServerConfig struct {
-	Port string `mapstructure:"port"`
-	Mode string `mapstructure:"mode"` // debug, release, test
-}
-
-type MongoDBConfig struct {
-	URI      string `mapstructure:"uri"`
-	Database string `mapstructure:"database"`
-}
-
-type RedisConfig struct {
-	Addr     string `mapstructure:"addr"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
-}
-
-type FirebaseConfig struct {
-	CredentialsFile string `mapstructure:"credentials_file"`
-}
-
-type GoogleConfig struct {
-	VisionCredentials string `mapstructure:"vision_credentials"`
-	VisionAPIKey      string `mapstructure:"vision_api_key"`
-}
-
-func LoadConfig() *Config {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
-
-	// Set defaults
-	viper.SetDefault("server.port", ":8080")
-	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("mongodb.uri", "mongodb://localhost:27017")
-	viper.SetDefault("mongodb.database", "splitbill")
-	viper.SetDefault("redis.addr", "localhost:6379")
-	viper.SetDefault("redis.password", "")
-	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("firebase.credentials_file", "firebase-credentials.json")
-	viper.SetDefault("google.vision_credentials", "")
-	viper.SetDefault("google.vision_api_key", "")
-
-	// Read from environment variables
-	viper.AutomaticEnv()
-
-	if err := viper.ReadInConfig(); err != nil {
-		log.Printf("Warning: config file not found, using defaults: %v", err)
-	}
-
-	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
-		log.Fatalf("Failed to unmarshal config: %v", err)
-	}
-
-	return &cfg
-}
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Server    ServerConfig    `mapstructure:"server"`
+	MongoDB   MongoDBConfig   `mapstructure:"mongodb"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	Firebase  FirebaseConfig  `mapstructure:"firebase"`
+	Google    GoogleConfig    `mapstructure:"google"`
+	Payments  PaymentsConfig  `mapstructure:"payments"`
+	Reminders RemindersConfig `mapstructure:"reminders"`
+	Digests   DigestsConfig   `mapstructure:"digests"`
+	Events    EventsConfig    `mapstructure:"events"`
+	Webhooks  WebhooksConfig  `mapstructure:"webhooks"`
+	Notify    NotifyConfig    `mapstructure:"notifications"`
+}
+
+type ServerConfig struct {
+	Port string `mapstructure:"port"`
+	Mode string `mapstructure:"mode"` // debug, release, test
+}
+
+type MongoDBConfig struct {
+	URI      string `mapstructure:"uri"`
+	Database string `mapstructure:"database"`
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+type FirebaseConfig struct {
+	CredentialsFile string `mapstructure:"credentials_file"`
+}
+
+type GoogleConfig struct {
+	VisionCredentials string `mapstructure:"vision_credentials"`
+	VisionAPIKey      string `mapstructure:"vision_api_key"`
+}
+
+type PaymentsConfig struct {
+	WebhookSecret string `mapstructure:"webhook_secret"` // HMAC key shared with the payment aggregator
+}
+
+type RemindersConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"` // how often due reminders are looked for
+}
+
+type DigestsConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"` // how often due digests are looked for
+}
+
+// EventsConfig selects how background side effects of group events are delivered
+type EventsConfig struct {
+	Backend  string `mapstructure:"backend"`  // "memory" or "redis" (Redis Streams, survives restarts)
+	Consumer string `mapstructure:"consumer"` // this instance's name in the Redis consumer groups; defaults to the hostname
+}
+
+// WebhooksConfig controls outgoing group webhooks
+type WebhooksConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	RetryInterval time.Duration `mapstructure:"retry_interval"` // how often failed deliveries due for a retry are looked for
+	Timeout       time.Duration `mapstructure:"timeout"`
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	AllowPrivate  bool          `mapstructure:"allow_private_networks"` // for local development only
+}
+
+// NotifyConfig configures the notification channels besides push
+type NotifyConfig struct {
+	Email   EmailChannelConfig   `mapstructure:"email"`
+	Zalo    ZaloChannelConfig    `mapstructure:"zalo"`
+	Webhook WebhookChannelConfig `mapstructure:"webhook"`
+
+	DeferredCheckInterval time.Duration `mapstructure:"deferred_check_interval"` // how often notifications held by quiet hours are looked for
+}
+
+type EmailChannelConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+type ZaloChannelConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	AccessToken string `mapstructure:"access_token"` // Official Account access token
+	BaseURL     string `mapstructure:"base_url"`     // override to point at a stub server
+}
+
+type WebhookChannelConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	AllowPrivate bool          `mapstructure:"allow_private_networks"` // for local development only
+}
+
+func LoadConfig() *Config {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("./config")
+
+	// Set defaults
+	viper.SetDefault("server.port", ":8080")
+	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("mongodb.uri", "mongodb://localhost:27017/?directConnection=true")
+	viper.SetDefault("mongodb.database", "splitbill")
+	viper.SetDefault("redis.addr", "localhost:6379")
+	viper.SetDefault("redis.password", "")
+	viper.SetDefault("redis.db", 0)
+	viper.SetDefault("firebase.credentials_file", "firebase-credentials.json")
+	viper.SetDefault("google.vision_credentials", "")
+	viper.SetDefault("google.vision_api_key", "")
+	viper.SetDefault("payments.webhook_secret", "")
+	viper.SetDefault("reminders.enabled", true)
+	viper.SetDefault("reminders.check_interval", "15m")
+	viper.SetDefault("digests.enabled", true)
+	viper.SetDefault("digests.check_interval", "15m")
+	viper.SetDefault("events.backend", "memory")
+	viper.SetDefault("webhooks.enabled", true)
+	viper.SetDefault("webhooks.retry_interval", "15s")
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("webhooks.max_attempts", 8)
+	viper.SetDefault("webhooks.allow_private_networks", false)
+	viper.SetDefault("notifications.email.enabled", false)
+	viper.SetDefault("notifications.email.host", "localhost")
+	viper.SetDefault("notifications.email.port", 1025)
+	viper.SetDefault("notifications.email.from", "Split Bill <no-reply@splitbill.vn>")
+	viper.SetDefault("notifications.zalo.enabled", false)
+	viper.SetDefault("notifications.zalo.base_url", "https://openapi.zalo.me")
+	viper.SetDefault("notifications.webhook.enabled", true)
+	viper.SetDefault("notifications.webhook.timeout", "10s")
+	viper.SetDefault("notifications.webhook.allow_private_networks", false)
+	viper.SetDefault("notifications.deferred_check_interval", "1m")
+
+	// Read from environment variables
+	viper.AutomaticEnv()
+
+	if err := viper.ReadInConfig(); err != nil {
+		log.Printf("Warning: config file not found, using defaults: %v", err)
+	}
+
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
+		log.Fatalf("Failed to unmarshal config: %v", err)
+	}
+
+	return &cfg
+}
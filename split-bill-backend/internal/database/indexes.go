@@ -1,129 +1,300 @@
-package database
-
-import (
-	"context"
-	"log"
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-// EnsureIndexes creates all necessary MongoDB indexes for optimal query performance.
-// This should be called once during application startup.
-func EnsureIndexes(db *MongoDB) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	log.Println("📇 Creating MongoDB indexes...")
-
-	// Users collection indexes
-	createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "firebase_uid", Value: 1}},
-			Options: options.Index().SetUnique(true).SetName("idx_users_firebase_uid"),
-		},
-		{
-			Keys:    bson.D{{Key: "phone", Value: 1}},
-			Options: options.Index().SetSparse(true).SetName("idx_users_phone"),
-		},
-		{
-			Keys:    bson.D{{Key: "email", Value: 1}},
-			Options: options.Index().SetSparse(true).SetName("idx_users_email"),
-		},
-	})
-
-	// Groups collection indexes
-	createIndexes(ctx, db.Collection("groups"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "invite_code", Value: 1}},
-			Options: options.Index().SetUnique(true).SetName("idx_groups_invite_code"),
-		},
-		{
-			Keys:    bson.D{{Key: "members.user_id", Value: 1}},
-			Options: options.Index().SetName("idx_groups_member_user_id"),
-		},
-		{
-			Keys:    bson.D{{Key: "created_by", Value: 1}},
-			Options: options.Index().SetName("idx_groups_created_by"),
-		},
-		{
-			Keys:    bson.D{{Key: "status", Value: 1}},
-			Options: options.Index().SetName("idx_groups_status"),
-		},
-	})
-
-	// Bills collection indexes
-	createIndexes(ctx, db.Collection("bills"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("idx_bills_group_id_created_at"),
-		},
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "status", Value: 1}},
-			Options: options.Index().SetName("idx_bills_group_id_status"),
-		},
-		{
-			Keys:    bson.D{{Key: "created_by", Value: 1}},
-			Options: options.Index().SetName("idx_bills_created_by"),
-		},
-		{
-			Keys:    bson.D{{Key: "category", Value: 1}},
-			Options: options.Index().SetName("idx_bills_category"),
-		},
-	})
-
-	// Transactions collection indexes
-	createIndexes(ctx, db.Collection("transactions"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("idx_transactions_group_id_created_at"),
-		},
-		{
-			Keys:    bson.D{{Key: "from_user", Value: 1}},
-			Options: options.Index().SetName("idx_transactions_from_user"),
-		},
-		{
-			Keys:    bson.D{{Key: "to_user", Value: 1}},
-			Options: options.Index().SetName("idx_transactions_to_user"),
-		},
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "status", Value: 1}},
-			Options: options.Index().SetName("idx_transactions_group_id_status"),
-		},
-	})
-
-	// Activities collection indexes
-	createIndexes(ctx, db.Collection("activities"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("idx_activities_group_id_created_at"),
-		},
-		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("idx_activities_user_id_created_at"),
-		},
-	})
-
-	// OCR results collection indexes
-	createIndexes(ctx, db.Collection("ocr_results"), []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
-			Options: options.Index().SetName("idx_ocr_results_user_id_status"),
-		},
-		{
-			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
-			Options: options.Index().SetName("idx_ocr_results_group_id_created_at"),
-		},
-	})
-
-	log.Println("✅ MongoDB indexes created successfully")
-}
-
-func createIndexes(ctx context.Context, collection *mongo.Collection, indexes []mongo.IndexModel) {
-	_, err := collection.Indexes().CreateMany(ctx, indexes)
-	if err != nil {
-		log.Printf("⚠️  Warning: Failed to create indexes for %s: %v", collection.Name(), err)
-	}
-}
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates all necessary MongoDB indexes for optimal query performance.
+// This should be called once during application startup.
+func EnsureIndexes(db *MongoDB) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	log.Println("📇 Creating MongoDB indexes...")
+
+	// Users collection indexes
+	createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "firebase_uid", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_users_firebase_uid"),
+		},
+		{
+			Keys:    bson.D{{Key: "phone", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("idx_users_phone"),
+		},
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("idx_users_email"),
+		},
+		{
+			Keys:    bson.D{{Key: "devices.token", Value: 1}},
+			Options: options.Index().SetName("idx_users_device_token"),
+		},
+		{
+			Keys:    bson.D{{Key: "notification_preferences.digest", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("idx_users_digest"),
+		},
+	})
+
+	// Groups collection indexes
+	createIndexes(ctx, db.Collection("groups"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "invite_code", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_groups_invite_code"),
+		},
+		{
+			Keys:    bson.D{{Key: "members.user_id", Value: 1}},
+			Options: options.Index().SetName("idx_groups_member_user_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_by", Value: 1}},
+			Options: options.Index().SetName("idx_groups_created_by"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_groups_status"),
+		},
+	})
+
+	// Bills collection indexes
+	createIndexes(ctx, db.Collection("bills"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_bills_group_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_bills_group_id_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_by", Value: 1}},
+			Options: options.Index().SetName("idx_bills_created_by"),
+		},
+		{
+			Keys:    bson.D{{Key: "category", Value: 1}},
+			Options: options.Index().SetName("idx_bills_category"),
+		},
+	})
+
+	// Transactions collection indexes
+	createIndexes(ctx, db.Collection("transactions"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_transactions_group_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "from_user", Value: 1}},
+			Options: options.Index().SetName("idx_transactions_from_user"),
+		},
+		{
+			Keys:    bson.D{{Key: "to_user", Value: 1}},
+			Options: options.Index().SetName("idx_transactions_to_user"),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_transactions_group_id_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "reference", Value: 1}},
+			Options: options.Index().SetName("idx_transactions_reference").SetUnique(true).SetSparse(true),
+		},
+		{
+			// A transaction has at most one pending or confirmed reversal
+			Keys: bson.D{{Key: "reversal_of", Value: 1}},
+			Options: options.Index().SetName("idx_transactions_reversal_of_active").SetUnique(true).SetPartialFilterExpression(bson.M{
+				"reversal_of": bson.M{"$exists": true},
+				"status":      bson.M{"$in": bson.A{"pending", "confirmed"}},
+			}),
+		},
+	})
+
+	// Activities collection indexes
+	createIndexes(ctx, db.Collection("activities"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_activities_group_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_activities_user_id_created_at"),
+		},
+	})
+
+	// OCR results collection indexes
+	createIndexes(ctx, db.Collection("ocr_results"), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_ocr_results_user_id_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_ocr_results_group_id_created_at"),
+		},
+	})
+
+	// Bank statement collections indexes
+	createIndexes(ctx, db.Collection(CollectionStatementImports), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_statement_imports_user_id_created_at"),
+		},
+	})
+	createIndexes(ctx, db.Collection(CollectionStatementLines), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "fingerprint", Value: 1}},
+			Options: options.Index().SetName("idx_statement_lines_user_id_fingerprint").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "date", Value: -1}},
+			Options: options.Index().SetName("idx_statement_lines_user_id_status_date"),
+		},
+		{
+			Keys:    bson.D{{Key: "import_id", Value: 1}},
+			Options: options.Index().SetName("idx_statement_lines_import_id"),
+		},
+	})
+
+	// Payment references collection indexes
+	createIndexes(ctx, db.Collection(CollectionPaymentReferences), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("idx_payment_references_code").SetUnique(true),
+		},
+		{
+			// One open settle-up reference per pair of members
+			Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "from_user", Value: 1}, {Key: "to_user", Value: 1}},
+			Options: options.Index().SetName("idx_payment_references_group_id_from_user_to_user_open").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"open": true}),
+		},
+	})
+
+	// Payment webhook events collection indexes
+	createIndexes(ctx, db.Collection(CollectionPaymentEvents), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "provider_tx_id", Value: 1}},
+			Options: options.Index().SetName("idx_payment_events_provider_provider_tx_id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "transaction_id", Value: 1}},
+			Options: options.Index().SetName("idx_payment_events_transaction_id").SetSparse(true),
+		},
+	})
+
+	// Reminder delivery log indexes
+	createIndexes(ctx, db.Collection(CollectionReminderDeliveries), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("idx_reminder_deliveries_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_reminder_deliveries_group_id_user_id_kind_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("idx_reminder_deliveries_created_at_ttl").SetExpireAfterSeconds(90 * 24 * 60 * 60),
+		},
+	})
+
+	// Digest delivery log indexes
+	createIndexes(ctx, db.Collection(CollectionDigestDeliveries), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("idx_digest_deliveries_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("idx_digest_deliveries_created_at_ttl").SetExpireAfterSeconds(90 * 24 * 60 * 60),
+		},
+	})
+
+	// Notification inbox indexes
+	createIndexes(ctx, db.Collection(CollectionNotifications), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("idx_notifications_user_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}},
+			Options: options.Index().SetName("idx_notifications_user_id_read_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("idx_notifications_created_at_ttl").SetExpireAfterSeconds(180 * 24 * 60 * 60),
+		},
+	})
+
+	// Notifications held back by quiet hours
+	createIndexes(ctx, db.Collection(CollectionDeferredNotifications), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "deliver_at", Value: 1}},
+			Options: options.Index().SetName("idx_deferred_notifications_deliver_at"),
+		},
+	})
+
+	// Group webhook indexes
+	createIndexes(ctx, db.Collection(CollectionGroupWebhooks), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "active", Value: 1}},
+			Options: options.Index().SetName("idx_group_webhooks_group_id_active"),
+		},
+	})
+	createIndexes(ctx, db.Collection(CollectionWebhookDeliveries), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("idx_webhook_deliveries_webhook_id_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("idx_webhook_deliveries_status_next_attempt_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("idx_webhook_deliveries_created_at_ttl").SetExpireAfterSeconds(30 * 24 * 60 * 60),
+		},
+	})
+
+	// Invite link indexes
+	createIndexes(ctx, db.Collection(CollectionInviteLinks), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("idx_invite_links_code").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_invite_links_group_id_created_at"),
+		},
+	})
+
+	// Join request indexes. A user has at most one pending request per group.
+	createIndexes(ctx, db.Collection(CollectionJoinRequests), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("idx_join_requests_group_id_user_id_pending").SetUnique(true).SetPartialFilterExpression(bson.M{"status": "pending"}),
+		},
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_join_requests_group_id_status_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_join_requests_user_id_created_at"),
+		},
+	})
+
+	log.Println("✅ MongoDB indexes created successfully")
+}
+
+func createIndexes(ctx context.Context, collection *mongo.Collection, indexes []mongo.IndexModel) {
+	_, err := collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		log.Printf("⚠️  Warning: Failed to create indexes for %s: %v", collection.Name(), err)
+	}
+}
@@ -1,66 +1,100 @@
-package database
-
-import (
-	"context"
-	"log"
-	"time"
-
-	"github.com/splitbill/backend/internal/config"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
-)
-
-type MongoDB struct {
-	Client   *mongo.Client
-	Database *mongo.Database
-}
-
-func NewMongoDB(cfg *config.MongoDBConfig) *MongoDB {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	clientOpts := options.Client().ApplyURI(cfg.URI)
-	client, err := mongo.Connect(ctx, clientOpts)
-	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
-	}
-
-	// Ping to verify connection
-	if err := client.Ping(ctx, readpref.Primary()); err != nil {
-		log.Fatalf("Failed to ping MongoDB: %v", err)
-	}
-
-	log.Println("✅ Connected to MongoDB successfully")
-
-	db := client.Database(cfg.Database)
-
-	return &MongoDB{
-		Client:   client,
-		Database: db,
-	}
-}
-
-func (m *MongoDB) Collection(name string) *mongo.Collection {
-	return m.Database.Collection(name)
-}
-
-func (m *MongoDB) Disconnect() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := m.Client.Disconnect(ctx); err != nil {
-		log.Printf("Error disconnecting from MongoDB: %v", err)
-	}
-	log.Println("Disconnected from MongoDB")
-}
-
-// Collection name constants
-const (
-	CollectionUsers        = "users"
-	CollectionGroups       = "groups"
-	CollectionBills        = "bills"
-	CollectionTransactions = "transactions"
-	CollectionOCRResults   = "ocr_results"
-	CollectionActivities   = "activities"
-)
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/splitbill/backend/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type MongoDB struct {
+	Client   *mongo.Client
+	Database *mongo.Database
+}
+
+func NewMongoDB(cfg *config.MongoDBConfig) *MongoDB {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientOpts := options.Client().ApplyURI(cfg.URI)
+	client, err := mongo.Connect(ctx, clientOpts)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+
+	// Ping to verify connection
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	log.Println("✅ Connected to MongoDB successfully")
+
+	db := client.Database(cfg.Database)
+
+	return &MongoDB{
+		Client:   client,
+		Database: db,
+	}
+}
+
+func (m *MongoDB) Collection(name string) *mongo.Collection {
+	return m.Database.Collection(name)
+}
+
+// WithTransaction runs fn in a multi-document transaction, retrying it on
+// transient errors. Operations inside fn must use the context it is given.
+// Transactions need a replica set; a single-node one is enough.
+func (m *MongoDB) WithTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+func (m *MongoDB) Disconnect() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.Client.Disconnect(ctx); err != nil {
+		log.Printf("Error disconnecting from MongoDB: %v", err)
+	}
+	log.Println("Disconnected from MongoDB")
+}
+
+// Collection name constants
+const (
+	CollectionUsers        = "users"
+	CollectionGroups       = "groups"
+	CollectionBills        = "bills"
+	CollectionTransactions = "transactions"
+	CollectionOCRResults   = "ocr_results"
+	CollectionActivities   = "activities"
+
+	CollectionStatementImports = "statement_imports"
+	CollectionStatementLines   = "statement_lines"
+
+	CollectionPaymentReferences = "payment_references"
+	CollectionPaymentEvents     = "payment_events"
+
+	CollectionReminderDeliveries = "reminder_deliveries"
+	CollectionDigestDeliveries   = "digest_deliveries"
+
+	CollectionNotifications         = "notifications"
+	CollectionDeferredNotifications = "deferred_notifications"
+
+	CollectionGroupWebhooks     = "group_webhooks"
+	CollectionWebhookDeliveries = "webhook_deliveries"
+
+	CollectionInviteLinks  = "invite_links"
+	CollectionJoinRequests = "join_requests"
+)
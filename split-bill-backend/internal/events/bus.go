@@ -0,0 +1,225 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// handlerTimeout bounds one asynchronous handler run
+const handlerTimeout = 30 * time.Second
+
+var errUnknownEvent = errors.New("unknown event")
+
+// Handler reacts to an event. Returning an error makes the Redis Streams
+// backend retry the event for that subscriber.
+type Handler func(ctx context.Context, event Event) error
+
+type subscription struct {
+	subscriber string
+	async      bool
+	handle     Handler
+}
+
+// Bus delivers published events to subscribers. Synchronous handlers run
+// inside Publish, before the request that caused the event returns: use
+// them for cheap work that must not lag behind, like cache invalidation.
+// Asynchronous handlers run in the background so a slow or failing side
+// effect never holds up or fails the request: in-process goroutines by
+// default, or Redis Streams consumer groups when a stream is configured,
+// which survive restarts and are retried.
+type Bus struct {
+	stream *Stream
+	logger *zap.Logger
+
+	mu   sync.RWMutex
+	subs map[Name][]subscription
+	all  []subscription
+
+	pending sync.WaitGroup
+}
+
+// NewBus creates a bus. With a nil stream, asynchronous handlers run in
+// this process and events still waiting for them are lost on a crash.
+func NewBus(stream *Stream, logger *zap.Logger) *Bus {
+	return &Bus{
+		stream: stream,
+		logger: logger,
+		subs:   make(map[Name][]subscription),
+	}
+}
+
+// Subscribe runs handler inside Publish for every event of type E
+func Subscribe[E Event](b *Bus, subscriber string, handler func(ctx context.Context, event E) error) {
+	b.add(nameOf[E](), subscription{subscriber: subscriber, handle: typed(handler)})
+}
+
+// SubscribeAsync runs handler in the background for every event of type E.
+// subscriber names the consumer; with Redis Streams each subscriber gets
+// every event once, however many instances are running.
+func SubscribeAsync[E Event](b *Bus, subscriber string, handler func(ctx context.Context, event E) error) {
+	b.add(nameOf[E](), subscription{subscriber: subscriber, async: true, handle: typed(handler)})
+}
+
+// SubscribeAll runs handler for every event, inside Publish or in the
+// background
+func (b *Bus) SubscribeAll(subscriber string, async bool, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, subscription{subscriber: subscriber, async: async, handle: handler})
+}
+
+func (b *Bus) add(name Name, sub subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[name] = append(b.subs[name], sub)
+}
+
+func nameOf[E Event]() Name {
+	var zero E
+	return zero.Name()
+}
+
+func typed[E Event](handler func(ctx context.Context, event E) error) Handler {
+	return func(ctx context.Context, event Event) error {
+		e, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("%w: %T", errUnknownEvent, event)
+		}
+		return handler(ctx, e)
+	}
+}
+
+// subscriptions returns who handles an event
+func (b *Bus) subscriptions(name Name) []subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	subs := make([]subscription, 0, len(b.subs[name])+len(b.all))
+	subs = append(subs, b.subs[name]...)
+	return append(subs, b.all...)
+}
+
+// Publish hands an event to its subscribers. It never fails: handler
+// errors are logged, and if the stream is unavailable asynchronous
+// handlers run in this process instead. A nil Bus drops everything, so
+// services work without one.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	async := false
+	for _, sub := range b.subscriptions(event.Name()) {
+		if sub.async {
+			async = true
+			continue
+		}
+		if err := sub.handle(ctx, event); err != nil {
+			b.logger.Warn("Event handler failed",
+				zap.String("event", string(event.Name())),
+				zap.String("subscriber", sub.subscriber),
+				zap.Error(err),
+			)
+		}
+	}
+	if !async {
+		return
+	}
+
+	if b.stream != nil {
+		err := b.stream.append(event)
+		if err == nil {
+			return
+		}
+		b.logger.Warn("Event not written to stream, handling it in process",
+			zap.String("event", string(event.Name())),
+			zap.Error(err),
+		)
+	}
+
+	for _, sub := range b.subscriptions(event.Name()) {
+		if sub.async {
+			b.goHandle(sub, event)
+		}
+	}
+}
+
+// goHandle runs an asynchronous handler in this process
+func (b *Bus) goHandle(sub subscription, event Event) {
+	b.pending.Add(1)
+	go func() {
+		defer b.pending.Done()
+
+		// The request that published the event may be finished already
+		ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+		defer cancel()
+
+		if err := sub.handle(ctx, event); err != nil {
+			b.logger.Warn("Event handler failed",
+				zap.String("event", string(event.Name())),
+				zap.String("subscriber", sub.subscriber),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// Run consumes the stream for every asynchronous subscriber until ctx is
+// cancelled. Call it once all subscribers are registered. Without a stream
+// it returns straight away.
+func (b *Bus) Run(ctx context.Context) {
+	if b.stream == nil {
+		return
+	}
+
+	subscribers := map[string]bool{}
+	b.mu.RLock()
+	for _, subs := range b.subs {
+		for _, sub := range subs {
+			subscribers[sub.subscriber] = subscribers[sub.subscriber] || sub.async
+		}
+	}
+	for _, sub := range b.all {
+		subscribers[sub.subscriber] = subscribers[sub.subscriber] || sub.async
+	}
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for subscriber, async := range subscribers {
+		if !async {
+			continue
+		}
+		wg.Add(1)
+		go func(subscriber string) {
+			defer wg.Done()
+			b.stream.consume(ctx, subscriber, b.dispatcher(subscriber))
+		}(subscriber)
+	}
+	wg.Wait()
+}
+
+// dispatcher runs every asynchronous handler of subscriber that matches
+// the event. Events it has no handler for are simply acknowledged.
+func (b *Bus) dispatcher(subscriber string) func(ctx context.Context, event Event) error {
+	return func(ctx context.Context, event Event) error {
+		var errs []error
+		for _, sub := range b.subscriptions(event.Name()) {
+			if !sub.async || sub.subscriber != subscriber {
+				continue
+			}
+			if err := sub.handle(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// Wait blocks until the asynchronous handlers running in this process are done
+func (b *Bus) Wait() {
+	b.pending.Wait()
+}
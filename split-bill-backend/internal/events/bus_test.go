@@ -0,0 +1,222 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// recorder collects the events handlers were called with
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, label)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func billCreated() BillCreated {
+	return BillCreated{Bill: models.Bill{ID: primitive.NewObjectID(), GroupID: primitive.NewObjectID()}}
+}
+
+func TestPublishSyncHandlersRunInline(t *testing.T) {
+	bus := NewBus(nil, zap.NewNop())
+	var seen []string
+	Subscribe(bus, "cache", func(ctx context.Context, e BillCreated) error {
+		seen = append(seen, ctx.Value(ctxKey{}).(string))
+		return nil
+	})
+	Subscribe(bus, "failing", func(ctx context.Context, e BillCreated) error {
+		return errors.New("boom")
+	})
+	Subscribe(bus, "after-failing", func(ctx context.Context, e BillCreated) error {
+		seen = append(seen, "after")
+		return nil
+	})
+	Subscribe(bus, "other", func(ctx context.Context, e BillDeleted) error {
+		t.Error("BillDeleted handler got a BillCreated")
+		return nil
+	})
+
+	bus.Publish(context.WithValue(context.Background(), ctxKey{}, "request"), billCreated())
+
+	// Everything ran before Publish returned, with the publisher's context,
+	// and one failing handler does not stop the others
+	if len(seen) != 2 || seen[0] != "request" || seen[1] != "after" {
+		t.Errorf("seen = %v", seen)
+	}
+}
+
+func TestPublishAsyncHandlersRunInBackground(t *testing.T) {
+	bus := NewBus(nil, zap.NewNop())
+	release := make(chan struct{})
+	done := make(chan context.Context, 1)
+	SubscribeAsync(bus, "digest", func(ctx context.Context, e BillCreated) error {
+		<-release
+		done <- ctx
+		return nil
+	})
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(context.WithValue(context.Background(), ctxKey{}, "request"), billCreated())
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish waited for an asynchronous handler")
+	}
+
+	close(release)
+	bus.Wait()
+	ctx := <-done
+	if ctx.Value(ctxKey{}) != nil {
+		t.Error("asynchronous handler got the request context, which may be cancelled by now")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("asynchronous handler has no deadline")
+	}
+}
+
+func TestSubscribeAll(t *testing.T) {
+	bus := NewBus(nil, zap.NewNop())
+	rec := &recorder{}
+	bus.SubscribeAll("audit", false, func(ctx context.Context, e Event) error {
+		rec.add("sync " + string(e.Name()))
+		return nil
+	})
+	bus.SubscribeAll("webhooks", true, func(ctx context.Context, e Event) error {
+		rec.add("async " + string(e.Name()))
+		return nil
+	})
+
+	bus.Publish(context.Background(), billCreated())
+	bus.Publish(context.Background(), MemberLeft{})
+	bus.Wait()
+
+	got := map[string]bool{}
+	for _, label := range rec.list() {
+		got[label] = true
+	}
+	for _, want := range []string{"sync bill.created", "async bill.created", "sync member.left", "async member.left"} {
+		if !got[want] {
+			t.Errorf("missing %q in %v", want, rec.list())
+		}
+	}
+	if len(rec.list()) != 4 {
+		t.Errorf("handled %v, want each event once per subscriber", rec.list())
+	}
+}
+
+func TestPublishFallsBackWhenStreamFails(t *testing.T) {
+	// A Redis address nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1, DialTimeout: time.Second})
+	defer client.Close()
+	bus := NewBus(NewStream(client, "test", zap.NewNop()), zap.NewNop())
+
+	rec := &recorder{}
+	Subscribe(bus, "cache", func(ctx context.Context, e BillCreated) error {
+		rec.add("sync")
+		return nil
+	})
+	SubscribeAsync(bus, "notifications", func(ctx context.Context, e BillCreated) error {
+		rec.add("notifications")
+		return nil
+	})
+	SubscribeAsync(bus, "activity", func(ctx context.Context, e BillCreated) error {
+		rec.add("activity")
+		return nil
+	})
+
+	bus.Publish(context.Background(), billCreated())
+	bus.Wait()
+
+	got := map[string]int{}
+	for _, label := range rec.list() {
+		got[label]++
+	}
+	if got["sync"] != 1 || got["notifications"] != 1 || got["activity"] != 1 {
+		t.Errorf("handled %v, want every handler once", got)
+	}
+}
+
+func TestPublishOnNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(context.Background(), billCreated())
+}
+
+func TestDispatcher(t *testing.T) {
+	bus := NewBus(nil, zap.NewNop())
+	rec := &recorder{}
+	SubscribeAsync(bus, "notifications", func(ctx context.Context, e BillCreated) error {
+		rec.add("notifications bill")
+		return errors.New("fcm down")
+	})
+	bus.SubscribeAll("notifications", true, func(ctx context.Context, e Event) error {
+		rec.add("notifications all")
+		return nil
+	})
+	SubscribeAsync(bus, "activity", func(ctx context.Context, e BillCreated) error {
+		rec.add("activity")
+		return nil
+	})
+	Subscribe(bus, "notifications", func(ctx context.Context, e BillCreated) error {
+		rec.add("notifications sync")
+		return nil
+	})
+
+	dispatch := bus.dispatcher("notifications")
+
+	// Both of the subscriber's asynchronous handlers run, and the failure
+	// is reported so the stream retries the event
+	if err := dispatch(context.Background(), billCreated()); err == nil {
+		t.Error("dispatch: want the handler error")
+	}
+	got := rec.list()
+	if len(got) != 2 || got[0] != "notifications bill" || got[1] != "notifications all" {
+		t.Errorf("handled %v", got)
+	}
+
+	// Events the subscriber has only SubscribeAll for are acknowledged once it ran
+	if err := dispatch(context.Background(), MemberLeft{}); err != nil {
+		t.Errorf("dispatch MemberLeft: %v", err)
+	}
+	if err := bus.dispatcher("nobody")(context.Background(), billCreated()); err != nil {
+		t.Errorf("dispatch for a subscriber with no handlers: %v", err)
+	}
+}
+
+func TestTypedRejectsOtherEvents(t *testing.T) {
+	handle := typed(func(ctx context.Context, e BillCreated) error { return nil })
+	if err := handle(context.Background(), MemberLeft{}); !errors.Is(err, errUnknownEvent) {
+		t.Errorf("err = %v, want errUnknownEvent", err)
+	}
+}
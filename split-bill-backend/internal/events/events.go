@@ -0,0 +1,221 @@
+// Package events is the domain event bus. Services publish what happened in
+// a group (a bill was added, a payment confirmed, a member joined) and the
+// side effects subscribe to it: the activity log, notifications, cache
+// invalidation, realtime updates and webhooks. Nothing that causes an event
+// has to know who reacts to it.
+package events
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Name identifies a kind of event, e.g. "bill.created"
+type Name string
+
+// Event is something that happened in a group. Events are plain data so
+// they can travel through Redis Streams as JSON.
+type Event interface {
+	Name() Name
+	Group() primitive.ObjectID
+}
+
+// Actor is the user who caused an event. Name may be empty when the
+// publisher only knew the ID.
+type Actor struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// ActorOf describes a user as the actor of an event
+func ActorOf(user *models.User) Actor {
+	return Actor{ID: user.ID, Name: user.DisplayName}
+}
+
+// BillCreated is published when a bill is added, by hand or from a scanned
+// receipt (OCRResultID)
+type BillCreated struct {
+	Bill        models.Bill         `json:"bill"`
+	Actor       Actor               `json:"actor"`
+	OCRResultID *primitive.ObjectID `json:"ocr_result_id,omitempty"`
+}
+
+func (BillCreated) Name() Name                  { return "bill.created" }
+func (e BillCreated) Group() primitive.ObjectID { return e.Bill.GroupID }
+
+// BillUpdated is published when a bill changes. Previous is the bill before the change.
+type BillUpdated struct {
+	Bill     models.Bill `json:"bill"`
+	Previous models.Bill `json:"previous"`
+	Actor    Actor       `json:"actor"`
+}
+
+func (BillUpdated) Name() Name                  { return "bill.updated" }
+func (e BillUpdated) Group() primitive.ObjectID { return e.Bill.GroupID }
+
+// BillDeleted is published when a bill is deleted
+type BillDeleted struct {
+	Bill  models.Bill `json:"bill"`
+	Actor Actor       `json:"actor"`
+}
+
+func (BillDeleted) Name() Name                  { return "bill.deleted" }
+func (e BillDeleted) Group() primitive.ObjectID { return e.Bill.GroupID }
+
+// TransactionCreated is published when a payment, or the reversal of one, is recorded
+type TransactionCreated struct {
+	Transaction models.Transaction `json:"transaction"`
+	Actor       Actor              `json:"actor"`
+}
+
+func (TransactionCreated) Name() Name                  { return "transaction.created" }
+func (e TransactionCreated) Group() primitive.ObjectID { return e.Transaction.GroupID }
+
+// TransactionUpdated is published when the sender amends a pending transaction
+type TransactionUpdated struct {
+	Transaction models.Transaction `json:"transaction"`
+	Previous    models.Transaction `json:"previous"`
+	Actor       Actor              `json:"actor"`
+}
+
+func (TransactionUpdated) Name() Name                  { return "transaction.updated" }
+func (e TransactionUpdated) Group() primitive.ObjectID { return e.Transaction.GroupID }
+
+// TransactionCancelled is published when the sender withdraws a pending transaction
+type TransactionCancelled struct {
+	Transaction models.Transaction `json:"transaction"`
+	Actor       Actor              `json:"actor"`
+}
+
+func (TransactionCancelled) Name() Name                  { return "transaction.cancelled" }
+func (e TransactionCancelled) Group() primitive.ObjectID { return e.Transaction.GroupID }
+
+// TransactionConfirmed is published when the recipient confirms a payment,
+// by hand, from a bank statement or from a payment webhook
+type TransactionConfirmed struct {
+	Transaction models.Transaction `json:"transaction"`
+	Actor       Actor              `json:"actor"`
+}
+
+func (TransactionConfirmed) Name() Name                  { return "transaction.confirmed" }
+func (e TransactionConfirmed) Group() primitive.ObjectID { return e.Transaction.GroupID }
+
+// TransactionRejected is published when the recipient declines a payment
+type TransactionRejected struct {
+	Transaction models.Transaction `json:"transaction"`
+	Actor       Actor              `json:"actor"`
+}
+
+func (TransactionRejected) Name() Name                  { return "transaction.rejected" }
+func (e TransactionRejected) Group() primitive.ObjectID { return e.Transaction.GroupID }
+
+// MemberJoined is published when someone joins a group with an invite code,
+// or is added by another member (AddedBy)
+type MemberJoined struct {
+	GroupID    primitive.ObjectID `json:"group_id"`
+	GroupName  string             `json:"group_name"`
+	Member     models.GroupMember `json:"member"`
+	MemberName string             `json:"member_name"`
+	AddedBy    *Actor             `json:"added_by,omitempty"`
+}
+
+func (MemberJoined) Name() Name                  { return "member.joined" }
+func (e MemberJoined) Group() primitive.ObjectID { return e.GroupID }
+
+// MemberLeft is published when a member leaves a group or is removed from
+// it by an admin (RemovedBy)
+type MemberLeft struct {
+	GroupID   primitive.ObjectID `json:"group_id"`
+	GroupName string             `json:"group_name"`
+	UserID    primitive.ObjectID `json:"user_id"`
+	RemovedBy *Actor             `json:"removed_by,omitempty"`
+}
+
+func (MemberLeft) Name() Name                  { return "member.left" }
+func (e MemberLeft) Group() primitive.ObjectID { return e.GroupID }
+
+// PlaceholderClaimed is published when someone takes a placeholder
+// member's place through a claim link, after which the placeholder's bills,
+// shares and payments are theirs. MemberJoined is published as well.
+type PlaceholderClaimed struct {
+	GroupID       primitive.ObjectID `json:"group_id"`
+	GroupName     string             `json:"group_name"`
+	PlaceholderID primitive.ObjectID `json:"placeholder_id"`
+	Member        models.GroupMember `json:"member"`
+	MemberName    string             `json:"member_name"`
+}
+
+func (PlaceholderClaimed) Name() Name                  { return "member.claimed" }
+func (e PlaceholderClaimed) Group() primitive.ObjectID { return e.GroupID }
+
+// JoinRequested is published when someone asks to join a group that
+// requires approval
+type JoinRequested struct {
+	GroupName     string             `json:"group_name"`
+	Request       models.JoinRequest `json:"request"`
+	ApplicantName string             `json:"applicant_name"`
+}
+
+func (JoinRequested) Name() Name                  { return "join_request.created" }
+func (e JoinRequested) Group() primitive.ObjectID { return e.Request.GroupID }
+
+// JoinRequestDecided is published when an owner or admin approves or
+// denies a join request. An approval also publishes MemberJoined.
+type JoinRequestDecided struct {
+	GroupName string             `json:"group_name"`
+	Request   models.JoinRequest `json:"request"`
+	DecidedBy Actor              `json:"decided_by"`
+}
+
+func (JoinRequestDecided) Name() Name                  { return "join_request.decided" }
+func (e JoinRequestDecided) Group() primitive.ObjectID { return e.Request.GroupID }
+
+// decoders turns the JSON of each kind of event back into its type
+var decoders = map[Name]func([]byte) (Event, error){}
+
+func register[E Event]() {
+	var zero E
+	decoders[zero.Name()] = func(data []byte) (Event, error) {
+		var event E
+		err := json.Unmarshal(data, &event)
+		return event, err
+	}
+}
+
+func init() {
+	register[BillCreated]()
+	register[BillUpdated]()
+	register[BillDeleted]()
+	register[TransactionCreated]()
+	register[TransactionUpdated]()
+	register[TransactionCancelled]()
+	register[TransactionConfirmed]()
+	register[TransactionRejected]()
+	register[MemberJoined]()
+	register[MemberLeft]()
+	register[PlaceholderClaimed]()
+	register[JoinRequested]()
+	register[JoinRequestDecided]()
+}
+
+// Names lists every kind of event, sorted
+func Names() []Name {
+	names := make([]Name, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// decode rebuilds an event from its name and JSON
+func decode(name Name, data []byte) (Event, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, errUnknownEvent
+	}
+	return decoder(data)
+}
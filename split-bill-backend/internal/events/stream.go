@@ -0,0 +1,215 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamKey holds every published event
+	streamKey = "events:domain"
+
+	// deadLetterKey holds the events a subscriber gave up on
+	deadLetterKey = "events:domain:dead"
+
+	// streamSize is roughly how many events are kept once every subscriber has read them
+	streamSize = 100000
+
+	// readBlock is how long a consumer waits for new events before checking for retries
+	readBlock = 5 * time.Second
+
+	// retryAfter is how long a failed or abandoned event waits before it is
+	// tried again. Each attempt waits twice as long as the one before.
+	retryAfter = 30 * time.Second
+
+	// maxAttempts is how often an event is tried before it is dead-lettered
+	maxAttempts = 5
+
+	appendTimeout = 3 * time.Second
+)
+
+// Stream is the durable backend for asynchronous handlers. Events are
+// appended to a Redis stream and every subscriber reads it through its
+// own consumer group, so each subscriber handles each event once across
+// all instances, and events published while a subscriber was down are
+// handled when it comes back. Events that keep failing end up in the
+// dead-letter stream. Handlers may see an event more than once.
+type Stream struct {
+	client   *redis.Client
+	consumer string
+	logger   *zap.Logger
+}
+
+// NewStream creates the Redis Streams backend. consumer names this
+// instance within each consumer group and should be stable across restarts,
+// so events it was handling when it stopped are picked up again.
+func NewStream(client *redis.Client, consumer string, logger *zap.Logger) *Stream {
+	return &Stream{client: client, consumer: consumer, logger: logger}
+}
+
+func (s *Stream) append(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), appendTimeout)
+	defer cancel()
+
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: streamKey,
+		MaxLen: streamSize,
+		Approx: true,
+		Values: map[string]interface{}{"name": string(event.Name()), "event": payload},
+	}).Err()
+}
+
+// consume hands the events of the stream to handle as the consumer group
+// subscriber, until ctx is cancelled
+func (s *Stream) consume(ctx context.Context, subscriber string, handle Handler) {
+	// Start from new events: a new subscriber does not replay history
+	err := s.client.XGroupCreateMkStream(ctx, streamKey, subscriber, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		s.logger.Error("Failed to create event consumer group",
+			zap.String("subscriber", subscriber),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for ctx.Err() == nil {
+		s.retry(ctx, subscriber, handle)
+
+		streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    subscriber,
+			Consumer: s.consumer,
+			Streams:  []string{streamKey, ">"},
+			Count:    50,
+			Block:    readBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			s.logger.Warn("Failed to read events", zap.String("subscriber", subscriber), zap.Error(err))
+			sleep(ctx, readBlock)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				s.handle(ctx, subscriber, message, 1, handle)
+			}
+		}
+	}
+}
+
+// retry takes over the events of subscriber that failed, or whose consumer
+// went away, once they have waited long enough for their next attempt
+func (s *Stream) retry(ctx context.Context, subscriber string, handle Handler) {
+	pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: streamKey,
+		Group:  subscriber,
+		Idle:   retryAfter,
+		Start:  "-",
+		End:    "+",
+		Count:  50,
+	}).Result()
+	if err != nil {
+		return
+	}
+
+	for _, entry := range pending {
+		attempts := int(entry.RetryCount)
+		if entry.Idle < backoff(attempts) {
+			continue
+		}
+
+		claimed, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   streamKey,
+			Group:    subscriber,
+			Consumer: s.consumer,
+			MinIdle:  entry.Idle,
+			Messages: []string{entry.ID},
+		}).Result()
+		if err != nil || len(claimed) == 0 {
+			continue // another instance got it first
+		}
+		s.handle(ctx, subscriber, claimed[0], attempts+1, handle)
+	}
+}
+
+// handle runs one attempt at an event and acknowledges it when it succeeded,
+// cannot be decoded, or has failed too often
+func (s *Stream) handle(ctx context.Context, subscriber string, message redis.XMessage, attempt int, handle Handler) {
+	name, _ := message.Values["name"].(string)
+	payload, _ := message.Values["event"].(string)
+
+	event, err := decode(Name(name), []byte(payload))
+	if err == nil {
+		hctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+		err = handle(hctx, event)
+		cancel()
+		if err == nil {
+			s.ack(ctx, subscriber, message.ID)
+			return
+		}
+		if attempt < maxAttempts {
+			s.logger.Warn("Event handler failed, will retry",
+				zap.String("event", name),
+				zap.String("subscriber", subscriber),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			return
+		}
+	}
+
+	s.logger.Error("Giving up on event",
+		zap.String("event", name),
+		zap.String("subscriber", subscriber),
+		zap.Int("attempt", attempt),
+		zap.Error(err),
+	)
+	s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: deadLetterKey,
+		MaxLen: streamSize,
+		Approx: true,
+		Values: map[string]interface{}{
+			"id":         message.ID,
+			"subscriber": subscriber,
+			"name":       name,
+			"event":      payload,
+			"error":      err.Error(),
+		},
+	})
+	s.ack(ctx, subscriber, message.ID)
+}
+
+func (s *Stream) ack(ctx context.Context, subscriber, id string) {
+	if err := s.client.XAck(ctx, streamKey, subscriber, id).Err(); err != nil {
+		s.logger.Warn("Failed to acknowledge event", zap.String("subscriber", subscriber), zap.Error(err))
+	}
+}
+
+// backoff is how long an event that was tried attempts times waits before
+// the next attempt: retryAfter, then twice as long each time
+func backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return retryAfter << (attempts - 1)
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{maxAttempts, 8 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	event := TransactionConfirmed{
+		Transaction: models.Transaction{ID: primitive.NewObjectID(), GroupID: primitive.NewObjectID(), Amount: 150000},
+		Actor:       Actor{ID: primitive.NewObjectID(), Name: "An"},
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decode(event.Name(), payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := decoded.(TransactionConfirmed)
+	if !ok {
+		t.Fatalf("decoded a %T", decoded)
+	}
+	if got.Transaction.ID != event.Transaction.ID || got.Group() != event.Group() ||
+		got.Transaction.Amount != 150000 || got.Actor != event.Actor {
+		t.Errorf("decoded = %+v, want %+v", got, event)
+	}
+}
+
+func TestDecodeUnknownEvent(t *testing.T) {
+	if _, err := decode("bill.archived", []byte(`{}`)); !errors.Is(err, errUnknownEvent) {
+		t.Errorf("err = %v, want errUnknownEvent", err)
+	}
+}
+
+func TestNamesCoverEveryDecoder(t *testing.T) {
+	names := Names()
+	if len(names) != len(decoders) {
+		t.Fatalf("%d names for %d decoders", len(names), len(decoders))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("Names not sorted: %s before %s", names[i-1], names[i])
+		}
+	}
+}
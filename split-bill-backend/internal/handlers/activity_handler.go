@@ -1,94 +1,219 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"github.com/splitbill/backend/internal/repository"
-	"github.com/splitbill/backend/internal/services"
-	"github.com/splitbill/backend/internal/utils"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-type ActivityHandler struct {
-	activityService *services.ActivityService
-	userRepo        *repository.UserRepository
-}
-
-func NewActivityHandler(activityService *services.ActivityService, userRepo *repository.UserRepository) *ActivityHandler {
-	return &ActivityHandler{
-		activityService: activityService,
-		userRepo:        userRepo,
-	}
-}
-
-// GetGroupActivities godoc
-// @Summary      Get group activities
-// @Description  Returns the activity feed for a specific group with optional limit
-// @Tags         Activities
-// @Produce      json
-// @Param        id     path      string  true   "Group ID"
-// @Param        limit  query     int     false  "Max number of activities (default 20)"
-// @Success      200    {object}  utils.APIResponse{data=[]models.ActivityResponse}
-// @Failure      400    {object}  utils.APIResponse
-// @Failure      500    {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /groups/{id}/activities [get]
-func (h *ActivityHandler) GetGroupActivities(c *gin.Context) {
-	groupID := c.Param("id")
-	if _, err := primitive.ObjectIDFromHex(groupID); err != nil {
-		utils.RespondBadRequest(c, "Invalid group ID")
-		return
-	}
-
-	limitStr := c.DefaultQuery("limit", "20")
-	limit, err := strconv.ParseInt(limitStr, 10, 64)
-	if err != nil || limit <= 0 {
-		limit = 20
-	}
-
-	activities, err := h.activityService.GetGroupActivities(c.Request.Context(), groupID, limit)
-	if err != nil {
-		utils.RespondInternalError(c, "Failed to get activities: "+err.Error())
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Group activities", activities)
-}
-
-// GetUserActivities godoc
-// @Summary      Get current user's activities
-// @Description  Returns activities across all groups the authenticated user belongs to
-// @Tags         Activities
-// @Produce      json
-// @Param        limit  query     int  false  "Max number of activities (default 30)"
-// @Success      200    {object}  utils.APIResponse{data=[]models.ActivityResponse}
-// @Failure      401    {object}  utils.APIResponse
-// @Failure      500    {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /activities/me [get]
-func (h *ActivityHandler) GetUserActivities(c *gin.Context) {
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	user, err := h.userRepo.FindByFirebaseUID(c.Request.Context(), uid)
-	if err != nil {
-		utils.RespondUnauthorized(c, "User not found")
-		return
-	}
-
-	limitStr := c.DefaultQuery("limit", "30")
-	limit, err := strconv.ParseInt(limitStr, 10, 64)
-	if err != nil || limit <= 0 {
-		limit = 30
-	}
-
-	activities, err := h.activityService.GetUserActivities(c.Request.Context(), user.ID, limit)
-	if err != nil {
-		utils.RespondInternalError(c, "Failed to get activities: "+err.Error())
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "User activities", activities)
-}
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/i18n"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ActivityHandler struct {
+	activityService *services.ActivityService
+	userRepo        *repository.UserRepository
+}
+
+func NewActivityHandler(activityService *services.ActivityService, userRepo *repository.UserRepository) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		userRepo:        userRepo,
+	}
+}
+
+// GetGroupActivities godoc
+// @Summary      Get group activities
+// @Description  Returns a page of a group's activity feed, newest first, in the user's language (profile setting, else Accept-Language). Pass next_cursor from the previous page as cursor to get the next one; activities arriving in between do not shift the pages.
+// @Tags         Activities
+// @Produce      json
+// @Param        id               path      string  true   "Group ID"
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Items per page (default 20, max 100)"
+// @Param        type             query     string  false  "Only these types, comma separated (e.g. bill_created,payment_confirmed)"
+// @Param        actor            query     string  false  "Only activities done by this user ID"
+// @Param        from             query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        to               query     string  false  "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param        Accept-Language  header    string  false  "vi or en"
+// @Success      200    {object}  utils.APIResponse{data=utils.CursorResponse{data=[]models.ActivityResponse}}
+// @Failure      400    {object}  utils.APIResponse
+// @Failure      401    {object}  utils.APIResponse
+// @Failure      403    {object}  utils.APIResponse
+// @Failure      500    {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/activities [get]
+func (h *ActivityHandler) GetGroupActivities(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	groupID := c.Param("id")
+	if _, err := primitive.ObjectIDFromHex(groupID); err != nil {
+		utils.RespondBadRequest(c, "Invalid group ID")
+		return
+	}
+
+	filter, page, ok := parseActivityQuery(c)
+	if !ok {
+		return
+	}
+
+	activities, next, err := h.activityService.GetGroupActivities(c.Request.Context(), groupID, filter, page, i18n.ForRequest(c.Request, user))
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to get activities: "+err.Error())
+		return
+	}
+
+	utils.RespondCursor(c, http.StatusOK, "Group activities", activities, next)
+}
+
+// GetUserActivities godoc
+// @Summary      Get current user's activities
+// @Description  Returns a page of the activities across all groups the authenticated user belongs to, newest first, in the user's language (profile setting, else Accept-Language). Pages are cursor based like the group feed.
+// @Tags         Activities
+// @Produce      json
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Items per page (default 20, max 100)"
+// @Param        type             query     string  false  "Only these types, comma separated"
+// @Param        actor            query     string  false  "Only activities done by this user ID"
+// @Param        from             query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        to               query     string  false  "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param        Accept-Language  header    string  false  "vi or en"
+// @Success      200    {object}  utils.APIResponse{data=utils.CursorResponse{data=[]models.ActivityResponse}}
+// @Failure      400    {object}  utils.APIResponse
+// @Failure      401    {object}  utils.APIResponse
+// @Failure      500    {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /activities/me [get]
+func (h *ActivityHandler) GetUserActivities(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	filter, page, ok := parseActivityQuery(c)
+	if !ok {
+		return
+	}
+
+	activities, next, err := h.activityService.GetUserActivities(c.Request.Context(), user.ID, filter, page, i18n.ForRequest(c.Request, user))
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to get activities: "+err.Error())
+		return
+	}
+
+	utils.RespondCursor(c, http.StatusOK, "User activities", activities, next)
+}
+
+// GetOwnActivities godoc
+// @Summary      Get what the current user did
+// @Description  Returns a page of the activities the authenticated user did themselves in any group, newest first, in the user's language (profile setting, else Accept-Language)
+// @Tags         Activities
+// @Produce      json
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Items per page (default 20, max 100)"
+// @Param        type             query     string  false  "Only these types, comma separated"
+// @Param        from             query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        to               query     string  false  "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param        Accept-Language  header    string  false  "vi or en"
+// @Success      200    {object}  utils.APIResponse{data=utils.CursorResponse{data=[]models.ActivityResponse}}
+// @Failure      400    {object}  utils.APIResponse
+// @Failure      401    {object}  utils.APIResponse
+// @Failure      500    {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /activities/me/own [get]
+func (h *ActivityHandler) GetOwnActivities(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	filter, page, ok := parseActivityQuery(c)
+	if !ok {
+		return
+	}
+
+	activities, next, err := h.activityService.GetOwnActivities(c.Request.Context(), user.ID, filter, page, i18n.ForRequest(c.Request, user))
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to get activities: "+err.Error())
+		return
+	}
+
+	utils.RespondCursor(c, http.StatusOK, "Own activities", activities, next)
+}
+
+// parseActivityQuery reads the page and the type, actor and date range
+// filters, responding 400 if any is invalid
+func parseActivityQuery(c *gin.Context) (repository.ActivityFilter, utils.CursorPage, bool) {
+	var filter repository.ActivityFilter
+
+	page, err := utils.ParseCursorPage(c)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return filter, page, false
+	}
+
+	if types := c.Query("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			actType := models.ActivityType(strings.TrimSpace(t))
+			if !isActivityType(actType) {
+				utils.RespondBadRequest(c, "invalid type filter: "+t)
+				return filter, page, false
+			}
+			filter.Types = append(filter.Types, actType)
+		}
+	}
+
+	if actor := c.Query("actor"); actor != "" {
+		actorID, err := primitive.ObjectIDFromHex(actor)
+		if err != nil {
+			utils.RespondBadRequest(c, "invalid actor ID")
+			return filter, page, false
+		}
+		filter.ActorID = actorID
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			utils.RespondBadRequest(c, "invalid from date")
+			return filter, page, false
+		}
+		filter.From = &t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			utils.RespondBadRequest(c, "invalid to date")
+			return filter, page, false
+		}
+		// A plain date means "up to the end of that day"
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		filter.To = &t
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		utils.RespondBadRequest(c, "from date must be before to date")
+		return filter, page, false
+	}
+
+	return filter, page, true
+}
+
+func isActivityType(t models.ActivityType) bool {
+	switch t {
+	case models.ActivityBillCreated, models.ActivityBillUpdated, models.ActivityBillDeleted,
+		models.ActivityReceiptConfirmed, models.ActivityMemberJoined, models.ActivityMemberLeft,
+		models.ActivityPaymentSent, models.ActivityPaymentConfirmed, models.ActivityPaymentRejected,
+		models.ActivityGroupCreated, models.ActivitySettlementCreated:
+		return true
+	}
+	return false
+}
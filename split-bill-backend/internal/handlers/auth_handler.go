@@ -1,103 +1,167 @@
-package handlers
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/services"
-	"github.com/splitbill/backend/internal/utils"
-)
-
-type AuthHandler struct {
-	authService *services.AuthService
-}
-
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
-}
-
-// VerifyToken godoc
-// @Summary      Verify Firebase token
-// @Description  Verifies Firebase ID token and returns or creates the user
-// @Tags         Auth
-// @Accept       json
-// @Produce      json
-// @Success      200  {object}  utils.APIResponse{data=models.UserResponse}
-// @Failure      401  {object}  utils.APIResponse
-// @Failure      500  {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /auth/verify-token [post]
-func (h *AuthHandler) VerifyToken(c *gin.Context) {
-	firebaseUID, _ := c.Get("firebase_uid")
-	phone, _ := c.Get("user_phone")
-
-	uid := firebaseUID.(string)
-	phoneStr := ""
-	if phone != nil {
-		phoneStr = phone.(string)
-	}
-
-	user, err := h.authService.VerifyAndGetUser(c.Request.Context(), uid, phoneStr)
-	if err != nil {
-		utils.RespondInternalError(c, "Failed to verify user: "+err.Error())
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "User verified", user.ToResponse())
-}
-
-// GetMe godoc
-// @Summary      Get current user profile
-// @Description  Returns the authenticated user's profile information
-// @Tags         Auth
-// @Produce      json
-// @Success      200  {object}  utils.APIResponse{data=models.UserResponse}
-// @Failure      401  {object}  utils.APIResponse
-// @Failure      404  {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /auth/me [get]
-func (h *AuthHandler) GetMe(c *gin.Context) {
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	user, err := h.authService.GetUserByFirebaseUID(c.Request.Context(), uid)
-	if err != nil {
-		utils.RespondNotFound(c, "User not found")
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "User profile", user.ToResponse())
-}
-
-// UpdateProfile godoc
-// @Summary      Update user profile
-// @Description  Updates the authenticated user's profile (display name, avatar, bank info)
-// @Tags         Auth
-// @Accept       json
-// @Produce      json
-// @Param        request  body      models.UpdateUserRequest  true  "Profile update data"
-// @Success      200      {object}  utils.APIResponse{data=models.UserResponse}
-// @Failure      400      {object}  utils.APIResponse
-// @Failure      401      {object}  utils.APIResponse
-// @Failure      500      {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /auth/profile [put]
-func (h *AuthHandler) UpdateProfile(c *gin.Context) {
-	var req models.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
-
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	user, err := h.authService.UpdateProfile(c.Request.Context(), uid, req)
-	if err != nil {
-		utils.RespondInternalError(c, "Failed to update profile: "+err.Error())
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Profile updated", user.ToResponse())
-}
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AuthHandler struct {
+	authService *services.AuthService
+}
+
+func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// VerifyToken godoc
+// @Summary      Verify Firebase token
+// @Description  Verifies Firebase ID token and returns or creates the user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  utils.APIResponse{data=models.UserResponse}
+// @Failure      401  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /auth/verify-token [post]
+func (h *AuthHandler) VerifyToken(c *gin.Context) {
+	firebaseUID, _ := c.Get("firebase_uid")
+	phone, _ := c.Get("user_phone")
+
+	uid := firebaseUID.(string)
+	phoneStr := ""
+	if phone != nil {
+		phoneStr = phone.(string)
+	}
+
+	user, err := h.authService.VerifyAndGetUser(c.Request.Context(), uid, phoneStr)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to verify user: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "User verified", user.ToResponse())
+}
+
+// GetMe godoc
+// @Summary      Get current user profile
+// @Description  Returns the authenticated user's profile information
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  utils.APIResponse{data=models.UserResponse}
+// @Failure      401  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /auth/me [get]
+func (h *AuthHandler) GetMe(c *gin.Context) {
+	firebaseUID, _ := c.Get("firebase_uid")
+	uid := firebaseUID.(string)
+
+	user, err := h.authService.GetUserByFirebaseUID(c.Request.Context(), uid)
+	if err != nil {
+		utils.RespondNotFound(c, "User not found")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "User profile", user.ToResponse())
+}
+
+// UpdateProfile godoc
+// @Summary      Update user profile
+// @Description  Updates the authenticated user's profile (display name, avatar, bank info, notification addresses). Bank accounts are checked against the bank directory and saved under the bank's canonical code. Email, Zalo user ID and webhook URL are where notifications go besides push; send "" to remove one. notification_preferences replaces the saved preferences: channels turned off, per-type and per-group settings, muted groups and quiet hours in the user's timezone.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.UpdateUserRequest  true  "Profile update data"
+// @Success      200      {object}  utils.APIResponse{data=models.UserResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      500      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /auth/profile [put]
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	firebaseUID, _ := c.Get("firebase_uid")
+	uid := firebaseUID.(string)
+
+	user, err := h.authService.UpdateProfile(c.Request.Context(), uid, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBankAccount) || errors.Is(err, services.ErrInvalidNotificationAddress) ||
+			errors.Is(err, services.ErrInvalidNotificationPreferences) {
+			utils.RespondBadRequest(c, err.Error())
+			return
+		}
+		utils.RespondInternalError(c, "Failed to update profile: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Profile updated", user.ToResponse())
+}
+
+// RegisterDevice godoc
+// @Summary      Register a device for push notifications
+// @Description  Saves the device's FCM token. Call it on every app start; registering the same token again refreshes it. A token is moved over if another account used it before.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.RegisterDeviceRequest  true  "Device token"
+// @Success      200      {object}  utils.APIResponse{data=models.Device}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /users/me/devices [post]
+func (h *AuthHandler) RegisterDevice(c *gin.Context) {
+	var req models.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	firebaseUID, _ := c.Get("firebase_uid")
+	device, err := h.authService.RegisterDevice(c.Request.Context(), firebaseUID.(string), req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			utils.RespondUnauthorized(c, "User not found")
+			return
+		}
+		utils.RespondInternalError(c, "Failed to register device: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Device registered", device)
+}
+
+// UnregisterDevice godoc
+// @Summary      Unregister a device
+// @Description  Stops push notifications to a device, e.g. when the user logs out on it
+// @Tags         Auth
+// @Produce      json
+// @Param        token  path      string  true  "FCM token"
+// @Success      200    {object}  utils.APIResponse
+// @Failure      401    {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /users/me/devices/{token} [delete]
+func (h *AuthHandler) UnregisterDevice(c *gin.Context) {
+	firebaseUID, _ := c.Get("firebase_uid")
+	if err := h.authService.UnregisterDevice(c.Request.Context(), firebaseUID.(string), c.Param("token")); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			utils.RespondUnauthorized(c, "User not found")
+			return
+		}
+		utils.RespondInternalError(c, "Failed to unregister device: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Device unregistered", nil)
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+// currentUser loads the user the auth middleware authenticated, responding
+// 401 if they do not exist
+func currentUser(c *gin.Context, userRepo *repository.UserRepository) (*models.User, bool) {
+	user, err := userRepo.FindByFirebaseUID(c.Request.Context(), c.GetString("firebase_uid"))
+	if err != nil {
+		utils.RespondUnauthorized(c, "User not found")
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+type DigestHandler struct {
+	digestService *services.DigestService
+	userRepo      *repository.UserRepository
+}
+
+func NewDigestHandler(digestService *services.DigestService, userRepo *repository.UserRepository) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+		userRepo:      userRepo,
+	}
+}
+
+// PreviewDigest godoc
+// @Summary      Preview group digest
+// @Description  Returns your digest of the group for the last full week or month. Turn digests on with notification_preferences.digest in your profile; they go out at 08:00 your time on Mondays (weekly) or the 1st (monthly).
+// @Tags         Notifications
+// @Produce      json
+// @Param        id         path      string  true   "Group ID"
+// @Param        frequency  query     string  false  "weekly or monthly; defaults to your digest setting, or weekly"
+// @Success      200        {object}  utils.APIResponse{data=models.Digest}
+// @Failure      400        {object}  utils.APIResponse
+// @Failure      403        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/digest [get]
+func (h *DigestHandler) PreviewDigest(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	frequency := models.DigestFrequency(c.Query("frequency"))
+	switch frequency {
+	case "", models.DigestWeekly, models.DigestMonthly:
+	default:
+		utils.RespondBadRequest(c, "frequency must be weekly or monthly")
+		return
+	}
+
+	digest, err := h.digestService.Preview(c.Request.Context(), c.Param("id"), user, frequency)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) {
+			utils.RespondForbidden(c, err.Error())
+			return
+		}
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Digest retrieved", digest)
+}
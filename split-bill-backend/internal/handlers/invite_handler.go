@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+type InviteHandler struct {
+	inviteService *services.InviteService
+	groupService  *services.GroupService
+	userRepo      *repository.UserRepository
+}
+
+func NewInviteHandler(inviteService *services.InviteService, groupService *services.GroupService, userRepo *repository.UserRepository) *InviteHandler {
+	return &InviteHandler{
+		inviteService: inviteService,
+		groupService:  groupService,
+		userRepo:      userRepo,
+	}
+}
+
+// JoinGroup godoc
+// @Summary      Join group via invite code
+// @Description  Joins a group with an invite link's code, taking the link's role, or with the group's own invite code as a member. Expired and used-up links are refused with 410 and different messages. If the group requires approval, a pending join request is returned with 202 instead, and the group's owners and admins are notified. Joining a group you are already in, or asking again, changes nothing. Claim links skip approval and reassign the placeholder's bills, shares and payments to you in one step; they are refused with 409 if you are or were in the group and 410 once claimed.
+// @Tags         Groups
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.JoinGroupRequest  true  "Invite code"
+// @Success      200      {object}  utils.APIResponse{data=models.GroupResponse}
+// @Success      202      {object}  utils.APIResponse{data=models.JoinRequestResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      404      {object}  utils.APIResponse
+// @Failure      410      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/join [post]
+func (h *InviteHandler) JoinGroup(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.JoinGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	group, request, err := h.inviteService.JoinGroup(c.Request.Context(), user, req.InviteCode)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+	if request != nil {
+		utils.RespondSuccess(c, http.StatusAccepted, "Join request sent for approval", h.inviteService.JoinRequestResponse(c.Request.Context(), request))
+		return
+	}
+
+	resp, _ := h.groupService.GetGroupWithMemberDetails(c.Request.Context(), group)
+	utils.RespondSuccess(c, http.StatusOK, "Joined group", resp)
+}
+
+// RegenerateInviteCode godoc
+// @Summary      Regenerate the group invite code
+// @Description  Replaces the group's invite code so the old one stops working. Invite links keep working. Only group owners and admins can do this.
+// @Tags         Invites
+// @Produce      json
+// @Param        id   path      string  true  "Group ID"
+// @Success      200  {object}  utils.APIResponse{data=models.InviteCodeResponse}
+// @Failure      403  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/invite-code/regenerate [post]
+func (h *InviteHandler) RegenerateInviteCode(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	code, err := h.inviteService.RegenerateInviteCode(c.Request.Context(), c.Param("id"), user)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invite code regenerated", code)
+}
+
+// ListInviteLinks godoc
+// @Summary      List group invite links
+// @Description  Returns the group's invite links, newest first, including expired, used-up and revoked ones, with the members who joined through each
+// @Tags         Invites
+// @Produce      json
+// @Param        id   path      string  true  "Group ID"
+// @Success      200  {object}  utils.APIResponse{data=[]models.InviteLinkResponse}
+// @Failure      403  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/invites [get]
+func (h *InviteHandler) ListInviteLinks(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	links, err := h.inviteService.ListInviteLinks(c.Request.Context(), c.Param("id"), user)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invite links retrieved", links)
+}
+
+// CreateInviteLink godoc
+// @Summary      Create a group invite link
+// @Description  Creates an invite link with its own code. It can expire after a number of hours, allow a limited number of joins and give a role other than member (viewer, or admin if you are an owner or admin). With a placeholder_id it is a single-use claim link: whoever uses it takes the placeholder member's place, with their bills, shares and payments.
+// @Tags         Invites
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                          true  "Group ID"
+// @Param        request  body      models.CreateInviteLinkRequest  true  "Role, use limit and expiry"
+// @Success      201      {object}  utils.APIResponse{data=models.InviteLink}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/invites [post]
+func (h *InviteHandler) CreateInviteLink(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	link, err := h.inviteService.CreateInviteLink(c.Request.Context(), c.Param("id"), user, req)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Invite link created", link)
+}
+
+// RevokeInviteLink godoc
+// @Summary      Revoke a group invite link
+// @Description  Stops an invite link from working. Members who joined through it stay. The link's creator, group owners and admins can revoke it.
+// @Tags         Invites
+// @Produce      json
+// @Param        id        path      string  true  "Group ID"
+// @Param        inviteId  path      string  true  "Invite link ID"
+// @Success      200       {object}  utils.APIResponse
+// @Failure      403       {object}  utils.APIResponse
+// @Failure      404       {object}  utils.APIResponse
+// @Failure      409       {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/invites/{inviteId} [delete]
+func (h *InviteHandler) RevokeInviteLink(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	if err := h.inviteService.RevokeInviteLink(c.Request.Context(), c.Param("id"), c.Param("inviteId"), user); err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Invite link revoked", nil)
+}
+
+// ListMyJoinRequests godoc
+// @Summary      List my join requests
+// @Description  Returns your requests to join groups that require approval, newest first, with their status
+// @Tags         Invites
+// @Produce      json
+// @Success      200  {object}  utils.APIResponse{data=[]models.JoinRequestResponse}
+// @Failure      401  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/join-requests [get]
+func (h *InviteHandler) ListMyJoinRequests(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	requests, err := h.inviteService.ListMyJoinRequests(c.Request.Context(), user)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Join requests retrieved", requests)
+}
+
+// ListJoinRequests godoc
+// @Summary      List a group's join requests
+// @Description  Returns the group's join requests with a status, oldest first. Only group owners and admins can see them.
+// @Tags         Invites
+// @Produce      json
+// @Param        id      path      string  true   "Group ID"
+// @Param        status  query     string  false  "pending (default), approved or denied"
+// @Success      200     {object}  utils.APIResponse{data=[]models.JoinRequestResponse}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      403     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/join-requests [get]
+func (h *InviteHandler) ListJoinRequests(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	status := models.JoinRequestStatus(c.Query("status"))
+	requests, err := h.inviteService.ListJoinRequests(c.Request.Context(), c.Param("id"), user, status)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Join requests retrieved", requests)
+}
+
+// ApproveJoinRequest godoc
+// @Summary      Approve a join request
+// @Description  Adds the applicant to the group with the role of the invite link they used, and tells them. Only group owners and admins can approve.
+// @Tags         Invites
+// @Produce      json
+// @Param        id         path      string  true  "Group ID"
+// @Param        requestId  path      string  true  "Join request ID"
+// @Success      200        {object}  utils.APIResponse{data=models.JoinRequest}
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Failure      409        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/join-requests/{requestId}/approve [post]
+func (h *InviteHandler) ApproveJoinRequest(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	request, err := h.inviteService.ApproveJoinRequest(c.Request.Context(), c.Param("id"), c.Param("requestId"), user)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Join request approved", request)
+}
+
+// DenyJoinRequest godoc
+// @Summary      Deny a join request
+// @Description  Turns the request down and tells the applicant. Only group owners and admins can deny.
+// @Tags         Invites
+// @Produce      json
+// @Param        id         path      string  true  "Group ID"
+// @Param        requestId  path      string  true  "Join request ID"
+// @Success      200        {object}  utils.APIResponse{data=models.JoinRequest}
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Failure      409        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/join-requests/{requestId}/deny [post]
+func (h *InviteHandler) DenyJoinRequest(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	request, err := h.inviteService.DenyJoinRequest(c.Request.Context(), c.Param("id"), c.Param("requestId"), user)
+	if err != nil {
+		respondInviteError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Join request denied", request)
+}
+
+func respondInviteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrGroupForbidden),
+		errors.Is(err, services.ErrInviteAdminRole), errors.Is(err, services.ErrInviteRevokeDenied):
+		utils.RespondForbidden(c, err.Error())
+	case errors.Is(err, services.ErrInvalidInviteCode), errors.Is(err, services.ErrInviteNotFound),
+		errors.Is(err, services.ErrJoinRequestNotFound), errors.Is(err, services.ErrPlaceholderNotFound):
+		utils.RespondNotFound(c, err.Error())
+	case errors.Is(err, services.ErrInviteExpired), errors.Is(err, services.ErrInviteUsedUp),
+		errors.Is(err, services.ErrPlaceholderClaimed):
+		utils.RespondError(c, http.StatusGone, err.Error())
+	case errors.Is(err, services.ErrInviteRevoked), errors.Is(err, services.ErrJoinRequestDecided),
+		errors.Is(err, services.ErrClaimAlreadyMember), errors.Is(err, services.ErrClaimHasHistory):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utils.RespondInternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/i18n"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+type NotificationHandler struct {
+	notifService *services.NotificationService
+	userRepo     *repository.UserRepository
+}
+
+func NewNotificationHandler(notifService *services.NotificationService, userRepo *repository.UserRepository) *NotificationHandler {
+	return &NotificationHandler{
+		notifService: notifService,
+		userRepo:     userRepo,
+	}
+}
+
+// ListNotifications godoc
+// @Summary      List notifications
+// @Description  Returns the user's notification inbox, newest first, in the user's language (profile setting, else Accept-Language). Pass next_cursor from the previous page as cursor to get the next one; notifications arriving in between do not shift the pages.
+// @Tags         Notifications
+// @Produce      json
+// @Param        cursor           query     string  false  "Cursor from the previous page"
+// @Param        limit            query     int     false  "Items per page (default 20, max 100)"
+// @Param        unread           query     bool    false  "Only unread notifications"
+// @Param        Accept-Language  header    string  false  "vi or en"
+// @Success      200     {object}  utils.APIResponse{data=utils.CursorResponse{data=[]models.Notification}}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      401     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	page, err := utils.ParseCursorPage(c)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	notifications, next, err := h.notifService.ListNotifications(c.Request.Context(), user, c.Query("unread") == "true", page)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to list notifications: "+err.Error())
+		return
+	}
+
+	lang := i18n.ForRequest(c.Request, user)
+	for i := range notifications {
+		i18n.RenderNotification(lang, &notifications[i])
+	}
+
+	utils.RespondCursor(c, http.StatusOK, "Notifications retrieved", notifications, next)
+}
+
+// GetUnreadCount godoc
+// @Summary      Count unread notifications
+// @Description  Returns the number shown on the notification badge
+// @Tags         Notifications
+// @Produce      json
+// @Success      200  {object}  utils.APIResponse{data=models.UnreadCountResponse}
+// @Failure      401  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	count, err := h.notifService.UnreadCount(c.Request.Context(), user)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to count notifications: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Unread count", models.UnreadCountResponse{Unread: count})
+}
+
+// MarkRead godoc
+// @Summary      Mark a notification read
+// @Tags         Notifications
+// @Produce      json
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  utils.APIResponse{data=models.Notification}
+// @Failure      401  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	notification, err := h.notifService.MarkRead(c.Request.Context(), user, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			utils.RespondNotFound(c, err.Error())
+			return
+		}
+		utils.RespondInternalError(c, "Failed to mark notification read: "+err.Error())
+		return
+	}
+	i18n.RenderNotification(i18n.ForRequest(c.Request, user), notification)
+
+	utils.RespondSuccess(c, http.StatusOK, "Notification marked read", notification)
+}
+
+// MarkAllRead godoc
+// @Summary      Mark all notifications read
+// @Tags         Notifications
+// @Produce      json
+// @Success      200  {object}  utils.APIResponse{data=models.MarkAllReadResponse}
+// @Failure      401  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	updated, err := h.notifService.MarkAllRead(c.Request.Context(), user)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to mark notifications read: "+err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "All notifications marked read", models.MarkAllReadResponse{Updated: updated})
+}
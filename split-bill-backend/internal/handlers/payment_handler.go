@@ -1,236 +1,473 @@
-package handlers
-
-import (
-	"fmt"
-	"net/http"
-	"net/url"
-
-	"github.com/gin-gonic/gin"
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-	"github.com/splitbill/backend/internal/utils"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-type PaymentHandler struct {
-	userRepo *repository.UserRepository
-}
-
-func NewPaymentHandler(userRepo *repository.UserRepository) *PaymentHandler {
-	return &PaymentHandler{
-		userRepo: userRepo,
-	}
-}
-
-// GenerateDeeplink godoc
-// @Summary      Generate payment deeplinks
-// @Description  Generates banking app deeplinks (Momo, ZaloPay, VNPay, etc.) and VietQR URL for payment
-// @Tags         Payment
-// @Accept       json
-// @Produce      json
-// @Param        request  body      models.PaymentDeeplinkRequest  true  "Payment details"
-// @Success      200      {object}  utils.APIResponse{data=models.PaymentDeeplinkResponse}
-// @Failure      400      {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /payment/deeplink [post]
-func (h *PaymentHandler) GenerateDeeplink(c *gin.Context) {
-	var req models.PaymentDeeplinkRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
-
-	note := req.Note
-	if note == "" {
-		note = "Split Bill Payment"
-	}
-
-	encodedNote := url.QueryEscape(note)
-	amountStr := fmt.Sprintf("%.0f", req.Amount)
-
-	// Generate deeplinks for Vietnamese banking apps
-	deeplinks := []models.BankingDeeplink{
-		{
-			AppName:  "Momo",
-			Scheme:   fmt.Sprintf("momo://transfer?phone=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#A6327F",
-			IconName: "wallet",
-		},
-		{
-			AppName:  "ZaloPay",
-			Scheme:   fmt.Sprintf("zalopay://transfer?phone=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#008FE5",
-			IconName: "wallet",
-		},
-		{
-			AppName:  "VNPay QR",
-			Scheme:   fmt.Sprintf("vnpayqr://pay?amount=%s&desc=%s", amountStr, encodedNote),
-			Color:    "#1A3C7B",
-			IconName: "credit-card",
-		},
-		{
-			AppName:  "Vietcombank",
-			Scheme:   fmt.Sprintf("vcbdigibank://transfer?account=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#00573F",
-			IconName: "bank",
-		},
-		{
-			AppName:  "Techcombank",
-			Scheme:   fmt.Sprintf("techcombank://transfer?account=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#E31937",
-			IconName: "bank",
-		},
-		{
-			AppName:  "VPBank",
-			Scheme:   fmt.Sprintf("vpbank://transfer?account=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#00A650",
-			IconName: "bank",
-		},
-		{
-			AppName:  "MB Bank",
-			Scheme:   fmt.Sprintf("mbbank://transfer?account=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#005BAA",
-			IconName: "bank",
-		},
-		{
-			AppName:  "ACB",
-			Scheme:   fmt.Sprintf("acb://transfer?account=%s&amount=%s&note=%s", req.AccountNumber, amountStr, encodedNote),
-			Color:    "#1C4587",
-			IconName: "bank",
-		},
-	}
-
-	// Generate VietQR URL using vietqr.io API
-	vietQRURL := generateVietQRURL(req.BankCode, req.AccountNumber, req.AccountName, req.Amount, note)
-
-	response := models.PaymentDeeplinkResponse{
-		Amount:    req.Amount,
-		Note:      note,
-		Deeplinks: deeplinks,
-		VietQRURL: vietQRURL,
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Payment deeplinks generated", response)
-}
-
-// GenerateVietQR godoc
-// @Summary      Generate VietQR code
-// @Description  Generates a VietQR code image URL for bank transfer payment
-// @Tags         Payment
-// @Accept       json
-// @Produce      json
-// @Param        request  body      models.VietQRRequest  true  "QR code details"
-// @Success      200      {object}  utils.APIResponse
-// @Failure      400      {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /payment/vietqr [post]
-func (h *PaymentHandler) GenerateVietQR(c *gin.Context) {
-	var req models.VietQRRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
-
-	template := req.Template
-	if template == "" {
-		template = "compact2"
-	}
-
-	description := req.Description
-	if description == "" {
-		description = "Split Bill Payment"
-	}
-
-	qrURL := fmt.Sprintf(
-		"https://img.vietqr.io/image/%s-%s-%s.png?amount=%.0f&addInfo=%s&accountName=%s",
-		req.BankID,
-		req.AccountNumber,
-		template,
-		req.Amount,
-		url.QueryEscape(description),
-		url.QueryEscape(req.AccountName),
-	)
-
-	utils.RespondSuccess(c, http.StatusOK, "VietQR generated", gin.H{
-		"qr_url":         qrURL,
-		"bank_id":        req.BankID,
-		"account_number": req.AccountNumber,
-		"account_name":   req.AccountName,
-		"amount":         req.Amount,
-		"description":    description,
-	})
-}
-
-// GetUserPaymentInfo godoc
-// @Summary      Get user payment info
-// @Description  Returns payment information (bank accounts, preferred payment method) for a user
-// @Tags         Payment
-// @Produce      json
-// @Param        userId  path      string  true  "User ID"
-// @Success      200     {object}  utils.APIResponse
-// @Failure      400     {object}  utils.APIResponse
-// @Failure      404     {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /payment/user/{userId} [get]
-func (h *PaymentHandler) GetUserPaymentInfo(c *gin.Context) {
-	userIDStr := c.Param("userId")
-	userID, err := primitive.ObjectIDFromHex(userIDStr)
-	if err != nil {
-		utils.RespondBadRequest(c, "Invalid user ID")
-		return
-	}
-
-	user, err := h.userRepo.FindByID(c.Request.Context(), userID)
-	if err != nil {
-		utils.RespondNotFound(c, "User not found")
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "User payment info", gin.H{
-		"user_id":           user.ID.Hex(),
-		"display_name":      user.DisplayName,
-		"bank_accounts":     user.BankAccounts,
-		"preferred_payment": user.PreferredPayment,
-	})
-}
-
-// GetSupportedBanks godoc
-// @Summary      Get supported banks
-// @Description  Returns the list of supported Vietnamese banks with IDs, names, codes, logos, and colors
-// @Tags         Payment
-// @Produce      json
-// @Success      200 {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /payment/banks [get]
-func (h *PaymentHandler) GetSupportedBanks(c *gin.Context) {
-	banks := []gin.H{
-		{"id": "VCB", "name": "Vietcombank", "code": "970436", "short_name": "VCB", "logo": "https://img.vietqr.io/img/VCB.png", "color": "#00573F"},
-		{"id": "TCB", "name": "Techcombank", "code": "970407", "short_name": "TCB", "logo": "https://img.vietqr.io/img/TCB.png", "color": "#E31937"},
-		{"id": "VPB", "name": "VPBank", "code": "970432", "short_name": "VPB", "logo": "https://img.vietqr.io/img/VPB.png", "color": "#00A650"},
-		{"id": "MB", "name": "MB Bank", "code": "970422", "short_name": "MB", "logo": "https://img.vietqr.io/img/MB.png", "color": "#005BAA"},
-		{"id": "ACB", "name": "ACB", "code": "970416", "short_name": "ACB", "logo": "https://img.vietqr.io/img/ACB.png", "color": "#1C4587"},
-		{"id": "TPB", "name": "TPBank", "code": "970423", "short_name": "TPB", "logo": "https://img.vietqr.io/img/TPB.png", "color": "#6C2D8E"},
-		{"id": "STB", "name": "Sacombank", "code": "970403", "short_name": "STB", "logo": "https://img.vietqr.io/img/STB.png", "color": "#0051A5"},
-		{"id": "BIDV", "name": "BIDV", "code": "970418", "short_name": "BIDV", "logo": "https://img.vietqr.io/img/BIDV.png", "color": "#1B3A6B"},
-		{"id": "VIB", "name": "VIB", "code": "970441", "short_name": "VIB", "logo": "https://img.vietqr.io/img/VIB.png", "color": "#1E3A8A"},
-		{"id": "SHB", "name": "SHB", "code": "970443", "short_name": "SHB", "logo": "https://img.vietqr.io/img/SHB.png", "color": "#1D428A"},
-		{"id": "CTG", "name": "VietinBank", "code": "970415", "short_name": "CTG", "logo": "https://img.vietqr.io/img/CTG.png", "color": "#004F9F"},
-		{"id": "HDB", "name": "HDBank", "code": "970437", "short_name": "HDB", "logo": "https://img.vietqr.io/img/HDB.png", "color": "#E6332A"},
-		{"id": "MSB", "name": "MSB", "code": "970426", "short_name": "MSB", "logo": "https://img.vietqr.io/img/MSB.png", "color": "#1A6DB0"},
-		{"id": "EIB", "name": "Eximbank", "code": "970431", "short_name": "EIB", "logo": "https://img.vietqr.io/img/EIB.png", "color": "#0057A0"},
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Supported banks", banks)
-}
-
-// generateVietQRURL creates a VietQR image URL
-func generateVietQRURL(bankCode, accountNumber, accountName string, amount float64, description string) string {
-	return fmt.Sprintf(
-		"https://img.vietqr.io/image/%s-%s-compact2.png?amount=%.0f&addInfo=%s&accountName=%s",
-		bankCode,
-		accountNumber,
-		amount,
-		url.QueryEscape(description),
-		url.QueryEscape(accountName),
-	)
-}
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+	"github.com/splitbill/backend/pkg/banks"
+	"github.com/splitbill/backend/pkg/vietqr"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// bankLogoURL is the VietQR CDN path for a bank's logo key
+	bankLogoURL = "https://img.vietqr.io/img/%s.png"
+
+	// defaultBankColor is used for banks without a brand color in the registry
+	defaultBankColor = "#1A3C7B"
+)
+
+type PaymentHandler struct {
+	userRepo          *repository.UserRepository
+	referenceService  *services.PaymentReferenceService
+	settlementService *services.SettlementPaymentService
+	baseURL           string
+}
+
+func NewPaymentHandler(
+	userRepo *repository.UserRepository,
+	referenceService *services.PaymentReferenceService,
+	settlementService *services.SettlementPaymentService,
+	baseURL string,
+) *PaymentHandler {
+	return &PaymentHandler{
+		userRepo:          userRepo,
+		referenceService:  referenceService,
+		settlementService: settlementService,
+		baseURL:           baseURL,
+	}
+}
+
+// GenerateDeeplink godoc
+// @Summary      Generate payment deeplinks
+// @Description  Generates banking app deeplinks (Momo, ZaloPay, VNPay, etc.) and VietQR URL for payment. A payment reference, if given, is put at the start of the transfer note.
+// @Tags         Payment
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.PaymentDeeplinkRequest  true  "Payment details"
+// @Success      200      {object}  utils.APIResponse{data=models.PaymentDeeplinkResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /payment/deeplink [post]
+func (h *PaymentHandler) GenerateDeeplink(c *gin.Context) {
+	var req models.PaymentDeeplinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	reference, ok := paymentReference(c, req.Reference)
+	if !ok {
+		return
+	}
+
+	note := req.Note
+	if note == "" {
+		note = "Split Bill Payment"
+	}
+	note = utils.WithPaymentReference(note, reference)
+
+	// The request has a single account field, used for wallets and banks alike
+	deeplinks := paymentDeeplinks(req.AccountNumber, req.BankCode, req.AccountNumber, req.Amount, note)
+
+	response := models.PaymentDeeplinkResponse{
+		Amount:    req.Amount,
+		Note:      note,
+		Reference: reference,
+		Deeplinks: deeplinks,
+	}
+
+	// The QR is only available for banks we know the NAPAS BIN of
+	if payload, err := buildVietQRPayload(req.BankCode, req.AccountNumber, req.Amount, note); err == nil {
+		response.VietQRPayload = payload
+		response.VietQRURL = h.qrImageURL(payload, "png")
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Payment deeplinks generated", response)
+}
+
+// GenerateVietQR godoc
+// @Summary      Generate VietQR code
+// @Description  Builds a NAPAS VietQR payload for a bank transfer and returns it with PNG and SVG image URLs served by this API. A payment reference, if given, is put at the start of the transfer note. The template field is ignored.
+// @Tags         Payment
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.VietQRRequest  true  "QR code details"
+// @Success      200      {object}  utils.APIResponse
+// @Failure      400      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /payment/vietqr [post]
+func (h *PaymentHandler) GenerateVietQR(c *gin.Context) {
+	var req models.VietQRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	reference, ok := paymentReference(c, req.Reference)
+	if !ok {
+		return
+	}
+
+	description := req.Description
+	if description == "" {
+		description = "Split Bill Payment"
+	}
+	description = utils.WithPaymentReference(description, reference)
+
+	payload, err := buildVietQRPayload(req.BankID, req.AccountNumber, req.Amount, description)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "VietQR generated", gin.H{
+		"payload":        payload,
+		"qr_url":         h.qrImageURL(payload, "png"),
+		"qr_svg_url":     h.qrImageURL(payload, "svg"),
+		"bank_id":        req.BankID,
+		"account_number": req.AccountNumber,
+		"account_name":   req.AccountName,
+		"amount":         req.Amount,
+		"description":    description,
+		"reference":      reference,
+	})
+}
+
+// PaySettlement godoc
+// @Summary      Pay a settle-up item
+// @Description  Returns everything needed to pay what you owe a member: their bank accounts and preferred payment method, the amount from the settle-up plan, banking app deeplinks, a VietQR payload and a pending transaction draft to submit to POST /transactions once the transfer is made. Only members the plan tells to pay this person can see their account details.
+// @Tags         Payment
+// @Produce      json
+// @Param        id        path      string  true  "Group ID"
+// @Param        toUserId  path      string  true  "User ID of the member being paid"
+// @Success      200       {object}  utils.APIResponse{data=models.SettlementPaymentResponse}
+// @Failure      400       {object}  utils.APIResponse
+// @Failure      401       {object}  utils.APIResponse
+// @Failure      403       {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/settlements/{toUserId}/pay [get]
+func (h *PaymentHandler) PaySettlement(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	payment, err := h.settlementService.PrepareSettlementPayment(c.Request.Context(), c.Param("id"), user, c.Param("toUserId"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrNoDebtToMember):
+			utils.RespondForbidden(c, err.Error())
+		default:
+			utils.RespondBadRequest(c, err.Error())
+		}
+		return
+	}
+
+	settlement := payment.Settlement
+	payee := payment.Payee
+	note := utils.WithPaymentReference("Split Bill - "+payment.Group.Name, settlement.Reference)
+
+	response := models.SettlementPaymentResponse{
+		GroupID: payment.Group.ID.Hex(),
+		Payee: models.PaymentPayee{
+			UserID:           payee.ID.Hex(),
+			DisplayName:      payee.DisplayName,
+			AvatarURL:        payee.AvatarURL,
+			BankAccounts:     payee.BankAccounts,
+			PreferredPayment: payee.PreferredPayment,
+		},
+		Amount:    settlement.Amount,
+		Currency:  "VND",
+		Reference: settlement.Reference,
+		Note:      note,
+		TransactionDraft: models.CreateTransactionRequest{
+			GroupID:       payment.Group.ID.Hex(),
+			ToUser:        payee.ID.Hex(),
+			Amount:        settlement.Amount,
+			Currency:      "VND",
+			PaymentMethod: "bank_transfer",
+			Note:          note,
+			Reference:     settlement.Reference,
+		},
+	}
+
+	account := preferredBankAccount(payee)
+	bankCode, accountNumber := "", ""
+	if account != nil {
+		bankCode, accountNumber = account.BankCode, account.AccountNumber
+		response.BankAccount = account
+		if payload, err := buildVietQRPayload(account.BankCode, account.AccountNumber, settlement.Amount, note); err == nil {
+			response.VietQRPayload = payload
+			response.VietQRURL = h.qrImageURL(payload, "png")
+		}
+	}
+	response.Deeplinks = paymentDeeplinks(payee.Phone, bankCode, accountNumber, settlement.Amount, note)
+
+	utils.RespondSuccess(c, http.StatusOK, "Settlement payment", response)
+}
+
+// GetUserPaymentInfo godoc
+// @Summary      Get user payment info
+// @Description  Returns payment information (bank accounts, preferred payment method) for a user. Only users who share a group with them can see it.
+// @Tags         Payment
+// @Produce      json
+// @Param        userId  path      string  true  "User ID"
+// @Success      200     {object}  utils.APIResponse
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      403     {object}  utils.APIResponse
+// @Failure      404     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /payment/user/{userId} [get]
+func (h *PaymentHandler) GetUserPaymentInfo(c *gin.Context) {
+	userIDStr := c.Param("userId")
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		utils.RespondBadRequest(c, "Invalid user ID")
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID)
+	if err != nil {
+		utils.RespondNotFound(c, "User not found")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "User payment info", gin.H{
+		"user_id":           user.ID.Hex(),
+		"display_name":      user.DisplayName,
+		"bank_accounts":     user.BankAccounts,
+		"preferred_payment": user.PreferredPayment,
+	})
+}
+
+// GetSupportedBanks godoc
+// @Summary      Get supported banks
+// @Description  Returns the NAPAS bank directory: code (as id and short_name), NAPAS BIN (as code), names, logo, color, app deeplink scheme and account number rules
+// @Tags         Payment
+// @Produce      json
+// @Success      200 {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /payment/banks [get]
+func (h *PaymentHandler) GetSupportedBanks(c *gin.Context) {
+	all := banks.Default().All()
+	list := make([]gin.H, 0, len(all))
+	for _, bank := range all {
+		list = append(list, gin.H{
+			"id":              bank.Code,
+			"name":            bank.ShortName,
+			"code":            bank.BIN,
+			"short_name":      bank.Code,
+			"full_name":       bank.Name,
+			"aliases":         bank.Aliases,
+			"logo":            fmt.Sprintf(bankLogoURL, bank.LogoKey),
+			"logo_key":        bank.LogoKey,
+			"color":           bankColor(bank),
+			"deeplink_scheme": bank.DeeplinkScheme,
+			"account_rules":   bank.Account,
+		})
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Supported banks", list)
+}
+
+// LookupReference godoc
+// @Summary      Look up a payment reference
+// @Description  Finds the pending transaction or settle-up item a payment reference (e.g. SB7K2Q9M) belongs to. Only members of its group can look it up.
+// @Tags         Payment
+// @Produce      json
+// @Param        code  path      string  true  "Payment reference"
+// @Success      200   {object}  utils.APIResponse{data=models.PaymentReferenceResponse}
+// @Failure      401   {object}  utils.APIResponse
+// @Failure      404   {object}  utils.APIResponse
+// @Failure      500   {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /payment/references/{code} [get]
+func (h *PaymentHandler) LookupReference(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	reference, err := h.referenceService.Lookup(c.Request.Context(), c.Param("code"), user)
+	if err != nil {
+		if errors.Is(err, services.ErrPaymentReferenceNotFound) {
+			utils.RespondNotFound(c, err.Error())
+			return
+		}
+		utils.RespondInternalError(c, "Failed to look up payment reference")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Payment reference", reference)
+}
+
+// paymentReference validates an optional payment reference from a request body
+func paymentReference(c *gin.Context, reference string) (string, bool) {
+	if reference == "" {
+		return "", true
+	}
+	reference = utils.NormalizePaymentReference(reference)
+	if !utils.IsPaymentReference(reference) {
+		utils.RespondBadRequest(c, "Invalid payment reference")
+		return "", false
+	}
+	return reference, true
+}
+
+// RenderQR godoc
+// @Summary      Render a VietQR image
+// @Description  Renders a VietQR payload as a PNG or SVG image. Only valid VietQR payloads (correct checksum) are rendered. Public so image views can load it without a token.
+// @Tags         Payment
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        data    query     string  true   "VietQR payload"
+// @Param        format  query     string  false  "png (default) or svg"
+// @Param        size    query     int     false  "Edge length in pixels (128-1024, default 512)"
+// @Success      200     {file}    binary
+// @Failure      400     {object}  utils.APIResponse
+// @Router       /payment/qr [get]
+func (h *PaymentHandler) RenderQR(c *gin.Context) {
+	payload := c.Query("data")
+	if _, err := vietqr.Decode(payload); err != nil {
+		utils.RespondBadRequest(c, "Invalid VietQR payload")
+		return
+	}
+
+	size := vietqr.DefaultSize
+	if s := c.Query("size"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 128 || parsed > 1024 {
+			utils.RespondBadRequest(c, "Size must be between 128 and 1024")
+			return
+		}
+		size = parsed
+	}
+
+	var (
+		image       []byte
+		contentType string
+		err         error
+	)
+	switch c.DefaultQuery("format", "png") {
+	case "png":
+		image, err = vietqr.PNG(payload, size)
+		contentType = "image/png"
+	case "svg":
+		image, err = vietqr.SVG(payload, size)
+		contentType = "image/svg+xml"
+	default:
+		utils.RespondBadRequest(c, "Format must be png or svg")
+		return
+	}
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to render QR code")
+		return
+	}
+
+	// The image depends only on the query, so clients may cache it
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, contentType, image)
+}
+
+// qrImageURL returns the URL of this server's rendering of a VietQR payload
+func (h *PaymentHandler) qrImageURL(payload, format string) string {
+	return fmt.Sprintf("%s/api/v1/payment/qr?format=%s&data=%s", h.baseURL, format, url.QueryEscape(payload))
+}
+
+// buildVietQRPayload builds the VietQR payload for a transfer to a bank account.
+// bank may be a NAPAS BIN (970436), a bank code (VCB) or any other key the registry knows.
+func buildVietQRPayload(bank, accountNumber string, amount float64, description string) (string, error) {
+	info, ok := banks.Default().Lookup(bank)
+	if !ok {
+		return "", errors.New("unsupported bank: " + bank)
+	}
+
+	payload, err := vietqr.Encode(vietqr.Payload{
+		BankBIN:       info.BIN,
+		AccountNumber: accountNumber,
+		Amount:        amount,
+		Purpose:       description,
+	})
+	if err != nil {
+		return "", err
+	}
+	return payload, nil
+}
+
+// paymentDeeplinks builds links that open wallet and banking apps with the
+// transfer prefilled. Wallets are addressed by phone number, banks by account
+// number; either may be empty to leave those apps out. Bank transfer links
+// carry no BIN, so only the account's own bank gets one; other banks' apps
+// pay by the VietQR payload, which names the bank.
+func paymentDeeplinks(walletPhone, bankCode, accountNumber string, amount float64, note string) []models.BankingDeeplink {
+	encodedNote := url.QueryEscape(note)
+	amountStr := fmt.Sprintf("%.0f", amount)
+
+	deeplinks := []models.BankingDeeplink{}
+	if walletPhone != "" {
+		deeplinks = append(deeplinks,
+			models.BankingDeeplink{
+				AppName:  "Momo",
+				Scheme:   fmt.Sprintf("momo://transfer?phone=%s&amount=%s&note=%s", walletPhone, amountStr, encodedNote),
+				Color:    "#A6327F",
+				IconName: "wallet",
+			},
+			models.BankingDeeplink{
+				AppName:  "ZaloPay",
+				Scheme:   fmt.Sprintf("zalopay://transfer?phone=%s&amount=%s&note=%s", walletPhone, amountStr, encodedNote),
+				Color:    "#008FE5",
+				IconName: "wallet",
+			},
+		)
+	}
+	deeplinks = append(deeplinks, models.BankingDeeplink{
+		AppName:  "VNPay QR",
+		Scheme:   fmt.Sprintf("vnpayqr://pay?amount=%s&desc=%s", amountStr, encodedNote),
+		Color:    "#1A3C7B",
+		IconName: "credit-card",
+	})
+
+	bank, ok := banks.Default().Lookup(bankCode)
+	if accountNumber == "" || !ok || bank.DeeplinkScheme == "" {
+		return deeplinks
+	}
+	return append(deeplinks, models.BankingDeeplink{
+		AppName:  bank.ShortName,
+		Scheme:   fmt.Sprintf("%s://transfer?account=%s&amount=%s&note=%s", bank.DeeplinkScheme, accountNumber, amountStr, encodedNote),
+		Color:    bankColor(bank),
+		IconName: "bank",
+	})
+}
+
+// preferredBankAccount picks the account to pay: the one at the bank named in the
+// user's preferred payment method if there is one, otherwise the first
+func preferredBankAccount(user *models.User) *models.BankAccount {
+	if len(user.BankAccounts) == 0 {
+		return nil
+	}
+	if preferred, ok := banks.Default().Lookup(user.PreferredPayment); ok {
+		for i := range user.BankAccounts {
+			if bank, ok := banks.Default().Lookup(user.BankAccounts[i].BankCode); ok && bank.BIN == preferred.BIN {
+				return &user.BankAccounts[i]
+			}
+		}
+	}
+	return &user.BankAccounts[0]
+}
+
+// bankColor returns the bank's brand color, or a neutral one if it has none
+func bankColor(bank banks.Bank) string {
+	if bank.Color == "" {
+		return defaultBankColor
+	}
+	return bank.Color
+}
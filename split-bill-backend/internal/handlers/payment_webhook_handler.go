@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+	"github.com/splitbill/backend/pkg/bankhook"
+)
+
+// maxWebhookBodySize limits payment webhook bodies to 1MB
+const maxWebhookBodySize = 1 << 20
+
+type PaymentWebhookHandler struct {
+	webhookService *services.PaymentWebhookService
+	secret         string
+}
+
+func NewPaymentWebhookHandler(webhookService *services.PaymentWebhookService, secret string) *PaymentWebhookHandler {
+	return &PaymentWebhookHandler{
+		webhookService: webhookService,
+		secret:         secret,
+	}
+}
+
+// ReceivePayment godoc
+// @Summary      Receive bank transfer notifications
+// @Description  Webhook for payment aggregators (Casso and SePay JSON formats). The body must be signed with HMAC-SHA256 using the configured secret, sent as "sha256=<hex>" in X-Webhook-Signature. Incoming transfers whose note carries a payment reference and whose amount matches a pending transaction confirm it. Notifications already received are ignored.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-Webhook-Signature  header    string  true  "HMAC-SHA256 of the raw body"
+// @Success      200                  {object}  utils.APIResponse{data=models.PaymentWebhookResult}
+// @Failure      400                  {object}  utils.APIResponse
+// @Failure      401                  {object}  utils.APIResponse
+// @Failure      500                  {object}  utils.APIResponse
+// @Failure      503                  {object}  utils.APIResponse
+// @Router       /webhooks/payments [post]
+func (h *PaymentWebhookHandler) ReceivePayment(c *gin.Context) {
+	// Without a secret anyone could confirm payments, so refuse everything
+	if h.secret == "" {
+		utils.RespondError(c, http.StatusServiceUnavailable, "Payment webhook is not configured")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize+1))
+	if err != nil {
+		utils.RespondBadRequest(c, "Failed to read request body")
+		return
+	}
+	if len(body) > maxWebhookBodySize {
+		utils.RespondBadRequest(c, "Request body exceeds 1MB limit")
+		return
+	}
+
+	if err := bankhook.Verify(h.secret, body, c.GetHeader(bankhook.SignatureHeader)); err != nil {
+		utils.RespondUnauthorized(c, "Invalid webhook signature")
+		return
+	}
+
+	transfers, err := bankhook.Parse(body)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.webhookService.HandleTransfers(c.Request.Context(), transfers)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to process payment notification")
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Payment notification received", result)
+}
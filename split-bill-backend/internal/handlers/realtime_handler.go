@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/realtime"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// streamHeartbeat keeps proxies from closing an idle stream and is when
+	// membership is checked again
+	streamHeartbeat = 25 * time.Second
+
+	// streamWriteTimeout replaces the server's write timeout, which would
+	// otherwise end every stream after 30 seconds
+	streamWriteTimeout = 10 * time.Second
+)
+
+type RealtimeHandler struct {
+	hub       *realtime.Hub
+	groupRepo *repository.GroupRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewRealtimeHandler(hub *realtime.Hub, groupRepo *repository.GroupRepository, userRepo *repository.UserRepository) *RealtimeHandler {
+	return &RealtimeHandler{
+		hub:       hub,
+		groupRepo: groupRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// StreamGroupEvents godoc
+// @Summary      Stream group events
+// @Description  Server-sent events for a group: bill.created, bill.updated, bill.deleted, transaction.created, transaction.updated, transaction.confirmed, transaction.rejected, transaction.cancelled, member.joined, member.left and balances.changed. Each event's data is the JSON of the changed bill, transaction or member. To resume after a disconnect, send the last event ID you received as the Last-Event-ID header (or last_event_id query parameter); a resync event means the missed events are gone and the group should be reloaded.
+// @Tags         Groups
+// @Produce      text/event-stream
+// @Param        id             path      string  true   "Group ID"
+// @Param        Last-Event-ID  header    string  false  "ID of the last event received"
+// @Param        last_event_id  query     string  false  "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success      200            {string}  string  "event stream"
+// @Failure      403            {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/events [get]
+func (h *RealtimeHandler) StreamGroupEvents(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.RespondBadRequest(c, "Invalid group ID")
+		return
+	}
+	if isMember, err := h.groupRepo.IsMember(c.Request.Context(), groupID, user.ID); err != nil || !isMember {
+		utils.RespondForbidden(c, "You are not a member of this group")
+		return
+	}
+
+	// Subscribe before replaying so nothing published in between is lost
+	sub := h.hub.Subscribe(groupID.Hex())
+	defer h.hub.Unsubscribe(sub)
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+	var backlog []realtime.Event
+	if lastID != "" {
+		backlog, err = h.hub.Replay(c.Request.Context(), groupID.Hex(), lastID)
+		if err != nil {
+			utils.RespondInternalError(c, "Failed to load missed events")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // stop nginx buffering the stream
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(frame string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if _, err := fmt.Fprint(c.Writer, frame); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write("retry: 3000\n\n") {
+		return
+	}
+	seen := lastID
+	for _, event := range backlog {
+		if !write(sseFrame(event)) {
+			return
+		}
+		if event.ID != "" {
+			seen = event.ID
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Fell behind; the client reconnects and resumes from seen
+				return
+			}
+			if event.ID != "" && seen != "" && realtime.CompareIDs(event.ID, seen) <= 0 {
+				continue // already sent from the backlog
+			}
+			if !write(sseFrame(event)) {
+				return
+			}
+			if event.ID != "" {
+				seen = event.ID
+			}
+
+		case <-heartbeat.C:
+			if isMember, err := h.groupRepo.IsMember(ctx, groupID, user.ID); err == nil && !isMember {
+				return
+			}
+			if !write(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// sseFrame formats an event for the text/event-stream wire format
+func sseFrame(event realtime.Event) string {
+	data := string(event.Data)
+	if data == "" {
+		data = "{}"
+	}
+	frame := ""
+	if event.ID != "" {
+		frame += "id: " + event.ID + "\n"
+	}
+	return frame + "event: " + string(event.Type) + "\ndata: " + data + "\n\n"
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+type ReminderHandler struct {
+	reminderService *services.ReminderService
+	userRepo        *repository.UserRepository
+}
+
+func NewReminderHandler(reminderService *services.ReminderService, userRepo *repository.UserRepository) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+		userRepo:        userRepo,
+	}
+}
+
+// GetSettings godoc
+// @Summary      Get reminder schedule
+// @Description  Returns the group's settlement reminder schedule. Groups start with reminders off.
+// @Tags         Reminders
+// @Produce      json
+// @Param        id   path      string  true  "Group ID"
+// @Success      200  {object}  utils.APIResponse{data=models.ReminderSettings}
+// @Failure      403  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/reminders [get]
+func (h *ReminderHandler) GetSettings(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	settings, err := h.reminderService.GetSettings(c.Request.Context(), c.Param("id"), user)
+	if err != nil {
+		respondReminderError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Reminder settings retrieved", settings)
+}
+
+// UpdateSettings godoc
+// @Summary      Update reminder schedule
+// @Description  Sets when members who owe money are reminded: weekly on a weekday, or a number of days after each bill. Reminders get firmer the more are sent in 30 days. Only group admins can change it.
+// @Tags         Reminders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Group ID"
+// @Param        request  body      models.UpdateReminderSettingsRequest  true  "Reminder schedule"
+// @Success      200      {object}  utils.APIResponse{data=models.ReminderSettings}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/reminders [put]
+func (h *ReminderHandler) UpdateSettings(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateReminderSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.reminderService.UpdateSettings(c.Request.Context(), c.Param("id"), user, req)
+	if err != nil {
+		respondReminderError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Reminder settings updated", settings)
+}
+
+// Snooze godoc
+// @Summary      Snooze reminders
+// @Description  Pauses your scheduled and manual reminders in the group for up to 30 days. Send 0 days to resume them.
+// @Tags         Reminders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Group ID"
+// @Param        request  body      models.SnoozeRemindersRequest  true  "Snooze length"
+// @Success      200      {object}  utils.APIResponse
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/reminders/snooze [put]
+func (h *ReminderHandler) Snooze(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.SnoozeRemindersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	until, err := h.reminderService.Snooze(c.Request.Context(), c.Param("id"), user, req.Days)
+	if err != nil {
+		respondReminderError(c, err)
+		return
+	}
+
+	message := "Reminders snoozed"
+	if until == nil {
+		message = "Reminders resumed"
+	}
+	utils.RespondSuccess(c, http.StatusOK, message, gin.H{"snoozed_until": until})
+}
+
+// RemindMember godoc
+// @Summary      Remind a member
+// @Description  Sends a member a reminder of what they owe in the group. Each member can be reminded this way once a day.
+// @Tags         Reminders
+// @Produce      json
+// @Param        id      path      string  true  "Group ID"
+// @Param        userId  path      string  true  "User ID of the member to remind"
+// @Success      200     {object}  utils.APIResponse{data=models.ReminderDelivery}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      403     {object}  utils.APIResponse
+// @Failure      429     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/members/{userId}/remind [post]
+func (h *ReminderHandler) RemindMember(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.reminderService.RemindMember(c.Request.Context(), c.Param("id"), user, c.Param("userId"))
+	if err != nil {
+		respondReminderError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Reminder sent", delivery)
+}
+
+func respondReminderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrNotGroupAdmin):
+		utils.RespondForbidden(c, err.Error())
+	case errors.Is(err, services.ErrAlreadyRemindedToday):
+		utils.RespondError(c, http.StatusTooManyRequests, err.Error())
+	default:
+		utils.RespondBadRequest(c, err.Error())
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+// maxStatementSize limits uploaded statement files to 5MB
+const maxStatementSize = 5 * 1024 * 1024
+
+type StatementHandler struct {
+	reconciliationService *services.ReconciliationService
+	userRepo              *repository.UserRepository
+}
+
+func NewStatementHandler(reconciliationService *services.ReconciliationService, userRepo *repository.UserRepository) *StatementHandler {
+	return &StatementHandler{
+		reconciliationService: reconciliationService,
+		userRepo:              userRepo,
+	}
+}
+
+// ImportStatement godoc
+// @Summary      Import a bank statement
+// @Description  Uploads a CSV statement export (Vietcombank, Techcombank, MB and similar). Incoming transfers are matched to pending transactions sent to you by payment reference, amount and date; matches are confirmed automatically and the rest go to the review queue.
+// @Tags         Statements
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file      formData  file    true   "CSV statement file (max 5MB)"
+// @Param        bank      formData  string  false  "Bank name, e.g. Vietcombank"
+// @Param        group_id  formData  string  false  "Only reconcile transactions in this group"
+// @Success      201       {object}  utils.APIResponse{data=models.StatementImportResult}
+// @Failure      400       {object}  utils.APIResponse
+// @Failure      401       {object}  utils.APIResponse
+// @Failure      403       {object}  utils.APIResponse
+// @Failure      500       {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /statements/import [post]
+func (h *StatementHandler) ImportStatement(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.RespondBadRequest(c, "No statement file provided")
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxStatementSize {
+		utils.RespondBadRequest(c, "Statement file exceeds 5MB limit")
+		return
+	}
+	if ext := strings.ToLower(filepath.Ext(header.Filename)); ext != ".csv" && ext != ".txt" {
+		utils.RespondBadRequest(c, "Invalid statement file. Export the statement as CSV")
+		return
+	}
+
+	result, err := h.reconciliationService.ImportStatement(
+		c.Request.Context(), user, c.PostForm("group_id"), c.PostForm("bank"), header.Filename, file,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotGroupMember):
+			utils.RespondForbidden(c, err.Error())
+		case errors.Is(err, services.ErrInvalidGroupID), errors.Is(err, utils.ErrStatementHeaderNotFound):
+			utils.RespondBadRequest(c, err.Error())
+		default:
+			utils.RespondInternalError(c, "Failed to import statement")
+		}
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Statement imported", result)
+}
+
+// ListImports godoc
+// @Summary      List statement imports
+// @Description  Returns the authenticated user's bank statement imports, newest first
+// @Tags         Statements
+// @Produce      json
+// @Param        page   query     int  false  "Page number (default 1)"
+// @Param        limit  query     int  false  "Items per page (default 20, max 100)"
+// @Success      200    {object}  utils.APIResponse{data=utils.PaginatedResponse{data=[]models.StatementImport}}
+// @Failure      401    {object}  utils.APIResponse
+// @Failure      500    {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /statements/imports [get]
+func (h *StatementHandler) ListImports(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	pagination := utils.ParsePagination(c)
+	imports, err := h.reconciliationService.ListImports(c.Request.Context(), user, &pagination)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to list statement imports")
+		return
+	}
+
+	utils.RespondPaginated(c, http.StatusOK, "Statement imports", imports, pagination)
+}
+
+// ListLines godoc
+// @Summary      List statement lines
+// @Description  Returns imported statement lines in a status. The default, unmatched, is the review queue of transfers that still need to be linked by hand.
+// @Tags         Statements
+// @Produce      json
+// @Param        status  query     string  false  "unmatched (default), matched, linked or ignored"
+// @Param        page    query     int     false  "Page number (default 1)"
+// @Param        limit   query     int     false  "Items per page (default 20, max 100)"
+// @Success      200     {object}  utils.APIResponse{data=utils.PaginatedResponse{data=[]models.StatementLine}}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      401     {object}  utils.APIResponse
+// @Failure      500     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /statements/lines [get]
+func (h *StatementHandler) ListLines(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	status := models.StatementLineStatus(c.DefaultQuery("status", string(models.StatementLineUnmatched)))
+	switch status {
+	case models.StatementLineUnmatched, models.StatementLineMatched, models.StatementLineLinked, models.StatementLineIgnored:
+	default:
+		utils.RespondBadRequest(c, "Invalid status filter")
+		return
+	}
+
+	pagination := utils.ParsePagination(c)
+	lines, err := h.reconciliationService.ListLines(c.Request.Context(), user, status, &pagination)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to list statement lines")
+		return
+	}
+
+	utils.RespondPaginated(c, http.StatusOK, "Statement lines", lines, pagination)
+}
+
+// LinkLine godoc
+// @Summary      Link a statement line to a transaction
+// @Description  Resolves a review queue line by confirming the pending transaction it pays. Only the recipient of the transaction can link it.
+// @Tags         Statements
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Statement line ID"
+// @Param        request  body      models.LinkStatementLineRequest  true  "Transaction to link"
+// @Success      200      {object}  utils.APIResponse{data=models.StatementLine}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Failure      404      {object}  utils.APIResponse
+// @Failure      409      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /statements/lines/{id}/link [post]
+func (h *StatementHandler) LinkLine(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.LinkStatementLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	line, err := h.reconciliationService.LinkLine(c.Request.Context(), user, c.Param("id"), req.TransactionID)
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Statement line linked", line)
+}
+
+// IgnoreLine godoc
+// @Summary      Ignore a statement line
+// @Description  Removes a transfer that is unrelated to any debt from the review queue
+// @Tags         Statements
+// @Produce      json
+// @Param        id  path      string  true  "Statement line ID"
+// @Success      200 {object}  utils.APIResponse{data=models.StatementLine}
+// @Failure      404 {object}  utils.APIResponse
+// @Failure      409 {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /statements/lines/{id}/ignore [put]
+func (h *StatementHandler) IgnoreLine(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	line, err := h.reconciliationService.IgnoreLine(c.Request.Context(), user, c.Param("id"))
+	if err != nil {
+		respondStatementError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Statement line ignored", line)
+}
+
+// respondStatementError maps reconciliation errors to HTTP responses
+func respondStatementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrStatementLineNotFound):
+		utils.RespondNotFound(c, err.Error())
+	case errors.Is(err, services.ErrStatementLineResolved):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidStatementLine):
+		utils.RespondBadRequest(c, err.Error())
+	default:
+		respondTransactionError(c, err)
+	}
+}
@@ -1,180 +1,407 @@
-package handlers
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-	"github.com/splitbill/backend/internal/utils"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-type TransactionHandler struct {
-	transactionRepo *repository.TransactionRepository
-	userRepo        *repository.UserRepository
-}
-
-func NewTransactionHandler(transactionRepo *repository.TransactionRepository, userRepo *repository.UserRepository) *TransactionHandler {
-	return &TransactionHandler{
-		transactionRepo: transactionRepo,
-		userRepo:        userRepo,
-	}
-}
-
-// CreateTransaction godoc
-// @Summary      Record a payment transaction
-// @Description  Creates a new settlement transaction between two users in a group
-// @Tags         Transactions
-// @Accept       json
-// @Produce      json
-// @Param        request  body      models.CreateTransactionRequest  true  "Transaction details"
-// @Success      201      {object}  utils.APIResponse{data=models.TransactionResponse}
-// @Failure      400      {object}  utils.APIResponse
-// @Failure      401      {object}  utils.APIResponse
-// @Failure      500      {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /transactions [post]
-func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
-	var req models.CreateTransactionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
-
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	// Get current user
-	fromUser, err := h.userRepo.FindByFirebaseUID(c.Request.Context(), uid)
-	if err != nil {
-		utils.RespondUnauthorized(c, "User not found")
-		return
-	}
-
-	toUserID, err := primitive.ObjectIDFromHex(req.ToUser)
-	if err != nil {
-		utils.RespondBadRequest(c, "Invalid to_user ID")
-		return
-	}
-
-	groupID, err := primitive.ObjectIDFromHex(req.GroupID)
-	if err != nil {
-		utils.RespondBadRequest(c, "Invalid group ID")
-		return
-	}
-
-	tx := &models.Transaction{
-		GroupID:         groupID,
-		FromUser:        fromUser.ID,
-		ToUser:          toUserID,
-		Amount:          req.Amount,
-		Currency:        req.Currency,
-		Type:            models.TransactionSettlement,
-		Status:          models.TransactionPending,
-		PaymentMethod:   req.PaymentMethod,
-		PaymentProofURL: req.PaymentProofURL,
-		Note:            req.Note,
-	}
-
-	if req.BillID != "" {
-		billID, err := primitive.ObjectIDFromHex(req.BillID)
-		if err == nil {
-			tx.BillID = billID
-		}
-	}
-
-	if err := h.transactionRepo.Create(c.Request.Context(), tx); err != nil {
-		utils.RespondInternalError(c, "Failed to create transaction: "+err.Error())
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusCreated, "Transaction recorded", tx.ToResponse())
-}
-
-// ConfirmTransaction godoc
-// @Summary      Confirm a transaction
-// @Description  Confirms a received payment. Only the recipient can confirm.
-// @Tags         Transactions
-// @Accept       json
-// @Produce      json
-// @Param        id   path      string  true  "Transaction ID"
-// @Success      200  {object}  utils.APIResponse
-// @Failure      400  {object}  utils.APIResponse
-// @Failure      401  {object}  utils.APIResponse
-// @Failure      403  {object}  utils.APIResponse
-// @Failure      404  {object}  utils.APIResponse
-// @Failure      500  {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /transactions/{id}/confirm [put]
-func (h *TransactionHandler) ConfirmTransaction(c *gin.Context) {
-	txID := c.Param("id")
-
-	objID, err := primitive.ObjectIDFromHex(txID)
-	if err != nil {
-		utils.RespondBadRequest(c, "Invalid transaction ID")
-		return
-	}
-
-	// Verify the current user is the recipient
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	currentUser, err := h.userRepo.FindByFirebaseUID(c.Request.Context(), uid)
-	if err != nil {
-		utils.RespondUnauthorized(c, "User not found")
-		return
-	}
-
-	tx, err := h.transactionRepo.FindByID(c.Request.Context(), objID)
-	if err != nil {
-		utils.RespondNotFound(c, "Transaction not found")
-		return
-	}
-
-	if tx.ToUser != currentUser.ID {
-		utils.RespondForbidden(c, "Only the recipient can confirm the transaction")
-		return
-	}
-
-	if err := h.transactionRepo.Confirm(c.Request.Context(), objID); err != nil {
-		utils.RespondInternalError(c, "Failed to confirm transaction")
-		return
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "Transaction confirmed", nil)
-}
-
-// GetUserDebts godoc
-// @Summary      Get current user's debts
-// @Description  Returns all transactions (debts) for the authenticated user across all groups
-// @Tags         Users
-// @Produce      json
-// @Success      200  {object}  utils.APIResponse{data=[]models.TransactionResponse}
-// @Failure      401  {object}  utils.APIResponse
-// @Failure      500  {object}  utils.APIResponse
-// @Security     BearerAuth
-// @Router       /users/me/debts [get]
-func (h *TransactionHandler) GetUserDebts(c *gin.Context) {
-	firebaseUID, _ := c.Get("firebase_uid")
-	uid := firebaseUID.(string)
-
-	currentUser, err := h.userRepo.FindByFirebaseUID(c.Request.Context(), uid)
-	if err != nil {
-		utils.RespondUnauthorized(c, "User not found")
-		return
-	}
-
-	transactions, err := h.transactionRepo.FindByUser(c.Request.Context(), currentUser.ID)
-	if err != nil {
-		utils.RespondInternalError(c, "Failed to get debts")
-		return
-	}
-
-	responses := make([]models.TransactionResponse, len(transactions))
-	for i, tx := range transactions {
-		responses[i] = tx.ToResponse()
-	}
-
-	utils.RespondSuccess(c, http.StatusOK, "User debts", responses)
-}
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TransactionHandler struct {
+	transactionService *services.TransactionService
+	userRepo           *repository.UserRepository
+}
+
+func NewTransactionHandler(transactionService *services.TransactionService, userRepo *repository.UserRepository) *TransactionHandler {
+	return &TransactionHandler{
+		transactionService: transactionService,
+		userRepo:           userRepo,
+	}
+}
+
+// CreateTransaction godoc
+// @Summary      Record a payment transaction
+// @Description  Creates a new settlement transaction between two users in a group. Viewers cannot record payments.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        request  body      models.CreateTransactionRequest  true  "Transaction details"
+// @Success      201      {object}  utils.APIResponse{data=models.TransactionResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Failure      500      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions [post]
+func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
+	var req models.CreateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	fromUser, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	tx, err := h.transactionService.CreateTransaction(c.Request.Context(), fromUser, req)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Transaction recorded", tx.ToResponse())
+}
+
+// UpdateTransaction godoc
+// @Summary      Amend a pending transaction
+// @Description  Lets the sender correct the amount, note or payment details while the transaction is still pending. Previous values are kept in the history.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Transaction ID"
+// @Param        request  body      models.UpdateTransactionRequest  true  "Fields to change"
+// @Success      200      {object}  utils.APIResponse{data=models.TransactionResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Failure      404      {object}  utils.APIResponse
+// @Failure      409      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions/{id} [put]
+func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
+	var req models.UpdateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	tx, err := h.transactionService.UpdateTransaction(c.Request.Context(), c.Param("id"), user, req)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Transaction updated", tx.ToResponse())
+}
+
+// CancelTransaction godoc
+// @Summary      Cancel a pending transaction
+// @Description  Lets the sender withdraw a transaction that has not been confirmed yet. The transaction is kept with status cancelled.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true   "Transaction ID"
+// @Param        request  body      models.CancelTransactionRequest  false  "Cancellation reason"
+// @Success      200      {object}  utils.APIResponse{data=models.TransactionResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Failure      404      {object}  utils.APIResponse
+// @Failure      409      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions/{id}/cancel [put]
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	var req models.CancelTransactionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+			return
+		}
+	}
+
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	tx, err := h.transactionService.CancelTransaction(c.Request.Context(), c.Param("id"), user, req.Reason)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Transaction cancelled", tx.ToResponse())
+}
+
+// ReverseTransaction godoc
+// @Summary      Request a reversal of a confirmed transaction
+// @Description  Creates a pending reversal that sends the amount back. The counterparty has to confirm it before balances change.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                            true   "Transaction ID"
+// @Param        request  body      models.ReverseTransactionRequest  false  "Reversal reason"
+// @Success      201      {object}  utils.APIResponse{data=models.TransactionResponse}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      401      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Failure      404      {object}  utils.APIResponse
+// @Failure      409      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions/{id}/reverse [post]
+func (h *TransactionHandler) ReverseTransaction(c *gin.Context) {
+	var req models.ReverseTransactionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+			return
+		}
+	}
+
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	reversal, err := h.transactionService.ReverseTransaction(c.Request.Context(), c.Param("id"), user, req.Reason)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Reversal requested", reversal.ToResponse())
+}
+
+// ConfirmTransaction godoc
+// @Summary      Confirm a transaction
+// @Description  Confirms a received payment. Only the recipient can confirm; reversals are confirmed by the original recipient.
+// @Tags         Transactions
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      401  {object}  utils.APIResponse
+// @Failure      403  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions/{id}/confirm [put]
+func (h *TransactionHandler) ConfirmTransaction(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	if _, err := h.transactionService.ConfirmTransaction(c.Request.Context(), c.Param("id"), user); err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Transaction confirmed", nil)
+}
+
+// RejectTransaction godoc
+// @Summary      Reject a transaction
+// @Description  Declines a pending payment or reversal. Only the party who would confirm it can reject it.
+// @Tags         Transactions
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      200  {object}  utils.APIResponse{data=models.TransactionResponse}
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      401  {object}  utils.APIResponse
+// @Failure      403  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /transactions/{id}/reject [put]
+func (h *TransactionHandler) RejectTransaction(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	tx, err := h.transactionService.RejectTransaction(c.Request.Context(), c.Param("id"), user)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Transaction rejected", tx.ToResponse())
+}
+
+// ListGroupTransactions godoc
+// @Summary      List group transactions
+// @Description  Returns a page of transactions in a group, newest first, with sender and recipient names. Only members can list.
+// @Tags         Transactions
+// @Produce      json
+// @Param        id      path      string  true   "Group ID"
+// @Param        status  query     string  false  "Filter by status (pending, confirmed, rejected, cancelled)"
+// @Param        type    query     string  false  "Filter by type (payment, settlement, reversal)"
+// @Param        member  query     string  false  "Only transactions sent or received by this user ID"
+// @Param        from    query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        to      query     string  false  "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param        page    query     int     false  "Page number (default 1)"
+// @Param        limit   query     int     false  "Items per page (default 20, max 100)"
+// @Success      200     {object}  utils.APIResponse{data=utils.PaginatedResponse{data=[]models.TransactionResponse}}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      401     {object}  utils.APIResponse
+// @Failure      403     {object}  utils.APIResponse
+// @Failure      500     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/transactions [get]
+func (h *TransactionHandler) ListGroupTransactions(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+	pagination := utils.ParsePagination(c)
+
+	transactions, err := h.transactionService.ListGroupTransactions(c.Request.Context(), c.Param("id"), user, filter, &pagination)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) {
+			utils.RespondForbidden(c, err.Error())
+			return
+		}
+		utils.RespondInternalError(c, "Failed to list transactions: "+err.Error())
+		return
+	}
+
+	utils.RespondPaginated(c, http.StatusOK, "Group transactions", transactions, pagination)
+}
+
+// GetUserDebts godoc
+// @Summary      Get current user's debts
+// @Description  Returns a page of transactions (debts) the authenticated user sent or received across all groups, with user names
+// @Tags         Users
+// @Produce      json
+// @Param        status  query     string  false  "Filter by status (pending, confirmed, rejected, cancelled)"
+// @Param        type    query     string  false  "Filter by type (payment, settlement, reversal)"
+// @Param        from    query     string  false  "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param        to      query     string  false  "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param        page    query     int     false  "Page number (default 1)"
+// @Param        limit   query     int     false  "Items per page (default 20, max 100)"
+// @Success      200     {object}  utils.APIResponse{data=utils.PaginatedResponse{data=[]models.TransactionResponse}}
+// @Failure      400     {object}  utils.APIResponse
+// @Failure      401     {object}  utils.APIResponse
+// @Failure      500     {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /users/me/debts [get]
+func (h *TransactionHandler) GetUserDebts(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+	pagination := utils.ParsePagination(c)
+
+	transactions, err := h.transactionService.ListUserTransactions(c.Request.Context(), user, filter, &pagination)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to get debts")
+		return
+	}
+
+	utils.RespondPaginated(c, http.StatusOK, "User debts", transactions, pagination)
+}
+
+// parseTransactionFilter reads the status, type, member and date range query parameters
+func parseTransactionFilter(c *gin.Context) (repository.TransactionFilter, error) {
+	var filter repository.TransactionFilter
+
+	if status := c.Query("status"); status != "" {
+		switch models.TransactionStatus(status) {
+		case models.TransactionPending, models.TransactionConfirmed, models.TransactionRejected, models.TransactionCancelled:
+			filter.Status = models.TransactionStatus(status)
+		default:
+			return filter, errors.New("invalid status filter")
+		}
+	}
+
+	if txType := c.Query("type"); txType != "" {
+		switch models.TransactionType(txType) {
+		case models.TransactionPayment, models.TransactionSettlement, models.TransactionReversal:
+			filter.Type = models.TransactionType(txType)
+		default:
+			return filter, errors.New("invalid type filter")
+		}
+	}
+
+	if member := c.Query("member"); member != "" {
+		memberID, err := primitive.ObjectIDFromHex(member)
+		if err != nil {
+			return filter, errors.New("invalid member ID")
+		}
+		filter.MemberID = memberID
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			return filter, errors.New("invalid from date")
+		}
+		filter.From = &t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			return filter, errors.New("invalid to date")
+		}
+		// A plain date means "up to the end of that day"
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		filter.To = &t
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from date must be before to date")
+	}
+
+	return filter, nil
+}
+
+// parseDateParam accepts either YYYY-MM-DD or an RFC3339 timestamp.
+// dateOnly reports whether the value had no time part.
+func parseDateParam(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// respondTransactionError maps transaction service errors to HTTP responses
+func respondTransactionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTransactionNotFound):
+		utils.RespondNotFound(c, err.Error())
+	case errors.Is(err, services.ErrTransactionForbidden), errors.Is(err, services.ErrNotGroupMember),
+		errors.Is(err, services.ErrGroupForbidden):
+		utils.RespondForbidden(c, err.Error())
+	case errors.Is(err, services.ErrTransactionNotPending), errors.Is(err, services.ErrTransactionReversed):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidToUserID), errors.Is(err, services.ErrInvalidGroupID),
+		errors.Is(err, services.ErrInvalidTransactionID), errors.Is(err, services.ErrReverseReversal),
+		errors.Is(err, services.ErrReverseNotConfirmed), errors.Is(err, services.ErrPaymentReferenceNotFound),
+		errors.Is(err, services.ErrPaymentReferenceInvalid):
+		utils.RespondBadRequest(c, err.Error())
+	default:
+		utils.RespondInternalError(c, err.Error())
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/services"
+	"github.com/splitbill/backend/internal/utils"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	userRepo       *repository.UserRepository
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService, userRepo *repository.UserRepository) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		userRepo:       userRepo,
+	}
+}
+
+// ListWebhooks godoc
+// @Summary      List group webhooks
+// @Description  Returns the URLs the group's events are sent to. Only group admins can see them; secrets are never returned.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Group ID"
+// @Success      200  {object}  utils.APIResponse{data=[]models.GroupWebhook}
+// @Failure      403  {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), c.Param("id"), user)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Webhooks retrieved", webhooks)
+}
+
+// CreateWebhook godoc
+// @Summary      Register a group webhook
+// @Description  Sends the group's events to a URL as JSON POSTs: {"id", "event", "group_id", "created_at", "data"}. Events can be limited to e.g. bill.created or transaction.confirmed; leave events empty for all. Each request carries X-SplitBill-Event, X-SplitBill-Delivery, X-SplitBill-Timestamp and X-SplitBill-Signature, which is "sha256=" + hex HMAC-SHA256 of timestamp + "." + body with the webhook secret. The secret is only shown in this response. Failed deliveries are retried with exponential backoff. Only group admins can register webhooks.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                       true  "Group ID"
+// @Param        request  body      models.CreateWebhookRequest  true  "Webhook URL and event filter"
+// @Success      201      {object}  utils.APIResponse{data=models.GroupWebhookWithSecret}
+// @Failure      400      {object}  utils.APIResponse
+// @Failure      403      {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), c.Param("id"), user, req)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Webhook created", webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update a group webhook
+// @Description  Changes the URL, description or event filter, switches the webhook on or off, or rotates its secret (the new secret is returned once). Only group admins can change webhooks.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string                       true  "Group ID"
+// @Param        webhookId  path      string                       true  "Webhook ID"
+// @Param        request    body      models.UpdateWebhookRequest  true  "Fields to change"
+// @Success      200        {object}  utils.APIResponse{data=models.GroupWebhookWithSecret}
+// @Failure      400        {object}  utils.APIResponse
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks/{webhookId} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), user, req)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Webhook updated", webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a group webhook
+// @Description  Stops sending events to the webhook and deletes its delivery log. Only group admins can delete webhooks.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id         path      string  true  "Group ID"
+// @Param        webhookId  path      string  true  "Webhook ID"
+// @Success      200        {object}  utils.APIResponse
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), user); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Webhook deleted", nil)
+}
+
+// TestWebhook godoc
+// @Summary      Send a test delivery
+// @Description  Sends a signed ping event to the webhook now and returns the delivery with the endpoint's response code and body. Test deliveries are not retried.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id         path      string  true  "Group ID"
+// @Param        webhookId  path      string  true  "Webhook ID"
+// @Success      200        {object}  utils.APIResponse{data=models.WebhookDelivery}
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks/{webhookId}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.TestWebhook(c.Request.Context(), c.Param("id"), c.Param("webhookId"), user)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Test delivery sent", delivery)
+}
+
+// ListDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  Returns the webhook's delivery log, newest first, with the outcome of each delivery's latest attempt. status=failed lists the dead letters: deliveries that ran out of attempts. Deliveries are kept for 30 days.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id         path      string  true   "Group ID"
+// @Param        webhookId  path      string  true   "Webhook ID"
+// @Param        status     query     string  false  "pending, delivered or failed"
+// @Param        cursor     query     string  false  "Cursor from the previous page"
+// @Param        limit      query     int     false  "Items per page (default 20, max 100)"
+// @Success      200        {object}  utils.APIResponse{data=utils.CursorResponse{data=[]models.WebhookDelivery}}
+// @Failure      400        {object}  utils.APIResponse
+// @Failure      403        {object}  utils.APIResponse
+// @Failure      404        {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	status := models.WebhookDeliveryStatus(c.Query("status"))
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		utils.RespondBadRequest(c, "status must be pending, delivered or failed")
+		return
+	}
+
+	page, err := utils.ParseCursorPage(c)
+	if err != nil {
+		utils.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	deliveries, next, err := h.webhookService.ListDeliveries(c.Request.Context(), c.Param("id"), c.Param("webhookId"), user, status, page)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondCursor(c, http.StatusOK, "Deliveries retrieved", deliveries, next)
+}
+
+// Redeliver godoc
+// @Summary      Redeliver a webhook delivery
+// @Description  Sends a delivered or failed delivery again with the same ID and payload, with a fresh set of retries. Use it for dead letters once the endpoint is fixed.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        id          path      string  true  "Group ID"
+// @Param        webhookId   path      string  true  "Webhook ID"
+// @Param        deliveryId  path      string  true  "Delivery ID"
+// @Success      200         {object}  utils.APIResponse{data=models.WebhookDelivery}
+// @Failure      403         {object}  utils.APIResponse
+// @Failure      404         {object}  utils.APIResponse
+// @Failure      409         {object}  utils.APIResponse
+// @Security     BearerAuth
+// @Router       /groups/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	user, ok := currentUser(c, h.userRepo)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), c.Param("id"), c.Param("webhookId"), c.Param("deliveryId"), user)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Delivery sent again", delivery)
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, services.ErrWebhookAdminOnly):
+		utils.RespondForbidden(c, err.Error())
+	case errors.Is(err, services.ErrWebhookNotFound), errors.Is(err, services.ErrDeliveryNotFound):
+		utils.RespondNotFound(c, err.Error())
+	case errors.Is(err, services.ErrDeliveryStillPending), errors.Is(err, services.ErrWebhookLimit):
+		utils.RespondError(c, http.StatusConflict, err.Error())
+	default:
+		utils.RespondBadRequest(c, err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Params fills the placeholders of a message. Amounts are plain numbers,
+// e.g. "150000", formatted by the message for its language.
+type Params map[string]string
+
+// templates is the parsed catalog. A broken message fails at startup
+// rather than when it is first shown.
+var templates = map[Lang]map[string]*template.Template{}
+
+func init() {
+	for lang, messages := range catalog {
+		funcs := template.FuncMap{
+			"money": func(amount string) string {
+				value, _ := strconv.ParseFloat(amount, 64)
+				return Money(lang, value)
+			},
+		}
+
+		templates[lang] = make(map[string]*template.Template, len(messages))
+		for key, message := range messages {
+			templates[lang][key] = template.Must(template.New(key).Funcs(funcs).Option("missingkey=zero").Parse(message))
+		}
+	}
+}
+
+// T renders the message key in lang, falling back to Default when lang has
+// no such message, and to the key itself when no language has it
+func T(lang Lang, key string, params Params) string {
+	tmpl, ok := templates[lang][key]
+	if !ok {
+		if tmpl, ok = templates[Default][key]; !ok {
+			return key
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, params); err != nil {
+		return key
+	}
+	return b.String()
+}
+
+// Has reports whether key is in the catalog
+func Has(key string) bool {
+	_, ok := templates[Default][key]
+	return ok
+}
+
+// Money formats a VND amount briefly, e.g. 1.5tr₫ and 150k₫ in Vietnamese,
+// 1.5M₫ and 150K₫ in English
+func Money(lang Lang, amount float64) string {
+	million, thousand := "M", "K"
+	if lang == Vietnamese {
+		million, thousand = "tr", "k"
+	}
+
+	switch {
+	case amount >= 1000000:
+		return fmt.Sprintf("%.1f%s₫", amount/1000000, million)
+	case amount >= 1000:
+		return fmt.Sprintf("%.0f%s₫", amount/1000, thousand)
+	default:
+		return fmt.Sprintf("%.0f₫", amount)
+	}
+}
+
+// Amount is an amount as a message parameter
+func Amount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// TimeAgo says how long ago t was, e.g. "5 phút trước" or "5m ago"
+func TimeAgo(lang Lang, t time.Time) string {
+	diff := time.Since(t)
+
+	var key string
+	var n int
+	switch {
+	case diff < time.Minute:
+		return T(lang, "time.just_now", nil)
+	case diff < time.Hour:
+		key, n = "time.minutes", int(diff.Minutes())
+	case diff < 24*time.Hour:
+		key, n = "time.hours", int(diff.Hours())
+	case diff < 7*24*time.Hour:
+		key, n = "time.days", int(diff.Hours()/24)
+	case diff < 30*24*time.Hour:
+		key, n = "time.weeks", int(diff.Hours()/(24*7))
+	default:
+		key, n = "time.months", int(diff.Hours()/(24*30))
+	}
+	return T(lang, key, Params{"n": strconv.Itoa(n)})
+}
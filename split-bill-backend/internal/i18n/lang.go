@@ -0,0 +1,97 @@
+// Package i18n renders user-facing text in the user's language. Activities
+// and notifications are stored as structured data and turned into text when
+// they are read, so the same record reads in Vietnamese for one member and
+// in English for another.
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/splitbill/backend/internal/models"
+)
+
+// Lang is a supported language, as its ISO 639-1 code
+type Lang string
+
+const (
+	Vietnamese Lang = "vi"
+	English    Lang = "en"
+
+	// Default is used when neither the user nor the request picks a language
+	Default = Vietnamese
+)
+
+// Supported lists the languages with a catalog
+var Supported = []Lang{Vietnamese, English}
+
+// Parse matches a language tag such as "en" or "vi-VN" to a supported language
+func Parse(tag string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	primary = strings.ToLower(primary)
+	for _, lang := range Supported {
+		if string(lang) == primary {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the supported language the Accept-Language header likes best
+func Negotiate(acceptLanguage string) (Lang, bool) {
+	type choice struct {
+		lang Lang
+		q    float64
+	}
+
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			choices = append(choices, choice{lang, q})
+		}
+	}
+	if len(choices) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	return choices[0].lang, true
+}
+
+// ForUser is the language a user has chosen in their profile, or Default
+func ForUser(user *models.User) Lang {
+	if user != nil {
+		if lang, ok := Parse(user.Language); ok {
+			return lang
+		}
+	}
+	return Default
+}
+
+// ForRequest is the language to answer a request in: the user's profile
+// choice if they made one, otherwise the request's Accept-Language,
+// otherwise Default. user may be nil.
+func ForRequest(r *http.Request, user *models.User) Lang {
+	if user != nil {
+		if lang, ok := Parse(user.Language); ok {
+			return lang
+		}
+	}
+	if lang, ok := Negotiate(r.Header.Get("Accept-Language")); ok {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,168 @@
+package i18n
+
+// catalog holds every message in every language as a text/template. The
+// data is a map of strings; money formats an amount for the language.
+var catalog = map[Lang]map[string]string{
+	Vietnamese: {
+		"time.just_now": "vừa xong",
+		"time.minutes":  "{{.n}} phút trước",
+		"time.hours":    "{{.n}} giờ trước",
+		"time.days":     "{{.n}} ngày trước",
+		"time.weeks":    "{{.n}} tuần trước",
+		"time.months":   "{{.n}} tháng trước",
+
+		"activity.bill_created.title":       "Hóa đơn mới",
+		"activity.bill_created.detail":      `{{.actor}} đã tạo hóa đơn "{{.target}}" - {{money .amount}}`,
+		"activity.receipt_confirmed.title":  "Hóa đơn từ ảnh",
+		"activity.receipt_confirmed.detail": `{{.actor}} đã quét hóa đơn "{{.target}}" - {{money .amount}}`,
+		"activity.bill_updated.title":       "Sửa hóa đơn",
+		"activity.bill_updated.detail":      `{{.actor}} đã sửa {{.changes}} của hóa đơn "{{.target}}"`,
+		"activity.bill_deleted.title":       "Xóa hóa đơn",
+		"activity.bill_deleted.detail":      `{{.actor}} đã xóa hóa đơn "{{.target}}" - {{money .amount}}`,
+		"activity.payment_sent.title":       "Thanh toán",
+		"activity.payment_sent.detail":      "{{.actor}} đã gửi {{money .amount}} cho {{.counterparty}}",
+		"activity.payment_confirmed.title":  "Xác nhận thanh toán",
+		"activity.payment_confirmed.detail": "{{.actor}} đã xác nhận thanh toán {{money .amount}} từ {{.counterparty}}",
+		"activity.payment_rejected.title":   "Từ chối thanh toán",
+		"activity.payment_rejected.detail":  "{{.actor}} đã từ chối thanh toán {{money .amount}} từ {{.counterparty}}",
+		"activity.member_joined.title":      "Thành viên mới",
+		"activity.member_joined.detail":     `{{.target}} đã tham gia nhóm "{{.group}}"`,
+		"activity.member_added.detail":      `{{.actor}} đã thêm {{.target}} vào nhóm "{{.group}}"`,
+		"activity.member_left.title":        "Thành viên rời nhóm",
+		"activity.member_left.detail":       `{{.target}} đã rời nhóm "{{.group}}"`,
+		"activity.member_removed.detail":    `{{.actor}} đã xóa {{.target}} khỏi nhóm "{{.group}}"`,
+		"activity.group_created.title":      "Nhóm mới",
+		"activity.settlement_created.title": "Thanh toán nợ",
+
+		"field.title":          "tên",
+		"field.description":    "mô tả",
+		"field.category":       "danh mục",
+		"field.total_amount":   "số tiền",
+		"field.currency":       "tiền tệ",
+		"field.paid_by":        "người trả",
+		"field.split_type":     "cách chia",
+		"field.status":         "trạng thái",
+		"field.tax":            "thuế",
+		"field.service_charge": "phí dịch vụ",
+		"field.tip":            "tiền tip",
+		"field.discount":       "giảm giá",
+
+		"notification.bill_created.title":                  "Hóa đơn mới trong {{.group}}",
+		"notification.bill_created.body":                   `{{.actor}} đã thêm "{{.bill}}" - {{money .amount}}`,
+		"notification.payment_received.title":              "Đã nhận thanh toán",
+		"notification.payment_received.body":               "{{.actor}} đã gửi bạn {{money .amount}}",
+		"notification.payment_confirmed.title":             "Thanh toán đã được xác nhận ✓",
+		"notification.payment_confirmed.body":              "{{.actor}} đã xác nhận khoản thanh toán {{money .amount}} của bạn",
+		"notification.member_joined.title":                 "{{.group}}",
+		"notification.member_joined.body":                  "{{.actor}} đã tham gia nhóm",
+		"notification.group_invite.title":                  "{{.group}}",
+		"notification.group_invite.body":                   "{{.actor}} đã thêm bạn vào nhóm",
+		"notification.join_request.title":                  "{{.group}}",
+		"notification.join_request.body":                   "{{.actor}} muốn tham gia nhóm và đang chờ duyệt",
+		"notification.join_request_result.approved.title":  "{{.group}}",
+		"notification.join_request_result.approved.body":   "Yêu cầu tham gia nhóm của bạn đã được chấp nhận",
+		"notification.join_request_result.denied.title":    "{{.group}}",
+		"notification.join_request_result.denied.body":     "Yêu cầu tham gia nhóm của bạn đã bị từ chối",
+		"notification.settlement_reminder.title":           "Nhắc thanh toán 💰",
+		"notification.settlement_reminder.body":            "Bạn nợ {{money .amount}} trong {{.group}}",
+		"notification.settlement_reminder.unpaid.title":    "Vẫn chưa thanh toán trong {{.group}}",
+		"notification.settlement_reminder.unpaid.body":     "Bạn vẫn còn nợ {{money .amount}} trong {{.group}}",
+		"notification.settlement_reminder.overdue.title":   "Khoản nợ quá hạn ⚠️",
+		"notification.settlement_reminder.overdue.body":    "Bạn đã nợ {{money .amount}} trong {{.group}} khá lâu. Hãy thanh toán trong hôm nay nhé.",
+		"notification.settlement_reminder.requested.title": "Lời nhắc từ {{.actor}}",
+		"notification.settlement_reminder.requested.body":  "{{.actor}} nhắc bạn còn nợ {{money .amount}} trong {{.group}}",
+		"notification.digest.weekly.title":                 "Tuần qua ở {{.group}}",
+		"notification.digest.monthly.title":                "Tháng qua ở {{.group}}",
+
+		"digest.no_bills":       "Không có hóa đơn mới",
+		"digest.one_bill":       "1 hóa đơn mới ({{money .total}})",
+		"digest.bills":          "{{.n}} hóa đơn mới ({{money .total}})",
+		"digest.owed":           "Bạn được nợ {{money .amount}}",
+		"digest.owe":            "Bạn nợ {{money .amount}}",
+		"digest.settled":        "Đã thanh toán hết",
+		"digest.one_to_confirm": "1 khoản cần xác nhận",
+		"digest.to_confirm":     "{{.n}} khoản cần xác nhận",
+		"digest.top":            "Chi nhiều nhất: {{.category}}",
+	},
+	English: {
+		"time.just_now": "just now",
+		"time.minutes":  "{{.n}}m ago",
+		"time.hours":    "{{.n}}h ago",
+		"time.days":     "{{.n}}d ago",
+		"time.weeks":    "{{.n}}w ago",
+		"time.months":   "{{.n}}mo ago",
+
+		"activity.bill_created.title":       "New bill",
+		"activity.bill_created.detail":      `{{.actor}} added "{{.target}}" - {{money .amount}}`,
+		"activity.receipt_confirmed.title":  "Bill from receipt",
+		"activity.receipt_confirmed.detail": `{{.actor}} scanned "{{.target}}" - {{money .amount}}`,
+		"activity.bill_updated.title":       "Bill edited",
+		"activity.bill_updated.detail":      `{{.actor}} changed the {{.changes}} of "{{.target}}"`,
+		"activity.bill_deleted.title":       "Bill deleted",
+		"activity.bill_deleted.detail":      `{{.actor}} deleted "{{.target}}" - {{money .amount}}`,
+		"activity.payment_sent.title":       "Payment",
+		"activity.payment_sent.detail":      "{{.actor}} sent {{money .amount}} to {{.counterparty}}",
+		"activity.payment_confirmed.title":  "Payment confirmed",
+		"activity.payment_confirmed.detail": "{{.actor}} confirmed a payment of {{money .amount}} from {{.counterparty}}",
+		"activity.payment_rejected.title":   "Payment rejected",
+		"activity.payment_rejected.detail":  "{{.actor}} rejected a payment of {{money .amount}} from {{.counterparty}}",
+		"activity.member_joined.title":      "New member",
+		"activity.member_joined.detail":     `{{.target}} joined "{{.group}}"`,
+		"activity.member_added.detail":      `{{.actor}} added {{.target}} to "{{.group}}"`,
+		"activity.member_left.title":        "Member left",
+		"activity.member_left.detail":       `{{.target}} left "{{.group}}"`,
+		"activity.member_removed.detail":    `{{.actor}} removed {{.target}} from "{{.group}}"`,
+		"activity.group_created.title":      "New group",
+		"activity.settlement_created.title": "Settlement",
+
+		"field.title":          "title",
+		"field.description":    "description",
+		"field.category":       "category",
+		"field.total_amount":   "amount",
+		"field.currency":       "currency",
+		"field.paid_by":        "payer",
+		"field.split_type":     "split",
+		"field.status":         "status",
+		"field.tax":            "tax",
+		"field.service_charge": "service charge",
+		"field.tip":            "tip",
+		"field.discount":       "discount",
+
+		"notification.bill_created.title":                  "New bill in {{.group}}",
+		"notification.bill_created.body":                   `{{.actor}} added "{{.bill}}" - {{money .amount}}`,
+		"notification.payment_received.title":              "Payment Received",
+		"notification.payment_received.body":               "{{.actor}} sent you {{money .amount}}",
+		"notification.payment_confirmed.title":             "Payment Confirmed ✓",
+		"notification.payment_confirmed.body":              "{{.actor}} confirmed your payment of {{money .amount}}",
+		"notification.member_joined.title":                 "{{.group}}",
+		"notification.member_joined.body":                  "{{.actor}} joined the group",
+		"notification.group_invite.title":                  "{{.group}}",
+		"notification.group_invite.body":                   "{{.actor}} added you to the group",
+		"notification.join_request.title":                  "{{.group}}",
+		"notification.join_request.body":                   "{{.actor}} asked to join the group and is waiting for approval",
+		"notification.join_request_result.approved.title":  "{{.group}}",
+		"notification.join_request_result.approved.body":   "Your request to join the group was approved",
+		"notification.join_request_result.denied.title":    "{{.group}}",
+		"notification.join_request_result.denied.body":     "Your request to join the group was declined",
+		"notification.settlement_reminder.title":           "Settlement Reminder 💰",
+		"notification.settlement_reminder.body":            "You owe {{money .amount}} in {{.group}}",
+		"notification.settlement_reminder.unpaid.title":    "Still unpaid in {{.group}}",
+		"notification.settlement_reminder.unpaid.body":     "You still owe {{money .amount}} in {{.group}}",
+		"notification.settlement_reminder.overdue.title":   "Overdue payment ⚠️",
+		"notification.settlement_reminder.overdue.body":    "You have owed {{money .amount}} in {{.group}} for a while. Please settle up today.",
+		"notification.settlement_reminder.requested.title": "Reminder from {{.actor}}",
+		"notification.settlement_reminder.requested.body":  "{{.actor}} reminded you that you owe {{money .amount}} in {{.group}}",
+		"notification.digest.weekly.title":                 "Your week in {{.group}}",
+		"notification.digest.monthly.title":                "Your month in {{.group}}",
+
+		"digest.no_bills":       "No new bills",
+		"digest.one_bill":       "1 new bill ({{money .total}})",
+		"digest.bills":          "{{.n}} new bills ({{money .total}})",
+		"digest.owed":           "You are owed {{money .amount}}",
+		"digest.owe":            "You owe {{money .amount}}",
+		"digest.settled":        "All settled up",
+		"digest.one_to_confirm": "1 payment to confirm",
+		"digest.to_confirm":     "{{.n}} payments to confirm",
+		"digest.top":            "Top: {{.category}}",
+	},
+}
@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/splitbill/backend/internal/models"
+)
+
+// ActivityText renders the title and detail of an activity from its
+// payload. Activities recorded before they had one keep their stored
+// detail; known types still get a title in lang.
+func ActivityText(lang Lang, actType models.ActivityType, payload *models.ActivityPayload, title, detail string) (string, string) {
+	if key := "activity." + string(actType) + ".title"; Has(key) {
+		title = T(lang, key, nil)
+	}
+	if payload == nil {
+		return title, detail
+	}
+
+	params := Params{
+		"actor":  payload.Actor.Name,
+		"target": payload.Target.Name,
+		"amount": Amount(payload.Amount),
+	}
+	if payload.Counterparty != nil {
+		params["counterparty"] = payload.Counterparty.Name
+	}
+	if payload.Group != nil {
+		params["group"] = payload.Group.Name
+	}
+	if len(payload.Changes) > 0 {
+		fields := make([]string, len(payload.Changes))
+		for i, change := range payload.Changes {
+			fields[i] = T(lang, "field."+change.Field, nil)
+		}
+		params["changes"] = strings.Join(fields, ", ")
+	}
+
+	key := "activity." + string(actType) + ".detail"
+	switch {
+	case actType == models.ActivityMemberJoined && payload.Actor.ID != payload.Target.ID:
+		key = "activity.member_added.detail"
+	case actType == models.ActivityMemberLeft && payload.Actor.ID != payload.Target.ID:
+		key = "activity.member_removed.detail"
+	}
+	if !Has(key) {
+		return title, detail
+	}
+	return title, T(lang, key, params)
+}
+
+// RenderActivity puts an activity response into lang
+func RenderActivity(lang Lang, activity *models.ActivityResponse) {
+	activity.Title, activity.Detail = ActivityText(lang, activity.Type, activity.Payload, activity.Title, activity.Detail)
+	activity.TimeAgo = TimeAgo(lang, activity.CreatedAt)
+}
+
+// NotificationText renders the title and body of a notification message,
+// e.g. "bill_created" or "settlement_reminder.overdue"
+func NotificationText(lang Lang, key string, params Params) (string, string) {
+	title := T(lang, "notification."+key+".title", params)
+	if strings.HasPrefix(key, "digest.") {
+		return title, digestSummary(lang, params)
+	}
+	return title, T(lang, "notification."+key+".body", params)
+}
+
+// RenderNotification puts an inbox notification into lang. Notifications
+// stored before they had a message key keep their stored text.
+func RenderNotification(lang Lang, notification *models.Notification) {
+	if notification.Key == "" {
+		return
+	}
+	notification.Title, notification.Body = NotificationText(lang, notification.Key, notification.Params)
+}
+
+// digestSummary is the push text of a digest, e.g.
+// "3 new bills (1.2M₫) · You owe 250K₫ · 1 payment to confirm · Top: food"
+func digestSummary(lang Lang, params Params) string {
+	var parts []string
+	switch bills, _ := strconv.Atoi(params["bills"]); bills {
+	case 0:
+		parts = append(parts, T(lang, "digest.no_bills", nil))
+	case 1:
+		parts = append(parts, T(lang, "digest.one_bill", Params{"total": params["bills_total"]}))
+	default:
+		parts = append(parts, T(lang, "digest.bills", Params{"n": params["bills"], "total": params["bills_total"]}))
+	}
+
+	balance, _ := strconv.ParseFloat(params["balance"], 64)
+	switch {
+	case balance >= 0.01:
+		parts = append(parts, T(lang, "digest.owed", Params{"amount": Amount(balance)}))
+	case balance <= -0.01:
+		parts = append(parts, T(lang, "digest.owe", Params{"amount": Amount(-balance)}))
+	default:
+		parts = append(parts, T(lang, "digest.settled", nil))
+	}
+
+	switch toConfirm, _ := strconv.Atoi(params["to_confirm"]); {
+	case toConfirm == 1:
+		parts = append(parts, T(lang, "digest.one_to_confirm", nil))
+	case toConfirm > 1:
+		parts = append(parts, T(lang, "digest.to_confirm", Params{"n": params["to_confirm"]}))
+	}
+	if params["top_category"] != "" {
+		parts = append(parts, T(lang, "digest.top", Params{"category": params["top_category"]}))
+	}
+	return strings.Join(parts, " · ")
+}
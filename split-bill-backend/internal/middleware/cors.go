@@ -1,45 +1,45 @@
-package middleware
-
-import (
-	"time"
-
-	"github.com/gin-gonic/gin"
-)
-
-// CORS returns a middleware that handles Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		c.Header("Access-Control-Max-Age", "86400")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	}
-}
-
-// Logger returns a middleware that logs requests
-func Logger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-
-		c.Next()
-
-		latency := time.Since(start)
-		statusCode := c.Writer.Status()
-
-		if statusCode >= 400 {
-			gin.DefaultWriter.Write([]byte(
-				"[ERROR] " + c.Request.Method + " " + path +
-					" | Status: " + string(rune(statusCode)) +
-					" | Latency: " + latency.String() + "\n",
-			))
-		}
-	}
-}
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS returns a middleware that handles Cross-Origin Resource Sharing
+func CORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+		c.Header("Access-Control-Max-Age", "86400")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Logger returns a middleware that logs requests
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		latency := time.Since(start)
+		statusCode := c.Writer.Status()
+
+		if statusCode >= 400 {
+			gin.DefaultWriter.Write([]byte(
+				"[ERROR] " + c.Request.Method + " " + path +
+					" | Status: " + string(rune(statusCode)) +
+					" | Latency: " + latency.String() + "\n",
+			))
+		}
+	}
+}
@@ -1,124 +1,185 @@
-package models
-
-import (
-	"fmt"
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// ActivityType represents the type of activity
-type ActivityType string
-
-const (
-	ActivityBillCreated       ActivityType = "bill_created"
-	ActivityBillDeleted       ActivityType = "bill_deleted"
-	ActivityBillUpdated       ActivityType = "bill_updated"
-	ActivityMemberJoined      ActivityType = "member_joined"
-	ActivityMemberLeft        ActivityType = "member_left"
-	ActivityPaymentSent       ActivityType = "payment_sent"
-	ActivityPaymentConfirmed  ActivityType = "payment_confirmed"
-	ActivityPaymentRejected   ActivityType = "payment_rejected"
-	ActivityGroupCreated      ActivityType = "group_created"
-	ActivitySettlementCreated ActivityType = "settlement_created"
-)
-
-// Activity represents an activity event in a group
-type Activity struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	GroupID   primitive.ObjectID `bson:"group_id" json:"group_id"`
-	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Type      ActivityType       `bson:"type" json:"type"`
-	Title     string             `bson:"title" json:"title"`
-	Detail    string             `bson:"detail" json:"detail"`
-	Amount    float64            `bson:"amount,omitempty" json:"amount,omitempty"`
-	RefID     string             `bson:"ref_id,omitempty" json:"ref_id,omitempty"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-}
-
-// ActivityResponse is the API response for an activity
-type ActivityResponse struct {
-	ID         string       `json:"id"`
-	GroupID    string       `json:"group_id"`
-	GroupName  string       `json:"group_name,omitempty"`
-	UserID     string       `json:"user_id"`
-	UserName   string       `json:"user_name"`
-	UserAvatar string       `json:"user_avatar,omitempty"`
-	Type       ActivityType `json:"type"`
-	Title      string       `json:"title"`
-	Detail     string       `json:"detail"`
-	Amount     float64      `json:"amount,omitempty"`
-	RefID      string       `json:"ref_id,omitempty"`
-	CreatedAt  time.Time    `json:"created_at"`
-	TimeAgo    string       `json:"time_ago"`
-}
-
-func (a *Activity) ToResponse() ActivityResponse {
-	return ActivityResponse{
-		ID:        a.ID.Hex(),
-		GroupID:   a.GroupID.Hex(),
-		UserID:    a.UserID.Hex(),
-		Type:      a.Type,
-		Title:     a.Title,
-		Detail:    a.Detail,
-		Amount:    a.Amount,
-		RefID:     a.RefID,
-		CreatedAt: a.CreatedAt,
-		TimeAgo:   timeAgo(a.CreatedAt),
-	}
-}
-
-// timeAgo returns a human-readable time difference
-func timeAgo(t time.Time) string {
-	diff := time.Since(t)
-
-	switch {
-	case diff < time.Minute:
-		return "vừa xong"
-	case diff < time.Hour:
-		return fmt.Sprintf("%d phút trước", int(diff.Minutes()))
-	case diff < 24*time.Hour:
-		return fmt.Sprintf("%d giờ trước", int(diff.Hours()))
-	case diff < 7*24*time.Hour:
-		return fmt.Sprintf("%d ngày trước", int(diff.Hours()/24))
-	case diff < 30*24*time.Hour:
-		return fmt.Sprintf("%d tuần trước", int(diff.Hours()/(24*7)))
-	default:
-		return fmt.Sprintf("%d tháng trước", int(diff.Hours()/(24*30)))
-	}
-}
-
-// PaymentDeeplinkRequest is the request for generating a payment deeplink
-type PaymentDeeplinkRequest struct {
-	BankCode      string  `json:"bank_code" binding:"required"`
-	AccountNumber string  `json:"account_number" binding:"required"`
-	AccountName   string  `json:"account_name"`
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	Note          string  `json:"note"`
-}
-
-// PaymentDeeplinkResponse contains deeplinks for various banking apps
-type PaymentDeeplinkResponse struct {
-	Amount    float64           `json:"amount"`
-	Note      string            `json:"note"`
-	Deeplinks []BankingDeeplink `json:"deeplinks"`
-	VietQRURL string            `json:"vietqr_url"`
-}
-
-// BankingDeeplink represents a deeplink to a banking app
-type BankingDeeplink struct {
-	AppName  string `json:"app_name"`
-	Scheme   string `json:"scheme"`
-	Color    string `json:"color"`
-	IconName string `json:"icon_name"`
-}
-
-// VietQRRequest is the request for generating a VietQR code
-type VietQRRequest struct {
-	BankID        string  `json:"bank_id" binding:"required"`
-	AccountNumber string  `json:"account_no" binding:"required"`
-	AccountName   string  `json:"account_name"`
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	Description   string  `json:"description"`
-	Template      string  `json:"template"`
-}
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ActivityType represents the type of activity
+type ActivityType string
+
+const (
+	ActivityBillCreated       ActivityType = "bill_created"
+	ActivityBillDeleted       ActivityType = "bill_deleted"
+	ActivityBillUpdated       ActivityType = "bill_updated"
+	ActivityMemberJoined      ActivityType = "member_joined"
+	ActivityMemberLeft        ActivityType = "member_left"
+	ActivityPaymentSent       ActivityType = "payment_sent"
+	ActivityPaymentConfirmed  ActivityType = "payment_confirmed"
+	ActivityPaymentRejected   ActivityType = "payment_rejected"
+	ActivityGroupCreated      ActivityType = "group_created"
+	ActivitySettlementCreated ActivityType = "settlement_created"
+	ActivityReceiptConfirmed  ActivityType = "receipt_confirmed"
+)
+
+// Activity represents an activity event in a group. UserID is the user who
+// did it. Title and Detail are pre-rendered in Vietnamese; Payload holds the
+// same facts in structured form.
+type Activity struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GroupID   primitive.ObjectID `bson:"group_id" json:"group_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type      ActivityType       `bson:"type" json:"type"`
+	Title     string             `bson:"title" json:"title"`
+	Detail    string             `bson:"detail" json:"detail"`
+	Amount    float64            `bson:"amount,omitempty" json:"amount,omitempty"`
+	RefID     string             `bson:"ref_id,omitempty" json:"ref_id,omitempty"`
+	Payload   *ActivityPayload   `bson:"payload,omitempty" json:"payload,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// ActivityRefKind says what an ActivityRef points at
+type ActivityRefKind string
+
+const (
+	ActivityRefUser        ActivityRefKind = "user"
+	ActivityRefGroup       ActivityRefKind = "group"
+	ActivityRefBill        ActivityRefKind = "bill"
+	ActivityRefTransaction ActivityRefKind = "transaction"
+	ActivityRefReceipt     ActivityRefKind = "receipt"
+)
+
+// ActivityRef names something an activity is about, with its name as it was
+// at the time
+type ActivityRef struct {
+	Kind ActivityRefKind `bson:"kind" json:"kind"`
+	ID   string          `bson:"id" json:"id"`
+	Name string          `bson:"name,omitempty" json:"name,omitempty"`
+}
+
+// ActivityChange is one field an activity changed. Old and New are strings,
+// numbers or booleans; an absent side means the field was empty.
+type ActivityChange struct {
+	Field string      `bson:"field" json:"field"`
+	Old   interface{} `bson:"old,omitempty" json:"old,omitempty"`
+	New   interface{} `bson:"new,omitempty" json:"new,omitempty"`
+}
+
+// ActivityPayload is what happened, who did it and to what.
+//
+//	bill_created, bill_updated, bill_deleted: Target is the bill
+//	receipt_confirmed: Target is the bill, Source the scanned receipt
+//	payment_*: Target is the transaction, Counterparty the other member
+//	member_joined, member_left: Target is the member; Actor is the member
+//	    themselves, or whoever added or removed them
+type ActivityPayload struct {
+	Actor        ActivityRef      `bson:"actor" json:"actor"`
+	Target       ActivityRef      `bson:"target" json:"target"`
+	Counterparty *ActivityRef     `bson:"counterparty,omitempty" json:"counterparty,omitempty"`
+	Source       *ActivityRef     `bson:"source,omitempty" json:"source,omitempty"`
+	Group        *ActivityRef     `bson:"group,omitempty" json:"group,omitempty"`
+	Amount       float64          `bson:"amount,omitempty" json:"amount,omitempty"`
+	Currency     string           `bson:"currency,omitempty" json:"currency,omitempty"`
+	Changes      []ActivityChange `bson:"changes,omitempty" json:"changes,omitempty"`
+}
+
+// ActivityResponse is the API response for an activity
+type ActivityResponse struct {
+	ID         string           `json:"id"`
+	GroupID    string           `json:"group_id"`
+	GroupName  string           `json:"group_name,omitempty"`
+	UserID     string           `json:"user_id"`
+	UserName   string           `json:"user_name"`
+	UserAvatar string           `json:"user_avatar,omitempty"`
+	Type       ActivityType     `json:"type"`
+	Title      string           `json:"title"`
+	Detail     string           `json:"detail"`
+	Amount     float64          `json:"amount,omitempty"`
+	RefID      string           `json:"ref_id,omitempty"`
+	Payload    *ActivityPayload `json:"payload,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	TimeAgo    string           `json:"time_ago"` // filled in when rendered for the reader
+}
+
+func (a *Activity) ToResponse() ActivityResponse {
+	return ActivityResponse{
+		ID:        a.ID.Hex(),
+		GroupID:   a.GroupID.Hex(),
+		UserID:    a.UserID.Hex(),
+		Type:      a.Type,
+		Title:     a.Title,
+		Detail:    a.Detail,
+		Amount:    a.Amount,
+		RefID:     a.RefID,
+		Payload:   a.Payload,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// PaymentDeeplinkRequest is the request for generating a payment deeplink
+type PaymentDeeplinkRequest struct {
+	BankCode      string  `json:"bank_code" binding:"required"`
+	AccountNumber string  `json:"account_number" binding:"required"`
+	AccountName   string  `json:"account_name"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Note          string  `json:"note"`
+	Reference     string  `json:"reference"` // payment reference, prepended to the note
+}
+
+// PaymentDeeplinkResponse contains deeplinks for various banking apps
+type PaymentDeeplinkResponse struct {
+	Amount    float64           `json:"amount"`
+	Note      string            `json:"note"`
+	Reference string            `json:"reference,omitempty"`
+	Deeplinks []BankingDeeplink `json:"deeplinks"`
+	VietQRURL string            `json:"vietqr_url,omitempty"`
+
+	// VietQRPayload is the raw EMVCo string, for clients that draw the QR themselves
+	VietQRPayload string `json:"vietqr_payload,omitempty"`
+}
+
+// BankingDeeplink represents a deeplink to a banking app
+type BankingDeeplink struct {
+	AppName  string `json:"app_name"`
+	Scheme   string `json:"scheme"`
+	Color    string `json:"color"`
+	IconName string `json:"icon_name"`
+}
+
+// SettlementPaymentResponse has everything needed to pay one settle-up item
+type SettlementPaymentResponse struct {
+	GroupID       string            `json:"group_id"`
+	Payee         PaymentPayee      `json:"payee"`
+	Amount        float64           `json:"amount"`
+	Currency      string            `json:"currency"`
+	Reference     string            `json:"reference"`
+	Note          string            `json:"note"`
+	BankAccount   *BankAccount      `json:"bank_account,omitempty"` // account the deeplinks and QR pay into
+	Deeplinks     []BankingDeeplink `json:"deeplinks"`
+	VietQRURL     string            `json:"vietqr_url,omitempty"`
+	VietQRPayload string            `json:"vietqr_payload,omitempty"`
+
+	// TransactionDraft is ready to submit to POST /transactions after paying
+	TransactionDraft CreateTransactionRequest `json:"transaction_draft"`
+}
+
+// PaymentPayee is the member being paid and how they like to receive money
+type PaymentPayee struct {
+	UserID           string        `json:"user_id"`
+	DisplayName      string        `json:"display_name"`
+	AvatarURL        string        `json:"avatar_url"`
+	BankAccounts     []BankAccount `json:"bank_accounts"`
+	PreferredPayment string        `json:"preferred_payment"`
+}
+
+// VietQRRequest is the request for generating a VietQR code
+type VietQRRequest struct {
+	BankID        string  `json:"bank_id" binding:"required"`
+	AccountNumber string  `json:"account_no" binding:"required"`
+	AccountName   string  `json:"account_name"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Description   string  `json:"description"`
+	Reference     string  `json:"reference"` // payment reference, prepended to addInfo
+	Template      string  `json:"template"`
+}
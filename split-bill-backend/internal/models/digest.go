@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DigestFrequency is how often a user gets a summary of each of their groups
+type DigestFrequency string
+
+const (
+	DigestOff     DigestFrequency = "off"
+	DigestWeekly  DigestFrequency = "weekly"  // Monday morning, covering the week before
+	DigestMonthly DigestFrequency = "monthly" // on the 1st, covering the month before
+)
+
+// Digest summarizes one group for one member over a period
+type Digest struct {
+	GroupID     string          `json:"group_id"`
+	GroupName   string          `json:"group_name"`
+	Frequency   DigestFrequency `json:"frequency"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"` // exclusive
+
+	NewBills      []DigestBill    `json:"new_bills"`
+	NewBillsTotal float64         `json:"new_bills_total"`
+	TopCategory   *DigestCategory `json:"top_category,omitempty"`
+
+	// Balance is the member's balance in the group now: positive when
+	// others owe them, negative when they owe
+	Balance float64 `json:"balance"`
+
+	ToConfirm       int     `json:"to_confirm"` // payments to the member waiting for them to confirm
+	ToConfirmAmount float64 `json:"to_confirm_amount"`
+	AwaitingConfirm int     `json:"awaiting_confirm"` // payments the member sent that are not confirmed yet
+	AwaitingAmount  float64 `json:"awaiting_amount"`
+
+	PaymentsConfirmed int `json:"payments_confirmed"` // in the period
+	MembersJoined     int `json:"members_joined"`     // in the period
+}
+
+// DigestBill is a bill added during the digest period
+type DigestBill struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Amount     float64   `json:"amount"`
+	Category   string    `json:"category"`
+	PaidByName string    `json:"paid_by_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DigestCategory is where most of the money in the period went
+type DigestCategory struct {
+	Category   string  `json:"category"`
+	Total      float64 `json:"total"`
+	Percentage float64 `json:"percentage"`
+}
+
+// IsEmpty reports whether there is nothing worth sending
+func (d *Digest) IsEmpty() bool {
+	return len(d.NewBills) == 0 && d.ToConfirm == 0 && d.AwaitingConfirm == 0 &&
+		d.PaymentsConfirmed == 0 && d.MembersJoined == 0 && (d.Balance > -0.01 && d.Balance < 0.01)
+}
+
+// DigestDelivery logs a digest that went out. Key is unique per user, group
+// and period, so every server instance can run the schedule and each digest
+// is still sent once.
+type DigestDelivery struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key         string             `bson:"key" json:"-"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	GroupID     primitive.ObjectID `bson:"group_id" json:"group_id"`
+	Frequency   DigestFrequency    `bson:"frequency" json:"frequency"`
+	PeriodStart time.Time          `bson:"period_start" json:"period_start"`
+	Empty       bool               `bson:"empty" json:"empty"` // nothing happened, so nothing was sent
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -1,109 +1,182 @@
-package models
-
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// MemberRole represents the role of a member in a group
-type MemberRole string
-
-const (
-	RoleAdmin  MemberRole = "admin"
-	RoleMember MemberRole = "member"
-)
-
-// GroupMember represents a member within a group
-type GroupMember struct {
-	UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Nickname string             `bson:"nickname" json:"nickname"`
-	Role     MemberRole         `bson:"role" json:"role"`
-	JoinedAt time.Time          `bson:"joined_at" json:"joined_at"`
-}
-
-// Group represents a group of people splitting bills
-type Group struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description" json:"description"`
-	AvatarURL   string             `bson:"avatar_url" json:"avatar_url"`
-	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
-	Members     []GroupMember      `bson:"members" json:"members"`
-	InviteCode  string             `bson:"invite_code" json:"invite_code"`
-	IsActive    bool               `bson:"is_active" json:"is_active"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
-}
-
-// CreateGroupRequest is the request body for creating a group
-type CreateGroupRequest struct {
-	Name        string `json:"name" binding:"required,min=2,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	AvatarURL   string `json:"avatar_url"`
-}
-
-// UpdateGroupRequest is the request body for updating a group
-type UpdateGroupRequest struct {
-	Name        string `json:"name" binding:"omitempty,min=2,max=100"`
-	Description string `json:"description" binding:"max=500"`
-	AvatarURL   string `json:"avatar_url"`
-}
-
-// AddMemberRequest is the request body for adding a member to a group
-type AddMemberRequest struct {
-	UserID   string `json:"user_id" binding:"required"`
-	Nickname string `json:"nickname"`
-}
-
-// JoinGroupRequest is the request body for joining a group by invite code
-type JoinGroupRequest struct {
-	InviteCode string `json:"invite_code" binding:"required"`
-}
-
-// GroupResponse is the API response for a group
-type GroupResponse struct {
-	ID          string                `json:"id"`
-	Name        string                `json:"name"`
-	Description string                `json:"description"`
-	AvatarURL   string                `json:"avatar_url"`
-	CreatedBy   string                `json:"created_by"`
-	Members     []GroupMemberResponse `json:"members"`
-	InviteCode  string                `json:"invite_code"`
-	IsActive    bool                  `json:"is_active"`
-	CreatedAt   time.Time             `json:"created_at"`
-}
-
-// GroupMemberResponse is the API response for a group member
-type GroupMemberResponse struct {
-	UserID      string     `json:"user_id"`
-	Nickname    string     `json:"nickname"`
-	DisplayName string     `json:"display_name"`
-	AvatarURL   string     `json:"avatar_url"`
-	Role        MemberRole `json:"role"`
-	JoinedAt    time.Time  `json:"joined_at"`
-}
-
-func (g *Group) ToResponse() GroupResponse {
-	members := make([]GroupMemberResponse, len(g.Members))
-	for i, m := range g.Members {
-		members[i] = GroupMemberResponse{
-			UserID:   m.UserID.Hex(),
-			Nickname: m.Nickname,
-			Role:     m.Role,
-			JoinedAt: m.JoinedAt,
-		}
-	}
-
-	return GroupResponse{
-		ID:          g.ID.Hex(),
-		Name:        g.Name,
-		Description: g.Description,
-		AvatarURL:   g.AvatarURL,
-		CreatedBy:   g.CreatedBy.Hex(),
-		Members:     members,
-		InviteCode:  g.InviteCode,
-		IsActive:    g.IsActive,
-		CreatedAt:   g.CreatedAt,
-	}
-}
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemberRole represents the role of a member in a group. What each role
+// may do is set by the permission matrix in permission.go.
+type MemberRole string
+
+const (
+	RoleOwner  MemberRole = "owner"
+	RoleAdmin  MemberRole = "admin"
+	RoleMember MemberRole = "member"
+	RoleViewer MemberRole = "viewer"
+)
+
+// GroupMember represents a member within a group
+type GroupMember struct {
+	UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Nickname string             `bson:"nickname" json:"nickname"`
+	Role     MemberRole         `bson:"role" json:"role"`
+	JoinedAt time.Time          `bson:"joined_at" json:"joined_at"`
+
+	// RemindersSnoozedUntil pauses settlement reminders to this member
+	RemindersSnoozedUntil *time.Time `bson:"reminders_snoozed_until,omitempty" json:"reminders_snoozed_until,omitempty"`
+
+	// InviteLinkID is the invite link the member joined with, if any
+	InviteLinkID *primitive.ObjectID `bson:"invite_link_id,omitempty" json:"invite_link_id,omitempty"`
+
+	// Placeholder members have no account yet. They share bills like
+	// anyone else and can be claimed through an invite link.
+	Placeholder bool `bson:"placeholder,omitempty" json:"placeholder,omitempty"`
+}
+
+// Group represents a group of people splitting bills
+type Group struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	AvatarURL   string             `bson:"avatar_url" json:"avatar_url"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	Members     []GroupMember      `bson:"members" json:"members"`
+	InviteCode  string             `bson:"invite_code" json:"invite_code"`
+	IsActive    bool               `bson:"is_active" json:"is_active"`
+	Reminders   *ReminderSettings  `bson:"reminders,omitempty" json:"reminders,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+
+	// JoinApprovalRequired makes joining with an invite code or link a
+	// request that an owner or admin has to approve
+	JoinApprovalRequired bool `bson:"join_approval_required" json:"join_approval_required"`
+}
+
+// CreateGroupRequest is the request body for creating a group
+type CreateGroupRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// UpdateGroupRequest is the request body for updating a group
+type UpdateGroupRequest struct {
+	Name                 string `json:"name" binding:"omitempty,min=2,max=100"`
+	Description          string `json:"description" binding:"max=500"`
+	AvatarURL            string `json:"avatar_url"`
+	JoinApprovalRequired *bool  `json:"join_approval_required"`
+}
+
+// AddPlaceholderRequest is the request body for adding a placeholder member
+type AddPlaceholderRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+// AddMemberRequest is the request body for adding a member to a group
+type AddMemberRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	Nickname string `json:"nickname"`
+}
+
+// TransferOwnershipRequest is the request body for handing a group to another member
+type TransferOwnershipRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// JoinGroupRequest is the request body for joining a group by invite code
+type JoinGroupRequest struct {
+	InviteCode string `json:"invite_code" binding:"required"`
+}
+
+// GroupResponse is the API response for a group
+type GroupResponse struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	AvatarURL   string                `json:"avatar_url"`
+	CreatedBy   string                `json:"created_by"`
+	Members     []GroupMemberResponse `json:"members"`
+	InviteCode  string                `json:"invite_code"`
+	IsActive    bool                  `json:"is_active"`
+	Reminders   *ReminderSettings     `json:"reminders,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+
+	JoinApprovalRequired bool `json:"join_approval_required"`
+}
+
+// GroupMemberResponse is the API response for a group member
+type GroupMemberResponse struct {
+	UserID      string     `json:"user_id"`
+	Nickname    string     `json:"nickname"`
+	DisplayName string     `json:"display_name"`
+	AvatarURL   string     `json:"avatar_url"`
+	Role        MemberRole `json:"role"`
+	JoinedAt    time.Time  `json:"joined_at"`
+
+	RemindersSnoozedUntil *time.Time          `json:"reminders_snoozed_until,omitempty"`
+	InviteLinkID          *primitive.ObjectID `json:"invite_link_id,omitempty"`
+	Placeholder           bool                `json:"placeholder,omitempty"`
+}
+
+// MemberIDs returns the user IDs of the group's members
+func (g *Group) MemberIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(g.Members))
+	for i, member := range g.Members {
+		ids[i] = member.UserID
+	}
+	return ids
+}
+
+// Member returns the group member with the user ID, or nil if there is none
+func (g *Group) Member(userID primitive.ObjectID) *GroupMember {
+	for i := range g.Members {
+		if g.Members[i].UserID == userID {
+			return &g.Members[i]
+		}
+	}
+	return nil
+}
+
+// OwnerCount returns how many members own the group
+func (g *Group) OwnerCount() int {
+	count := 0
+	for _, member := range g.Members {
+		if member.Role == RoleOwner {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *Group) ToResponse() GroupResponse {
+	members := make([]GroupMemberResponse, len(g.Members))
+	for i, m := range g.Members {
+		members[i] = GroupMemberResponse{
+			UserID:   m.UserID.Hex(),
+			Nickname: m.Nickname,
+			Role:     m.Role,
+			JoinedAt: m.JoinedAt,
+
+			RemindersSnoozedUntil: m.RemindersSnoozedUntil,
+			InviteLinkID:          m.InviteLinkID,
+			Placeholder:           m.Placeholder,
+		}
+	}
+
+	return GroupResponse{
+		ID:          g.ID.Hex(),
+		Name:        g.Name,
+		Description: g.Description,
+		AvatarURL:   g.AvatarURL,
+		CreatedBy:   g.CreatedBy.Hex(),
+		Members:     members,
+		InviteCode:  g.InviteCode,
+		IsActive:    g.IsActive,
+		Reminders:   g.Reminders,
+		CreatedAt:   g.CreatedAt,
+
+		JoinApprovalRequired: g.JoinApprovalRequired,
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InviteLink lets people join a group with a code of its own. Unlike the
+// group's invite code it can expire, stop working after a number of uses,
+// and give the people who join with it a role other than member. A claim
+// link is made for one placeholder member: whoever joins with it takes
+// the placeholder's place.
+type InviteLink struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	GroupID   primitive.ObjectID `json:"group_id" bson:"group_id"`
+	Code      string             `json:"code" bson:"code"`
+	Role      MemberRole         `json:"role" bson:"role"`
+	MaxUses   int                `json:"max_uses" bson:"max_uses"` // 0 means no limit
+	Uses      int                `json:"uses" bson:"uses"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	RevokedAt *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+
+	// PlaceholderID makes this a claim link for that placeholder member
+	PlaceholderID *primitive.ObjectID `json:"placeholder_id,omitempty" bson:"placeholder_id,omitempty"`
+}
+
+// Expired reports whether the link's expiry has passed at now
+func (l *InviteLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// UsedUp reports whether the link has been used as often as allowed
+func (l *InviteLink) UsedUp() bool {
+	return l.MaxUses > 0 && l.Uses >= l.MaxUses
+}
+
+// CreateInviteLinkRequest is the request body for creating an invite link
+type CreateInviteLinkRequest struct {
+	// Role given to people who join; member if empty
+	Role MemberRole `json:"role" binding:"omitempty,oneof=admin member viewer"`
+	// MaxUses is how many people can join with the link; 0 means no limit
+	MaxUses int `json:"max_uses" binding:"min=0,max=1000"`
+	// ExpiresInHours is how long the link works; 0 means until revoked
+	ExpiresInHours int `json:"expires_in_hours" binding:"min=0,max=8760"`
+	// PlaceholderID makes a single-use link for claiming that placeholder
+	PlaceholderID string `json:"placeholder_id"`
+}
+
+// InviteLinkResponse is an invite link with the members who joined through
+// it. Code is empty for links the caller may not share.
+type InviteLinkResponse struct {
+	InviteLink
+	Joined []InviteJoin `json:"joined"`
+}
+
+// InviteJoin is a member who joined through an invite link
+type InviteJoin struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// InviteCodeResponse is the group's invite code after it was regenerated
+type InviteCodeResponse struct {
+	InviteCode string `json:"invite_code"`
+}
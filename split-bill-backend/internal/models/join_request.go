@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JoinRequestStatus is where a join request is in its review
+type JoinRequestStatus string
+
+const (
+	JoinRequestPending  JoinRequestStatus = "pending"
+	JoinRequestApproved JoinRequestStatus = "approved"
+	JoinRequestDenied   JoinRequestStatus = "denied"
+)
+
+// JoinRequest is someone asking to join a group that requires approval.
+// Role and InviteLinkID come from the invite link they used, if any, and
+// apply once the request is approved.
+type JoinRequest struct {
+	ID           primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	GroupID      primitive.ObjectID  `json:"group_id" bson:"group_id"`
+	UserID       primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	Role         MemberRole          `json:"role" bson:"role"`
+	InviteLinkID *primitive.ObjectID `json:"invite_link_id,omitempty" bson:"invite_link_id,omitempty"`
+	Status       JoinRequestStatus   `json:"status" bson:"status"`
+	DecidedBy    *primitive.ObjectID `json:"decided_by,omitempty" bson:"decided_by,omitempty"`
+	DecidedAt    *time.Time          `json:"decided_at,omitempty" bson:"decided_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at" bson:"created_at"`
+}
+
+// JoinRequestResponse is a join request with the names clients show
+type JoinRequestResponse struct {
+	JoinRequest
+	GroupName   string `json:"group_name"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is one recipient's copy of a notification in their in-app inbox.
+// It is stored whether or not a push reached any of their devices. Title and
+// Body are rendered from Key and Params in the reader's language; they are
+// stored as sent for notifications from before Key existed.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"-"`
+	Type      string             `bson:"type" json:"type"`
+	Title     string             `bson:"title" json:"title"`
+	Body      string             `bson:"body" json:"body"`
+	Key       string             `bson:"key,omitempty" json:"-"`    // message in the catalog, e.g. "bill_created"
+	Params    map[string]string  `bson:"params,omitempty" json:"-"` // fills the message's placeholders
+	Data      map[string]string  `bson:"data,omitempty" json:"data,omitempty"`
+	ReadAt    *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// UnreadCountResponse is the response for the unread badge
+type UnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// MarkAllReadResponse is the response for marking the whole inbox read
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
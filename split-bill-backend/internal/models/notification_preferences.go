@@ -0,0 +1,179 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultTimezone is used for quiet hours when the user has not set one
+const DefaultTimezone = "Asia/Ho_Chi_Minh"
+
+// NotificationPreferences decides which notifications reach a user outside
+// the in-app inbox, on which channels, and when. The inbox always gets
+// every notification.
+type NotificationPreferences struct {
+	Channels   map[string]bool            `bson:"channels,omitempty" json:"channels,omitempty"` // channel -> on; unlisted channels are on
+	Types      map[string]TypePreference  `bson:"types,omitempty" json:"types,omitempty"`       // by notification type
+	Groups     map[string]GroupPreference `bson:"groups,omitempty" json:"groups,omitempty"`     // by group ID
+	QuietHours *QuietHours                `bson:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+	Timezone   string                     `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA name, e.g. Asia/Ho_Chi_Minh
+	Digest     DigestFrequency            `bson:"digest,omitempty" json:"digest,omitempty"`     // group summaries; empty means off
+}
+
+// TypePreference controls one kind of notification
+type TypePreference struct {
+	Off      bool     `bson:"off,omitempty" json:"off,omitempty"`
+	Channels []string `bson:"channels,omitempty" json:"channels,omitempty"` // only these channels; empty means all
+}
+
+// GroupPreference controls the notifications from one group
+type GroupPreference struct {
+	Muted    bool     `bson:"muted,omitempty" json:"muted,omitempty"`
+	Channels []string `bson:"channels,omitempty" json:"channels,omitempty"` // only these channels; empty means all
+}
+
+// QuietHours is a daily period without notifications, in "HH:MM" local time.
+// It may span midnight, e.g. 22:00 to 07:00.
+type QuietHours struct {
+	Start string `bson:"start" json:"start" binding:"required"`
+	End   string `bson:"end" json:"end" binding:"required"`
+}
+
+// Allows reports whether a notification of type from groupID may go out on channel
+func (p *NotificationPreferences) Allows(channel, notifType, groupID string) bool {
+	if p == nil {
+		return true
+	}
+	if on, ok := p.Channels[channel]; ok && !on {
+		return false
+	}
+	if pref, ok := p.Types[notifType]; ok && (pref.Off || !listed(pref.Channels, channel)) {
+		return false
+	}
+	if pref, ok := p.Groups[groupID]; ok && groupID != "" && (pref.Muted || !listed(pref.Channels, channel)) {
+		return false
+	}
+	return true
+}
+
+// QuietUntil returns when the quiet period containing now ends, or the zero
+// time if now is outside quiet hours
+func (p *NotificationPreferences) QuietUntil(now time.Time) time.Time {
+	if p == nil || p.QuietHours == nil {
+		return time.Time{}
+	}
+	start, err := parseClock(p.QuietHours.Start)
+	if err != nil {
+		return time.Time{}
+	}
+	end, err := parseClock(p.QuietHours.End)
+	if err != nil || start == end {
+		return time.Time{}
+	}
+
+	local := now.In(p.Location())
+	minute := local.Hour()*60 + local.Minute()
+	endToday := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+
+	if start < end {
+		if minute >= start && minute < end {
+			return endToday
+		}
+		return time.Time{}
+	}
+
+	// The quiet period spans midnight
+	switch {
+	case minute >= start:
+		return endToday.AddDate(0, 0, 1)
+	case minute < end:
+		return endToday
+	}
+	return time.Time{}
+}
+
+// Location returns the user's timezone, falling back to Vietnam time
+func (p *NotificationPreferences) Location() *time.Location {
+	name := DefaultTimezone
+	if p != nil && p.Timezone != "" {
+		name = p.Timezone
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone("ICT", 7*60*60)
+}
+
+// DigestFrequency returns how often the user wants group digests
+func (p *NotificationPreferences) DigestFrequency() DigestFrequency {
+	if p == nil || p.Digest == "" {
+		return DigestOff
+	}
+	return p.Digest
+}
+
+// Validate checks the quiet hours, timezone and digest frequency
+func (p *NotificationPreferences) Validate() error {
+	switch p.Digest {
+	case "", DigestOff, DigestWeekly, DigestMonthly:
+	default:
+		return fmt.Errorf("unknown digest frequency %q, use off, weekly or monthly", p.Digest)
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", p.Timezone)
+		}
+	}
+	if p.QuietHours != nil {
+		start, err := parseClock(p.QuietHours.Start)
+		if err != nil {
+			return err
+		}
+		end, err := parseClock(p.QuietHours.End)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("quiet hours must not start and end at the same time")
+		}
+	}
+	return nil
+}
+
+// parseClock turns "HH:MM" into minutes after midnight
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func listed(channels []string, channel string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// DeferredNotification is a notification held back by the recipient's quiet
+// hours, to be delivered on the listed channels once they end
+type DeferredNotification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type      string             `bson:"type" json:"type"`
+	Title     string             `bson:"title" json:"title"`
+	Body      string             `bson:"body" json:"body"`
+	Data      map[string]string  `bson:"data,omitempty" json:"data,omitempty"`
+	HTML      string             `bson:"html,omitempty" json:"-"`
+	Channels  []string           `bson:"channels" json:"channels"`
+	DeliverAt time.Time          `bson:"deliver_at" json:"deliver_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
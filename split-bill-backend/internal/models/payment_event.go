@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentEventStatus represents the outcome of a payment webhook notification
+type PaymentEventStatus string
+
+const (
+	PaymentEventProcessing PaymentEventStatus = "processing" // recorded, matching not finished yet
+	PaymentEventMatched    PaymentEventStatus = "matched"    // confirmed a pending transaction
+	PaymentEventUnmatched  PaymentEventStatus = "unmatched"  // incoming, but no pending transaction fits
+	PaymentEventIgnored    PaymentEventStatus = "ignored"    // outgoing transfer, nothing to confirm
+)
+
+// PaymentEvent is one bank transfer reported by a payment aggregator webhook.
+// Provider and ProviderTxID are unique together, so redelivered notifications are dropped
+// unless matching did not finish the first time.
+type PaymentEvent struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Provider        string             `json:"provider" bson:"provider"`
+	ProviderTxID    string             `json:"provider_tx_id" bson:"provider_tx_id"`
+	Incoming        bool               `json:"incoming" bson:"incoming"`
+	Amount          float64            `json:"amount" bson:"amount"`
+	Description     string             `json:"description" bson:"description"`
+	AccountNumber   string             `json:"account_number,omitempty" bson:"account_number,omitempty"`
+	Bank            string             `json:"bank,omitempty" bson:"bank,omitempty"`
+	BankReference   string             `json:"bank_reference,omitempty" bson:"bank_reference,omitempty"`
+	TransactionTime time.Time          `json:"transaction_time" bson:"transaction_time"`
+	Status          PaymentEventStatus `json:"status" bson:"status"`
+	Reference       string             `json:"reference,omitempty" bson:"reference,omitempty"`
+	TransactionID   primitive.ObjectID `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	Reason          string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// PaymentWebhookResult summarizes one webhook delivery
+type PaymentWebhookResult struct {
+	Received   int            `json:"received"`
+	Duplicates int            `json:"duplicates"`
+	Matched    int            `json:"matched"`
+	Unmatched  int            `json:"unmatched"`
+	Events     []PaymentEvent `json:"events"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentReferenceKind tells what a payment reference points at
+type PaymentReferenceKind string
+
+const (
+	ReferenceTransaction PaymentReferenceKind = "transaction" // a pending transaction
+	ReferenceSettlement  PaymentReferenceKind = "settlement"  // a settle-up suggestion not yet paid
+)
+
+// PaymentReference is a short code (e.g. "SB7K2Q9M") that payers put in the
+// transfer note so the payment can be traced back from a bank statement,
+// an SMS or a bank webhook
+type PaymentReference struct {
+	ID            primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Code          string               `json:"code" bson:"code"`
+	Kind          PaymentReferenceKind `json:"kind" bson:"kind"`
+	GroupID       primitive.ObjectID   `json:"group_id" bson:"group_id"`
+	FromUser      primitive.ObjectID   `json:"from_user" bson:"from_user"`
+	ToUser        primitive.ObjectID   `json:"to_user" bson:"to_user"`
+	Amount        float64              `json:"amount" bson:"amount"`
+	TransactionID primitive.ObjectID   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	CreatedAt     time.Time            `json:"created_at" bson:"created_at"`
+
+	// Open marks the settle-up reference currently handed out for what
+	// FromUser owes ToUser; a unique index keeps it to one per pair
+	Open bool `json:"-" bson:"open,omitempty"`
+}
+
+// PaymentReferenceResponse is the result of looking up a reference
+type PaymentReferenceResponse struct {
+	Code        string               `json:"code"`
+	Kind        PaymentReferenceKind `json:"kind"`
+	GroupID     string               `json:"group_id"`
+	FromUser    string               `json:"from_user"`
+	ToUser      string               `json:"to_user"`
+	Amount      float64              `json:"amount"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+func (r *PaymentReference) ToResponse() PaymentReferenceResponse {
+	return PaymentReferenceResponse{
+		Code:      r.Code,
+		Kind:      r.Kind,
+		GroupID:   r.GroupID.Hex(),
+		FromUser:  r.FromUser.Hex(),
+		ToUser:    r.ToUser.Hex(),
+		Amount:    r.Amount,
+		CreatedAt: r.CreatedAt,
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+// Permission is something a group member may be allowed to do
+type Permission string
+
+const (
+	// PermViewGroup covers reading the group, its bills, balances, stats and feeds
+	PermViewGroup Permission = "view_group"
+	// PermRecordExpenses covers adding bills, scanning receipts and recording payments
+	PermRecordExpenses Permission = "record_expenses"
+	// PermEditOwnBills covers editing and deleting bills the member added
+	PermEditOwnBills Permission = "edit_own_bills"
+	// PermEditOthersBills covers editing and deleting bills someone else added
+	PermEditOthersBills Permission = "edit_others_bills"
+	PermAddMembers      Permission = "add_members"
+	// PermApproveJoins covers approving and denying requests to join
+	PermApproveJoins Permission = "approve_joins"
+	// PermRemoveMembers covers removing members ranked below oneself
+	PermRemoveMembers Permission = "remove_members"
+	// PermManageGroup covers the group's details, reminders and webhooks
+	PermManageGroup Permission = "manage_group"
+	// PermManageRoles covers promoting and demoting members ranked below oneself
+	PermManageRoles       Permission = "manage_roles"
+	PermTransferOwnership Permission = "transfer_ownership"
+	PermDeleteGroup       Permission = "delete_group"
+)
+
+// Permissions lists every permission, in the order clients should show them
+var Permissions = []Permission{
+	PermViewGroup,
+	PermRecordExpenses,
+	PermEditOwnBills,
+	PermEditOthersBills,
+	PermAddMembers,
+	PermApproveJoins,
+	PermRemoveMembers,
+	PermManageGroup,
+	PermManageRoles,
+	PermTransferOwnership,
+	PermDeleteGroup,
+}
+
+// Roles lists the member roles from the most to the least privileged
+var Roles = []MemberRole{RoleOwner, RoleAdmin, RoleMember, RoleViewer}
+
+// rolePermissions is the permission matrix. Viewers can only read.
+var rolePermissions = map[MemberRole][]Permission{
+	RoleOwner: Permissions,
+	RoleAdmin: {
+		PermViewGroup, PermRecordExpenses, PermEditOwnBills, PermEditOthersBills,
+		PermAddMembers, PermApproveJoins, PermRemoveMembers, PermManageGroup, PermManageRoles,
+	},
+	RoleMember: {PermViewGroup, PermRecordExpenses, PermEditOwnBills, PermAddMembers},
+	RoleViewer: {PermViewGroup},
+}
+
+// PermissionMatrix returns the permissions of every role
+func PermissionMatrix() map[MemberRole][]Permission {
+	matrix := make(map[MemberRole][]Permission, len(rolePermissions))
+	for role, perms := range rolePermissions {
+		matrix[role] = append([]Permission{}, perms...)
+	}
+	return matrix
+}
+
+// Valid reports whether r is a known role
+func (r MemberRole) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission
+func (r MemberRole) Can(perm Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// CanInviteAs reports whether r can create invite links that give role,
+// which for admin links takes being able to manage roles
+func (r MemberRole) CanInviteAs(role MemberRole) bool {
+	if role == RoleAdmin {
+		return r.Can(PermManageRoles)
+	}
+	return r.Can(PermAddMembers)
+}
+
+// Outranks reports whether r is strictly more privileged than other
+func (r MemberRole) Outranks(other MemberRole) bool {
+	return r.rank() > other.rank()
+}
+
+// Promoted returns the role one step up, stopping below owner since
+// ownership is handed over by transfer. ok is false if there is none.
+func (r MemberRole) Promoted() (MemberRole, bool) {
+	switch r {
+	case RoleViewer:
+		return RoleMember, true
+	case RoleMember:
+		return RoleAdmin, true
+	}
+	return r, false
+}
+
+// Demoted returns the role one step down. ok is false for viewers.
+func (r MemberRole) Demoted() (MemberRole, bool) {
+	switch r {
+	case RoleOwner:
+		return RoleAdmin, true
+	case RoleAdmin:
+		return RoleMember, true
+	case RoleMember:
+		return RoleViewer, true
+	}
+	return r, false
+}
+
+func (r MemberRole) rank() int {
+	for i, role := range Roles {
+		if role == r {
+			return len(Roles) - i
+		}
+	}
+	return 0
+}
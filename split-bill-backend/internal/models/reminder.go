@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReminderSchedule decides when a group's members are reminded of their debts
+type ReminderSchedule string
+
+const (
+	ReminderScheduleOff       ReminderSchedule = "off"
+	ReminderScheduleWeekly    ReminderSchedule = "weekly"     // every week on Weekday at Hour
+	ReminderScheduleAfterBill ReminderSchedule = "after_bill" // DaysAfterBill days after each bill a member still owes on
+)
+
+// ReminderSettings is a group's settlement reminder schedule.
+// Hours are local time (ICT).
+type ReminderSettings struct {
+	Schedule      ReminderSchedule `bson:"schedule" json:"schedule"`
+	Weekday       time.Weekday     `bson:"weekday" json:"weekday"` // 0 = Sunday
+	Hour          int              `bson:"hour" json:"hour"`
+	DaysAfterBill int              `bson:"days_after_bill" json:"days_after_bill"`
+	MinAmount     float64          `bson:"min_amount" json:"min_amount"` // smaller debts are not reminded
+}
+
+// UpdateReminderSettingsRequest is the request body for changing a group's reminder schedule
+type UpdateReminderSettingsRequest struct {
+	Schedule      ReminderSchedule `json:"schedule" binding:"required,oneof=off weekly after_bill"`
+	Weekday       int              `json:"weekday" binding:"min=0,max=6"`
+	Hour          *int             `json:"hour" binding:"omitempty,min=0,max=23"`
+	DaysAfterBill int              `json:"days_after_bill" binding:"min=0,max=90"`
+	MinAmount     float64          `json:"min_amount" binding:"min=0"`
+}
+
+// SnoozeRemindersRequest pauses reminders for a number of days; 0 resumes them
+type SnoozeRemindersRequest struct {
+	Days int `json:"days" binding:"min=0,max=30"`
+}
+
+// ReminderKind tells scheduled reminders from ones a member asked for
+type ReminderKind string
+
+const (
+	ReminderKindScheduled ReminderKind = "scheduled"
+	ReminderKindManual    ReminderKind = "manual" // "remind this person"
+)
+
+// ReminderDelivery logs a reminder that was sent. Key is unique per reminder
+// slot, so every instance of the server can run the scheduler and each
+// reminder still goes out once.
+type ReminderDelivery struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Key         string             `json:"-" bson:"key"`
+	Kind        ReminderKind       `json:"kind" bson:"kind"`
+	GroupID     primitive.ObjectID `json:"group_id" bson:"group_id"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
+	RequestedBy primitive.ObjectID `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	Amount      float64            `json:"amount" bson:"amount"`
+	Level       int                `json:"level" bson:"level"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StatementImport records one uploaded bank statement file
+type StatementImport struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID         primitive.ObjectID `json:"user_id" bson:"user_id"`
+	GroupID        primitive.ObjectID `json:"group_id,omitempty" bson:"group_id,omitempty"`
+	Bank           string             `json:"bank" bson:"bank"`
+	FileName       string             `json:"file_name" bson:"file_name"`
+	TotalLines     int                `json:"total_lines" bson:"total_lines"`
+	IncomingLines  int                `json:"incoming_lines" bson:"incoming_lines"`
+	DuplicateLines int                `json:"duplicate_lines" bson:"duplicate_lines"`
+	MatchedLines   int                `json:"matched_lines" bson:"matched_lines"`
+	UnmatchedLines int                `json:"unmatched_lines" bson:"unmatched_lines"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// StatementLineStatus represents where a statement line is in reconciliation
+type StatementLineStatus string
+
+const (
+	StatementLineUnmatched StatementLineStatus = "unmatched" // waiting in the review queue
+	StatementLineMatched   StatementLineStatus = "matched"   // auto-confirmed by reconciliation
+	StatementLineLinked    StatementLineStatus = "linked"    // linked to a transaction by hand
+	StatementLineIgnored   StatementLineStatus = "ignored"   // dismissed as unrelated
+)
+
+// StatementMatchReason explains why a line was matched automatically
+type StatementMatchReason string
+
+const (
+	MatchByReference  StatementMatchReason = "reference"   // payment reference found in description, amount equal
+	MatchByAmountDate StatementMatchReason = "amount_date" // only pending transaction with this amount in the date window
+)
+
+// StatementLine is one incoming transfer from an imported bank statement
+type StatementLine struct {
+	ID            primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	ImportID      primitive.ObjectID   `json:"import_id" bson:"import_id"`
+	UserID        primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	GroupID       primitive.ObjectID   `json:"group_id,omitempty" bson:"group_id,omitempty"`
+	Bank          string               `json:"bank" bson:"bank"`
+	Row           int                  `json:"row" bson:"row"`
+	Date          time.Time            `json:"date" bson:"date"`
+	Amount        float64              `json:"amount" bson:"amount"`
+	Description   string               `json:"description" bson:"description"`
+	BankReference string               `json:"bank_reference,omitempty" bson:"bank_reference,omitempty"`
+	Fingerprint   string               `json:"-" bson:"fingerprint"`
+	Status        StatementLineStatus  `json:"status" bson:"status"`
+	TransactionID primitive.ObjectID   `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	MatchReason   StatementMatchReason `json:"match_reason,omitempty" bson:"match_reason,omitempty"`
+	Candidates    []primitive.ObjectID `json:"candidates,omitempty" bson:"candidates,omitempty"`
+	CreatedAt     time.Time            `json:"created_at" bson:"created_at"`
+	ResolvedAt    *time.Time           `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+}
+
+// StatementImportResult is returned after uploading a statement
+type StatementImportResult struct {
+	Import    StatementImport `json:"import"`
+	Matched   []StatementLine `json:"matched"`
+	Unmatched []StatementLine `json:"unmatched"`
+}
+
+// LinkStatementLineRequest links a review queue line to a pending transaction
+type LinkStatementLineRequest struct {
+	TransactionID string `json:"transaction_id" binding:"required"`
+}
@@ -1,113 +1,209 @@
-package models
-
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// TransactionType represents the type of transaction
-type TransactionType string
-
-const (
-	TransactionPayment    TransactionType = "payment"
-	TransactionSettlement TransactionType = "settlement"
-)
-
-// TransactionStatus represents the status of a transaction
-type TransactionStatus string
-
-const (
-	TransactionPending   TransactionStatus = "pending"
-	TransactionConfirmed TransactionStatus = "confirmed"
-	TransactionRejected  TransactionStatus = "rejected"
-)
-
-// Transaction represents a payment between users
-type Transaction struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	GroupID         primitive.ObjectID `bson:"group_id" json:"group_id"`
-	FromUser        primitive.ObjectID `bson:"from_user" json:"from_user"`
-	ToUser          primitive.ObjectID `bson:"to_user" json:"to_user"`
-	Amount          float64            `bson:"amount" json:"amount"`
-	Currency        string             `bson:"currency" json:"currency"`
-	BillID          primitive.ObjectID `bson:"bill_id,omitempty" json:"bill_id,omitempty"`
-	Type            TransactionType    `bson:"type" json:"type"`
-	Status          TransactionStatus  `bson:"status" json:"status"`
-	PaymentMethod   string             `bson:"payment_method" json:"payment_method"`
-	PaymentProofURL string             `bson:"payment_proof_url" json:"payment_proof_url"`
-	Note            string             `bson:"note" json:"note"`
-	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
-	ConfirmedAt     *time.Time         `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
-}
-
-// CreateTransactionRequest is the request body for creating a transaction
-type CreateTransactionRequest struct {
-	GroupID         string  `json:"group_id" binding:"required"`
-	ToUser          string  `json:"to_user" binding:"required"`
-	Amount          float64 `json:"amount" binding:"required,gt=0"`
-	Currency        string  `json:"currency" binding:"required"`
-	BillID          string  `json:"bill_id"`
-	PaymentMethod   string  `json:"payment_method"`
-	PaymentProofURL string  `json:"payment_proof_url"`
-	Note            string  `json:"note" binding:"max=500"`
-}
-
-// TransactionResponse is the API response for a transaction
-type TransactionResponse struct {
-	ID              string            `json:"id"`
-	GroupID         string            `json:"group_id"`
-	FromUser        string            `json:"from_user"`
-	FromUserName    string            `json:"from_user_name"`
-	ToUser          string            `json:"to_user"`
-	ToUserName      string            `json:"to_user_name"`
-	Amount          float64           `json:"amount"`
-	Currency        string            `json:"currency"`
-	BillID          string            `json:"bill_id,omitempty"`
-	Type            TransactionType   `json:"type"`
-	Status          TransactionStatus `json:"status"`
-	PaymentMethod   string            `json:"payment_method"`
-	PaymentProofURL string            `json:"payment_proof_url"`
-	Note            string            `json:"note"`
-	CreatedAt       time.Time         `json:"created_at"`
-	ConfirmedAt     *time.Time        `json:"confirmed_at,omitempty"`
-}
-
-func (t *Transaction) ToResponse() TransactionResponse {
-	billID := ""
-	if !t.BillID.IsZero() {
-		billID = t.BillID.Hex()
-	}
-	return TransactionResponse{
-		ID:              t.ID.Hex(),
-		GroupID:         t.GroupID.Hex(),
-		FromUser:        t.FromUser.Hex(),
-		ToUser:          t.ToUser.Hex(),
-		Amount:          t.Amount,
-		Currency:        t.Currency,
-		BillID:          billID,
-		Type:            t.Type,
-		Status:          t.Status,
-		PaymentMethod:   t.PaymentMethod,
-		PaymentProofURL: t.PaymentProofURL,
-		Note:            t.Note,
-		CreatedAt:       t.CreatedAt,
-		ConfirmedAt:     t.ConfirmedAt,
-	}
-}
-
-// Settlement represents an optimized payment suggestion
-type Settlement struct {
-	FromUserID   string  `json:"from_user_id"`
-	FromUserName string  `json:"from_user_name"`
-	ToUserID     string  `json:"to_user_id"`
-	ToUserName   string  `json:"to_user_name"`
-	Amount       float64 `json:"amount"`
-}
-
-// BalanceResponse represents the balance info for a user in a group
-type BalanceResponse struct {
-	UserID      string  `json:"user_id"`
-	DisplayName string  `json:"display_name"`
-	Balance     float64 `json:"balance"` // positive = owed money, negative = owes money
-}
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransactionType represents the type of transaction
+type TransactionType string
+
+const (
+	TransactionPayment    TransactionType = "payment"
+	TransactionSettlement TransactionType = "settlement"
+	TransactionReversal   TransactionType = "reversal"
+)
+
+// TransactionStatus represents the status of a transaction
+type TransactionStatus string
+
+const (
+	TransactionPending   TransactionStatus = "pending"
+	TransactionConfirmed TransactionStatus = "confirmed"
+	TransactionRejected  TransactionStatus = "rejected"
+	TransactionCancelled TransactionStatus = "cancelled"
+)
+
+// TransactionRevisionAction describes what happened to a transaction in its history
+type TransactionRevisionAction string
+
+const (
+	RevisionEdited    TransactionRevisionAction = "edited"
+	RevisionCancelled TransactionRevisionAction = "cancelled"
+)
+
+// TransactionRevision is a snapshot of a transaction taken before it was changed
+type TransactionRevision struct {
+	Action          TransactionRevisionAction `bson:"action" json:"action"`
+	Amount          float64                   `bson:"amount" json:"amount"`
+	Currency        string                    `bson:"currency" json:"currency"`
+	PaymentMethod   string                    `bson:"payment_method" json:"payment_method"`
+	PaymentProofURL string                    `bson:"payment_proof_url" json:"payment_proof_url"`
+	Note            string                    `bson:"note" json:"note"`
+	Reason          string                    `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedBy       primitive.ObjectID        `bson:"changed_by" json:"changed_by"`
+	ChangedAt       time.Time                 `bson:"changed_at" json:"changed_at"`
+}
+
+// Transaction represents a payment between users
+type Transaction struct {
+	ID              primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	GroupID         primitive.ObjectID    `bson:"group_id" json:"group_id"`
+	FromUser        primitive.ObjectID    `bson:"from_user" json:"from_user"`
+	ToUser          primitive.ObjectID    `bson:"to_user" json:"to_user"`
+	Amount          float64               `bson:"amount" json:"amount"`
+	Currency        string                `bson:"currency" json:"currency"`
+	BillID          primitive.ObjectID    `bson:"bill_id,omitempty" json:"bill_id,omitempty"`
+	Type            TransactionType       `bson:"type" json:"type"`
+	Status          TransactionStatus     `bson:"status" json:"status"`
+	PaymentMethod   string                `bson:"payment_method" json:"payment_method"`
+	PaymentProofURL string                `bson:"payment_proof_url" json:"payment_proof_url"`
+	Note            string                `bson:"note" json:"note"`
+	Reference       string                `bson:"reference,omitempty" json:"reference,omitempty"`
+	ReversalOf      primitive.ObjectID    `bson:"reversal_of,omitempty" json:"reversal_of,omitempty"`
+	ReversedBy      primitive.ObjectID    `bson:"reversed_by,omitempty" json:"reversed_by,omitempty"`
+	History         []TransactionRevision `bson:"history,omitempty" json:"history,omitempty"`
+	CreatedAt       time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `bson:"updated_at" json:"updated_at"`
+	ConfirmedAt     *time.Time            `bson:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	CancelledAt     *time.Time            `bson:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+}
+
+// Approver returns the user who has to accept the transaction.
+// Reversals send the money back, so the original recipient (now the
+// sender of the reversal) is the one who approves them.
+func (t *Transaction) Approver() primitive.ObjectID {
+	if t.Type == TransactionReversal {
+		return t.FromUser
+	}
+	return t.ToUser
+}
+
+// Snapshot captures the editable fields of the transaction as a history entry
+func (t *Transaction) Snapshot(action TransactionRevisionAction, changedBy primitive.ObjectID, reason string) TransactionRevision {
+	return TransactionRevision{
+		Action:          action,
+		Amount:          t.Amount,
+		Currency:        t.Currency,
+		PaymentMethod:   t.PaymentMethod,
+		PaymentProofURL: t.PaymentProofURL,
+		Note:            t.Note,
+		Reason:          reason,
+		ChangedBy:       changedBy,
+		ChangedAt:       time.Now(),
+	}
+}
+
+// CreateTransactionRequest is the request body for creating a transaction
+type CreateTransactionRequest struct {
+	GroupID         string  `json:"group_id" binding:"required"`
+	ToUser          string  `json:"to_user" binding:"required"`
+	Amount          float64 `json:"amount" binding:"required,gt=0"`
+	Currency        string  `json:"currency" binding:"required"`
+	BillID          string  `json:"bill_id"`
+	PaymentMethod   string  `json:"payment_method"`
+	PaymentProofURL string  `json:"payment_proof_url"`
+	Note            string  `json:"note" binding:"max=500"`
+	Reference       string  `json:"reference"` // settle-up item reference this transaction pays, if any
+}
+
+// UpdateTransactionRequest is the request body for amending a pending transaction
+type UpdateTransactionRequest struct {
+	Amount          *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Currency        string   `json:"currency"`
+	PaymentMethod   *string  `json:"payment_method"`
+	PaymentProofURL *string  `json:"payment_proof_url"`
+	Note            *string  `json:"note" binding:"omitempty,max=500"`
+	Reason          string   `json:"reason" binding:"max=500"`
+}
+
+// CancelTransactionRequest is the request body for cancelling a pending transaction
+type CancelTransactionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// ReverseTransactionRequest is the request body for reversing a confirmed transaction
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// TransactionResponse is the API response for a transaction
+type TransactionResponse struct {
+	ID              string                `json:"id"`
+	GroupID         string                `json:"group_id"`
+	FromUser        string                `json:"from_user"`
+	FromUserName    string                `json:"from_user_name"`
+	ToUser          string                `json:"to_user"`
+	ToUserName      string                `json:"to_user_name"`
+	Amount          float64               `json:"amount"`
+	Currency        string                `json:"currency"`
+	BillID          string                `json:"bill_id,omitempty"`
+	Type            TransactionType       `json:"type"`
+	Status          TransactionStatus     `json:"status"`
+	PaymentMethod   string                `json:"payment_method"`
+	PaymentProofURL string                `json:"payment_proof_url"`
+	Note            string                `json:"note"`
+	Reference       string                `json:"reference,omitempty"`
+	ReversalOf      string                `json:"reversal_of,omitempty"`
+	ReversedBy      string                `json:"reversed_by,omitempty"`
+	History         []TransactionRevision `json:"history,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+	ConfirmedAt     *time.Time            `json:"confirmed_at,omitempty"`
+	CancelledAt     *time.Time            `json:"cancelled_at,omitempty"`
+}
+
+func (t *Transaction) ToResponse() TransactionResponse {
+	billID := ""
+	if !t.BillID.IsZero() {
+		billID = t.BillID.Hex()
+	}
+	reversalOf := ""
+	if !t.ReversalOf.IsZero() {
+		reversalOf = t.ReversalOf.Hex()
+	}
+	reversedBy := ""
+	if !t.ReversedBy.IsZero() {
+		reversedBy = t.ReversedBy.Hex()
+	}
+	return TransactionResponse{
+		ID:              t.ID.Hex(),
+		GroupID:         t.GroupID.Hex(),
+		FromUser:        t.FromUser.Hex(),
+		ToUser:          t.ToUser.Hex(),
+		Amount:          t.Amount,
+		Currency:        t.Currency,
+		BillID:          billID,
+		Type:            t.Type,
+		Status:          t.Status,
+		PaymentMethod:   t.PaymentMethod,
+		PaymentProofURL: t.PaymentProofURL,
+		Note:            t.Note,
+		Reference:       t.Reference,
+		ReversalOf:      reversalOf,
+		ReversedBy:      reversedBy,
+		History:         t.History,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+		ConfirmedAt:     t.ConfirmedAt,
+		CancelledAt:     t.CancelledAt,
+	}
+}
+
+// Settlement represents an optimized payment suggestion
+type Settlement struct {
+	FromUserID   string  `json:"from_user_id"`
+	FromUserName string  `json:"from_user_name"`
+	ToUserID     string  `json:"to_user_id"`
+	ToUserName   string  `json:"to_user_name"`
+	Amount       float64 `json:"amount"`
+	Reference    string  `json:"reference,omitempty"` // payment reference to put in the transfer note
+}
+
+// BalanceResponse represents the balance info for a user in a group
+type BalanceResponse struct {
+	UserID      string  `json:"user_id"`
+	DisplayName string  `json:"display_name"`
+	Balance     float64 `json:"balance"` // positive = owed money, negative = owes money
+}
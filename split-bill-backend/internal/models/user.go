@@ -1,65 +1,111 @@
-package models
-
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
-// BankAccount represents a user's bank account info
-type BankAccount struct {
-	BankCode      string `bson:"bank_code" json:"bank_code"`
-	AccountNumber string `bson:"account_number" json:"account_number"`
-	AccountName   string `bson:"account_name" json:"account_name"`
-}
-
-// User represents a user in the system
-type User struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	FirebaseUID      string             `bson:"firebase_uid" json:"firebase_uid"`
-	Phone            string             `bson:"phone" json:"phone"`
-	DisplayName      string             `bson:"display_name" json:"display_name"`
-	AvatarURL        string             `bson:"avatar_url" json:"avatar_url"`
-	BankAccounts     []BankAccount      `bson:"bank_accounts" json:"bank_accounts"`
-	PreferredPayment string             `bson:"preferred_payment" json:"preferred_payment"`
-	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
-}
-
-// CreateUserRequest is the request body for creating/updating a user
-type CreateUserRequest struct {
-	Phone       string `json:"phone" binding:"required"`
-	DisplayName string `json:"display_name" binding:"required,min=2,max=50"`
-	AvatarURL   string `json:"avatar_url"`
-}
-
-// UpdateUserRequest is the request body for updating a user profile
-type UpdateUserRequest struct {
-	DisplayName      string        `json:"display_name" binding:"omitempty,min=2,max=50"`
-	AvatarURL        string        `json:"avatar_url"`
-	BankAccounts     []BankAccount `json:"bank_accounts"`
-	PreferredPayment string        `json:"preferred_payment"`
-}
-
-// UserResponse is the response for user info
-type UserResponse struct {
-	ID               string        `json:"id"`
-	Phone            string        `json:"phone"`
-	DisplayName      string        `json:"display_name"`
-	AvatarURL        string        `json:"avatar_url"`
-	BankAccounts     []BankAccount `json:"bank_accounts"`
-	PreferredPayment string        `json:"preferred_payment"`
-	CreatedAt        time.Time     `json:"created_at"`
-}
-
-func (u *User) ToResponse() UserResponse {
-	return UserResponse{
-		ID:               u.ID.Hex(),
-		Phone:            u.Phone,
-		DisplayName:      u.DisplayName,
-		AvatarURL:        u.AvatarURL,
-		BankAccounts:     u.BankAccounts,
-		PreferredPayment: u.PreferredPayment,
-		CreatedAt:        u.CreatedAt,
-	}
-}
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BankAccount represents a user's bank account info
+type BankAccount struct {
+	BankCode      string `bson:"bank_code" json:"bank_code"`
+	AccountNumber string `bson:"account_number" json:"account_number"`
+	AccountName   string `bson:"account_name" json:"account_name"`
+}
+
+// User represents a user in the system
+type User struct {
+	ID                      primitive.ObjectID       `bson:"_id,omitempty" json:"id"`
+	FirebaseUID             string                   `bson:"firebase_uid" json:"firebase_uid"`
+	Phone                   string                   `bson:"phone" json:"phone"`
+	DisplayName             string                   `bson:"display_name" json:"display_name"`
+	AvatarURL               string                   `bson:"avatar_url" json:"avatar_url"`
+	BankAccounts            []BankAccount            `bson:"bank_accounts" json:"bank_accounts"`
+	PreferredPayment        string                   `bson:"preferred_payment" json:"preferred_payment"`
+	Email                   string                   `bson:"email" json:"email,omitempty"`
+	ZaloUserID              string                   `bson:"zalo_user_id" json:"zalo_user_id,omitempty"` // follower ID of our Zalo Official Account
+	WebhookURL              string                   `bson:"webhook_url" json:"webhook_url,omitempty"`   // personal notification webhook, e.g. Slack or Discord
+	NotificationPreferences *NotificationPreferences `bson:"notification_preferences,omitempty" json:"notification_preferences,omitempty"`
+	Language                string                   `bson:"language,omitempty" json:"language,omitempty"` // "vi" or "en"; empty follows the device
+	Devices                 []Device                 `bson:"devices,omitempty" json:"-"`
+	CreatedAt               time.Time                `bson:"created_at" json:"created_at"`
+	UpdatedAt               time.Time                `bson:"updated_at" json:"updated_at"`
+
+	// Placeholder users stand in for someone without an account in one
+	// group until they claim it; ClaimedBy is the account that did
+	Placeholder bool                `bson:"placeholder,omitempty" json:"placeholder,omitempty"`
+	ClaimedBy   *primitive.ObjectID `bson:"claimed_by,omitempty" json:"claimed_by,omitempty"`
+}
+
+// MaxDevicesPerUser caps the push tokens kept per user; the least recently seen go first
+const MaxDevicesPerUser = 10
+
+// Device is a phone or browser registered for push notifications
+type Device struct {
+	Token      string    `bson:"token" json:"token"`
+	Platform   string    `bson:"platform" json:"platform"` // ios, android, web
+	AppVersion string    `bson:"app_version,omitempty" json:"app_version,omitempty"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	LastSeenAt time.Time `bson:"last_seen_at" json:"last_seen_at"`
+}
+
+// RegisterDeviceRequest is the request body for registering a push token
+type RegisterDeviceRequest struct {
+	Token      string `json:"token" binding:"required,max=4096"`
+	Platform   string `json:"platform" binding:"required,oneof=ios android web"`
+	AppVersion string `json:"app_version" binding:"max=32"`
+}
+
+// CreateUserRequest is the request body for creating/updating a user
+type CreateUserRequest struct {
+	Phone       string `json:"phone" binding:"required"`
+	DisplayName string `json:"display_name" binding:"required,min=2,max=50"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// UpdateUserRequest is the request body for updating a user profile.
+// For the notification addresses, an empty string removes the address.
+type UpdateUserRequest struct {
+	DisplayName             string                   `json:"display_name" binding:"omitempty,min=2,max=50"`
+	AvatarURL               string                   `json:"avatar_url"`
+	BankAccounts            []BankAccount            `json:"bank_accounts"`
+	PreferredPayment        string                   `json:"preferred_payment"`
+	Email                   *string                  `json:"email" binding:"omitempty,max=254"`
+	ZaloUserID              *string                  `json:"zalo_user_id" binding:"omitempty,max=64"`
+	WebhookURL              *string                  `json:"webhook_url" binding:"omitempty,max=2048"`
+	NotificationPreferences *NotificationPreferences `json:"notification_preferences"` // replaces the saved preferences
+	Language                *string                  `json:"language" binding:"omitempty,oneof=vi en"`
+}
+
+// UserResponse is the response for user info
+type UserResponse struct {
+	ID                      string                   `json:"id"`
+	Phone                   string                   `json:"phone"`
+	DisplayName             string                   `json:"display_name"`
+	AvatarURL               string                   `json:"avatar_url"`
+	BankAccounts            []BankAccount            `json:"bank_accounts"`
+	PreferredPayment        string                   `json:"preferred_payment"`
+	Email                   string                   `json:"email,omitempty"`
+	ZaloUserID              string                   `json:"zalo_user_id,omitempty"`
+	WebhookURL              string                   `json:"webhook_url,omitempty"`
+	NotificationPreferences *NotificationPreferences `json:"notification_preferences,omitempty"`
+	Language                string                   `json:"language,omitempty"`
+	CreatedAt               time.Time                `json:"created_at"`
+}
+
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:                      u.ID.Hex(),
+		Phone:                   u.Phone,
+		DisplayName:             u.DisplayName,
+		AvatarURL:               u.AvatarURL,
+		BankAccounts:            u.BankAccounts,
+		PreferredPayment:        u.PreferredPayment,
+		Email:                   u.Email,
+		ZaloUserID:              u.ZaloUserID,
+		WebhookURL:              u.WebhookURL,
+		NotificationPreferences: u.NotificationPreferences,
+		Language:                u.Language,
+		CreatedAt:               u.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GroupWebhook sends a group's events to a URL of the admins' choosing,
+// e.g. a spreadsheet script or a chat bot. Events lists the event names
+// it wants, such as "bill.created"; empty means all of them.
+type GroupWebhook struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	GroupID     primitive.ObjectID `json:"group_id" bson:"group_id"`
+	URL         string             `json:"url" bson:"url"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Events      []string           `json:"events" bson:"events"`
+	Secret      string             `json:"-" bson:"secret"` // HMAC key for the signature header
+	Active      bool               `json:"active" bson:"active"`
+	CreatedBy   primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// Wants reports whether the webhook subscribes to an event
+func (w *GroupWebhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, name := range w.Events {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupWebhookWithSecret is returned when a webhook is created or its
+// secret rotated, the only times the secret is shown
+type GroupWebhookWithSecret struct {
+	GroupWebhook
+	Secret string `json:"secret,omitempty"`
+}
+
+// CreateWebhookRequest is the request body for registering a group webhook
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	Description string   `json:"description" binding:"max=200"`
+	Events      []string `json:"events"`
+}
+
+// UpdateWebhookRequest changes a group webhook. Omitted fields stay as they are.
+type UpdateWebhookRequest struct {
+	URL          *string   `json:"url" binding:"omitempty,url,max=2048"`
+	Description  *string   `json:"description" binding:"omitempty,max=200"`
+	Events       *[]string `json:"events"`
+	Active       *bool     `json:"active"`
+	RotateSecret bool      `json:"rotate_secret"`
+}
+
+// WebhookDeliveryStatus tracks a delivery through its attempts
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // waiting for its next attempt
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered" // the endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // gave up after the last attempt (dead letter)
+)
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook, with the
+// outcome of the latest attempt. Failed deliveries are kept as the dead
+// letters of the webhook and can be redelivered.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID    `json:"id" bson:"_id,omitempty"`
+	WebhookID     primitive.ObjectID    `json:"webhook_id" bson:"webhook_id"`
+	GroupID       primitive.ObjectID    `json:"group_id" bson:"group_id"`
+	Event         string                `json:"event" bson:"event"`
+	Payload       string                `json:"payload" bson:"payload"` // the exact body that is signed and sent
+	Test          bool                  `json:"test,omitempty" bson:"test,omitempty"`
+	Status        WebhookDeliveryStatus `json:"status" bson:"status"`
+	Attempts      int                   `json:"attempts" bson:"attempts"`
+	NextAttemptAt *time.Time            `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	ResponseCode  int                   `json:"response_code,omitempty" bson:"response_code,omitempty"`
+	ResponseBody  string                `json:"response_body,omitempty" bson:"response_body,omitempty"` // first KB of the last response
+	LastError     string                `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	DurationMs    int64                 `json:"duration_ms,omitempty" bson:"duration_ms,omitempty"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at" bson:"updated_at"`
+}
+
+// WebhookPayload is the JSON body of every delivery. ID identifies the
+// delivery and stays the same across retries, so receivers can drop
+// duplicates.
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	GroupID   string      `json:"group_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
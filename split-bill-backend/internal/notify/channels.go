@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"github.com/splitbill/backend/pkg/mailer"
+	"github.com/splitbill/backend/pkg/push"
+	"github.com/splitbill/backend/pkg/safehttp"
+	"github.com/splitbill/backend/pkg/zalo"
+)
+
+// PushChannel sends to the recipients' registered devices
+type PushChannel struct {
+	sender push.Sender
+	prune  func(ctx context.Context, tokens []string) error
+}
+
+// NewPushChannel creates a push channel. prune is called with the tokens the
+// push service no longer accepts.
+func NewPushChannel(sender push.Sender, prune func(ctx context.Context, tokens []string) error) *PushChannel {
+	return &PushChannel{sender: sender, prune: prune}
+}
+
+func (c *PushChannel) Name() ChannelName { return ChannelPush }
+
+func (c *PushChannel) Reaches(recipient Recipient) bool {
+	return len(recipient.DeviceTokens) > 0
+}
+
+// Deliver sends one multicast to every device of every recipient
+func (c *PushChannel) Deliver(ctx context.Context, msg Message, recipients []Recipient) error {
+	var tokens []string
+	for _, recipient := range recipients {
+		tokens = append(tokens, recipient.DeviceTokens...)
+	}
+
+	result, err := c.sender.Send(ctx, push.Message{
+		Tokens: tokens,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		Data:   msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(result.InvalidTokens) > 0 && c.prune != nil {
+		if err := c.prune(ctx, result.InvalidTokens); err != nil {
+			return fmt.Errorf("prune invalid tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+// EmailChannel sends each recipient an HTML email with a plain text alternative
+type EmailChannel struct {
+	mailer *mailer.Mailer
+}
+
+func NewEmailChannel(m *mailer.Mailer) *EmailChannel {
+	return &EmailChannel{mailer: m}
+}
+
+func (c *EmailChannel) Name() ChannelName { return ChannelEmail }
+
+func (c *EmailChannel) Reaches(recipient Recipient) bool {
+	return recipient.Email != ""
+}
+
+// Deliver sends one email per recipient, so addresses are never shared
+func (c *EmailChannel) Deliver(ctx context.Context, msg Message, recipients []Recipient) error {
+	var errs []error
+	for _, recipient := range recipients {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		email, err := renderEmail(msg, recipient)
+		if err != nil {
+			return err
+		}
+		if err := c.mailer.Send(mailer.Email{
+			To:      []string{recipient.Email},
+			Subject: email.Subject,
+			Text:    email.Text,
+			HTML:    email.HTML,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ZaloChannel messages recipients who follow the Zalo Official Account
+type ZaloChannel struct {
+	client *zalo.Client
+}
+
+func NewZaloChannel(client *zalo.Client) *ZaloChannel {
+	return &ZaloChannel{client: client}
+}
+
+func (c *ZaloChannel) Name() ChannelName { return ChannelZalo }
+
+func (c *ZaloChannel) Reaches(recipient Recipient) bool {
+	return recipient.ZaloUserID != ""
+}
+
+func (c *ZaloChannel) Deliver(ctx context.Context, msg Message, recipients []Recipient) error {
+	var errs []error
+	for _, recipient := range recipients {
+		text, err := renderZalo(msg, recipient)
+		if err != nil {
+			return err
+		}
+		if _, err := c.client.SendText(ctx, recipient.ZaloUserID, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookChannel posts a JSON payload to the recipient's own webhook URL,
+// e.g. a Slack or Discord incoming webhook
+type WebhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel posts with a client that, since the URLs are chosen by
+// users, refuses private addresses unless allowPrivate is set and does not
+// follow redirects
+func NewWebhookChannel(timeout time.Duration, allowPrivate bool) *WebhookChannel {
+	return &WebhookChannel{client: safehttp.NewClient(timeout, allowPrivate)}
+}
+
+func (c *WebhookChannel) Name() ChannelName { return ChannelWebhook }
+
+func (c *WebhookChannel) Reaches(recipient Recipient) bool {
+	return recipient.WebhookURL != ""
+}
+
+func (c *WebhookChannel) Deliver(ctx context.Context, msg Message, recipients []Recipient) error {
+	body, err := renderWebhook(msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		if err := c.post(ctx, recipient.WebhookURL, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *WebhookChannel) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SplitBill-Notifications/1.0")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Webhook URLs carry their own secret, so keep them out of logs
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("webhook %s failed: %w", urlErr.Op, urlErr.Err)
+		}
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
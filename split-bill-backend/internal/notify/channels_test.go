@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/splitbill/backend/pkg/safehttp"
+)
+
+func TestWebhookChannelRefusesPrivateAddresses(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+
+	msg := Message{Type: "payment_reminder", Title: "Nhắc thanh toán", Body: "Bạn còn nợ 150.000₫"}
+	recipients := []Recipient{{UserID: "u1", WebhookURL: server.URL + "/hooks/secret-token"}}
+
+	err := NewWebhookChannel(5*time.Second, false).Deliver(context.Background(), msg, recipients)
+	if !errors.Is(err, safehttp.ErrPrivateAddress) {
+		t.Errorf("Deliver to %s: err = %v, want ErrPrivateAddress", server.URL, err)
+	}
+	if err != nil && strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("err = %v, leaks the webhook URL", err)
+	}
+	if received != 0 {
+		t.Errorf("server received %d requests, want none", received)
+	}
+
+	// Allowed for local development
+	if err := NewWebhookChannel(5*time.Second, true).Deliver(context.Background(), msg, recipients); err != nil {
+		t.Errorf("Deliver with private networks allowed: %v", err)
+	}
+	if received != 1 {
+		t.Errorf("server received %d requests, want 1", received)
+	}
+}
+
+func TestWebhookChannelDoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	err := NewWebhookChannel(5*time.Second, true).Deliver(context.Background(),
+		Message{Title: "Hi"}, []Recipient{{WebhookURL: server.URL}})
+	if err == nil || !strings.Contains(err.Error(), "307") {
+		t.Errorf("err = %v, want the redirect reported as a failure", err)
+	}
+	if redirected {
+		t.Error("the redirect was followed")
+	}
+}
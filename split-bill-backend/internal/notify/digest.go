@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+)
+
+var digestTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(htmltemplate.FuncMap{
+	"vnd":         fullVND,
+	"abs":         math.Abs,
+	"date":        func(t time.Time) string { return t.In(vietnamTime).Format("02/01") },
+	"periodLabel": digestPeriodLabel,
+}).ParseFS(templateFiles, "templates/digest.html"))
+
+var vietnamTime = time.FixedZone("ICT", 7*60*60)
+
+// RenderDigestHTML renders a group digest for email. Send it as
+// Message.HTML and the email channel puts it in its usual layout.
+func RenderDigestHTML(digest *models.Digest) (string, error) {
+	var html bytes.Buffer
+	if err := digestTemplate.Execute(&html, digest); err != nil {
+		return "", err
+	}
+	return html.String(), nil
+}
+
+func digestPeriodLabel(digest *models.Digest) string {
+	last := digest.PeriodEnd.Add(-time.Second).In(vietnamTime)
+	start := digest.PeriodStart.In(vietnamTime)
+	if digest.Frequency == models.DigestMonthly {
+		return "Your month in " + digest.GroupName + ", " + start.Format("01/2006")
+	}
+	return "Your week in " + digest.GroupName + ", " + start.Format("02/01") + " – " + last.Format("02/01/2006")
+}
+
+// fullVND formats an amount in full with dot separators, e.g. 1.250.000₫
+func fullVND(amount float64) string {
+	digits := fmt.Sprintf("%.0f", math.Abs(amount))
+	var b strings.Builder
+	if amount <= -0.5 {
+		b.WriteByte('-')
+	}
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return b.String() + "₫"
+}
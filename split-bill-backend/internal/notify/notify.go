@@ -0,0 +1,165 @@
+// Package notify routes notifications to the channels a recipient can be
+// reached on: push, email, Zalo and webhooks. Each channel renders the
+// message with its own template, and the recipient's preferences decide
+// which channels are used and when.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// ChannelName identifies a delivery channel
+type ChannelName string
+
+const (
+	ChannelPush    ChannelName = "push"
+	ChannelEmail   ChannelName = "email"
+	ChannelZalo    ChannelName = "zalo"
+	ChannelWebhook ChannelName = "webhook"
+)
+
+// Message is a notification before it is rendered for a channel
+type Message struct {
+	Type    string
+	Title   string
+	Body    string
+	Data    map[string]string
+	GroupID string // group the notification is about, if any
+	HTML    string // replaces Body in emails when set, see RenderDigestHTML
+	SentAt  time.Time
+}
+
+// Recipient is a user, the addresses they can be reached on and what they
+// want to receive
+type Recipient struct {
+	UserID       string
+	Name         string
+	DeviceTokens []string
+	Email        string
+	ZaloUserID   string
+	WebhookURL   string
+	Preferences  *models.NotificationPreferences
+}
+
+// Channel delivers messages over one medium
+type Channel interface {
+	Name() ChannelName
+
+	// Reaches reports whether the recipient has an address on this channel
+	Reaches(recipient Recipient) bool
+
+	// Deliver sends msg to recipients, all of whom the channel reaches
+	Deliver(ctx context.Context, msg Message, recipients []Recipient) error
+}
+
+// Deferral is a message held back for a recipient in quiet hours. The
+// caller stores it and hands it to Router.Deliver once Until has passed.
+type Deferral struct {
+	Recipient Recipient
+	Channels  []ChannelName
+	Until     time.Time
+}
+
+// Router fans a message out to the channels each recipient allows
+type Router struct {
+	channels []Channel
+	logger   *zap.Logger
+}
+
+// NewRouter creates a router over the given channels
+func NewRouter(logger *zap.Logger, channels ...Channel) *Router {
+	return &Router{channels: channels, logger: logger}
+}
+
+// Channels lists the configured channels
+func (r *Router) Channels() []ChannelName {
+	names := make([]ChannelName, len(r.channels))
+	for i, channel := range r.channels {
+		names[i] = channel.Name()
+	}
+	return names
+}
+
+// Route delivers msg to each recipient on every channel that reaches them
+// and that their preferences allow. Recipients in quiet hours are returned
+// as deferrals instead. A failing channel does not stop the others; all
+// failures are returned.
+func (r *Router) Route(ctx context.Context, msg Message, recipients []Recipient) ([]Deferral, error) {
+	if msg.SentAt.IsZero() {
+		msg.SentAt = time.Now()
+	}
+
+	var deferrals []Deferral
+	byChannel := make(map[ChannelName][]Recipient)
+	for _, recipient := range recipients {
+		allowed := r.allowed(msg, recipient, nil)
+		if len(allowed) == 0 {
+			continue
+		}
+
+		if until := recipient.Preferences.QuietUntil(msg.SentAt); !until.IsZero() {
+			names := make([]ChannelName, len(allowed))
+			for i, channel := range allowed {
+				names[i] = channel.Name()
+			}
+			deferrals = append(deferrals, Deferral{Recipient: recipient, Channels: names, Until: until})
+			continue
+		}
+
+		for _, channel := range allowed {
+			byChannel[channel.Name()] = append(byChannel[channel.Name()], recipient)
+		}
+	}
+
+	var errs []error
+	for _, channel := range r.channels {
+		if reached := byChannel[channel.Name()]; len(reached) > 0 {
+			errs = append(errs, r.deliver(ctx, channel, msg, reached))
+		}
+	}
+	return deferrals, errors.Join(errs...)
+}
+
+// Deliver sends a deferred message to one recipient on the given channels,
+// skipping any the recipient has since turned off
+func (r *Router) Deliver(ctx context.Context, msg Message, recipient Recipient, channels []ChannelName) error {
+	var errs []error
+	for _, channel := range r.allowed(msg, recipient, channels) {
+		errs = append(errs, r.deliver(ctx, channel, msg, []Recipient{recipient}))
+	}
+	return errors.Join(errs...)
+}
+
+// allowed returns the channels, out of only if given, that reach the
+// recipient and that their preferences allow for msg
+func (r *Router) allowed(msg Message, recipient Recipient, only []ChannelName) []Channel {
+	var allowed []Channel
+	for _, channel := range r.channels {
+		if only != nil && !slices.Contains(only, channel.Name()) {
+			continue
+		}
+		if channel.Reaches(recipient) && recipient.Preferences.Allows(string(channel.Name()), msg.Type, msg.GroupID) {
+			allowed = append(allowed, channel)
+		}
+	}
+	return allowed
+}
+
+func (r *Router) deliver(ctx context.Context, channel Channel, msg Message, recipients []Recipient) error {
+	if err := channel.Deliver(ctx, msg, recipients); err != nil {
+		r.logger.Warn("Notification channel failed",
+			zap.String("channel", string(channel.Name())),
+			zap.String("type", msg.Type),
+			zap.Error(err),
+		)
+		return fmt.Errorf("%s: %w", channel.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*
+var templateFiles embed.FS
+
+var (
+	emailHTMLTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/email.html"))
+	emailTextTemplate = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/email.txt"))
+	zaloTemplate      = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/zalo.txt"))
+)
+
+// templateData is what the channel templates see
+type templateData struct {
+	Message
+	Name string
+	HTML htmltemplate.HTML // Message.HTML, trusted because we rendered it
+}
+
+func newTemplateData(msg Message, recipient Recipient) templateData {
+	name := recipient.Name
+	if name == "" {
+		name = "there"
+	}
+	return templateData{Message: msg, Name: name, HTML: htmltemplate.HTML(msg.HTML)}
+}
+
+// renderedEmail is a message rendered for the email channel
+type renderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func renderEmail(msg Message, recipient Recipient) (*renderedEmail, error) {
+	data := newTemplateData(msg, recipient)
+
+	var html, text bytes.Buffer
+	if err := emailHTMLTemplate.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	if err := emailTextTemplate.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	return &renderedEmail{
+		Subject: msg.Title,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func renderZalo(msg Message, recipient Recipient) (string, error) {
+	var text bytes.Buffer
+	if err := zaloTemplate.Execute(&text, newTemplateData(msg, recipient)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// webhookPayload is the JSON body posted to webhook channels. Text and
+// Content carry the message for Slack and Discord incoming webhooks.
+type webhookPayload struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	Body    string            `json:"body"`
+	Data    map[string]string `json:"data,omitempty"`
+	SentAt  time.Time         `json:"sent_at"`
+	Text    string            `json:"text"`
+	Content string            `json:"content"`
+}
+
+func renderWebhook(msg Message) ([]byte, error) {
+	summary := msg.Title + "\n" + msg.Body
+	return json.Marshal(webhookPayload{
+		Type:    msg.Type,
+		Title:   msg.Title,
+		Body:    msg.Body,
+		Data:    msg.Data,
+		SentAt:  msg.SentAt,
+		Text:    summary,
+		Content: summary,
+	})
+}
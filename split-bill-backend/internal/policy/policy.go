@@ -0,0 +1,375 @@
+// Package policy decides who may call each API route. Every route under
+// /api/v1 has a rule saying where its group comes from (the path, a bill,
+// an OCR result) and which permission the caller's role needs in it. Routes
+// without a rule are refused, so a new endpoint cannot be left open by
+// forgetting a membership check in its handler.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Scope says how a route finds the group its caller must belong to
+type Scope int
+
+const (
+	// Public routes need no token
+	Public Scope = iota
+	// Self routes only touch the caller's own data, or take the group from
+	// the request body and check it in the service
+	Self
+	// Group routes take the group from the :id path parameter
+	Group
+	// Bill routes act on the bill in :id, in that bill's group
+	Bill
+	// OCRResult routes act on the receipt scan in :id, in its group
+	OCRResult
+	// Peer routes read another user's data; the user in :userId must share
+	// a group with the caller
+	Peer
+)
+
+// Rule is the access rule of one route
+type Rule struct {
+	Scope Scope
+	// Need is the permission the caller's role needs in the group
+	Need models.Permission
+	// Own, when set, is needed instead of Need on bills the caller added
+	Own models.Permission
+}
+
+// APIPrefix is where the routes governed by Rules live
+const APIPrefix = "/api/v1"
+
+// Key identifies a route by method and registered path, e.g.
+// "GET /api/v1/groups/:id"
+func Key(method, path string) string {
+	return method + " " + path
+}
+
+// Rules holds the rule of every route under APIPrefix
+var Rules = map[string]Rule{
+	// Auth and profile
+	Key("POST", "/api/v1/auth/verify-token"): {Scope: Self},
+	Key("GET", "/api/v1/auth/me"):            {Scope: Self},
+	Key("PUT", "/api/v1/auth/profile"):       {Scope: Self},
+
+	// Groups
+	Key("POST", "/api/v1/groups"):              {Scope: Self},
+	Key("GET", "/api/v1/groups"):               {Scope: Self},
+	Key("POST", "/api/v1/groups/join"):         {Scope: Self},
+	Key("GET", "/api/v1/groups/join-requests"): {Scope: Self},
+	Key("GET", "/api/v1/groups/permissions"):   {Scope: Self},
+	Key("GET", "/api/v1/groups/:id"):           {Scope: Group, Need: models.PermViewGroup},
+	Key("PUT", "/api/v1/groups/:id"):           {Scope: Group, Need: models.PermManageGroup},
+	Key("DELETE", "/api/v1/groups/:id"):        {Scope: Group, Need: models.PermDeleteGroup},
+
+	// Members and roles. Anyone can leave or step down; the service
+	// checks rank before removing or demoting someone else.
+	Key("POST", "/api/v1/groups/:id/members"):                 {Scope: Group, Need: models.PermAddMembers},
+	Key("POST", "/api/v1/groups/:id/placeholders"):            {Scope: Group, Need: models.PermAddMembers},
+	Key("DELETE", "/api/v1/groups/:id/members/:userId"):       {Scope: Group, Need: models.PermViewGroup},
+	Key("POST", "/api/v1/groups/:id/members/:userId/promote"): {Scope: Group, Need: models.PermManageRoles},
+	Key("POST", "/api/v1/groups/:id/members/:userId/demote"):  {Scope: Group, Need: models.PermViewGroup},
+	Key("POST", "/api/v1/groups/:id/transfer-ownership"):      {Scope: Group, Need: models.PermTransferOwnership},
+	Key("POST", "/api/v1/groups/:id/members/:userId/remind"):  {Scope: Group, Need: models.PermViewGroup},
+
+	// Invite code and invite links. The service lets a link's creator
+	// revoke it and checks who may create admin links.
+	Key("POST", "/api/v1/groups/:id/invite-code/regenerate"): {Scope: Group, Need: models.PermManageGroup},
+	Key("GET", "/api/v1/groups/:id/invites"):                 {Scope: Group, Need: models.PermAddMembers},
+	Key("POST", "/api/v1/groups/:id/invites"):                {Scope: Group, Need: models.PermAddMembers},
+	Key("DELETE", "/api/v1/groups/:id/invites/:inviteId"):    {Scope: Group, Need: models.PermAddMembers},
+
+	// Join requests to groups that require approval
+	Key("GET", "/api/v1/groups/:id/join-requests"):                     {Scope: Group, Need: models.PermApproveJoins},
+	Key("POST", "/api/v1/groups/:id/join-requests/:requestId/approve"): {Scope: Group, Need: models.PermApproveJoins},
+	Key("POST", "/api/v1/groups/:id/join-requests/:requestId/deny"):    {Scope: Group, Need: models.PermApproveJoins},
+
+	// Group bills, balances and settlements
+	Key("POST", "/api/v1/groups/:id/bills"):                    {Scope: Group, Need: models.PermRecordExpenses},
+	Key("GET", "/api/v1/groups/:id/bills"):                     {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/balances"):                  {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/settlements"):               {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/settlements/:toUserId/pay"): {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/transactions"):              {Scope: Group, Need: models.PermViewGroup},
+
+	// Reminders, feeds, stats and live events
+	Key("GET", "/api/v1/groups/:id/reminders"):        {Scope: Group, Need: models.PermViewGroup},
+	Key("PUT", "/api/v1/groups/:id/reminders"):        {Scope: Group, Need: models.PermManageGroup},
+	Key("PUT", "/api/v1/groups/:id/reminders/snooze"): {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/activities"):       {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/stats"):            {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/stats/categories"): {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/export"):           {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/digest"):           {Scope: Group, Need: models.PermViewGroup},
+	Key("GET", "/api/v1/groups/:id/events"):           {Scope: Group, Need: models.PermViewGroup},
+
+	// Webhooks
+	Key("GET", "/api/v1/groups/:id/webhooks"):                                              {Scope: Group, Need: models.PermManageGroup},
+	Key("POST", "/api/v1/groups/:id/webhooks"):                                             {Scope: Group, Need: models.PermManageGroup},
+	Key("PUT", "/api/v1/groups/:id/webhooks/:webhookId"):                                   {Scope: Group, Need: models.PermManageGroup},
+	Key("DELETE", "/api/v1/groups/:id/webhooks/:webhookId"):                                {Scope: Group, Need: models.PermManageGroup},
+	Key("POST", "/api/v1/groups/:id/webhooks/:webhookId/test"):                             {Scope: Group, Need: models.PermManageGroup},
+	Key("GET", "/api/v1/groups/:id/webhooks/:webhookId/deliveries"):                        {Scope: Group, Need: models.PermManageGroup},
+	Key("POST", "/api/v1/groups/:id/webhooks/:webhookId/deliveries/:deliveryId/redeliver"): {Scope: Group, Need: models.PermManageGroup},
+
+	// Bills
+	Key("GET", "/api/v1/bills/:id"):    {Scope: Bill, Need: models.PermViewGroup},
+	Key("PUT", "/api/v1/bills/:id"):    {Scope: Bill, Need: models.PermEditOthersBills, Own: models.PermEditOwnBills},
+	Key("DELETE", "/api/v1/bills/:id"): {Scope: Bill, Need: models.PermEditOthersBills, Own: models.PermEditOwnBills},
+
+	// Transactions: the group comes from the body, and only the payer or
+	// payee can change one, which the service checks
+	Key("POST", "/api/v1/transactions"):             {Scope: Self},
+	Key("PUT", "/api/v1/transactions/:id"):          {Scope: Self},
+	Key("PUT", "/api/v1/transactions/:id/cancel"):   {Scope: Self},
+	Key("PUT", "/api/v1/transactions/:id/confirm"):  {Scope: Self},
+	Key("PUT", "/api/v1/transactions/:id/reject"):   {Scope: Self},
+	Key("POST", "/api/v1/transactions/:id/reverse"): {Scope: Self},
+
+	// Bank statements belong to the user who imported them
+	Key("POST", "/api/v1/statements/import"):          {Scope: Self},
+	Key("GET", "/api/v1/statements/imports"):          {Scope: Self},
+	Key("GET", "/api/v1/statements/lines"):            {Scope: Self},
+	Key("POST", "/api/v1/statements/lines/:id/link"):  {Scope: Self},
+	Key("PUT", "/api/v1/statements/lines/:id/ignore"): {Scope: Self},
+
+	// Users
+	Key("GET", "/api/v1/users/me/debts"):             {Scope: Self},
+	Key("POST", "/api/v1/users/me/devices"):          {Scope: Self},
+	Key("DELETE", "/api/v1/users/me/devices/:token"): {Scope: Self},
+
+	// Receipt scans: scanning takes the group from the body
+	Key("POST", "/api/v1/ocr/scan"):        {Scope: Self},
+	Key("POST", "/api/v1/ocr/scan-base64"): {Scope: Self},
+	Key("GET", "/api/v1/ocr/:id/result"):   {Scope: OCRResult, Need: models.PermViewGroup},
+	Key("POST", "/api/v1/ocr/:id/confirm"): {Scope: OCRResult, Need: models.PermRecordExpenses},
+	Key("GET", "/api/v1/ocr/pending"):      {Scope: Self},
+
+	// Uploads
+	Key("POST", "/api/v1/upload/image"):        {Scope: Self},
+	Key("POST", "/api/v1/upload/image-base64"): {Scope: Self},
+
+	// Payments
+	Key("POST", "/api/v1/payment/deeplink"):        {Scope: Self},
+	Key("POST", "/api/v1/payment/vietqr"):          {Scope: Self},
+	Key("GET", "/api/v1/payment/user/:userId"):     {Scope: Peer},
+	Key("GET", "/api/v1/payment/banks"):            {Scope: Self},
+	Key("GET", "/api/v1/payment/references/:code"): {Scope: Self}, // the service checks the reference's group
+
+	// Feeds, inbox and personal stats
+	Key("GET", "/api/v1/activities/me"):              {Scope: Self},
+	Key("GET", "/api/v1/activities/me/own"):          {Scope: Self},
+	Key("GET", "/api/v1/notifications"):              {Scope: Self},
+	Key("GET", "/api/v1/notifications/unread-count"): {Scope: Self},
+	Key("PUT", "/api/v1/notifications/read-all"):     {Scope: Self},
+	Key("PUT", "/api/v1/notifications/:id/read"):     {Scope: Self},
+	Key("GET", "/api/v1/stats/me"):                   {Scope: Self},
+
+	// No token
+	Key("GET", "/api/v1/payment/qr"):         {Scope: Public},
+	Key("POST", "/api/v1/webhooks/payments"): {Scope: Public},
+	Key("GET", "/api/v1/categories"):         {Scope: Public},
+}
+
+// UserFinder looks up the signed-in user
+type UserFinder interface {
+	FindByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error)
+}
+
+// GroupFinder looks up groups
+type GroupFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Group, error)
+	FindByMemberUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Group, error)
+}
+
+// BillFinder looks up bills
+type BillFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Bill, error)
+}
+
+// OCRResultFinder looks up receipt scans
+type OCRResultFinder interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.OCRResult, error)
+}
+
+// Enforcer applies Rules to requests
+type Enforcer struct {
+	users      UserFinder
+	groups     GroupFinder
+	bills      BillFinder
+	ocrResults OCRResultFinder
+}
+
+func NewEnforcer(users UserFinder, groups GroupFinder, bills BillFinder, ocrResults OCRResultFinder) *Enforcer {
+	return &Enforcer{
+		users:      users,
+		groups:     groups,
+		bills:      bills,
+		ocrResults: ocrResults,
+	}
+}
+
+// denial is why a request was refused
+type denial struct {
+	status  int
+	message string
+}
+
+func (d *denial) Error() string { return d.message }
+
+func deny(status int, message string) error {
+	return &denial{status: status, message: message}
+}
+
+// Enforce checks the matched route's rule. It runs after authentication,
+// so the Firebase UID is already in the context.
+func (e *Enforcer) Enforce() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := e.check(c); err != nil {
+			var d *denial
+			if !errors.As(err, &d) {
+				utils.RespondInternalError(c, "Failed to check access: "+err.Error())
+			} else {
+				utils.RespondError(c, d.status, d.message)
+			}
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (e *Enforcer) check(c *gin.Context) error {
+	rule, ok := Rules[Key(c.Request.Method, c.FullPath())]
+	if !ok {
+		return deny(http.StatusForbidden, "no access rule for this route")
+	}
+	if rule.Scope == Public || rule.Scope == Self {
+		return nil
+	}
+
+	ctx := c.Request.Context()
+	firebaseUID, _ := c.Get("firebase_uid")
+	uid, _ := firebaseUID.(string)
+	user, err := e.users.FindByFirebaseUID(ctx, uid)
+	if err != nil {
+		return deny(http.StatusUnauthorized, "User not found")
+	}
+
+	if rule.Scope == Peer {
+		return e.checkPeer(ctx, user, c.Param("userId"))
+	}
+
+	group, creator, err := e.resolve(ctx, rule.Scope, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	need := rule.Need
+	if rule.Own != "" && creator == user.ID {
+		need = rule.Own
+	}
+
+	member := group.Member(user.ID)
+	if member == nil {
+		return deny(http.StatusForbidden, "you are not a member of this group")
+	}
+	if !member.Role.Can(need) {
+		return deny(http.StatusForbidden, "your role in this group does not allow this")
+	}
+	return nil
+}
+
+// resolve loads the group a route acts in, and who created the entity
+// the route acts on if it is not the group itself
+func (e *Enforcer) resolve(ctx context.Context, scope Scope, id string) (*models.Group, primitive.ObjectID, error) {
+	var creator primitive.ObjectID
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, creator, deny(http.StatusBadRequest, "Invalid ID")
+	}
+
+	groupID := objID
+	switch scope {
+	case Bill:
+		bill, err := e.bills.FindByID(ctx, objID)
+		if err != nil {
+			return nil, creator, notFound(err, "Bill not found")
+		}
+		groupID, creator = bill.GroupID, bill.Creator()
+	case OCRResult:
+		result, err := e.ocrResults.FindByID(ctx, objID)
+		if err != nil {
+			return nil, creator, notFound(err, "OCR result not found")
+		}
+		groupID, creator = result.GroupID, result.UploadedBy
+	}
+
+	group, err := e.groups.FindByID(ctx, groupID)
+	if err != nil {
+		return nil, creator, notFound(err, "Group not found")
+	}
+	return group, creator, nil
+}
+
+// checkPeer allows reading another user only if they share a group
+func (e *Enforcer) checkPeer(ctx context.Context, user *models.User, peer string) error {
+	peerID, err := primitive.ObjectIDFromHex(peer)
+	if err != nil {
+		return deny(http.StatusBadRequest, "Invalid user ID")
+	}
+	if peerID == user.ID {
+		return nil
+	}
+
+	groups, err := e.groups.FindByMemberUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for i := range groups {
+		if groups[i].Member(peerID) != nil {
+			return nil
+		}
+	}
+	return deny(http.StatusForbidden, "you do not share a group with this user")
+}
+
+func notFound(err error, message string) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return deny(http.StatusNotFound, message)
+	}
+	return err
+}
+
+// Check returns an error naming every registered route under APIPrefix
+// that has no rule
+func Check(routes gin.RoutesInfo) error {
+	var missing []string
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, APIPrefix+"/") {
+			continue
+		}
+		if _, ok := Rules[Key(route.Method, route.Path)]; !ok {
+			missing = append(missing, Key(route.Method, route.Path))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("routes without an access rule: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package policy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// access is the least a caller needs to get past the policy on a route
+type access int
+
+const (
+	anyone   access = iota // public and self routes
+	peer                   // shares a group with the user in :userId
+	viewer                 // viewer or higher in the group
+	member                 // member or higher
+	admin                  // admin or owner
+	owner                  // owner only
+	outsider access = -1   // rank of a signed-in user outside the group
+)
+
+// matrix is who may call every route, as the product decided it. It is kept
+// apart from Rules on purpose: changing a rule must change this too.
+var matrix = map[string]access{
+	Key("POST", "/api/v1/auth/verify-token"): anyone,
+	Key("GET", "/api/v1/auth/me"):            anyone,
+	Key("PUT", "/api/v1/auth/profile"):       anyone,
+
+	Key("POST", "/api/v1/groups"):              anyone,
+	Key("GET", "/api/v1/groups"):               anyone,
+	Key("POST", "/api/v1/groups/join"):         anyone,
+	Key("GET", "/api/v1/groups/join-requests"): anyone,
+	Key("GET", "/api/v1/groups/permissions"):   anyone,
+	Key("GET", "/api/v1/groups/:id"):           viewer,
+	Key("PUT", "/api/v1/groups/:id"):           admin,
+	Key("DELETE", "/api/v1/groups/:id"):        owner,
+
+	Key("POST", "/api/v1/groups/:id/members"):                 member,
+	Key("POST", "/api/v1/groups/:id/placeholders"):            member,
+	Key("DELETE", "/api/v1/groups/:id/members/:userId"):       viewer,
+	Key("POST", "/api/v1/groups/:id/members/:userId/promote"): admin,
+	Key("POST", "/api/v1/groups/:id/members/:userId/demote"):  viewer,
+	Key("POST", "/api/v1/groups/:id/transfer-ownership"):      owner,
+	Key("POST", "/api/v1/groups/:id/members/:userId/remind"):  viewer,
+
+	Key("POST", "/api/v1/groups/:id/invite-code/regenerate"): admin,
+	Key("GET", "/api/v1/groups/:id/invites"):                 member,
+	Key("POST", "/api/v1/groups/:id/invites"):                member,
+	Key("DELETE", "/api/v1/groups/:id/invites/:inviteId"):    member,
+
+	Key("GET", "/api/v1/groups/:id/join-requests"):                     admin,
+	Key("POST", "/api/v1/groups/:id/join-requests/:requestId/approve"): admin,
+	Key("POST", "/api/v1/groups/:id/join-requests/:requestId/deny"):    admin,
+
+	Key("POST", "/api/v1/groups/:id/bills"):                    member,
+	Key("GET", "/api/v1/groups/:id/bills"):                     viewer,
+	Key("GET", "/api/v1/groups/:id/balances"):                  viewer,
+	Key("GET", "/api/v1/groups/:id/settlements"):               viewer,
+	Key("GET", "/api/v1/groups/:id/settlements/:toUserId/pay"): viewer,
+	Key("GET", "/api/v1/groups/:id/transactions"):              viewer,
+
+	Key("GET", "/api/v1/groups/:id/reminders"):        viewer,
+	Key("PUT", "/api/v1/groups/:id/reminders"):        admin,
+	Key("PUT", "/api/v1/groups/:id/reminders/snooze"): viewer,
+	Key("GET", "/api/v1/groups/:id/activities"):       viewer,
+	Key("GET", "/api/v1/groups/:id/stats"):            viewer,
+	Key("GET", "/api/v1/groups/:id/stats/categories"): viewer,
+	Key("GET", "/api/v1/groups/:id/export"):           viewer,
+	Key("GET", "/api/v1/groups/:id/digest"):           viewer,
+	Key("GET", "/api/v1/groups/:id/events"):           viewer,
+
+	Key("GET", "/api/v1/groups/:id/webhooks"):                                              admin,
+	Key("POST", "/api/v1/groups/:id/webhooks"):                                             admin,
+	Key("PUT", "/api/v1/groups/:id/webhooks/:webhookId"):                                   admin,
+	Key("DELETE", "/api/v1/groups/:id/webhooks/:webhookId"):                                admin,
+	Key("POST", "/api/v1/groups/:id/webhooks/:webhookId/test"):                             admin,
+	Key("GET", "/api/v1/groups/:id/webhooks/:webhookId/deliveries"):                        admin,
+	Key("POST", "/api/v1/groups/:id/webhooks/:webhookId/deliveries/:deliveryId/redeliver"): admin,
+
+	// On a bill someone else added; see TestOwnBill for the caller's own
+	Key("GET", "/api/v1/bills/:id"):    viewer,
+	Key("PUT", "/api/v1/bills/:id"):    admin,
+	Key("DELETE", "/api/v1/bills/:id"): admin,
+
+	Key("POST", "/api/v1/transactions"):             anyone,
+	Key("PUT", "/api/v1/transactions/:id"):          anyone,
+	Key("PUT", "/api/v1/transactions/:id/cancel"):   anyone,
+	Key("PUT", "/api/v1/transactions/:id/confirm"):  anyone,
+	Key("PUT", "/api/v1/transactions/:id/reject"):   anyone,
+	Key("POST", "/api/v1/transactions/:id/reverse"): anyone,
+
+	Key("POST", "/api/v1/statements/import"):          anyone,
+	Key("GET", "/api/v1/statements/imports"):          anyone,
+	Key("GET", "/api/v1/statements/lines"):            anyone,
+	Key("POST", "/api/v1/statements/lines/:id/link"):  anyone,
+	Key("PUT", "/api/v1/statements/lines/:id/ignore"): anyone,
+
+	Key("GET", "/api/v1/users/me/debts"):             anyone,
+	Key("POST", "/api/v1/users/me/devices"):          anyone,
+	Key("DELETE", "/api/v1/users/me/devices/:token"): anyone,
+
+	Key("POST", "/api/v1/ocr/scan"):        anyone,
+	Key("POST", "/api/v1/ocr/scan-base64"): anyone,
+	Key("GET", "/api/v1/ocr/:id/result"):   viewer,
+	Key("POST", "/api/v1/ocr/:id/confirm"): member,
+	Key("GET", "/api/v1/ocr/pending"):      anyone,
+
+	Key("POST", "/api/v1/upload/image"):        anyone,
+	Key("POST", "/api/v1/upload/image-base64"): anyone,
+
+	Key("POST", "/api/v1/payment/deeplink"):        anyone,
+	Key("POST", "/api/v1/payment/vietqr"):          anyone,
+	Key("GET", "/api/v1/payment/user/:userId"):     peer,
+	Key("GET", "/api/v1/payment/banks"):            anyone,
+	Key("GET", "/api/v1/payment/references/:code"): anyone,
+
+	Key("GET", "/api/v1/activities/me"):              anyone,
+	Key("GET", "/api/v1/activities/me/own"):          anyone,
+	Key("GET", "/api/v1/notifications"):              anyone,
+	Key("GET", "/api/v1/notifications/unread-count"): anyone,
+	Key("PUT", "/api/v1/notifications/read-all"):     anyone,
+	Key("PUT", "/api/v1/notifications/:id/read"):     anyone,
+	Key("GET", "/api/v1/stats/me"):                   anyone,
+
+	Key("GET", "/api/v1/payment/qr"):         anyone,
+	Key("POST", "/api/v1/webhooks/payments"): anyone,
+	Key("GET", "/api/v1/categories"):         anyone,
+}
+
+type fakeUsers map[string]*models.User
+
+func (f fakeUsers) FindByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
+	if user, ok := f[firebaseUID]; ok {
+		return user, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+type fakeGroups map[primitive.ObjectID]*models.Group
+
+func (f fakeGroups) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Group, error) {
+	if group, ok := f[id]; ok {
+		return group, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (f fakeGroups) FindByMemberUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Group, error) {
+	var groups []models.Group
+	for _, group := range f {
+		if group.Member(userID) != nil {
+			groups = append(groups, *group)
+		}
+	}
+	return groups, nil
+}
+
+type fakeBills map[primitive.ObjectID]*models.Bill
+
+func (f fakeBills) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Bill, error) {
+	if bill, ok := f[id]; ok {
+		return bill, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+type fakeScans map[primitive.ObjectID]*models.OCRResult
+
+func (f fakeScans) FindByID(ctx context.Context, id primitive.ObjectID) (*models.OCRResult, error) {
+	if scan, ok := f[id]; ok {
+		return scan, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// fixture is one group with a user of every role, a bill and a receipt
+// scan in it, and a signed-in user outside it
+type fixture struct {
+	users    fakeUsers
+	group    *models.Group
+	bill     *models.Bill // added by the owner
+	ownBills map[string]*models.Bill
+	scan     *models.OCRResult
+	router   *gin.Engine
+}
+
+var ranks = map[string]access{
+	"owner":    owner,
+	"admin":    admin,
+	"member":   member,
+	"viewer":   viewer,
+	"outsider": outsider,
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	f := &fixture{users: fakeUsers{}, ownBills: map[string]*models.Bill{}}
+	f.group = &models.Group{ID: primitive.NewObjectID(), IsActive: true}
+	for name := range ranks {
+		user := &models.User{ID: primitive.NewObjectID(), FirebaseUID: name}
+		f.users[name] = user
+		if name != "outsider" {
+			f.group.Members = append(f.group.Members, models.GroupMember{UserID: user.ID, Role: models.MemberRole(name)})
+		}
+	}
+
+	bills := fakeBills{}
+	f.bill = &models.Bill{ID: primitive.NewObjectID(), GroupID: f.group.ID, PaidBy: f.users["member"].ID, CreatedBy: f.users["owner"].ID}
+	bills[f.bill.ID] = f.bill
+	for _, name := range []string{"member", "viewer"} {
+		bill := &models.Bill{ID: primitive.NewObjectID(), GroupID: f.group.ID, CreatedBy: f.users[name].ID}
+		f.ownBills[name] = bill
+		bills[bill.ID] = bill
+	}
+
+	f.scan = &models.OCRResult{ID: primitive.NewObjectID(), GroupID: f.group.ID, UploadedBy: f.users["member"].ID}
+
+	enforcer := NewEnforcer(f.users, fakeGroups{f.group.ID: f.group}, bills, fakeScans{f.scan.ID: f.scan})
+
+	f.router = gin.New()
+	signIn := func(c *gin.Context) {
+		c.Set("firebase_uid", c.GetHeader("X-Test-User"))
+	}
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	for key := range Rules {
+		method, path, _ := strings.Cut(key, " ")
+		f.router.Handle(method, path, signIn, enforcer.Enforce(), ok)
+	}
+	f.router.GET("/api/v1/unruled/:id", signIn, enforcer.Enforce(), ok)
+	return f
+}
+
+// url fills a route's path parameters with the fixture's IDs
+func (f *fixture) url(path string, scope Scope) string {
+	id := primitive.NewObjectID().Hex()
+	switch scope {
+	case Group:
+		id = f.group.ID.Hex()
+	case Bill:
+		id = f.bill.ID.Hex()
+	case OCRResult:
+		id = f.scan.ID.Hex()
+	}
+
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		switch {
+		case part == ":id":
+			part = id
+		case part == ":userId":
+			part = f.users["member"].ID.Hex()
+		case strings.HasPrefix(part, ":"):
+			part = primitive.NewObjectID().Hex()
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "/")
+}
+
+func (f *fixture) do(method, url, user string) int {
+	req := httptest.NewRequest(method, url, nil)
+	req.Header.Set("X-Test-User", user)
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestMatrixCoversEveryRule(t *testing.T) {
+	for key := range Rules {
+		if _, ok := matrix[key]; !ok {
+			t.Errorf("%s has a rule but no expected access in the test matrix", key)
+		}
+	}
+	for key := range matrix {
+		if _, ok := Rules[key]; !ok {
+			t.Errorf("%s is in the test matrix but has no rule", key)
+		}
+	}
+}
+
+func TestRouteMatrix(t *testing.T) {
+	f := newFixture(t)
+
+	for key, need := range matrix {
+		rule, ok := Rules[key]
+		if !ok {
+			continue
+		}
+		method, path, _ := strings.Cut(key, " ")
+		url := f.url(path, rule.Scope)
+
+		for name, rank := range ranks {
+			allowed := rank >= need
+			switch need {
+			case anyone:
+				allowed = true
+			case peer:
+				allowed = rank != outsider
+			}
+
+			want := http.StatusForbidden
+			if allowed {
+				want = http.StatusOK
+			}
+			if got := f.do(method, url, name); got != want {
+				t.Errorf("%s as %s: status %d, want %d", key, name, got, want)
+			}
+		}
+	}
+}
+
+func TestOwnBill(t *testing.T) {
+	f := newFixture(t)
+
+	for _, method := range []string{"PUT", "DELETE"} {
+		url := "/api/v1/bills/" + f.ownBills["member"].ID.Hex()
+		if got := f.do(method, url, "member"); got != http.StatusOK {
+			t.Errorf("%s own bill as member: status %d, want 200", method, got)
+		}
+
+		// Viewers cannot edit even bills that name them as the creator
+		url = "/api/v1/bills/" + f.ownBills["viewer"].ID.Hex()
+		if got := f.do(method, url, "viewer"); got != http.StatusForbidden {
+			t.Errorf("%s own bill as viewer: status %d, want 403", method, got)
+		}
+	}
+}
+
+func TestOldBillCountsAsAddedByPayer(t *testing.T) {
+	f := newFixture(t)
+	f.bill.CreatedBy = primitive.NilObjectID
+
+	if got := f.do("PUT", "/api/v1/bills/"+f.bill.ID.Hex(), "member"); got != http.StatusOK {
+		t.Errorf("edit old bill as its payer: status %d, want 200", got)
+	}
+}
+
+func TestEnforceErrors(t *testing.T) {
+	f := newFixture(t)
+	unknown := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		user   string
+		want   int
+	}{
+		{"invalid group ID", "GET", "/api/v1/groups/nope/bills", "member", http.StatusBadRequest},
+		{"unknown group", "GET", "/api/v1/groups/" + unknown + "/bills", "member", http.StatusNotFound},
+		{"unknown bill", "GET", "/api/v1/bills/" + unknown, "member", http.StatusNotFound},
+		{"unknown receipt scan", "GET", "/api/v1/ocr/" + unknown + "/result", "member", http.StatusNotFound},
+		{"invalid peer ID", "GET", "/api/v1/payment/user/nope", "member", http.StatusBadRequest},
+		{"own payment info", "GET", "/api/v1/payment/user/" + f.users["outsider"].ID.Hex(), "outsider", http.StatusOK},
+		{"user without profile", "GET", "/api/v1/groups/" + f.group.ID.Hex(), "stranger", http.StatusUnauthorized},
+		{"route without rule", "GET", "/api/v1/unruled/" + unknown, "owner", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := f.do(tt.method, tt.url, tt.user); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	router := gin.New()
+	noop := func(c *gin.Context) {}
+	router.GET("/health", noop)
+	router.GET("/api/v1/groups/:id", noop)
+	if err := Check(router.Routes()); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+
+	router.GET("/api/v1/groups/:id/secrets", noop)
+	err := Check(router.Routes())
+	if err == nil || !strings.Contains(err.Error(), "GET /api/v1/groups/:id/secrets") {
+		t.Errorf("Check = %v, want the route without a rule named", err)
+	}
+}
@@ -0,0 +1,50 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/splitbill/backend/internal/events"
+)
+
+// RegisterEventHandlers forwards the domain events clients care about to
+// the group's stream, together with balances.changed when they move money
+func (h *Hub) RegisterEventHandlers(bus *events.Bus) {
+	bus.SubscribeAll("realtime", false, func(ctx context.Context, event events.Event) error {
+		groupID := event.Group()
+		eventType := EventType(event.Name())
+
+		switch e := event.(type) {
+		case events.BillCreated:
+			h.Publish(groupID, eventType, e.Bill.ToResponse())
+			h.Publish(groupID, BalancesChanged, nil)
+		case events.BillUpdated:
+			h.Publish(groupID, eventType, e.Bill.ToResponse())
+			h.Publish(groupID, BalancesChanged, nil)
+		case events.BillDeleted:
+			h.Publish(groupID, eventType, map[string]string{"id": e.Bill.ID.Hex()})
+			h.Publish(groupID, BalancesChanged, nil)
+		case events.TransactionConfirmed:
+			h.Publish(groupID, eventType, e.Transaction.ToResponse())
+			h.Publish(groupID, BalancesChanged, nil)
+		case events.TransactionCreated:
+			h.Publish(groupID, eventType, e.Transaction.ToResponse())
+		case events.TransactionUpdated:
+			h.Publish(groupID, eventType, e.Transaction.ToResponse())
+		case events.TransactionCancelled:
+			h.Publish(groupID, eventType, e.Transaction.ToResponse())
+		case events.TransactionRejected:
+			h.Publish(groupID, eventType, e.Transaction.ToResponse())
+		case events.MemberJoined:
+			h.Publish(groupID, eventType, e.Member)
+		case events.MemberLeft:
+			h.Publish(groupID, eventType, map[string]string{"user_id": e.UserID.Hex()})
+		case events.PlaceholderClaimed:
+			h.Publish(groupID, eventType, map[string]string{
+				"placeholder_id": e.PlaceholderID.Hex(),
+				"user_id":        e.Member.UserID.Hex(),
+			})
+			h.Publish(groupID, BalancesChanged, nil)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,333 @@
+// Package realtime pushes group changes to connected clients. Each event is
+// appended to a capped Redis stream per group, so a client that reconnects
+// can catch up from the last event it saw, and published on a Redis channel
+// every server instance listens to, so clients hear about a change whichever
+// instance handled it.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// EventType says what changed in the group
+type EventType string
+
+const (
+	BillCreated          EventType = "bill.created"
+	BillUpdated          EventType = "bill.updated"
+	BillDeleted          EventType = "bill.deleted"
+	TransactionCreated   EventType = "transaction.created"
+	TransactionUpdated   EventType = "transaction.updated"
+	TransactionConfirmed EventType = "transaction.confirmed"
+	TransactionRejected  EventType = "transaction.rejected"
+	TransactionCancelled EventType = "transaction.cancelled"
+	MemberJoined         EventType = "member.joined"
+	MemberLeft           EventType = "member.left"
+	MemberClaimed        EventType = "member.claimed"   // a placeholder's bills and payments moved to user_id
+	BalancesChanged      EventType = "balances.changed" // refetch /groups/:id/balances
+
+	// Resync tells a client that events it missed are no longer kept and
+	// it should reload the group
+	Resync EventType = "resync"
+)
+
+const (
+	// eventsChannel is the pub/sub channel every instance listens on
+	eventsChannel = "realtime:events"
+
+	// historySize is roughly how many events are kept per group for resuming
+	historySize = 500
+
+	// historyTTL drops the history of groups nothing happened in for a while
+	historyTTL = 24 * time.Hour
+
+	// subscriberBuffer is how many events a slow client may fall behind
+	// before it is disconnected to catch up by resuming
+	subscriberBuffer = 64
+
+	publishTimeout = 3 * time.Second
+)
+
+// Event is one change in a group. ID is the Redis stream entry ID, which
+// clients send back as Last-Event-ID to resume.
+type Event struct {
+	ID        string          `json:"id"`
+	GroupID   string          `json:"group_id"`
+	Type      EventType       `json:"type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Subscription receives the events of one group
+type Subscription struct {
+	groupID string
+	events  chan Event
+}
+
+// Events is closed when the subscriber fell too far behind
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub publishes group events and delivers them to the clients connected to
+// this instance. A nil Hub drops everything, so services work without one.
+type Hub struct {
+	client *redis.Client
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+func NewHub(client *redis.Client, logger *zap.Logger) *Hub {
+	return &Hub{
+		client: client,
+		logger: logger,
+		subs:   make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish records an event in the group's history and sends it to every
+// instance. If Redis is unavailable the event still reaches the clients
+// connected here, but cannot be resumed from.
+func (h *Hub) Publish(groupID primitive.ObjectID, eventType EventType, data interface{}) {
+	if h == nil {
+		return
+	}
+
+	event := Event{
+		GroupID:   groupID.Hex(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+	}
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			h.logger.Warn("Dropping realtime event", zap.String("type", string(eventType)), zap.Error(err))
+			return
+		}
+		event.Data = payload
+	}
+
+	// The request that caused the event may be finished or cancelled already
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := h.publish(ctx, &event); err != nil {
+		h.logger.Warn("Realtime event not shared with other instances",
+			zap.String("type", string(eventType)),
+			zap.Error(err),
+		)
+		h.dispatch(event)
+	}
+}
+
+func (h *Hub) publish(ctx context.Context, event *Event) error {
+	stored, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	key := historyKey(event.GroupID)
+	id, err := h.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: historySize,
+		Approx: true,
+		Values: map[string]interface{}{"event": stored},
+	}).Result()
+	if err != nil {
+		return err
+	}
+	h.client.Expire(ctx, key, historyTTL)
+
+	event.ID = id
+	message, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return h.client.Publish(ctx, eventsChannel, message).Err()
+}
+
+// Run delivers the events published by every instance to the clients
+// connected to this one, until ctx is cancelled
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.client.Subscribe(ctx, eventsChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				h.logger.Warn("Ignoring malformed realtime event", zap.Error(err))
+				continue
+			}
+			h.dispatch(event)
+		}
+	}
+}
+
+// Subscribe starts receiving a group's events. Call Unsubscribe when done.
+func (h *Hub) Subscribe(groupID string) *Subscription {
+	sub := &Subscription{groupID: groupID, events: make(chan Event, subscriberBuffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[groupID] == nil {
+		h.subs[groupID] = make(map[*Subscription]struct{})
+	}
+	h.subs[groupID][sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops a subscription and closes its channel
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+// Close ends every subscription, so open streams finish and the server can shut down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, group := range h.subs {
+		for sub := range group {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove must be called with h.mu held
+func (h *Hub) remove(sub *Subscription) {
+	group := h.subs[sub.groupID]
+	if _, ok := group[sub]; !ok {
+		return
+	}
+	delete(group, sub)
+	if len(group) == 0 {
+		delete(h.subs, sub.groupID)
+	}
+	close(sub.events)
+}
+
+// dispatch hands an event to this instance's subscribers of its group.
+// Subscribers whose buffer is full are dropped rather than slowing the rest.
+func (h *Hub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[event.GroupID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Replay returns the group's events after lastID, oldest first. If events
+// after lastID have already been trimmed from the history, or lastID is not
+// one of ours, it returns a single Resync event instead.
+func (h *Hub) Replay(ctx context.Context, groupID, lastID string) ([]Event, error) {
+	if !validID(lastID) {
+		return []Event{{GroupID: groupID, Type: Resync, CreatedAt: time.Now()}}, nil
+	}
+
+	key := historyKey(groupID)
+	oldest, err := h.client.XRangeN(ctx, key, "-", "+", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	oldestID := ""
+	if len(oldest) > 0 {
+		oldestID = oldest[0].ID
+	}
+	if needsResync(lastID, oldestID, time.Now()) {
+		return []Event{{GroupID: groupID, Type: Resync, CreatedAt: time.Now()}}, nil
+	}
+
+	entries, err := h.client.XRangeN(ctx, key, "("+lastID, "+", historySize).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		raw, _ := entry.Values["event"].(string)
+		var event Event
+		if err := json.Unmarshal([]byte(raw), &event); err != nil {
+			continue
+		}
+		event.ID = entry.ID
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// needsResync reports whether events after lastID may be gone from a
+// history whose oldest entry is oldestID, "" when the history is empty.
+// A client that saw an entry older than every kept one may have missed
+// the trimmed entries in between; an empty history is only complete if
+// lastID is too recent for the history to have expired since.
+func needsResync(lastID, oldestID string, now time.Time) bool {
+	if oldestID == "" {
+		return idTime(lastID).Before(now.Add(-historyTTL))
+	}
+	return CompareIDs(oldestID, lastID) > 0
+}
+
+func historyKey(groupID string) string {
+	return "realtime:group:" + groupID
+}
+
+// CompareIDs orders two stream IDs ("<ms>-<seq>"), returning -1, 0 or 1
+func CompareIDs(a, b string) int {
+	aMs, aSeq := splitID(a)
+	bMs, bSeq := splitID(b)
+	switch {
+	case aMs < bMs || (aMs == bMs && aSeq < bSeq):
+		return -1
+	case aMs == bMs && aSeq == bSeq:
+		return 0
+	}
+	return 1
+}
+
+func splitID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	msN, _ := strconv.ParseUint(ms, 10, 64)
+	seqN, _ := strconv.ParseUint(seq, 10, 64)
+	return msN, seqN
+}
+
+// idTime is when the event with the stream ID was added
+func idTime(id string) time.Time {
+	ms, _ := splitID(id)
+	return time.UnixMilli(int64(ms))
+}
+
+func validID(id string) bool {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return false
+	}
+	_, errMs := strconv.ParseUint(ms, 10, 64)
+	_, errSeq := strconv.ParseUint(seq, 10, 64)
+	return errMs == nil && errSeq == nil
+}
@@ -0,0 +1,95 @@
+package realtime
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1700000000000-0", "1700000000000-0", 0},
+		{"1700000000000-0", "1700000000000-1", -1},
+		{"1700000000000-2", "1700000000000-1", 1},
+		{"1700000000000-9", "1700000000001-0", -1},
+		{"1700000000001-0", "1700000000000-9", 1},
+		// Numeric, not string, order
+		{"999-0", "1000-0", -1},
+		{"1700000000000-10", "1700000000000-9", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareIDs(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"1700000000000-0", true},
+		{"0-1", true},
+		{"1700000000000-18446744073709551615", true},
+		{"1700000000000", false},
+		{"", false},
+		{"-", false},
+		{"1700000000000-", false},
+		{"-5", false},
+		{"abc-0", false},
+		{"1700000000000-0-0", false},
+		{"-1700000000000-0", false},
+		{"1700000000000-18446744073709551616", false},
+		{"+", false},
+	}
+	for _, tt := range tests {
+		if got := validID(tt.id); got != tt.want {
+			t.Errorf("validID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestIDTime(t *testing.T) {
+	want := time.Date(2024, 3, 15, 9, 12, 45, 123_000_000, time.UTC)
+	if got := idTime("1710493965123-7"); !got.Equal(want) {
+		t.Errorf("idTime = %v, want %v", got, want)
+	}
+}
+
+func TestNeedsResync(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	id := func(at time.Time, seq int) string {
+		return formatID(at.UnixMilli(), seq)
+	}
+	hourAgo := now.Add(-time.Hour)
+	twoDaysAgo := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		name     string
+		lastID   string
+		oldestID string
+		want     bool
+	}{
+		{"saw the oldest kept event", id(hourAgo, 0), id(hourAgo, 0), false},
+		{"saw a later event", id(now, 3), id(hourAgo, 0), false},
+		{"saw an event that was trimmed", id(hourAgo, 0), id(hourAgo, 1), true},
+		{"saw an event long gone", id(twoDaysAgo, 0), id(hourAgo, 0), true},
+		{"nothing kept, recent last event", id(hourAgo, 0), "", false},
+		{"nothing kept, history expired since", id(twoDaysAgo, 0), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsResync(tt.lastID, tt.oldestID, now); got != tt.want {
+				t.Errorf("needsResync(%s, %q) = %v, want %v", tt.lastID, tt.oldestID, got, tt.want)
+			}
+		})
+	}
+}
+
+func formatID(ms int64, seq int) string {
+	return strconv.FormatInt(ms, 10) + "-" + strconv.Itoa(seq)
+}
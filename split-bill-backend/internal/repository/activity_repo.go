@@ -1,91 +1,136 @@
-package repository
-
-import (
-	"context"
-	"time"
-
-	"github.com/splitbill/backend/internal/database"
-	"github.com/splitbill/backend/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-type ActivityRepository struct {
-	collection *mongo.Collection
-}
-
-func NewActivityRepository(db *database.MongoDB) *ActivityRepository {
-	return &ActivityRepository{
-		collection: db.Collection(database.CollectionActivities),
-	}
-}
-
-func (r *ActivityRepository) Create(ctx context.Context, activity *models.Activity) error {
-	activity.CreatedAt = time.Now()
-
-	result, err := r.collection.InsertOne(ctx, activity)
-	if err != nil {
-		return err
-	}
-
-	activity.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-func (r *ActivityRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID, limit int64) ([]models.Activity, error) {
-	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetLimit(limit)
-
-	cursor, err := r.collection.Find(ctx, bson.M{"group_id": groupID}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var activities []models.Activity
-	if err := cursor.All(ctx, &activities); err != nil {
-		return nil, err
-	}
-	return activities, nil
-}
-
-func (r *ActivityRepository) FindByUserGroups(ctx context.Context, groupIDs []primitive.ObjectID, limit int64) ([]models.Activity, error) {
-	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetLimit(limit)
-
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"group_id": bson.M{"$in": groupIDs},
-	}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var activities []models.Activity
-	if err := cursor.All(ctx, &activities); err != nil {
-		return nil, err
-	}
-	return activities, nil
-}
-
-func (r *ActivityRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, limit int64) ([]models.Activity, error) {
-	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetLimit(limit)
-
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var activities []models.Activity
-	if err := cursor.All(ctx, &activities); err != nil {
-		return nil, err
-	}
-	return activities, nil
-}
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ActivityFilter narrows down activity feeds. Zero values are ignored.
+type ActivityFilter struct {
+	Types   []models.ActivityType
+	ActorID primitive.ObjectID // who did it
+	From    *time.Time
+	To      *time.Time
+}
+
+func (f ActivityFilter) toBSON() bson.M {
+	filter := bson.M{}
+	if len(f.Types) > 0 {
+		filter["type"] = bson.M{"$in": f.Types}
+	}
+	if !f.ActorID.IsZero() {
+		filter["user_id"] = f.ActorID
+	}
+	if f.From != nil || f.To != nil {
+		createdAt := bson.M{}
+		if f.From != nil {
+			createdAt["$gte"] = *f.From
+		}
+		if f.To != nil {
+			createdAt["$lt"] = *f.To
+		}
+		filter["created_at"] = createdAt
+	}
+	return filter
+}
+
+type ActivityRepository struct {
+	collection *mongo.Collection
+}
+
+func NewActivityRepository(db *database.MongoDB) *ActivityRepository {
+	return &ActivityRepository{
+		collection: db.Collection(database.CollectionActivities),
+	}
+}
+
+func (r *ActivityRepository) Create(ctx context.Context, activity *models.Activity) error {
+	activity.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	activity.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByGroupID returns a page of a group's activities, newest first, and
+// the cursor of the next page
+func (r *ActivityRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID, filter ActivityFilter, page utils.CursorPage) ([]models.Activity, *utils.Cursor, error) {
+	query := filter.toBSON()
+	query["group_id"] = groupID
+	return r.findPage(ctx, query, page)
+}
+
+// FindByUserGroups returns a page of the activities of several groups, newest first
+func (r *ActivityRepository) FindByUserGroups(ctx context.Context, groupIDs []primitive.ObjectID, filter ActivityFilter, page utils.CursorPage) ([]models.Activity, *utils.Cursor, error) {
+	query := filter.toBSON()
+	query["group_id"] = bson.M{"$in": groupIDs}
+	return r.findPage(ctx, query, page)
+}
+
+// FindByUserID returns a page of the activities a user did, newest first
+func (r *ActivityRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, filter ActivityFilter, page utils.CursorPage) ([]models.Activity, *utils.Cursor, error) {
+	query := filter.toBSON()
+	query["user_id"] = userID
+	return r.findPage(ctx, query, page)
+}
+
+func (r *ActivityRepository) findPage(ctx context.Context, query bson.M, page utils.CursorPage) ([]models.Activity, *utils.Cursor, error) {
+	if page.After != nil {
+		query["$or"] = beforeCursor(page.After)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(page.Limit) + 1)
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	activities := []models.Activity{}
+	if err := cursor.All(ctx, &activities); err != nil {
+		return nil, nil, err
+	}
+
+	var next *utils.Cursor
+	if len(activities) > page.Limit {
+		activities = activities[:page.Limit]
+		last := activities[len(activities)-1]
+		next = utils.NewCursor(last.CreatedAt, last.ID)
+	}
+	return activities, next, nil
+}
+
+// FindByGroupIDBetween returns a group's activities from from up to, but not including, to
+func (r *ActivityRepository) FindByGroupIDBetween(ctx context.Context, groupID primitive.ObjectID, from, to time.Time) ([]models.Activity, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"group_id":   groupID,
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []models.Activity
+	if err := cursor.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeferredNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDeferredNotificationRepository(db *database.MongoDB) *DeferredNotificationRepository {
+	return &DeferredNotificationRepository{
+		collection: db.Collection(database.CollectionDeferredNotifications),
+	}
+}
+
+func (r *DeferredNotificationRepository) Create(ctx context.Context, deferred *models.DeferredNotification) error {
+	deferred.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, deferred)
+	if err != nil {
+		return err
+	}
+
+	deferred.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ClaimDue removes and returns the oldest notification due at now, or nil
+// if there is none. Removing it is the claim, so with several server
+// instances each deferred notification is delivered once.
+func (r *DeferredNotificationRepository) ClaimDue(ctx context.Context, now time.Time) (*models.DeferredNotification, error) {
+	var deferred models.DeferredNotification
+	err := r.collection.FindOneAndDelete(
+		ctx,
+		bson.M{"deliver_at": bson.M{"$lte": now}},
+		options.FindOneAndDelete().SetSort(bson.D{{Key: "deliver_at", Value: 1}}),
+	).Decode(&deferred)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deferred, nil
+}
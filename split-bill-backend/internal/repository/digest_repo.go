@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DigestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDigestRepository(db *database.MongoDB) *DigestRepository {
+	return &DigestRepository{
+		collection: db.Collection(database.CollectionDigestDeliveries),
+	}
+}
+
+// CreateDelivery claims a digest. If another instance already sent it, it
+// returns a duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *DigestRepository) CreateDelivery(ctx context.Context, delivery *models.DigestDelivery) error {
+	delivery.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Exists reports whether the digest with the key has been handled
+func (r *DigestRepository) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"key": key})
+	return count > 0, err
+}
@@ -1,126 +1,268 @@
-package repository
-
-import (
-	"context"
-	"time"
-
-	"github.com/splitbill/backend/internal/database"
-	"github.com/splitbill/backend/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-type GroupRepository struct {
-	collection *mongo.Collection
-}
-
-func NewGroupRepository(db *database.MongoDB) *GroupRepository {
-	return &GroupRepository{
-		collection: db.Collection(database.CollectionGroups),
-	}
-}
-
-func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error {
-	group.CreatedAt = time.Now()
-	group.UpdatedAt = time.Now()
-
-	result, err := r.collection.InsertOne(ctx, group)
-	if err != nil {
-		return err
-	}
-
-	group.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-func (r *GroupRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Group, error) {
-	var group models.Group
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&group)
-	if err != nil {
-		return nil, err
-	}
-	return &group, nil
-}
-
-func (r *GroupRepository) FindByInviteCode(ctx context.Context, code string) (*models.Group, error) {
-	var group models.Group
-	err := r.collection.FindOne(ctx, bson.M{"invite_code": code, "is_active": true}).Decode(&group)
-	if err != nil {
-		return nil, err
-	}
-	return &group, nil
-}
-
-func (r *GroupRepository) FindByMemberUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Group, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"members.user_id": userID,
-		"is_active":       true,
-	}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var groups []models.Group
-	if err := cursor.All(ctx, &groups); err != nil {
-		return nil, err
-	}
-	return groups, nil
-}
-
-func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error {
-	group.UpdatedAt = time.Now()
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": group.ID},
-		bson.M{"$set": group},
-	)
-	return err
-}
-
-func (r *GroupRepository) AddMember(ctx context.Context, groupID primitive.ObjectID, member models.GroupMember) error {
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": groupID},
-		bson.M{
-			"$push": bson.M{"members": member},
-			"$set":  bson.M{"updated_at": time.Now()},
-		},
-	)
-	return err
-}
-
-func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) error {
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": groupID},
-		bson.M{
-			"$pull": bson.M{"members": bson.M{"user_id": userID}},
-			"$set":  bson.M{"updated_at": time.Now()},
-		},
-	)
-	return err
-}
-
-func (r *GroupRepository) IsMember(ctx context.Context, groupID, userID primitive.ObjectID) (bool, error) {
-	count, err := r.collection.CountDocuments(ctx, bson.M{
-		"_id":             groupID,
-		"members.user_id": userID,
-	})
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
-func (r *GroupRepository) Delete(ctx context.Context, groupID primitive.ObjectID) error {
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": groupID},
-		bson.M{"$set": bson.M{"is_active": false, "updated_at": time.Now()}},
-	)
-	return err
-}
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GroupRepository struct {
+	collection *mongo.Collection
+}
+
+func NewGroupRepository(db *database.MongoDB) *GroupRepository {
+	return &GroupRepository{
+		collection: db.Collection(database.CollectionGroups),
+	}
+}
+
+func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error {
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	group.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *GroupRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Group, error) {
+	var group models.Group
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&group)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *GroupRepository) FindByInviteCode(ctx context.Context, code string) (*models.Group, error) {
+	var group models.Group
+	err := r.collection.FindOne(ctx, bson.M{"invite_code": code, "is_active": true}).Decode(&group)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// SetInviteCode replaces the group's invite code, so the old one stops working
+func (r *GroupRepository) SetInviteCode(ctx context.Context, groupID primitive.ObjectID, code string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": groupID},
+		bson.M{"$set": bson.M{"invite_code": code, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *GroupRepository) FindByMemberUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Group, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"members.user_id": userID,
+		"is_active":       true,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []models.Group
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UpdateDetails saves the group's editable details. Members, roles and the
+// invite code have their own updates, so a concurrent join or role change
+// is not overwritten.
+func (r *GroupRepository) UpdateDetails(ctx context.Context, group *models.Group) error {
+	group.UpdatedAt = time.Now()
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": group.ID},
+		bson.M{"$set": bson.M{
+			"name":                   group.Name,
+			"description":            group.Description,
+			"avatar_url":             group.AvatarURL,
+			"join_approval_required": group.JoinApprovalRequired,
+			"updated_at":             group.UpdatedAt,
+		}},
+	)
+	return err
+}
+
+// AddMember adds a member unless the user is in the group already. Returns
+// mongo.ErrNoDocuments if they are.
+func (r *GroupRepository) AddMember(ctx context.Context, groupID primitive.ObjectID, member models.GroupMember) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": groupID, "members.user_id": bson.M{"$ne": member.UserID}},
+		bson.M{
+			"$push": bson.M{"members": member},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RemoveMember removes a member unless that would leave the group without
+// an owner. Returns mongo.ErrNoDocuments if the member is its last owner.
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": groupID, "members": keepsOwner([]primitive.ObjectID{userID})},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"user_id": userID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SetMemberRoles changes the roles of several members in one update, so an
+// ownership transfer is never half applied. Unless someone is made owner, the
+// update only applies while an owner outside the changed members remains.
+// Returns mongo.ErrNoDocuments if it did not apply.
+func (r *GroupRepository) SetMemberRoles(ctx context.Context, groupID primitive.ObjectID, roles map[primitive.ObjectID]models.MemberRole) error {
+	userIDs := make([]primitive.ObjectID, 0, len(roles))
+	set := bson.M{"updated_at": time.Now()}
+	arrayFilters := make([]interface{}, 0, len(roles))
+	makesOwner := false
+	i := 0
+	for userID, role := range roles {
+		userIDs = append(userIDs, userID)
+		name := fmt.Sprintf("m%d", i)
+		set["members.$["+name+"].role"] = role
+		arrayFilters = append(arrayFilters, bson.M{name + ".user_id": userID})
+		if role == models.RoleOwner {
+			makesOwner = true
+		}
+		i++
+	}
+
+	filter := bson.M{"_id": groupID, "members.user_id": bson.M{"$all": userIDs}}
+	if !makesOwner {
+		filter["members"] = keepsOwner(userIDs)
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		filter,
+		bson.M{"$set": set},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: arrayFilters}),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// FindWithoutOwner returns groups none of whose members is an owner, which
+// is every group created before the owner role existed
+func (r *GroupRepository) FindWithoutOwner(ctx context.Context) ([]models.Group, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"members.0":    bson.M{"$exists": true},
+		"members.role": bson.M{"$ne": models.RoleOwner},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []models.Group
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// keepsOwner matches member lists with an owner other than the given users
+func keepsOwner(userIDs []primitive.ObjectID) bson.M {
+	return bson.M{"$elemMatch": bson.M{
+		"role":    models.RoleOwner,
+		"user_id": bson.M{"$nin": userIDs},
+	}}
+}
+
+func (r *GroupRepository) IsMember(ctx context.Context, groupID, userID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"_id":             groupID,
+		"members.user_id": userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GroupRepository) Delete(ctx context.Context, groupID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": groupID},
+		bson.M{"$set": bson.M{"is_active": false, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// FindWithReminders returns active groups that have a reminder schedule turned on
+func (r *GroupRepository) FindWithReminders(ctx context.Context) ([]models.Group, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"is_active":          true,
+		"reminders.schedule": bson.M{"$in": []models.ReminderSchedule{models.ReminderScheduleWeekly, models.ReminderScheduleAfterBill}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []models.Group
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UpdateReminders replaces a group's reminder schedule
+func (r *GroupRepository) UpdateReminders(ctx context.Context, groupID primitive.ObjectID, settings *models.ReminderSettings) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": groupID},
+		bson.M{"$set": bson.M{"reminders": settings, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// SnoozeReminders pauses reminders to a member until a time; nil resumes them
+func (r *GroupRepository) SnoozeReminders(ctx context.Context, groupID, userID primitive.ObjectID, until *time.Time) error {
+	update := bson.M{"$unset": bson.M{"members.$.reminders_snoozed_until": ""}}
+	if until != nil {
+		update = bson.M{"$set": bson.M{"members.$.reminders_snoozed_until": *until}}
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": groupID, "members.user_id": userID}, update)
+	return err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InviteRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInviteRepository(db *database.MongoDB) *InviteRepository {
+	return &InviteRepository{
+		collection: db.Collection(database.CollectionInviteLinks),
+	}
+}
+
+// Create stores a new invite link. If its code is taken it returns a
+// duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *InviteRepository) Create(ctx context.Context, link *models.InviteLink) error {
+	link.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, link)
+	if err != nil {
+		return err
+	}
+	link.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID returns an invite link of a group, or mongo.ErrNoDocuments
+func (r *InviteRepository) FindByID(ctx context.Context, groupID, id primitive.ObjectID) (*models.InviteLink, error) {
+	var link models.InviteLink
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id, "group_id": groupID}).Decode(&link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByCode returns the invite link with a code, revoked or not
+func (r *InviteRepository) FindByCode(ctx context.Context, code string) (*models.InviteLink, error) {
+	var link models.InviteLink
+	if err := r.collection.FindOne(ctx, bson.M{"code": code}).Decode(&link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByGroupID returns a group's invite links, newest first
+func (r *InviteRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.InviteLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"group_id": groupID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	links := []models.InviteLink{}
+	if err := cursor.All(ctx, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// Use counts one join through the link if it is still usable at now, so
+// concurrent joins cannot go past its limit. Returns mongo.ErrNoDocuments
+// if the link is revoked, expired or used up.
+func (r *InviteRepository) Use(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":        id,
+			"revoked_at": bson.M{"$exists": false},
+			"$and": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"expires_at": bson.M{"$exists": false}},
+					bson.M{"expires_at": bson.M{"$gt": now}},
+				}},
+				bson.M{"$or": bson.A{
+					bson.M{"max_uses": 0},
+					bson.M{"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}},
+				}},
+			},
+		},
+		bson.M{"$inc": bson.M{"uses": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Release gives back a use taken by a join that did not go through
+func (r *InviteRepository) Release(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "uses": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"uses": -1}},
+	)
+	return err
+}
+
+// Revoke stops a link from working. Returns mongo.ErrNoDocuments if it
+// was already revoked.
+func (r *InviteRepository) Revoke(ctx context.Context, groupID, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "group_id": groupID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
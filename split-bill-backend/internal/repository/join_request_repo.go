@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type JoinRequestRepository struct {
+	collection *mongo.Collection
+}
+
+func NewJoinRequestRepository(db *database.MongoDB) *JoinRequestRepository {
+	return &JoinRequestRepository{
+		collection: db.Collection(database.CollectionJoinRequests),
+	}
+}
+
+// Create stores a pending join request. If the user already has one for
+// the group it returns a duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *JoinRequestRepository) Create(ctx context.Context, request *models.JoinRequest) error {
+	request.Status = models.JoinRequestPending
+	request.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, request)
+	if err != nil {
+		return err
+	}
+	request.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID returns a join request to a group, or mongo.ErrNoDocuments
+func (r *JoinRequestRepository) FindByID(ctx context.Context, groupID, id primitive.ObjectID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id, "group_id": groupID}).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPending returns the user's pending request to join a group, or
+// mongo.ErrNoDocuments
+func (r *JoinRequestRepository) FindPending(ctx context.Context, groupID, userID primitive.ObjectID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	filter := bson.M{"group_id": groupID, "user_id": userID, "status": models.JoinRequestPending}
+	if err := r.collection.FindOne(ctx, filter).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindByGroupID returns a group's join requests with a status, oldest
+// first so the queue is worked through in order
+func (r *JoinRequestRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID, status models.JoinRequestStatus) ([]models.JoinRequest, error) {
+	return r.find(ctx, bson.M{"group_id": groupID, "status": status}, 1)
+}
+
+// FindByUserID returns the user's join requests, newest first
+func (r *JoinRequestRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]models.JoinRequest, error) {
+	return r.find(ctx, bson.M{"user_id": userID}, -1)
+}
+
+func (r *JoinRequestRepository) find(ctx context.Context, filter bson.M, order int) ([]models.JoinRequest, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: order}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := []models.JoinRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Decide approves or denies a pending request, so two admins cannot both
+// decide it. Returns mongo.ErrNoDocuments if it was decided already.
+func (r *JoinRequestRepository) Decide(ctx context.Context, request *models.JoinRequest, status models.JoinRequestStatus, by primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": request.ID, "status": models.JoinRequestPending},
+		bson.M{"$set": bson.M{"status": status, "decided_by": by, "decided_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	request.Status = status
+	request.DecidedBy = &by
+	request.DecidedAt = &now
+	return nil
+}
+
+// Reopen puts a request back in the queue after its approval could not be
+// carried out
+func (r *JoinRequestRepository) Reopen(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": models.JoinRequestPending},
+			"$unset": bson.M{"decided_by": "", "decided_at": ""},
+		},
+	)
+	return err
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *database.MongoDB) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection(database.CollectionNotifications),
+	}
+}
+
+// CreateMany stores one inbox entry per recipient
+func (r *NotificationRepository) CreateMany(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(notifications))
+	for i := range notifications {
+		notifications[i].ID = primitive.NewObjectID()
+		notifications[i].CreatedAt = now
+		docs[i] = notifications[i]
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// FindByUserID returns a page of the user's inbox, newest first, and the
+// cursor of the next page (nil on the last page)
+func (r *NotificationRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, unreadOnly bool, page utils.CursorPage) ([]models.Notification, *utils.Cursor, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+	if page.After != nil {
+		filter["$or"] = beforeCursor(page.After)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(page.Limit) + 1)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, nil, err
+	}
+
+	var next *utils.Cursor
+	if len(notifications) > page.Limit {
+		notifications = notifications[:page.Limit]
+		last := notifications[len(notifications)-1]
+		next = utils.NewCursor(last.CreatedAt, last.ID)
+	}
+	return notifications, next, nil
+}
+
+// MarkRead marks one of the user's notifications read. It returns
+// mongo.ErrNoDocuments if the notification is not in the user's inbox.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id primitive.ObjectID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": id, "user_id": userID},
+		// Keep the first read time when marked read again
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&notification)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marks every unread notification of the user read
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// CountUnread counts the user's unread notifications
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+// beforeCursor matches the items that sort after the cursor in a
+// newest-first (created_at, _id) listing
+func beforeCursor(c *utils.Cursor) []bson.M {
+	return []bson.M{
+		{"created_at": bson.M{"$lt": c.CreatedAt}},
+		{"created_at": c.CreatedAt, "_id": bson.M{"$lt": c.ID}},
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PaymentEventRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPaymentEventRepository(db *database.MongoDB) *PaymentEventRepository {
+	return &PaymentEventRepository{
+		collection: db.Collection(database.CollectionPaymentEvents),
+	}
+}
+
+// Create records an event. A notification seen before returns a
+// duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *PaymentEventRepository) Create(ctx context.Context, event *models.PaymentEvent) error {
+	event.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+	event.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByProviderTxID returns the event a provider reported under providerTxID
+func (r *PaymentEventRepository) FindByProviderTxID(ctx context.Context, provider, providerTxID string) (*models.PaymentEvent, error) {
+	var event models.PaymentEvent
+	err := r.collection.FindOne(ctx, bson.M{"provider": provider, "provider_tx_id": providerTxID}).Decode(&event)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Resolve stores the outcome of matching an event
+func (r *PaymentEventRepository) Resolve(ctx context.Context, event *models.PaymentEvent) error {
+	set := bson.M{
+		"status": event.Status,
+		"reason": event.Reason,
+	}
+	if event.Reference != "" {
+		set["reference"] = event.Reference
+	}
+	if !event.TransactionID.IsZero() {
+		set["transaction_id"] = event.TransactionID
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": event.ID}, bson.M{"$set": set})
+	return err
+}
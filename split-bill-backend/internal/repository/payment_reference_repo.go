@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PaymentReferenceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPaymentReferenceRepository(db *database.MongoDB) *PaymentReferenceRepository {
+	return &PaymentReferenceRepository{
+		collection: db.Collection(database.CollectionPaymentReferences),
+	}
+}
+
+// Create inserts a reference. A code that is already taken returns a
+// duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *PaymentReferenceRepository) Create(ctx context.Context, ref *models.PaymentReference) error {
+	ref.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, ref)
+	if err != nil {
+		return err
+	}
+	ref.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *PaymentReferenceRepository) FindByCode(ctx context.Context, code string) (*models.PaymentReference, error) {
+	var ref models.PaymentReference
+	err := r.collection.FindOne(ctx, bson.M{"code": code}).Decode(&ref)
+	if err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// UpsertOpenSettlement returns the open settle-up reference for what
+// fromUser owes toUser, set to the debt's current amount, handing out code
+// for it if there is none. A duplicate key error (see
+// mongo.IsDuplicateKeyError) means code is taken or another caller handed
+// out a reference for the pair at the same time; trying again finds theirs.
+func (r *PaymentReferenceRepository) UpsertOpenSettlement(ctx context.Context, groupID, fromUser, toUser primitive.ObjectID, amount float64, code string) (*models.PaymentReference, error) {
+	var ref models.PaymentReference
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{
+		"kind":      models.ReferenceSettlement,
+		"group_id":  groupID,
+		"from_user": fromUser,
+		"to_user":   toUser,
+		"open":      true,
+	}, bson.M{
+		"$set":         bson.M{"amount": amount},
+		"$setOnInsert": bson.M{"code": code, "created_at": time.Now()},
+	}, opts).Decode(&ref)
+	if err != nil {
+		return nil, err
+	}
+	return &ref, nil
+}
+
+// Release undoes what reserving a reference for a transaction did, if the
+// transaction was never stored: a claimed settle-up reference is freed and
+// a reference issued for the transaction is deleted
+func (r *PaymentReferenceRepository) Release(ctx context.Context, code string, txID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{
+		"code":           code,
+		"kind":           models.ReferenceTransaction,
+		"transaction_id": txID,
+	})
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"code": code, "kind": models.ReferenceSettlement, "transaction_id": txID}
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{
+		"$unset": bson.M{"transaction_id": ""},
+		"$set":   bson.M{"open": true},
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		// The pair was handed a new reference meanwhile, which stays the
+		// open one; the freed code still works for whoever typed it
+		_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"transaction_id": ""}})
+	}
+	return err
+}
+
+// LinkTransaction attaches the transaction paying a reference, which closes
+// a settle-up reference so the pair gets a new one. Returns
+// mongo.ErrNoDocuments if the reference is already linked to a transaction.
+func (r *PaymentReferenceRepository) LinkTransaction(ctx context.Context, code string, txID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"code": code, "transaction_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"transaction_id": txID}, "$unset": bson.M{"open": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// placeholderUIDPrefix marks the made-up Firebase UIDs of placeholder
+// users, which only exist because the UID index is unique
+const placeholderUIDPrefix = "placeholder:"
+
+// PlaceholderRepository stores placeholder members, who have a user
+// document of their own so bills, balances and names work as for anyone,
+// and hands everything they have over to the account that claims them.
+// Both happen in a transaction across the collections involved.
+type PlaceholderRepository struct {
+	db           *database.MongoDB
+	users        *mongo.Collection
+	groups       *mongo.Collection
+	bills        *mongo.Collection
+	transactions *mongo.Collection
+	references   *mongo.Collection
+}
+
+func NewPlaceholderRepository(db *database.MongoDB) *PlaceholderRepository {
+	return &PlaceholderRepository{
+		db:           db,
+		users:        db.Collection(database.CollectionUsers),
+		groups:       db.Collection(database.CollectionGroups),
+		bills:        db.Collection(database.CollectionBills),
+		transactions: db.Collection(database.CollectionTransactions),
+		references:   db.Collection(database.CollectionPaymentReferences),
+	}
+}
+
+// Create stores a placeholder user and adds it to the group as a member
+func (r *PlaceholderRepository) Create(ctx context.Context, groupID primitive.ObjectID, name string) (*models.GroupMember, error) {
+	now := time.Now()
+	user := &models.User{
+		ID:          primitive.NewObjectID(),
+		DisplayName: name,
+		Placeholder: true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	user.FirebaseUID = placeholderUIDPrefix + user.ID.Hex()
+
+	member := &models.GroupMember{
+		UserID:      user.ID,
+		Nickname:    name,
+		Role:        models.RoleMember,
+		JoinedAt:    now,
+		Placeholder: true,
+	}
+
+	err := r.db.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		if _, err := r.users.InsertOne(sc, user); err != nil {
+			return err
+		}
+		result, err := r.groups.UpdateOne(sc,
+			bson.M{"_id": groupID, "is_active": true},
+			bson.M{
+				"$push": bson.M{"members": member},
+				"$set":  bson.M{"updated_at": now},
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// HasHistory reports whether the user appears in any of the group's bills or
+// payments, as someone who left the group would
+func (r *PlaceholderRepository) HasHistory(ctx context.Context, groupID, userID primitive.ObjectID) (bool, error) {
+	bills, err := r.bills.CountDocuments(ctx, bson.M{
+		"group_id": groupID,
+		"$or": bson.A{
+			bson.M{"paid_by": userID},
+			bson.M{"splits.user_id": userID},
+			bson.M{"items.assigned_to": userID},
+		},
+	}, options.Count().SetLimit(1))
+	if err != nil || bills > 0 {
+		return bills > 0, err
+	}
+	transactions, err := r.transactions.CountDocuments(ctx, bson.M{
+		"group_id": groupID,
+		"$or":      bson.A{bson.M{"from_user": userID}, bson.M{"to_user": userID}},
+	}, options.Count().SetLimit(1))
+	return transactions > 0, err
+}
+
+// Claim gives member.UserID the placeholder's place in the group: its
+// membership, its payments and shares of the group's bills, and the
+// payments and payment references to and from it. Callers check with
+// HasHistory first, since bills the user already has a share in would end
+// up with two of theirs. Nothing changes unless all of it does. Returns
+// mongo.ErrNoDocuments if the placeholder is no longer in the group or the
+// user already is.
+func (r *PlaceholderRepository) Claim(ctx context.Context, groupID, placeholderID primitive.ObjectID, member models.GroupMember) error {
+	to := member.UserID
+	now := time.Now()
+
+	return r.db.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		result, err := r.groups.UpdateOne(sc,
+			bson.M{
+				"_id":             groupID,
+				"members":         bson.M{"$elemMatch": bson.M{"user_id": placeholderID, "placeholder": true}},
+				"members.user_id": bson.M{"$ne": to},
+			},
+			bson.M{"$set": bson.M{"members.$[m]": member, "updated_at": now}},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"m.user_id": placeholderID}},
+			}),
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return mongo.ErrNoDocuments
+		}
+
+		inGroup := func(filter bson.M) bson.M {
+			filter["group_id"] = groupID
+			return filter
+		}
+		if _, err := r.bills.UpdateMany(sc,
+			inGroup(bson.M{"paid_by": placeholderID}),
+			bson.M{"$set": bson.M{"paid_by": to}},
+		); err != nil {
+			return err
+		}
+		if _, err := r.bills.UpdateMany(sc,
+			inGroup(bson.M{"splits.user_id": placeholderID}),
+			bson.M{"$set": bson.M{"splits.$[s].user_id": to}},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"s.user_id": placeholderID}},
+			}),
+		); err != nil {
+			return err
+		}
+		if _, err := r.bills.UpdateMany(sc,
+			inGroup(bson.M{"items.assigned_to": placeholderID}),
+			bson.M{"$set": bson.M{"items.$[].assigned_to.$[a]": to}},
+			options.Update().SetArrayFilters(options.ArrayFilters{
+				Filters: []interface{}{bson.M{"a": placeholderID}},
+			}),
+		); err != nil {
+			return err
+		}
+
+		for _, collection := range []*mongo.Collection{r.transactions, r.references} {
+			for _, field := range []string{"from_user", "to_user"} {
+				if _, err := collection.UpdateMany(sc,
+					inGroup(bson.M{field: placeholderID}),
+					bson.M{"$set": bson.M{field: to}},
+				); err != nil {
+					return err
+				}
+			}
+		}
+
+		// The placeholder stays so old activities still have a name to show
+		_, err = r.users.UpdateOne(sc,
+			bson.M{"_id": placeholderID},
+			bson.M{"$set": bson.M{"claimed_by": to, "updated_at": now}},
+		)
+		return err
+	})
+}
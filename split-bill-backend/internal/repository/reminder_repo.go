@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ReminderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReminderRepository(db *database.MongoDB) *ReminderRepository {
+	return &ReminderRepository{
+		collection: db.Collection(database.CollectionReminderDeliveries),
+	}
+}
+
+// CreateDelivery claims a reminder slot. If another instance already sent
+// the reminder, it returns a duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *ReminderRepository) CreateDelivery(ctx context.Context, delivery *models.ReminderDelivery) error {
+	delivery.CreatedAt = time.Now()
+	result, err := r.collection.InsertOne(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// DeleteDelivery gives up a claimed reminder slot whose reminder was not sent
+func (r *ReminderRepository) DeleteDelivery(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// CountSince counts reminders of a kind a member received in a group since a time
+func (r *ReminderRepository) CountSince(ctx context.Context, groupID, userID primitive.ObjectID, kind models.ReminderKind, since time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"group_id":   groupID,
+		"user_id":    userID,
+		"kind":       kind,
+		"created_at": bson.M{"$gte": since},
+	})
+}
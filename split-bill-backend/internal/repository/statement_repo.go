@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type StatementRepository struct {
+	imports *mongo.Collection
+	lines   *mongo.Collection
+}
+
+func NewStatementRepository(db *database.MongoDB) *StatementRepository {
+	return &StatementRepository{
+		imports: db.Collection(database.CollectionStatementImports),
+		lines:   db.Collection(database.CollectionStatementLines),
+	}
+}
+
+func (r *StatementRepository) CreateImport(ctx context.Context, imp *models.StatementImport) error {
+	imp.CreatedAt = time.Now()
+	result, err := r.imports.InsertOne(ctx, imp)
+	if err != nil {
+		return err
+	}
+	imp.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// UpdateImportCounts stores the line counts once reconciliation has finished
+func (r *StatementRepository) UpdateImportCounts(ctx context.Context, imp *models.StatementImport) error {
+	_, err := r.imports.UpdateOne(ctx, bson.M{"_id": imp.ID}, bson.M{"$set": bson.M{
+		"total_lines":     imp.TotalLines,
+		"incoming_lines":  imp.IncomingLines,
+		"duplicate_lines": imp.DuplicateLines,
+		"matched_lines":   imp.MatchedLines,
+		"unmatched_lines": imp.UnmatchedLines,
+	}})
+	return err
+}
+
+// FindImportsByUser returns one page of the user's imports, newest first, with the total count
+func (r *StatementRepository) FindImportsByUser(ctx context.Context, userID primitive.ObjectID, skip, limit int64) ([]models.StatementImport, int64, error) {
+	filter := bson.M{"user_id": userID}
+
+	total, err := r.imports.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := r.imports.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	imports := []models.StatementImport{}
+	if err := cursor.All(ctx, &imports); err != nil {
+		return nil, 0, err
+	}
+	return imports, total, nil
+}
+
+// CreateLine inserts a statement line. A line the user already imported
+// returns a duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *StatementRepository) CreateLine(ctx context.Context, line *models.StatementLine) error {
+	line.CreatedAt = time.Now()
+	result, err := r.lines.InsertOne(ctx, line)
+	if err != nil {
+		return err
+	}
+	line.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *StatementRepository) FindLineByID(ctx context.Context, id primitive.ObjectID) (*models.StatementLine, error) {
+	var line models.StatementLine
+	err := r.lines.FindOne(ctx, bson.M{"_id": id}).Decode(&line)
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// FindLines returns one page of the user's statement lines in the given status, newest first
+func (r *StatementRepository) FindLines(ctx context.Context, userID primitive.ObjectID, status models.StatementLineStatus, skip, limit int64) ([]models.StatementLine, int64, error) {
+	filter := bson.M{"user_id": userID, "status": status}
+
+	total, err := r.lines.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := r.lines.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	lines := []models.StatementLine{}
+	if err := cursor.All(ctx, &lines); err != nil {
+		return nil, 0, err
+	}
+	return lines, total, nil
+}
+
+// SetCandidates stores the transactions suggested for a line in the review queue
+func (r *StatementRepository) SetCandidates(ctx context.Context, id primitive.ObjectID, candidates []primitive.ObjectID) error {
+	_, err := r.lines.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"candidates": candidates}})
+	return err
+}
+
+// ResolveLine moves an unmatched line to a final status. Returns
+// mongo.ErrNoDocuments if the line was already resolved.
+func (r *StatementRepository) ResolveLine(ctx context.Context, id primitive.ObjectID, status models.StatementLineStatus, txID primitive.ObjectID, reason models.StatementMatchReason) error {
+	set := bson.M{
+		"status":      status,
+		"resolved_at": time.Now(),
+	}
+	if !txID.IsZero() {
+		set["transaction_id"] = txID
+	}
+	if reason != "" {
+		set["match_reason"] = reason
+	}
+
+	result, err := r.lines.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": models.StatementLineUnmatched},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReopenLine puts a line back into the review queue, e.g. when linking it failed
+func (r *StatementRepository) ReopenLine(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.lines.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": models.StatementLineUnmatched},
+		"$unset": bson.M{"transaction_id": "", "match_reason": "", "resolved_at": ""},
+	})
+	return err
+}
@@ -1,118 +1,304 @@
-package repository
-
-import (
-	"context"
-	"time"
-
-	"github.com/splitbill/backend/internal/database"
-	"github.com/splitbill/backend/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-type TransactionRepository struct {
-	collection *mongo.Collection
-}
-
-func NewTransactionRepository(db *database.MongoDB) *TransactionRepository {
-	return &TransactionRepository{
-		collection: db.Collection(database.CollectionTransactions),
-	}
-}
-
-func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
-	tx.CreatedAt = time.Now()
-
-	result, err := r.collection.InsertOne(ctx, tx)
-	if err != nil {
-		return err
-	}
-
-	tx.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-func (r *TransactionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error) {
-	var tx models.Transaction
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tx)
-	if err != nil {
-		return nil, err
-	}
-	return &tx, nil
-}
-
-func (r *TransactionRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.Transaction, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := r.collection.Find(ctx, bson.M{"group_id": groupID}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var transactions []models.Transaction
-	if err := cursor.All(ctx, &transactions); err != nil {
-		return nil, err
-	}
-	return transactions, nil
-}
-
-func (r *TransactionRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Transaction, error) {
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"$or": []bson.M{
-			{"from_user": userID},
-			{"to_user": userID},
-		},
-	}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var transactions []models.Transaction
-	if err := cursor.All(ctx, &transactions); err != nil {
-		return nil, err
-	}
-	return transactions, nil
-}
-
-func (r *TransactionRepository) FindConfirmedByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.Transaction, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{
-		"group_id": groupID,
-		"status":   models.TransactionConfirmed,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var transactions []models.Transaction
-	if err := cursor.All(ctx, &transactions); err != nil {
-		return nil, err
-	}
-	return transactions, nil
-}
-
-func (r *TransactionRepository) Confirm(ctx context.Context, id primitive.ObjectID) error {
-	now := time.Now()
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": id},
-		bson.M{"$set": bson.M{
-			"status":       models.TransactionConfirmed,
-			"confirmed_at": now,
-		}},
-	)
-	return err
-}
-
-func (r *TransactionRepository) Reject(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": id},
-		bson.M{"$set": bson.M{"status": models.TransactionRejected}},
-	)
-	return err
-}
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TransactionFilter narrows down transaction listings. Zero values are ignored.
+type TransactionFilter struct {
+	GroupID  primitive.ObjectID
+	MemberID primitive.ObjectID // matches either side of the transaction
+	Status   models.TransactionStatus
+	Type     models.TransactionType
+	From     *time.Time
+	To       *time.Time
+}
+
+func (f TransactionFilter) toBSON() bson.M {
+	filter := bson.M{}
+	if !f.GroupID.IsZero() {
+		filter["group_id"] = f.GroupID
+	}
+	if !f.MemberID.IsZero() {
+		filter["$or"] = []bson.M{
+			{"from_user": f.MemberID},
+			{"to_user": f.MemberID},
+		}
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	if f.Type != "" {
+		filter["type"] = f.Type
+	}
+	if f.From != nil || f.To != nil {
+		createdAt := bson.M{}
+		if f.From != nil {
+			createdAt["$gte"] = *f.From
+		}
+		if f.To != nil {
+			createdAt["$lt"] = *f.To
+		}
+		filter["created_at"] = createdAt
+	}
+	return filter
+}
+
+type TransactionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTransactionRepository(db *database.MongoDB) *TransactionRepository {
+	return &TransactionRepository{
+		collection: db.Collection(database.CollectionTransactions),
+	}
+}
+
+// Create inserts a transaction. A second pending or confirmed reversal of
+// the same transaction returns a duplicate key error (see mongo.IsDuplicateKeyError).
+func (r *TransactionRepository) Create(ctx context.Context, tx *models.Transaction) error {
+	tx.CreatedAt = time.Now()
+	tx.UpdatedAt = tx.CreatedAt
+
+	result, err := r.collection.InsertOne(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	tx.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *TransactionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (r *TransactionRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.Transaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"group_id": groupID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// FindPage returns one page of transactions matching the filter, newest first,
+// together with the total number of matches
+func (r *TransactionRepository) FindPage(ctx context.Context, filter TransactionFilter, skip, limit int64) ([]models.Transaction, int64, error) {
+	query := filter.toBSON()
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	transactions := []models.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
+func (r *TransactionRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Transaction, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"$or": []bson.M{
+			{"from_user": userID},
+			{"to_user": userID},
+		},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *TransactionRepository) FindConfirmedByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.Transaction, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"group_id": groupID,
+		"status":   models.TransactionConfirmed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// FindPendingToUser returns pending payments the user is waiting to receive,
+// created within [from, to) and optionally limited to one group. Reversals are
+// excluded since the recipient is not the one who confirms them.
+func (r *TransactionRepository) FindPendingToUser(ctx context.Context, userID, groupID primitive.ObjectID, from, to time.Time) ([]models.Transaction, error) {
+	filter := bson.M{
+		"to_user":    userID,
+		"status":     models.TransactionPending,
+		"type":       bson.M{"$ne": models.TransactionReversal},
+		"created_at": bson.M{"$gte": from, "$lt": to},
+	}
+	if !groupID.IsZero() {
+		filter["group_id"] = groupID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// FindPendingByGroupID returns a group's pending payments, without reversals
+func (r *TransactionRepository) FindPendingByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.Transaction, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"group_id": groupID,
+		"status":   models.TransactionPending,
+		"type":     bson.M{"$ne": models.TransactionReversal},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transactions []models.Transaction
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// FindActiveReversal returns the pending or confirmed reversal of a transaction, if any
+func (r *TransactionRepository) FindActiveReversal(ctx context.Context, originalID primitive.ObjectID) (*models.Transaction, error) {
+	var tx models.Transaction
+	err := r.collection.FindOne(ctx, bson.M{
+		"reversal_of": originalID,
+		"status": bson.M{"$in": []models.TransactionStatus{
+			models.TransactionPending,
+			models.TransactionConfirmed,
+		}},
+	}).Decode(&tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// Confirm marks a pending transaction as confirmed.
+// Returns mongo.ErrNoDocuments if the transaction is no longer pending.
+func (r *TransactionRepository) Confirm(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	return r.updatePending(ctx, id, bson.M{"$set": bson.M{
+		"status":       models.TransactionConfirmed,
+		"confirmed_at": now,
+		"updated_at":   now,
+	}})
+}
+
+// Reject marks a pending transaction as rejected.
+// Returns mongo.ErrNoDocuments if the transaction is no longer pending.
+func (r *TransactionRepository) Reject(ctx context.Context, id primitive.ObjectID) error {
+	return r.updatePending(ctx, id, bson.M{"$set": bson.M{
+		"status":     models.TransactionRejected,
+		"updated_at": time.Now(),
+	}})
+}
+
+// Amend applies changes to a pending transaction and appends the previous
+// values to its history. Returns mongo.ErrNoDocuments if it is no longer pending.
+func (r *TransactionRepository) Amend(ctx context.Context, id primitive.ObjectID, changes bson.M, revision models.TransactionRevision) error {
+	changes["updated_at"] = time.Now()
+	return r.updatePending(ctx, id, bson.M{
+		"$set":  changes,
+		"$push": bson.M{"history": revision},
+	})
+}
+
+// Cancel marks a pending transaction as cancelled and records why in its history.
+// Returns mongo.ErrNoDocuments if the transaction is no longer pending.
+func (r *TransactionRepository) Cancel(ctx context.Context, id primitive.ObjectID, revision models.TransactionRevision) error {
+	now := time.Now()
+	return r.updatePending(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":       models.TransactionCancelled,
+			"cancelled_at": now,
+			"updated_at":   now,
+		},
+		"$push": bson.M{"history": revision},
+	})
+}
+
+// MarkReversed links a confirmed transaction to the reversal that undid it
+func (r *TransactionRepository) MarkReversed(ctx context.Context, id, reversalID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": models.TransactionConfirmed},
+		bson.M{"$set": bson.M{
+			"reversed_by": reversalID,
+			"updated_at":  time.Now(),
+		}},
+	)
+	return err
+}
+
+// updatePending applies an update only while the transaction is still pending,
+// so concurrent confirm/cancel/edit calls cannot overwrite each other.
+func (r *TransactionRepository) updatePending(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": models.TransactionPending},
+		update,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -1,86 +1,151 @@
-package repository
-
-import (
-	"context"
-	"time"
-
-	"github.com/splitbill/backend/internal/database"
-	"github.com/splitbill/backend/internal/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-)
-
-type UserRepository struct {
-	collection *mongo.Collection
-}
-
-func NewUserRepository(db *database.MongoDB) *UserRepository {
-	return &UserRepository{
-		collection: db.Collection(database.CollectionUsers),
-	}
-}
-
-func (r *UserRepository) FindByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
-	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"firebase_uid": firebaseUID}).Decode(&user)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
-	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-func (r *UserRepository) FindByPhone(ctx context.Context, phone string) (*models.User, error) {
-	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"phone": phone}).Decode(&user)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-func (r *UserRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var users []models.User
-	if err := cursor.All(ctx, &users); err != nil {
-		return nil, err
-	}
-	return users, nil
-}
-
-func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
-
-	result, err := r.collection.InsertOne(ctx, user)
-	if err != nil {
-		return err
-	}
-
-	user.ID = result.InsertedID.(primitive.ObjectID)
-	return nil
-}
-
-func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
-	user.UpdatedAt = time.Now()
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": user.ID},
-		bson.M{"$set": user},
-	)
-	return err
-}
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserRepository(db *database.MongoDB) *UserRepository {
+	return &UserRepository{
+		collection: db.Collection(database.CollectionUsers),
+	}
+}
+
+func (r *UserRepository) FindByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
+	var user models.User
+	// Placeholders have made-up UIDs nobody can sign in with
+	err := r.collection.FindOne(ctx, bson.M{"firebase_uid": firebaseUID, "placeholder": bson.M{"$ne": true}}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindByPhone(ctx context.Context, phone string) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, bson.M{"phone": phone}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	user.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	user.UpdatedAt = time.Now()
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": user.ID},
+		bson.M{"$set": user},
+	)
+	return err
+}
+
+// AddDevice registers a push token for the user. A token belongs to one user
+// at a time, so it is first taken off any account that signed in on the same
+// device before. Only the most recently seen devices are kept.
+func (r *UserRepository) AddDevice(ctx context.Context, userID primitive.ObjectID, device models.Device) error {
+	if _, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"devices.token": device.Token},
+		bson.M{"$pull": bson.M{"devices": bson.M{"token": device.Token}}},
+	); err != nil {
+		return err
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$push": bson.M{"devices": bson.M{
+			"$each":  []models.Device{device},
+			"$sort":  bson.M{"last_seen_at": 1},
+			"$slice": -models.MaxDevicesPerUser,
+		}}},
+	)
+	return err
+}
+
+// RemoveDevice unregisters one of the user's push tokens
+func (r *UserRepository) RemoveDevice(ctx context.Context, userID primitive.ObjectID, token string) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$pull": bson.M{"devices": bson.M{"token": token}}},
+	)
+	return err
+}
+
+// RemoveDeviceTokens drops tokens the push service rejected, whoever they belong to
+func (r *UserRepository) RemoveDeviceTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"devices.token": bson.M{"$in": tokens}},
+		bson.M{"$pull": bson.M{"devices": bson.M{"token": bson.M{"$in": tokens}}}},
+	)
+	return err
+}
+
+// FindWithDigest returns the users who asked for weekly or monthly group digests
+func (r *UserRepository) FindWithDigest(ctx context.Context) ([]models.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"notification_preferences.digest": bson.M{"$in": []models.DigestFrequency{models.DigestWeekly, models.DigestMonthly}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/splitbill/backend/internal/database"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepository(db *database.MongoDB) *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:   db.Collection(database.CollectionGroupWebhooks),
+		deliveries: db.Collection(database.CollectionWebhookDeliveries),
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.GroupWebhook) error {
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	result, err := r.webhooks.InsertOne(ctx, webhook)
+	if err != nil {
+		return err
+	}
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID returns a webhook of a group, or mongo.ErrNoDocuments
+func (r *WebhookRepository) FindByID(ctx context.Context, groupID, id primitive.ObjectID) (*models.GroupWebhook, error) {
+	var webhook models.GroupWebhook
+	if err := r.webhooks.FindOne(ctx, bson.M{"_id": id, "group_id": groupID}).Decode(&webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// FindByGroupID returns a group's webhooks, oldest first
+func (r *WebhookRepository) FindByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.GroupWebhook, error) {
+	return r.find(ctx, bson.M{"group_id": groupID})
+}
+
+// FindActiveByGroupID returns the webhooks of a group that are switched on
+func (r *WebhookRepository) FindActiveByGroupID(ctx context.Context, groupID primitive.ObjectID) ([]models.GroupWebhook, error) {
+	return r.find(ctx, bson.M{"group_id": groupID, "active": true})
+}
+
+func (r *WebhookRepository) find(ctx context.Context, filter bson.M) ([]models.GroupWebhook, error) {
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.GroupWebhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.GroupWebhook) error {
+	webhook.UpdatedAt = time.Now()
+	_, err := r.webhooks.ReplaceOne(ctx, bson.M{"_id": webhook.ID}, webhook)
+	return err
+}
+
+// Delete removes a webhook and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, groupID, id primitive.ObjectID) error {
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id, "group_id": groupID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	_, err = r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": id})
+	return err
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.ID.IsZero() {
+		delivery.ID = primitive.NewObjectID()
+	}
+	delivery.CreatedAt = time.Now()
+	delivery.UpdatedAt = delivery.CreatedAt
+
+	_, err := r.deliveries.InsertOne(ctx, delivery)
+	return err
+}
+
+// FindDelivery returns a delivery of a webhook, or mongo.ErrNoDocuments
+func (r *WebhookRepository) FindDelivery(ctx context.Context, webhookID, id primitive.ObjectID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.deliveries.FindOne(ctx, bson.M{"_id": id, "webhook_id": webhookID}).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDeliveries returns a page of a webhook's delivery log, newest first,
+// optionally only with one status, and the cursor of the next page
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, webhookID primitive.ObjectID, status models.WebhookDeliveryStatus, page utils.CursorPage) ([]models.WebhookDelivery, *utils.Cursor, error) {
+	filter := bson.M{"webhook_id": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+	if page.After != nil {
+		filter["$or"] = beforeCursor(page.After)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(page.Limit) + 1)
+
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, nil, err
+	}
+
+	var next *utils.Cursor
+	if len(deliveries) > page.Limit {
+		deliveries = deliveries[:page.Limit]
+		last := deliveries[len(deliveries)-1]
+		next = utils.NewCursor(last.CreatedAt, last.ID)
+	}
+	return deliveries, next, nil
+}
+
+// ClaimDue takes the oldest pending delivery due at now, or returns nil if
+// there is none. Its next attempt is pushed back by lease, so no other
+// instance attempts it meanwhile, and a delivery whose attempt never
+// finished is tried again once the lease runs out.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.deliveries.FindOneAndUpdate(
+		ctx,
+		bson.M{"status": models.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// SaveAttempt records the outcome of an attempt
+func (r *WebhookRepository) SaveAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	_, err := r.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
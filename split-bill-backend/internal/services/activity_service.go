@@ -1,176 +1,379 @@
-package services
-
-import (
-	"context"
-	"fmt"
-
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.uber.org/zap"
-)
-
-type ActivityService struct {
-	activityRepo *repository.ActivityRepository
-	userRepo     *repository.UserRepository
-	groupRepo    *repository.GroupRepository
-	logger       *zap.Logger
-}
-
-func NewActivityService(
-	activityRepo *repository.ActivityRepository,
-	userRepo *repository.UserRepository,
-	groupRepo *repository.GroupRepository,
-	logger *zap.Logger,
-) *ActivityService {
-	return &ActivityService{
-		activityRepo: activityRepo,
-		userRepo:     userRepo,
-		groupRepo:    groupRepo,
-		logger:       logger,
-	}
-}
-
-// LogActivity creates a new activity record
-func (s *ActivityService) LogActivity(ctx context.Context, groupID, userID primitive.ObjectID, actType models.ActivityType, title, detail string, amount float64, refID string) {
-	activity := &models.Activity{
-		GroupID: groupID,
-		UserID:  userID,
-		Type:    actType,
-		Title:   title,
-		Detail:  detail,
-		Amount:  amount,
-		RefID:   refID,
-	}
-
-	if err := s.activityRepo.Create(ctx, activity); err != nil {
-		s.logger.Error("Failed to log activity", zap.Error(err))
-	}
-}
-
-// LogBillCreated logs a bill creation event
-func (s *ActivityService) LogBillCreated(ctx context.Context, bill *models.Bill, creatorName string) {
-	detail := fmt.Sprintf("%s đã tạo hóa đơn \"%s\" - %s", creatorName, bill.Title, formatVNDAmount(bill.TotalAmount))
-	s.LogActivity(ctx, bill.GroupID, bill.PaidBy, models.ActivityBillCreated, "Hóa đơn mới", detail, bill.TotalAmount, bill.ID.Hex())
-}
-
-// LogPaymentSent logs a payment sent event
-func (s *ActivityService) LogPaymentSent(ctx context.Context, tx *models.Transaction, fromName, toName string) {
-	detail := fmt.Sprintf("%s đã gửi %s cho %s", fromName, formatVNDAmount(tx.Amount), toName)
-	s.LogActivity(ctx, tx.GroupID, tx.FromUser, models.ActivityPaymentSent, "Thanh toán", detail, tx.Amount, tx.ID.Hex())
-}
-
-// LogPaymentConfirmed logs a payment confirmation event
-func (s *ActivityService) LogPaymentConfirmed(ctx context.Context, tx *models.Transaction, confirmerName, senderName string) {
-	detail := fmt.Sprintf("%s đã xác nhận thanh toán %s từ %s", confirmerName, formatVNDAmount(tx.Amount), senderName)
-	s.LogActivity(ctx, tx.GroupID, tx.ToUser, models.ActivityPaymentConfirmed, "Xác nhận thanh toán", detail, tx.Amount, tx.ID.Hex())
-}
-
-// LogPaymentRejected logs a payment rejection event
-func (s *ActivityService) LogPaymentRejected(ctx context.Context, tx *models.Transaction, rejecterName, senderName string) {
-	detail := fmt.Sprintf("%s đã từ chối thanh toán %s từ %s", rejecterName, formatVNDAmount(tx.Amount), senderName)
-	s.LogActivity(ctx, tx.GroupID, tx.ToUser, models.ActivityPaymentRejected, "Từ chối thanh toán", detail, tx.Amount, tx.ID.Hex())
-}
-
-// LogMemberJoined logs a member join event
-func (s *ActivityService) LogMemberJoined(ctx context.Context, groupID, userID primitive.ObjectID, memberName, groupName string) {
-	detail := fmt.Sprintf("%s đã tham gia nhóm \"%s\"", memberName, groupName)
-	s.LogActivity(ctx, groupID, userID, models.ActivityMemberJoined, "Thành viên mới", detail, 0, "")
-}
-
-// GetGroupActivities gets activities for a specific group
-func (s *ActivityService) GetGroupActivities(ctx context.Context, groupID string, limit int64) ([]models.ActivityResponse, error) {
-	objID, err := primitive.ObjectIDFromHex(groupID)
-	if err != nil {
-		return nil, err
-	}
-
-	if limit <= 0 || limit > 50 {
-		limit = 20
-	}
-
-	activities, err := s.activityRepo.FindByGroupID(ctx, objID, limit)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.enrichActivities(ctx, activities)
-}
-
-// GetUserActivities gets activities across all user's groups
-func (s *ActivityService) GetUserActivities(ctx context.Context, userID primitive.ObjectID, limit int64) ([]models.ActivityResponse, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 30
-	}
-
-	// Get all groups the user belongs to
-	groups, err := s.groupRepo.FindByMemberUserID(ctx, userID)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(groups) == 0 {
-		return []models.ActivityResponse{}, nil
-	}
-
-	groupIDs := make([]primitive.ObjectID, len(groups))
-	groupNameMap := make(map[string]string)
-	for i, g := range groups {
-		groupIDs[i] = g.ID
-		groupNameMap[g.ID.Hex()] = g.Name
-	}
-
-	activities, err := s.activityRepo.FindByUserGroups(ctx, groupIDs, limit)
-	if err != nil {
-		return nil, err
-	}
-
-	responses, err := s.enrichActivities(ctx, activities)
-	if err != nil {
-		return nil, err
-	}
-
-	// Add group names
-	for i := range responses {
-		if name, ok := groupNameMap[responses[i].GroupID]; ok {
-			responses[i].GroupName = name
-		}
-	}
-
-	return responses, nil
-}
-
-// enrichActivities adds user details to activity responses
-func (s *ActivityService) enrichActivities(ctx context.Context, activities []models.Activity) ([]models.ActivityResponse, error) {
-	responses := make([]models.ActivityResponse, len(activities))
-	userCache := make(map[string]*models.User)
-
-	for i, activity := range activities {
-		responses[i] = activity.ToResponse()
-
-		userIDStr := activity.UserID.Hex()
-		if user, ok := userCache[userIDStr]; ok {
-			responses[i].UserName = user.DisplayName
-			responses[i].UserAvatar = user.AvatarURL
-		} else {
-			user, err := s.userRepo.FindByID(ctx, activity.UserID)
-			if err == nil {
-				userCache[userIDStr] = user
-				responses[i].UserName = user.DisplayName
-				responses[i].UserAvatar = user.AvatarURL
-			}
-		}
-	}
-
-	return responses, nil
-}
-
-func formatVNDAmount(amount float64) string {
-	if amount >= 1000000 {
-		return fmt.Sprintf("%.1ftr₫", amount/1000000)
-	}
-	if amount >= 1000 {
-		return fmt.Sprintf("%.0fk₫", amount/1000)
-	}
-	return fmt.Sprintf("%.0f₫", amount)
-}
+package services
+
+import (
+	"context"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/i18n"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type ActivityService struct {
+	activityRepo *repository.ActivityRepository
+	userRepo     *repository.UserRepository
+	groupRepo    *repository.GroupRepository
+	logger       *zap.Logger
+}
+
+func NewActivityService(
+	activityRepo *repository.ActivityRepository,
+	userRepo *repository.UserRepository,
+	groupRepo *repository.GroupRepository,
+	logger *zap.Logger,
+) *ActivityService {
+	return &ActivityService{
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		logger:       logger,
+	}
+}
+
+// LogActivity creates a new activity record
+func (s *ActivityService) LogActivity(ctx context.Context, groupID, userID primitive.ObjectID, actType models.ActivityType, title, detail string, amount float64, refID string) {
+	activity := &models.Activity{
+		GroupID: groupID,
+		UserID:  userID,
+		Type:    actType,
+		Amount:  amount,
+		RefID:   refID,
+	}
+
+	if err := s.activityRepo.Create(ctx, activity); err != nil {
+		s.logger.Error("Failed to log activity", zap.Error(err))
+	}
+}
+
+// RegisterEventHandlers records group events in the activity feed. A failed
+// insert is returned to the bus so the event is tried again.
+func (s *ActivityService) RegisterEventHandlers(bus *events.Bus) {
+	events.SubscribeAsync(bus, "activity", s.billCreated)
+	events.SubscribeAsync(bus, "activity", s.billUpdated)
+	events.SubscribeAsync(bus, "activity", s.billDeleted)
+	events.SubscribeAsync(bus, "activity", func(ctx context.Context, e events.TransactionCreated) error {
+		return s.payment(ctx, models.ActivityPaymentSent, &e.Transaction, e.Actor)
+	})
+	events.SubscribeAsync(bus, "activity", func(ctx context.Context, e events.TransactionConfirmed) error {
+		return s.payment(ctx, models.ActivityPaymentConfirmed, &e.Transaction, e.Actor)
+	})
+	events.SubscribeAsync(bus, "activity", func(ctx context.Context, e events.TransactionRejected) error {
+		return s.payment(ctx, models.ActivityPaymentRejected, &e.Transaction, e.Actor)
+	})
+	events.SubscribeAsync(bus, "activity", s.memberJoined)
+	events.SubscribeAsync(bus, "activity", s.memberLeft)
+}
+
+func (s *ActivityService) billCreated(ctx context.Context, e events.BillCreated) error {
+	bill := &e.Bill
+	actor := s.actorRef(ctx, e.Actor)
+	activity := &models.Activity{
+		GroupID: bill.GroupID,
+		UserID:  e.Actor.ID,
+		Type:    models.ActivityBillCreated,
+		Amount:  bill.TotalAmount,
+		RefID:   bill.ID.Hex(),
+		Payload: &models.ActivityPayload{
+			Actor:    actor,
+			Target:   billRef(bill),
+			Amount:   bill.TotalAmount,
+			Currency: bill.Currency,
+		},
+	}
+
+	if e.OCRResultID != nil {
+		activity.Type = models.ActivityReceiptConfirmed
+		activity.Payload.Source = &models.ActivityRef{Kind: models.ActivityRefReceipt, ID: e.OCRResultID.Hex()}
+	}
+
+	return s.record(ctx, activity)
+}
+
+func (s *ActivityService) billUpdated(ctx context.Context, e events.BillUpdated) error {
+	changes := billChanges(&e.Previous, &e.Bill)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	bill := &e.Bill
+	actor := s.actorRef(ctx, e.Actor)
+	return s.record(ctx, &models.Activity{
+		GroupID: bill.GroupID,
+		UserID:  e.Actor.ID,
+		Type:    models.ActivityBillUpdated,
+		Amount:  bill.TotalAmount,
+		RefID:   bill.ID.Hex(),
+		Payload: &models.ActivityPayload{
+			Actor:    actor,
+			Target:   billRef(bill),
+			Amount:   bill.TotalAmount,
+			Currency: bill.Currency,
+			Changes:  changes,
+		},
+	})
+}
+
+func (s *ActivityService) billDeleted(ctx context.Context, e events.BillDeleted) error {
+	bill := &e.Bill
+	actor := s.actorRef(ctx, e.Actor)
+	return s.record(ctx, &models.Activity{
+		GroupID: bill.GroupID,
+		UserID:  e.Actor.ID,
+		Type:    models.ActivityBillDeleted,
+		Amount:  bill.TotalAmount,
+		RefID:   bill.ID.Hex(),
+		Payload: &models.ActivityPayload{
+			Actor:    actor,
+			Target:   billRef(bill),
+			Amount:   bill.TotalAmount,
+			Currency: bill.Currency,
+		},
+	})
+}
+
+// payment records a payment being sent, confirmed or rejected. The
+// counterparty is the recipient of a sent payment and the sender otherwise.
+// Reversals are bookkeeping and stay out of the feed.
+func (s *ActivityService) payment(ctx context.Context, actType models.ActivityType, tx *models.Transaction, e events.Actor) error {
+	if tx.Type == models.TransactionReversal {
+		return nil
+	}
+
+	actor := s.actorRef(ctx, e)
+	counterparty := s.userRef(ctx, tx.FromUser)
+	if actType == models.ActivityPaymentSent {
+		counterparty = s.userRef(ctx, tx.ToUser)
+	}
+
+	return s.record(ctx, &models.Activity{
+		GroupID: tx.GroupID,
+		UserID:  e.ID,
+		Type:    actType,
+		Amount:  tx.Amount,
+		RefID:   tx.ID.Hex(),
+		Payload: &models.ActivityPayload{
+			Actor:        actor,
+			Target:       models.ActivityRef{Kind: models.ActivityRefTransaction, ID: tx.ID.Hex()},
+			Counterparty: &counterparty,
+			Amount:       tx.Amount,
+			Currency:     tx.Currency,
+		},
+	})
+}
+
+func (s *ActivityService) memberJoined(ctx context.Context, e events.MemberJoined) error {
+	member := models.ActivityRef{Kind: models.ActivityRefUser, ID: e.Member.UserID.Hex(), Name: e.MemberName}
+	actor := member
+	if e.AddedBy != nil {
+		actor = s.actorRef(ctx, *e.AddedBy)
+	}
+
+	return s.record(ctx, &models.Activity{
+		GroupID: e.GroupID,
+		UserID:  objectID(actor.ID),
+		Type:    models.ActivityMemberJoined,
+		Payload: &models.ActivityPayload{
+			Actor:  actor,
+			Target: member,
+			Group:  &models.ActivityRef{Kind: models.ActivityRefGroup, ID: e.GroupID.Hex(), Name: e.GroupName},
+		},
+	})
+}
+
+func (s *ActivityService) memberLeft(ctx context.Context, e events.MemberLeft) error {
+	member := s.userRef(ctx, e.UserID)
+	actor := member
+	if e.RemovedBy != nil {
+		actor = s.actorRef(ctx, *e.RemovedBy)
+	}
+
+	return s.record(ctx, &models.Activity{
+		GroupID: e.GroupID,
+		UserID:  objectID(actor.ID),
+		Type:    models.ActivityMemberLeft,
+		Payload: &models.ActivityPayload{
+			Actor:  actor,
+			Target: member,
+			Group:  &models.ActivityRef{Kind: models.ActivityRefGroup, ID: e.GroupID.Hex(), Name: e.GroupName},
+		},
+	})
+}
+
+// record stores an activity with its text in Vietnamese, for readers that
+// do not render the payload themselves
+func (s *ActivityService) record(ctx context.Context, activity *models.Activity) error {
+	activity.Title, activity.Detail = i18n.ActivityText(i18n.Vietnamese, activity.Type, activity.Payload, "", "")
+	return s.activityRepo.Create(ctx, activity)
+}
+
+// billChanges lists the fields that differ between two versions of a bill
+func billChanges(old, new *models.Bill) []models.ActivityChange {
+	var changes []models.ActivityChange
+	change := func(field string, before, after interface{}) {
+		if before != after {
+			changes = append(changes, models.ActivityChange{Field: field, Old: nonZero(before), New: nonZero(after)})
+		}
+	}
+
+	change("title", old.Title, new.Title)
+	change("description", old.Description, new.Description)
+	change("category", old.Category, new.Category)
+	change("total_amount", old.TotalAmount, new.TotalAmount)
+	change("currency", old.Currency, new.Currency)
+	change("paid_by", old.PaidBy.Hex(), new.PaidBy.Hex())
+	change("split_type", string(old.SplitType), string(new.SplitType))
+	change("status", string(old.Status), string(new.Status))
+	change("tax", old.ExtraCharges.Tax, new.ExtraCharges.Tax)
+	change("service_charge", old.ExtraCharges.ServiceCharge, new.ExtraCharges.ServiceCharge)
+	change("tip", old.ExtraCharges.Tip, new.ExtraCharges.Tip)
+	change("discount", old.ExtraCharges.Discount, new.ExtraCharges.Discount)
+	return changes
+}
+
+// nonZero drops empty values, so they are left out of a change
+func nonZero(v interface{}) interface{} {
+	switch v {
+	case "", 0.0:
+		return nil
+	}
+	return v
+}
+
+func billRef(bill *models.Bill) models.ActivityRef {
+	return models.ActivityRef{Kind: models.ActivityRefBill, ID: bill.ID.Hex(), Name: bill.Title}
+}
+
+func (s *ActivityService) actorRef(ctx context.Context, actor events.Actor) models.ActivityRef {
+	return models.ActivityRef{Kind: models.ActivityRefUser, ID: actor.ID.Hex(), Name: actorName(ctx, s.userRepo, actor)}
+}
+
+func (s *ActivityService) userRef(ctx context.Context, userID primitive.ObjectID) models.ActivityRef {
+	return s.actorRef(ctx, events.Actor{ID: userID})
+}
+
+// objectID parses the ID of an ActivityRef, which was made from one
+func objectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
+
+// actorName is the display name of an event's actor, looked up when the
+// publisher only knew the ID
+func actorName(ctx context.Context, userRepo *repository.UserRepository, actor events.Actor) string {
+	if actor.Name != "" {
+		return actor.Name
+	}
+	user, err := userRepo.FindByID(ctx, actor.ID)
+	if err != nil {
+		return ""
+	}
+	return user.DisplayName
+}
+
+// GetGroupActivities returns a page of a group's activities, newest first,
+// rendered in lang
+func (s *ActivityService) GetGroupActivities(ctx context.Context, groupID string, filter repository.ActivityFilter, page utils.CursorPage, lang i18n.Lang) ([]models.ActivityResponse, *utils.Cursor, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activities, next, err := s.activityRepo.FindByGroupID(ctx, objID, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses, err := s.enrichActivities(ctx, activities, lang)
+	return responses, next, err
+}
+
+// GetUserActivities returns a page of the activities across all the user's
+// groups, newest first, rendered in lang
+func (s *ActivityService) GetUserActivities(ctx context.Context, userID primitive.ObjectID, filter repository.ActivityFilter, page utils.CursorPage, lang i18n.Lang) ([]models.ActivityResponse, *utils.Cursor, error) {
+	groups, err := s.groupRepo.FindByMemberUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(groups) == 0 {
+		return []models.ActivityResponse{}, nil, nil
+	}
+
+	groupIDs := make([]primitive.ObjectID, len(groups))
+	for i, g := range groups {
+		groupIDs[i] = g.ID
+	}
+
+	activities, next, err := s.activityRepo.FindByUserGroups(ctx, groupIDs, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses, err := s.enrichActivities(ctx, activities, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+	addGroupNames(responses, groups)
+	return responses, next, nil
+}
+
+// GetOwnActivities returns a page of the activities the user did themselves,
+// newest first, rendered in lang
+func (s *ActivityService) GetOwnActivities(ctx context.Context, userID primitive.ObjectID, filter repository.ActivityFilter, page utils.CursorPage, lang i18n.Lang) ([]models.ActivityResponse, *utils.Cursor, error) {
+	activities, next, err := s.activityRepo.FindByUserID(ctx, userID, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses, err := s.enrichActivities(ctx, activities, lang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, err := s.groupRepo.FindByMemberUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	addGroupNames(responses, groups)
+	return responses, next, nil
+}
+
+// addGroupNames fills in the names of the groups the activities happened in
+func addGroupNames(responses []models.ActivityResponse, groups []models.Group) {
+	names := make(map[string]string, len(groups))
+	for _, g := range groups {
+		names[g.ID.Hex()] = g.Name
+	}
+	for i := range responses {
+		responses[i].GroupName = names[responses[i].GroupID]
+	}
+}
+
+// enrichActivities adds user details to activity responses and renders them in lang
+func (s *ActivityService) enrichActivities(ctx context.Context, activities []models.Activity, lang i18n.Lang) ([]models.ActivityResponse, error) {
+	responses := make([]models.ActivityResponse, len(activities))
+	userCache := make(map[string]*models.User)
+
+	for i, activity := range activities {
+		responses[i] = activity.ToResponse()
+		i18n.RenderActivity(lang, &responses[i])
+
+		userIDStr := activity.UserID.Hex()
+		if user, ok := userCache[userIDStr]; ok {
+			responses[i].UserName = user.DisplayName
+			responses[i].UserAvatar = user.AvatarURL
+		} else {
+			user, err := s.userRepo.FindByID(ctx, activity.UserID)
+			if err == nil {
+				userCache[userIDStr] = user
+				responses[i].UserName = user.DisplayName
+				responses[i].UserAvatar = user.AvatarURL
+			}
+		}
+	}
+
+	return responses, nil
+}
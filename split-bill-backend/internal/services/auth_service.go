@@ -1,90 +1,275 @@
-package services
-
-import (
-	"context"
-	"errors"
-	"time"
-
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-)
-
-type AuthService struct {
-	userRepo *repository.UserRepository
-}
-
-func NewAuthService(userRepo *repository.UserRepository) *AuthService {
-	return &AuthService{userRepo: userRepo}
-}
-
-// VerifyAndGetUser finds or creates a user based on Firebase UID
-func (s *AuthService) VerifyAndGetUser(ctx context.Context, firebaseUID, phone string) (*models.User, error) {
-	// Try to find existing user
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err == nil {
-		return user, nil
-	}
-
-	if !errors.Is(err, mongo.ErrNoDocuments) {
-		return nil, err
-	}
-
-	// User doesn't exist, create new one
-	newUser := &models.User{
-		FirebaseUID:  firebaseUID,
-		Phone:        phone,
-		DisplayName:  "User",
-		BankAccounts: []models.BankAccount{},
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-	}
-
-	if err := s.userRepo.Create(ctx, newUser); err != nil {
-		return nil, err
-	}
-
-	return newUser, nil
-}
-
-// GetUserByID gets a user by their ID
-func (s *AuthService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, errors.New("invalid user ID")
-	}
-	return s.userRepo.FindByID(ctx, objID)
-}
-
-// GetUserByFirebaseUID gets a user by their Firebase UID
-func (s *AuthService) GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
-	return s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-}
-
-// UpdateProfile updates a user's profile
-func (s *AuthService) UpdateProfile(ctx context.Context, firebaseUID string, req models.UpdateUserRequest) (*models.User, error) {
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return nil, err
-	}
-
-	if req.DisplayName != "" {
-		user.DisplayName = req.DisplayName
-	}
-	if req.AvatarURL != "" {
-		user.AvatarURL = req.AvatarURL
-	}
-	if req.BankAccounts != nil {
-		user.BankAccounts = req.BankAccounts
-	}
-	if req.PreferredPayment != "" {
-		user.PreferredPayment = req.PreferredPayment
-	}
-
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, err
-	}
-
-	return user, nil
-}
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/notify"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/pkg/banks"
+	"github.com/splitbill/backend/pkg/safehttp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrInvalidBankAccount is returned when a saved bank account does not match the bank directory
+var ErrInvalidBankAccount = errors.New("invalid bank account")
+
+// ErrInvalidNotificationAddress is returned for an unusable email or webhook address
+var ErrInvalidNotificationAddress = errors.New("invalid notification address")
+
+// ErrInvalidNotificationPreferences is returned for preferences naming unknown channels, types or groups
+var ErrInvalidNotificationPreferences = errors.New("invalid notification preferences")
+
+type AuthService struct {
+	userRepo *repository.UserRepository
+}
+
+func NewAuthService(userRepo *repository.UserRepository) *AuthService {
+	return &AuthService{userRepo: userRepo}
+}
+
+// VerifyAndGetUser finds or creates a user based on Firebase UID
+func (s *AuthService) VerifyAndGetUser(ctx context.Context, firebaseUID, phone string) (*models.User, error) {
+	// Try to find existing user
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err == nil {
+		return user, nil
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// User doesn't exist, create new one
+	newUser := &models.User{
+		FirebaseUID:  firebaseUID,
+		Phone:        phone,
+		DisplayName:  "User",
+		BankAccounts: []models.BankAccount{},
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := s.userRepo.Create(ctx, newUser); err != nil {
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+// GetUserByID gets a user by their ID
+func (s *AuthService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return s.userRepo.FindByID(ctx, objID)
+}
+
+// GetUserByFirebaseUID gets a user by their Firebase UID
+func (s *AuthService) GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
+	return s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+}
+
+// UpdateProfile updates a user's profile
+func (s *AuthService) UpdateProfile(ctx context.Context, firebaseUID string, req models.UpdateUserRequest) (*models.User, error) {
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DisplayName != "" {
+		user.DisplayName = req.DisplayName
+	}
+	if req.AvatarURL != "" {
+		user.AvatarURL = req.AvatarURL
+	}
+	if req.BankAccounts != nil {
+		accounts, err := validateBankAccounts(req.BankAccounts)
+		if err != nil {
+			return nil, err
+		}
+		user.BankAccounts = accounts
+	}
+	if req.PreferredPayment != "" {
+		user.PreferredPayment = req.PreferredPayment
+	}
+	if req.Email != nil {
+		email, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		user.Email = email
+	}
+	if req.ZaloUserID != nil {
+		user.ZaloUserID = strings.TrimSpace(*req.ZaloUserID)
+	}
+	if req.WebhookURL != nil {
+		webhookURL, err := validateWebhookURL(*req.WebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		user.WebhookURL = webhookURL
+	}
+	if req.NotificationPreferences != nil {
+		if err := validateNotificationPreferences(req.NotificationPreferences); err != nil {
+			return nil, err
+		}
+		user.NotificationPreferences = req.NotificationPreferences
+	}
+	if req.Language != nil {
+		user.Language = *req.Language
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RegisterDevice saves a push token for the user, or refreshes it if it is already registered
+func (s *AuthService) RegisterDevice(ctx context.Context, firebaseUID string, req models.RegisterDeviceRequest) (*models.Device, error) {
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	device := models.Device{
+		Token:      strings.TrimSpace(req.Token),
+		Platform:   req.Platform,
+		AppVersion: req.AppVersion,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	for _, existing := range user.Devices {
+		if existing.Token == device.Token {
+			device.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	if err := s.userRepo.AddDevice(ctx, user.ID, device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// UnregisterDevice stops push notifications to one of the user's devices, e.g. on logout
+func (s *AuthService) UnregisterDevice(ctx context.Context, firebaseUID, token string) error {
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.RemoveDevice(ctx, user.ID, token)
+}
+
+// normalizeEmail checks an email address and strips any display name; "" clears it
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", nil
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", fmt.Errorf("%w: email: %s", ErrInvalidNotificationAddress, email)
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+// validateWebhookURL only accepts https URLs, since messages can contain amounts owed,
+// and not ones naming localhost or a private IP; "" clears it
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return "", fmt.Errorf("%w: webhook_url must be an https URL", ErrInvalidNotificationAddress)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", fmt.Errorf("%w: webhook_url must be a public address", ErrInvalidNotificationAddress)
+	}
+	if ip := net.ParseIP(host); ip != nil && !safehttp.IsPublicIP(ip) {
+		return "", fmt.Errorf("%w: webhook_url must be a public address", ErrInvalidNotificationAddress)
+	}
+	return parsed.String(), nil
+}
+
+// validateNotificationPreferences checks that preferences only name known
+// channels, notification types and groups, and that quiet hours parse
+func validateNotificationPreferences(prefs *models.NotificationPreferences) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidNotificationPreferences, fmt.Sprintf(format, args...))
+	}
+
+	if err := prefs.Validate(); err != nil {
+		return invalid("%s", err.Error())
+	}
+
+	channels := map[string]bool{}
+	for _, name := range []notify.ChannelName{notify.ChannelPush, notify.ChannelEmail, notify.ChannelZalo, notify.ChannelWebhook} {
+		channels[string(name)] = true
+	}
+	checkChannels := func(names []string) error {
+		for _, name := range names {
+			if !channels[name] {
+				return invalid("unknown channel %q", name)
+			}
+		}
+		return nil
+	}
+
+	for name := range prefs.Channels {
+		if !channels[name] {
+			return invalid("unknown channel %q", name)
+		}
+	}
+	for notifType, pref := range prefs.Types {
+		if !slices.Contains(notificationTypes, NotificationType(notifType)) {
+			return invalid("unknown notification type %q", notifType)
+		}
+		if err := checkChannels(pref.Channels); err != nil {
+			return err
+		}
+	}
+	for groupID, pref := range prefs.Groups {
+		if _, err := primitive.ObjectIDFromHex(groupID); err != nil {
+			return invalid("invalid group ID %q", groupID)
+		}
+		if err := checkChannels(pref.Channels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBankAccounts checks each account against the bank registry and stores
+// it under the bank's canonical code, so "CTG" and "970415" both become "ICB"
+func validateBankAccounts(accounts []models.BankAccount) ([]models.BankAccount, error) {
+	registry := banks.Default()
+	validated := make([]models.BankAccount, 0, len(accounts))
+	for i, account := range accounts {
+		bank, number, err := registry.ValidateAccount(account.BankCode, account.AccountNumber)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %s", ErrInvalidBankAccount, i+1, err.Error())
+		}
+		validated = append(validated, models.BankAccount{
+			BankCode:      bank.Code,
+			AccountNumber: number,
+			AccountName:   strings.TrimSpace(account.AccountName),
+		})
+	}
+	return validated, nil
+}
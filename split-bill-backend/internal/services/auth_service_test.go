@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/splitbill/backend/internal/models"
+)
+
+func TestValidateBankAccounts(t *testing.T) {
+	got, err := validateBankAccounts([]models.BankAccount{
+		{BankCode: "CTG", AccountNumber: "1010 2345 6789", AccountName: " NGUYEN VAN AN "},
+		{BankCode: "970436", AccountNumber: "0071000123456"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.BankAccount{
+		{BankCode: "ICB", AccountNumber: "101023456789", AccountName: "NGUYEN VAN AN"},
+		{BankCode: "VCB", AccountNumber: "0071000123456"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("validateBankAccounts = %+v, want %+v", got, want)
+	}
+
+	_, err = validateBankAccounts([]models.BankAccount{
+		{BankCode: "VCB", AccountNumber: "0071000123456"},
+		{BankCode: "VBA", AccountNumber: "12345"},
+	})
+	if !errors.Is(err, ErrInvalidBankAccount) || !strings.Contains(err.Error(), "account 2") {
+		t.Errorf("err = %v, want ErrInvalidBankAccount naming account 2", err)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"https://hooks.slack.com/services/T0/B0/x", "https://hooks.slack.com/services/T0/B0/x", false},
+		{" https://discord.com/api/webhooks/1/abc ", "https://discord.com/api/webhooks/1/abc", false},
+		{"", "", false},
+		{"http://hooks.slack.com/services/T0/B0/x", "", true},
+		{"hooks.slack.com/services", "", true},
+		{"https://localhost/hook", "", true},
+		{"https://api.localhost:8443/hook", "", true},
+		{"https://127.0.0.1/hook", "", true},
+		{"https://10.0.0.5/hook", "", true},
+		{"https://169.254.169.254/latest/meta-data", "", true},
+		{"https://100.64.1.1/hook", "", true},
+		{"https://[::1]/hook", "", true},
+	}
+	for _, tt := range tests {
+		got, err := validateWebhookURL(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("validateWebhookURL(%q) = %q, %v, want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidNotificationAddress) {
+			t.Errorf("validateWebhookURL(%q): err = %v, want ErrInvalidNotificationAddress", tt.raw, err)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// digestHour is when digests go out, in each user's own timezone
+const digestHour = 8
+
+// DigestService summarizes each group for the members who would rather get
+// one weekly or monthly digest than a notification for everything
+type DigestService struct {
+	userRepo        *repository.UserRepository
+	groupRepo       *repository.GroupRepository
+	transactionRepo *repository.TransactionRepository
+	activityRepo    *repository.ActivityRepository
+	digestRepo      *repository.DigestRepository
+	statsService    *StatsService
+	debtService     *DebtService
+	notifService    *NotificationService
+	logger          *zap.Logger
+}
+
+func NewDigestService(
+	userRepo *repository.UserRepository,
+	groupRepo *repository.GroupRepository,
+	transactionRepo *repository.TransactionRepository,
+	activityRepo *repository.ActivityRepository,
+	digestRepo *repository.DigestRepository,
+	statsService *StatsService,
+	debtService *DebtService,
+	notifService *NotificationService,
+	logger *zap.Logger,
+) *DigestService {
+	return &DigestService{
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+		transactionRepo: transactionRepo,
+		activityRepo:    activityRepo,
+		digestRepo:      digestRepo,
+		statsService:    statsService,
+		debtService:     debtService,
+		notifService:    notifService,
+		logger:          logger,
+	}
+}
+
+// Start sends due digests every interval until ctx is cancelled. Every
+// server instance may run it; the delivery log keeps digests single.
+func (s *DigestService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx, time.Now()); err != nil {
+			s.logger.Warn("Digest run failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue sends the digests that are due at now. Running it again for the
+// same period sends nothing new.
+func (s *DigestService) RunDue(ctx context.Context, now time.Time) error {
+	users, err := s.userRepo.FindWithDigest(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		if err := s.runUser(ctx, &users[i], now); err != nil {
+			s.logger.Warn("Digests failed for user",
+				zap.String("user_id", users[i].ID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// runUser sends the user one digest per group for the last full period,
+// once it is past digestHour on the first day after it
+func (s *DigestService) runUser(ctx context.Context, user *models.User, now time.Time) error {
+	prefs := user.NotificationPreferences
+	frequency := prefs.DigestFrequency()
+	if frequency == models.DigestOff {
+		return nil
+	}
+
+	start, end := digestPeriod(frequency, now, prefs.Location())
+	if now.Before(end.Add(digestHour * time.Hour)) {
+		return nil
+	}
+
+	groups, err := s.groupRepo.FindByMemberUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		if prefs.Groups[group.ID.Hex()].Muted || joinedAfter(group, user.ID, end) {
+			continue
+		}
+
+		key := fmt.Sprintf("digest:%s:%s:%s:%s", frequency, user.ID.Hex(), group.ID.Hex(), start.Format("2006-01-02"))
+		done, err := s.digestRepo.Exists(ctx, key)
+		if err != nil {
+			return err
+		}
+		if done {
+			continue
+		}
+
+		digest, err := s.BuildDigest(ctx, group, user.ID, frequency, start, end)
+		if err != nil {
+			return err
+		}
+
+		delivery := &models.DigestDelivery{
+			Key:         key,
+			UserID:      user.ID,
+			GroupID:     group.ID,
+			Frequency:   frequency,
+			PeriodStart: start,
+			Empty:       digest.IsEmpty(),
+		}
+		if err := s.digestRepo.CreateDelivery(ctx, delivery); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return err
+		}
+		if delivery.Empty {
+			continue
+		}
+
+		if err := s.notifService.NotifyDigest(ctx, user.ID, digest); err != nil {
+			s.logger.Warn("Failed to send digest",
+				zap.String("user_id", user.ID.Hex()),
+				zap.String("group_id", group.ID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// BuildDigest summarizes a group for one member over a period: the bills
+// added, where the money went, the member's balance now and the payments
+// waiting to be confirmed
+func (s *DigestService) BuildDigest(ctx context.Context, group *models.Group, userID primitive.ObjectID, frequency models.DigestFrequency, start, end time.Time) (*models.Digest, error) {
+	period, err := s.statsService.GetGroupPeriodStats(ctx, group.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &models.Digest{
+		GroupID:       group.ID.Hex(),
+		GroupName:     group.Name,
+		Frequency:     frequency,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		NewBills:      make([]models.DigestBill, len(period.Bills)),
+		NewBillsTotal: period.TotalSpent,
+	}
+	for i, bill := range period.Bills {
+		digest.NewBills[i] = models.DigestBill{
+			ID:         bill.ID,
+			Title:      bill.Title,
+			Amount:     bill.Amount,
+			Category:   bill.Category,
+			PaidByName: bill.PaidByName,
+			CreatedAt:  bill.CreatedAt,
+		}
+	}
+	if len(period.CategoryStats) > 0 {
+		top := period.CategoryStats[0]
+		digest.TopCategory = &models.DigestCategory{
+			Category:   top.Category,
+			Total:      top.Total,
+			Percentage: top.Percentage,
+		}
+	}
+
+	balances, err := s.debtService.GetGroupBalances(ctx, group.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		if balance.UserID == userID.Hex() {
+			digest.Balance = balance.Balance
+		}
+	}
+
+	pending, err := s.transactionRepo.FindPendingByGroupID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range pending {
+		switch userID {
+		case tx.ToUser:
+			digest.ToConfirm++
+			digest.ToConfirmAmount += tx.Amount
+		case tx.FromUser:
+			digest.AwaitingConfirm++
+			digest.AwaitingAmount += tx.Amount
+		}
+	}
+
+	activities, err := s.activityRepo.FindByGroupIDBetween(ctx, group.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities {
+		switch activity.Type {
+		case models.ActivityPaymentConfirmed:
+			digest.PaymentsConfirmed++
+		case models.ActivityMemberJoined:
+			digest.MembersJoined++
+		}
+	}
+
+	return digest, nil
+}
+
+// Preview builds the user's digest of a group for the last full period,
+// as it was or will be sent
+func (s *DigestService) Preview(ctx context.Context, groupID string, user *models.User, frequency models.DigestFrequency) (*models.Digest, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, errors.New("invalid group ID")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if !isGroupMember(group, user.ID) {
+		return nil, ErrNotGroupMember
+	}
+
+	if frequency == "" || frequency == models.DigestOff {
+		frequency = user.NotificationPreferences.DigestFrequency()
+	}
+	if frequency == models.DigestOff {
+		frequency = models.DigestWeekly
+	}
+
+	start, end := digestPeriod(frequency, time.Now(), user.NotificationPreferences.Location())
+	return s.BuildDigest(ctx, group, user.ID, frequency, start, end)
+}
+
+// digestPeriod returns the last full week (Monday to Sunday) or calendar
+// month before now, in loc
+func digestPeriod(frequency models.DigestFrequency, now time.Time, loc *time.Location) (start, end time.Time) {
+	local := now.In(loc)
+	if frequency == models.DigestMonthly {
+		end = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+		return end.AddDate(0, -1, 0), end
+	}
+
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	end = time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	return end.AddDate(0, 0, -7), end
+}
+
+// joinedAfter reports whether the user joined the group after t
+func joinedAfter(group *models.Group, userID primitive.ObjectID, t time.Time) bool {
+	for _, member := range group.Members {
+		if member.UserID == userID {
+			return member.JoinedAt.After(t)
+		}
+	}
+	return true
+}
@@ -1,288 +1,499 @@
-package services
-
-import (
-	"context"
-	"errors"
-	"time"
-
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-	"github.com/splitbill/backend/internal/utils"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-)
-
-type GroupService struct {
-	groupRepo *repository.GroupRepository
-	userRepo  *repository.UserRepository
-}
-
-func NewGroupService(groupRepo *repository.GroupRepository, userRepo *repository.UserRepository) *GroupService {
-	return &GroupService{
-		groupRepo: groupRepo,
-		userRepo:  userRepo,
-	}
-}
-
-// CreateGroup creates a new group with the creator as admin
-func (s *GroupService) CreateGroup(ctx context.Context, creatorFirebaseUID string, req models.CreateGroupRequest) (*models.Group, error) {
-	creator, err := s.userRepo.FindByFirebaseUID(ctx, creatorFirebaseUID)
-	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	group := &models.Group{
-		Name:        req.Name,
-		Description: req.Description,
-		AvatarURL:   req.AvatarURL,
-		CreatedBy:   creator.ID,
-		Members: []models.GroupMember{
-			{
-				UserID:   creator.ID,
-				Nickname: creator.DisplayName,
-				Role:     models.RoleAdmin,
-				JoinedAt: time.Now(),
-			},
-		},
-		InviteCode: utils.GenerateInviteCode(),
-		IsActive:   true,
-	}
-
-	if err := s.groupRepo.Create(ctx, group); err != nil {
-		return nil, err
-	}
-
-	return group, nil
-}
-
-// GetGroup gets a group by ID, verifying the user is a member
-func (s *GroupService) GetGroup(ctx context.Context, groupID string, firebaseUID string) (*models.Group, error) {
-	objID, err := primitive.ObjectIDFromHex(groupID)
-	if err != nil {
-		return nil, errors.New("invalid group ID")
-	}
-
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	group, err := s.groupRepo.FindByID(ctx, objID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("group not found")
-		}
-		return nil, err
-	}
-
-	// Check membership
-	isMember := false
-	for _, m := range group.Members {
-		if m.UserID == user.ID {
-			isMember = true
-			break
-		}
-	}
-	if !isMember {
-		return nil, errors.New("you are not a member of this group")
-	}
-
-	return group, nil
-}
-
-// ListGroups lists all groups for a user
-func (s *GroupService) ListGroups(ctx context.Context, firebaseUID string) ([]models.Group, error) {
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	return s.groupRepo.FindByMemberUserID(ctx, user.ID)
-}
-
-// UpdateGroup updates group details
-func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, firebaseUID string, req models.UpdateGroupRequest) (*models.Group, error) {
-	group, err := s.GetGroup(ctx, groupID, firebaseUID)
-	if err != nil {
-		return nil, err
-	}
-
-	if req.Name != "" {
-		group.Name = req.Name
-	}
-	if req.Description != "" {
-		group.Description = req.Description
-	}
-	if req.AvatarURL != "" {
-		group.AvatarURL = req.AvatarURL
-	}
-
-	if err := s.groupRepo.Update(ctx, group); err != nil {
-		return nil, err
-	}
-
-	return group, nil
-}
-
-// AddMember adds a member to a group
-func (s *GroupService) AddMember(ctx context.Context, groupID string, firebaseUID string, req models.AddMemberRequest) error {
-	group, err := s.GetGroup(ctx, groupID, firebaseUID)
-	if err != nil {
-		return err
-	}
-
-	newMemberID, err := primitive.ObjectIDFromHex(req.UserID)
-	if err != nil {
-		return errors.New("invalid user ID")
-	}
-
-	// Check if user exists
-	newMember, err := s.userRepo.FindByID(ctx, newMemberID)
-	if err != nil {
-		return errors.New("user not found")
-	}
-
-	// Check if already a member
-	for _, m := range group.Members {
-		if m.UserID == newMemberID {
-			return errors.New("user is already a member")
-		}
-	}
-
-	nickname := req.Nickname
-	if nickname == "" {
-		nickname = newMember.DisplayName
-	}
-
-	member := models.GroupMember{
-		UserID:   newMemberID,
-		Nickname: nickname,
-		Role:     models.RoleMember,
-		JoinedAt: time.Now(),
-	}
-
-	return s.groupRepo.AddMember(ctx, group.ID, member)
-}
-
-// JoinGroup joins a group using an invite code
-func (s *GroupService) JoinGroup(ctx context.Context, firebaseUID string, inviteCode string) (*models.Group, error) {
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	group, err := s.groupRepo.FindByInviteCode(ctx, inviteCode)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("invalid invite code")
-		}
-		return nil, err
-	}
-
-	// Check if already a member
-	for _, m := range group.Members {
-		if m.UserID == user.ID {
-			return group, nil // Already a member, just return group
-		}
-	}
-
-	member := models.GroupMember{
-		UserID:   user.ID,
-		Nickname: user.DisplayName,
-		Role:     models.RoleMember,
-		JoinedAt: time.Now(),
-	}
-
-	if err := s.groupRepo.AddMember(ctx, group.ID, member); err != nil {
-		return nil, err
-	}
-
-	// Refresh group data
-	return s.groupRepo.FindByID(ctx, group.ID)
-}
-
-// RemoveMember removes a member from a group
-func (s *GroupService) RemoveMember(ctx context.Context, groupID string, firebaseUID string, memberUserID string) error {
-	group, err := s.GetGroup(ctx, groupID, firebaseUID)
-	if err != nil {
-		return err
-	}
-
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return errors.New("user not found")
-	}
-
-	// Check if requester is admin
-	isAdmin := false
-	for _, m := range group.Members {
-		if m.UserID == user.ID && m.Role == models.RoleAdmin {
-			isAdmin = true
-			break
-		}
-	}
-
-	memberObjID, err := primitive.ObjectIDFromHex(memberUserID)
-	if err != nil {
-		return errors.New("invalid member user ID")
-	}
-
-	// Can remove self, or admin can remove others
-	if user.ID != memberObjID && !isAdmin {
-		return errors.New("only admins can remove other members")
-	}
-
-	return s.groupRepo.RemoveMember(ctx, group.ID, memberObjID)
-}
-
-// DeleteGroup soft-deletes a group (admin only)
-func (s *GroupService) DeleteGroup(ctx context.Context, groupID string, firebaseUID string) error {
-	group, err := s.GetGroup(ctx, groupID, firebaseUID)
-	if err != nil {
-		return err
-	}
-
-	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
-	if err != nil {
-		return errors.New("user not found")
-	}
-
-	// Check if admin
-	for _, m := range group.Members {
-		if m.UserID == user.ID && m.Role == models.RoleAdmin {
-			return s.groupRepo.Delete(ctx, group.ID)
-		}
-	}
-
-	return errors.New("only admins can delete groups")
-}
-
-// GetGroupWithMemberDetails returns a group response with full member details
-func (s *GroupService) GetGroupWithMemberDetails(ctx context.Context, group *models.Group) (*models.GroupResponse, error) {
-	resp := group.ToResponse()
-
-	// Fetch member details
-	memberIDs := make([]primitive.ObjectID, len(group.Members))
-	for i, m := range group.Members {
-		memberIDs[i] = m.UserID
-	}
-
-	users, err := s.userRepo.FindByIDs(ctx, memberIDs)
-	if err != nil {
-		return &resp, nil // Return without details if fetch fails
-	}
-
-	userMap := make(map[string]*models.User)
-	for i := range users {
-		userMap[users[i].ID.Hex()] = &users[i]
-	}
-
-	for i, m := range resp.Members {
-		if user, ok := userMap[m.UserID]; ok {
-			resp.Members[i].DisplayName = user.DisplayName
-			resp.Members[i].AvatarURL = user.AvatarURL
-		}
-	}
-
-	return &resp, nil
-}
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrGroupForbidden  = errors.New("your role in this group does not allow this")
+	ErrLastOwner       = errors.New("the group's last owner cannot leave or step down; transfer ownership first")
+	ErrMemberNotFound  = errors.New("user is not a member of this group")
+	ErrCannotPromote   = errors.New("admins cannot be promoted further; transfer ownership instead")
+	ErrCannotDemote    = errors.New("viewers cannot be demoted further")
+	ErrAlreadyOwner    = errors.New("user already owns this group")
+	ErrPlaceholderRole = errors.New("placeholder members cannot be given roles until someone claims them")
+)
+
+type GroupService struct {
+	groupRepo       *repository.GroupRepository
+	userRepo        *repository.UserRepository
+	placeholderRepo *repository.PlaceholderRepository
+	bus             *events.Bus
+}
+
+func NewGroupService(groupRepo *repository.GroupRepository, userRepo *repository.UserRepository, placeholderRepo *repository.PlaceholderRepository, bus *events.Bus) *GroupService {
+	return &GroupService{
+		groupRepo:       groupRepo,
+		userRepo:        userRepo,
+		placeholderRepo: placeholderRepo,
+		bus:             bus,
+	}
+}
+
+// CreateGroup creates a new group with the creator as owner
+func (s *GroupService) CreateGroup(ctx context.Context, creatorFirebaseUID string, req models.CreateGroupRequest) (*models.Group, error) {
+	creator, err := s.userRepo.FindByFirebaseUID(ctx, creatorFirebaseUID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	group := &models.Group{
+		Name:        req.Name,
+		Description: req.Description,
+		AvatarURL:   req.AvatarURL,
+		CreatedBy:   creator.ID,
+		Members: []models.GroupMember{
+			{
+				UserID:   creator.ID,
+				Nickname: creator.DisplayName,
+				Role:     models.RoleOwner,
+				JoinedAt: time.Now(),
+			},
+		},
+		InviteCode: utils.GenerateInviteCode(),
+		IsActive:   true,
+	}
+
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroup gets a group by ID, verifying the user is a member
+func (s *GroupService) GetGroup(ctx context.Context, groupID string, firebaseUID string) (*models.Group, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, errors.New("invalid group ID")
+	}
+
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("group not found")
+		}
+		return nil, err
+	}
+
+	if group.Member(user.ID) == nil {
+		return nil, ErrNotGroupMember
+	}
+
+	return group, nil
+}
+
+// groupAs loads a group for one of its members whose role grants perm
+func (s *GroupService) groupAs(ctx context.Context, groupID string, firebaseUID string, perm models.Permission) (*models.Group, *models.User, *models.GroupMember, error) {
+	group, err := s.GetGroup(ctx, groupID, firebaseUID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, nil, nil, errors.New("user not found")
+	}
+
+	member := group.Member(user.ID)
+	if !member.Role.Can(perm) {
+		return nil, nil, nil, ErrGroupForbidden
+	}
+	return group, user, member, nil
+}
+
+// ListGroups lists all groups for a user
+func (s *GroupService) ListGroups(ctx context.Context, firebaseUID string) ([]models.Group, error) {
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.groupRepo.FindByMemberUserID(ctx, user.ID)
+}
+
+// UpdateGroup updates group details (owners and admins)
+func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, firebaseUID string, req models.UpdateGroupRequest) (*models.Group, error) {
+	group, _, _, err := s.groupAs(ctx, groupID, firebaseUID, models.PermManageGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		group.Name = req.Name
+	}
+	if req.Description != "" {
+		group.Description = req.Description
+	}
+	if req.AvatarURL != "" {
+		group.AvatarURL = req.AvatarURL
+	}
+	if req.JoinApprovalRequired != nil {
+		group.JoinApprovalRequired = *req.JoinApprovalRequired
+	}
+
+	if err := s.groupRepo.UpdateDetails(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// AddMember adds a member to a group (anyone but viewers)
+func (s *GroupService) AddMember(ctx context.Context, groupID string, firebaseUID string, req models.AddMemberRequest) error {
+	group, adder, _, err := s.groupAs(ctx, groupID, firebaseUID, models.PermAddMembers)
+	if err != nil {
+		return err
+	}
+
+	newMemberID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+
+	// Check if user exists. Placeholders belong to the group they were made in.
+	newMember, err := s.userRepo.FindByID(ctx, newMemberID)
+	if err != nil || newMember.Placeholder {
+		return errors.New("user not found")
+	}
+
+	// Check if already a member
+	for _, m := range group.Members {
+		if m.UserID == newMemberID {
+			return errors.New("user is already a member")
+		}
+	}
+
+	nickname := req.Nickname
+	if nickname == "" {
+		nickname = newMember.DisplayName
+	}
+
+	member := models.GroupMember{
+		UserID:   newMemberID,
+		Nickname: nickname,
+		Role:     models.RoleMember,
+		JoinedAt: time.Now(),
+	}
+
+	if err := s.groupRepo.AddMember(ctx, group.ID, member); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("user is already a member")
+		}
+		return err
+	}
+
+	event := events.MemberJoined{
+		GroupID:    group.ID,
+		GroupName:  group.Name,
+		Member:     member,
+		MemberName: newMember.DisplayName,
+	}
+	actor := events.ActorOf(adder)
+	event.AddedBy = &actor
+	s.bus.Publish(ctx, event)
+
+	return nil
+}
+
+// AddPlaceholder adds a named member without an account, for someone who
+// does not use the app yet. They share bills like anyone else until they
+// claim their place through a claim link (see InviteService).
+func (s *GroupService) AddPlaceholder(ctx context.Context, groupID string, firebaseUID string, req models.AddPlaceholderRequest) (*models.GroupMember, error) {
+	group, adder, _, err := s.groupAs(ctx, groupID, firebaseUID, models.PermAddMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	member, err := s.placeholderRepo.Create(ctx, group.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := events.ActorOf(adder)
+	s.bus.Publish(ctx, events.MemberJoined{
+		GroupID:    group.ID,
+		GroupName:  group.Name,
+		Member:     *member,
+		MemberName: name,
+		AddedBy:    &actor,
+	})
+
+	return member, nil
+}
+
+// RemoveMember removes a member from a group. Anyone can leave; owners and
+// admins can remove members ranked below them, and owners anyone. The last
+// owner cannot leave.
+func (s *GroupService) RemoveMember(ctx context.Context, groupID string, firebaseUID string, memberUserID string) error {
+	group, err := s.GetGroup(ctx, groupID, firebaseUID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	memberObjID, err := primitive.ObjectIDFromHex(memberUserID)
+	if err != nil {
+		return errors.New("invalid member user ID")
+	}
+
+	target := group.Member(memberObjID)
+	if target == nil {
+		return ErrMemberNotFound
+	}
+	if user.ID != memberObjID && !canManage(group.Member(user.ID).Role, target.Role, models.PermRemoveMembers) {
+		return ErrGroupForbidden
+	}
+
+	if err := s.groupRepo.RemoveMember(ctx, group.ID, memberObjID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrLastOwner
+		}
+		return err
+	}
+
+	event := events.MemberLeft{GroupID: group.ID, GroupName: group.Name, UserID: memberObjID}
+	if user.ID != memberObjID {
+		actor := events.ActorOf(user)
+		event.RemovedBy = &actor
+	}
+	s.bus.Publish(ctx, event)
+	return nil
+}
+
+// PromoteMember moves a member one role up, up to admin
+func (s *GroupService) PromoteMember(ctx context.Context, groupID string, firebaseUID string, memberUserID string) (*models.Group, error) {
+	return s.changeRole(ctx, groupID, firebaseUID, memberUserID, func(role models.MemberRole) (models.MemberRole, error) {
+		promoted, ok := role.Promoted()
+		if !ok {
+			return role, ErrCannotPromote
+		}
+		return promoted, nil
+	})
+}
+
+// DemoteMember moves a member one role down. Members may step down
+// themselves, but the last owner cannot.
+func (s *GroupService) DemoteMember(ctx context.Context, groupID string, firebaseUID string, memberUserID string) (*models.Group, error) {
+	return s.changeRole(ctx, groupID, firebaseUID, memberUserID, func(role models.MemberRole) (models.MemberRole, error) {
+		demoted, ok := role.Demoted()
+		if !ok {
+			return role, ErrCannotDemote
+		}
+		return demoted, nil
+	})
+}
+
+// changeRole gives a member the role next returns for their current one.
+// Changing someone else's role needs PermManageRoles over their current role,
+// and nobody can hand out a role above their own.
+func (s *GroupService) changeRole(ctx context.Context, groupID string, firebaseUID string, memberUserID string, next func(models.MemberRole) (models.MemberRole, error)) (*models.Group, error) {
+	group, err := s.GetGroup(ctx, groupID, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByFirebaseUID(ctx, firebaseUID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	memberObjID, err := primitive.ObjectIDFromHex(memberUserID)
+	if err != nil {
+		return nil, errors.New("invalid member user ID")
+	}
+
+	target := group.Member(memberObjID)
+	if target == nil {
+		return nil, ErrMemberNotFound
+	}
+	if target.Placeholder {
+		return nil, ErrPlaceholderRole
+	}
+
+	role, err := next(target.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	actorRole := group.Member(user.ID).Role
+	self := user.ID == memberObjID
+	if self && role.Outranks(target.Role) {
+		return nil, ErrGroupForbidden
+	}
+	if !self && (!canManage(actorRole, target.Role, models.PermManageRoles) || role.Outranks(actorRole)) {
+		return nil, ErrGroupForbidden
+	}
+
+	if err := s.groupRepo.SetMemberRoles(ctx, group.ID, map[primitive.ObjectID]models.MemberRole{memberObjID: role}); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if target.Role == models.RoleOwner {
+				return nil, ErrLastOwner
+			}
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	return s.groupRepo.FindByID(ctx, group.ID)
+}
+
+// TransferOwnership makes another member owner and the current owner an admin
+func (s *GroupService) TransferOwnership(ctx context.Context, groupID string, firebaseUID string, req models.TransferOwnershipRequest) (*models.Group, error) {
+	group, user, _, err := s.groupAs(ctx, groupID, firebaseUID, models.PermTransferOwnership)
+	if err != nil {
+		return nil, err
+	}
+
+	newOwnerID, err := primitive.ObjectIDFromHex(req.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	newOwner := group.Member(newOwnerID)
+	if newOwner == nil {
+		return nil, ErrMemberNotFound
+	}
+	if newOwner.Role == models.RoleOwner {
+		return nil, ErrAlreadyOwner
+	}
+	if newOwner.Placeholder {
+		return nil, ErrPlaceholderRole
+	}
+
+	if err := s.groupRepo.SetMemberRoles(ctx, group.ID, map[primitive.ObjectID]models.MemberRole{
+		newOwnerID: models.RoleOwner,
+		user.ID:    models.RoleAdmin,
+	}); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	return s.groupRepo.FindByID(ctx, group.ID)
+}
+
+// DeleteGroup soft-deletes a group (owners only)
+func (s *GroupService) DeleteGroup(ctx context.Context, groupID string, firebaseUID string) error {
+	group, _, _, err := s.groupAs(ctx, groupID, firebaseUID, models.PermDeleteGroup)
+	if err != nil {
+		return err
+	}
+	return s.groupRepo.Delete(ctx, group.ID)
+}
+
+// AssignMissingOwners gives every group without an owner one: its creator
+// if still a member, else its longest-standing admin, else its
+// longest-standing member. Groups created before the owner role existed
+// only had admins. Returns how many groups were changed.
+func (s *GroupService) AssignMissingOwners(ctx context.Context) (int, error) {
+	groups, err := s.groupRepo.FindWithoutOwner(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	assigned := 0
+	for i := range groups {
+		owner := defaultOwner(&groups[i])
+		err := s.groupRepo.SetMemberRoles(ctx, groups[i].ID, map[primitive.ObjectID]models.MemberRole{owner: models.RoleOwner})
+		if err != nil {
+			return assigned, err
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+func defaultOwner(group *models.Group) primitive.ObjectID {
+	if group.Member(group.CreatedBy) != nil {
+		return group.CreatedBy
+	}
+
+	var owner *models.GroupMember
+	for i := range group.Members {
+		m := &group.Members[i]
+		if owner == nil ||
+			m.Role.Outranks(owner.Role) ||
+			(m.Role == owner.Role && m.JoinedAt.Before(owner.JoinedAt)) {
+			owner = m
+		}
+	}
+	return owner.UserID
+}
+
+// canManage reports whether a member with role actor may use perm on a
+// member with role target: owners over anyone, others only over lower roles
+func canManage(actor, target models.MemberRole, perm models.Permission) bool {
+	if !actor.Can(perm) {
+		return false
+	}
+	return actor == models.RoleOwner || actor.Outranks(target)
+}
+
+// requireGroupPermission checks that userID is a member of the group whose
+// role grants perm
+func requireGroupPermission(group *models.Group, userID primitive.ObjectID, perm models.Permission) error {
+	member := group.Member(userID)
+	if member == nil {
+		return ErrNotGroupMember
+	}
+	if !member.Role.Can(perm) {
+		return ErrGroupForbidden
+	}
+	return nil
+}
+
+// GetGroupWithMemberDetails returns a group response with full member details
+func (s *GroupService) GetGroupWithMemberDetails(ctx context.Context, group *models.Group) (*models.GroupResponse, error) {
+	resp := group.ToResponse()
+
+	// Fetch member details
+	memberIDs := make([]primitive.ObjectID, len(group.Members))
+	for i, m := range group.Members {
+		memberIDs[i] = m.UserID
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, memberIDs)
+	if err != nil {
+		return &resp, nil // Return without details if fetch fails
+	}
+
+	userMap := make(map[string]*models.User)
+	for i := range users {
+		userMap[users[i].ID.Hex()] = &users[i]
+	}
+
+	for i, m := range resp.Members {
+		if user, ok := userMap[m.UserID]; ok {
+			resp.Members[i].DisplayName = user.DisplayName
+			resp.Members[i].AvatarURL = user.AvatarURL
+		}
+	}
+
+	return &resp, nil
+}
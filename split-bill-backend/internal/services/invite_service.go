@@ -0,0 +1,675 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// inviteCodeAttempts is how often a new code is drawn when one is taken
+const inviteCodeAttempts = 5
+
+var (
+	ErrInvalidInviteCode  = errors.New("invalid invite code")
+	ErrInviteExpired      = errors.New("this invite link has expired")
+	ErrInviteUsedUp       = errors.New("this invite link has already been used the maximum number of times")
+	ErrInviteNotFound     = errors.New("invite link not found")
+	ErrInviteRevoked      = errors.New("this invite link has already been revoked")
+	ErrInviteAdminRole    = errors.New("only group owners and admins can create invite links for admins")
+	ErrInviteRevokeDenied = errors.New("only the link's creator, group owners and admins can revoke an invite link")
+
+	ErrPlaceholderNotFound = errors.New("placeholder member not found")
+	ErrPlaceholderClaimed  = errors.New("this placeholder has already been claimed")
+	ErrClaimAlreadyMember  = errors.New("you are already in this group, so you cannot claim a placeholder in it")
+	ErrClaimHasHistory     = errors.New("you have bills or payments in this group from before, so you cannot claim a placeholder in it")
+
+	ErrJoinRequestNotFound = errors.New("join request not found")
+	ErrJoinRequestDecided  = errors.New("this join request has already been approved or denied")
+
+	ErrNoFreeInviteCode = errors.New("could not draw an invite code that is not in use")
+)
+
+// InviteService lets people join groups. Every group has an invite code
+// that makes whoever uses it a member; members can also create invite
+// links that expire, allow a limited number of joins or give a different
+// role, and see who joined through each of them. In groups that require
+// approval, using a code files a join request for an owner or admin to
+// approve or deny instead. Claim links let someone take over a placeholder
+// member's place.
+type InviteService struct {
+	inviteRepo      *repository.InviteRepository
+	joinRequestRepo *repository.JoinRequestRepository
+	groupRepo       *repository.GroupRepository
+	userRepo        *repository.UserRepository
+	placeholderRepo *repository.PlaceholderRepository
+	bus             *events.Bus
+}
+
+func NewInviteService(
+	inviteRepo *repository.InviteRepository,
+	joinRequestRepo *repository.JoinRequestRepository,
+	groupRepo *repository.GroupRepository,
+	userRepo *repository.UserRepository,
+	placeholderRepo *repository.PlaceholderRepository,
+	bus *events.Bus,
+) *InviteService {
+	return &InviteService{
+		inviteRepo:      inviteRepo,
+		joinRequestRepo: joinRequestRepo,
+		groupRepo:       groupRepo,
+		userRepo:        userRepo,
+		placeholderRepo: placeholderRepo,
+		bus:             bus,
+	}
+}
+
+// RegenerateInviteCode gives the group a new invite code. The old code
+// stops working; invite links are not affected.
+func (s *InviteService) RegenerateInviteCode(ctx context.Context, groupID string, user *models.User) (*models.InviteCodeResponse, error) {
+	group, _, err := s.memberGroup(ctx, groupID, user, models.PermManageGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.newCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.groupRepo.SetInviteCode(ctx, group.ID, code); err != nil {
+		return nil, err
+	}
+	return &models.InviteCodeResponse{InviteCode: code}, nil
+}
+
+// ListInviteLinks returns the group's invite links, newest first, with the
+// current members who joined through each. Codes of links giving a role the
+// caller could not create links for are left out, so members cannot pass on
+// admin links.
+func (s *InviteService) ListInviteLinks(ctx context.Context, groupID string, user *models.User) ([]models.InviteLinkResponse, error) {
+	group, member, err := s.memberGroup(ctx, groupID, user, models.PermAddMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.inviteRepo.FindByGroupID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	return inviteLinkResponses(group, links, s.memberNames(ctx, group), member.Role), nil
+}
+
+// inviteLinkResponses pairs links with the members who joined through them,
+// as seen by a member with the role viewer
+func inviteLinkResponses(group *models.Group, links []models.InviteLink, names map[primitive.ObjectID]string, viewer models.MemberRole) []models.InviteLinkResponse {
+	joined := make(map[primitive.ObjectID][]models.InviteJoin)
+	for _, m := range group.Members {
+		if m.InviteLinkID == nil {
+			continue
+		}
+		joined[*m.InviteLinkID] = append(joined[*m.InviteLinkID], models.InviteJoin{
+			UserID:      m.UserID.Hex(),
+			DisplayName: names[m.UserID],
+			JoinedAt:    m.JoinedAt,
+		})
+	}
+
+	resp := make([]models.InviteLinkResponse, len(links))
+	for i, link := range links {
+		if !viewer.CanInviteAs(link.Role) {
+			link.Code = ""
+		}
+		resp[i] = models.InviteLinkResponse{InviteLink: link, Joined: joined[link.ID]}
+		if resp[i].Joined == nil {
+			resp[i].Joined = []models.InviteJoin{}
+		}
+	}
+	return resp
+}
+
+// CreateInviteLink creates an invite link. Only owners and admins can
+// create links that make people admins. A link for a placeholder member is
+// a claim link and can be used once.
+func (s *InviteService) CreateInviteLink(ctx context.Context, groupID string, user *models.User, req models.CreateInviteLinkRequest) (*models.InviteLink, error) {
+	group, member, err := s.memberGroup(ctx, groupID, user, models.PermAddMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.RoleMember
+	}
+	if !member.Role.CanInviteAs(role) {
+		return nil, ErrInviteAdminRole
+	}
+
+	link := &models.InviteLink{
+		GroupID:   group.ID,
+		Role:      role,
+		MaxUses:   req.MaxUses,
+		CreatedBy: user.ID,
+	}
+	if req.PlaceholderID != "" {
+		placeholderID, err := primitive.ObjectIDFromHex(req.PlaceholderID)
+		if err != nil {
+			return nil, ErrPlaceholderNotFound
+		}
+		placeholder := group.Member(placeholderID)
+		if placeholder == nil || !placeholder.Placeholder {
+			return nil, ErrPlaceholderNotFound
+		}
+		link.PlaceholderID = &placeholderID
+		link.MaxUses = 1
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+
+	for attempt := 0; ; attempt++ {
+		link.Code, err = s.newCode(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = s.inviteRepo.Create(ctx, link)
+		if err == nil {
+			return link, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == inviteCodeAttempts-1 {
+			return nil, err
+		}
+	}
+}
+
+// RevokeInviteLink stops an invite link from working. Its creator and
+// anyone who manages the group can revoke it. Members who already joined
+// through it stay.
+func (s *InviteService) RevokeInviteLink(ctx context.Context, groupID, inviteID string, user *models.User) error {
+	group, member, err := s.memberGroup(ctx, groupID, user, models.PermAddMembers)
+	if err != nil {
+		return err
+	}
+
+	id, err := primitive.ObjectIDFromHex(inviteID)
+	if err != nil {
+		return ErrInviteNotFound
+	}
+	link, err := s.inviteRepo.FindByID(ctx, group.ID, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInviteNotFound
+		}
+		return err
+	}
+	if link.CreatedBy != user.ID && !member.Role.Can(models.PermManageGroup) {
+		return ErrInviteRevokeDenied
+	}
+
+	if err := s.inviteRepo.Revoke(ctx, group.ID, link.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInviteRevoked
+		}
+		return err
+	}
+	return nil
+}
+
+// JoinGroup joins a group with an invite link's code or the group's own
+// invite code. Links give their role and count the join against their
+// limit; the group's code makes the user a member. If the group requires
+// approval, a pending join request is returned instead of the group.
+// Joining a group one is already in, or asking again, changes nothing and
+// does not use up the link. Claim links skip approval, since a member made
+// the placeholder for this person.
+func (s *InviteService) JoinGroup(ctx context.Context, user *models.User, code string) (*models.Group, *models.JoinRequest, error) {
+	group, link, err := s.findInvite(ctx, code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if link != nil && link.PlaceholderID != nil {
+		group, err = s.claimPlaceholder(ctx, group, link, user)
+		return group, nil, err
+	}
+	if group.Member(user.ID) != nil {
+		return group, nil, nil
+	}
+	if group.JoinApprovalRequired {
+		request, err := s.requestToJoin(ctx, group, link, user)
+		return nil, request, err
+	}
+
+	now := time.Now()
+	if err := s.useLink(ctx, link, now); err != nil {
+		return nil, nil, err
+	}
+
+	member := models.GroupMember{
+		UserID:   user.ID,
+		Nickname: user.DisplayName,
+		Role:     models.RoleMember,
+		JoinedAt: now,
+	}
+	if link != nil {
+		member.Role = link.Role
+		member.InviteLinkID = &link.ID
+	}
+	err = s.groupRepo.AddMember(ctx, group.ID, member)
+	if err != nil {
+		s.releaseLink(ctx, member.InviteLinkID)
+		// Joined meanwhile, e.g. by tapping the link twice
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, err
+		}
+	} else {
+		s.publishJoined(ctx, group, user, member)
+	}
+	group, err = s.groupRepo.FindByID(ctx, group.ID)
+	return group, nil, err
+}
+
+// claimPlaceholder makes the user a member in the place of the claim
+// link's placeholder, taking over its bills, shares and payments
+func (s *InviteService) claimPlaceholder(ctx context.Context, group *models.Group, link *models.InviteLink, user *models.User) (*models.Group, error) {
+	placeholder := group.Member(*link.PlaceholderID)
+	if placeholder == nil || !placeholder.Placeholder {
+		return nil, ErrPlaceholderClaimed
+	}
+	if group.Member(user.ID) != nil {
+		return nil, ErrClaimAlreadyMember
+	}
+	// Someone who left the group keeps their shares of its bills; taking
+	// over the placeholder's as well would give them two in one bill
+	history, err := s.placeholderRepo.HasHistory(ctx, group.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if history {
+		return nil, ErrClaimHasHistory
+	}
+
+	if err := s.useLink(ctx, link, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// They have been in the group since the placeholder was added
+	member := models.GroupMember{
+		UserID:       user.ID,
+		Nickname:     user.DisplayName,
+		Role:         link.Role,
+		JoinedAt:     placeholder.JoinedAt,
+		InviteLinkID: &link.ID,
+	}
+	if err := s.placeholderRepo.Claim(ctx, group.ID, placeholder.UserID, member); err != nil {
+		s.releaseLink(ctx, member.InviteLinkID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrPlaceholderClaimed
+		}
+		return nil, err
+	}
+
+	s.publishJoined(ctx, group, user, member)
+	s.bus.Publish(ctx, events.PlaceholderClaimed{
+		GroupID:       group.ID,
+		GroupName:     group.Name,
+		PlaceholderID: placeholder.UserID,
+		Member:        member,
+		MemberName:    user.DisplayName,
+	})
+	return s.groupRepo.FindByID(ctx, group.ID)
+}
+
+// findInvite returns the group an invite code joins, and the invite link
+// if the code is a link's
+func (s *InviteService) findInvite(ctx context.Context, code string) (*models.Group, *models.InviteLink, error) {
+	link, err := s.inviteRepo.FindByCode(ctx, code)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil, err
+	}
+
+	var group *models.Group
+	if link != nil {
+		group, err = s.groupRepo.FindByID(ctx, link.GroupID)
+	} else {
+		group, err = s.groupRepo.FindByInviteCode(ctx, code)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrInvalidInviteCode
+		}
+		return nil, nil, err
+	}
+	if !group.IsActive || (link != nil && link.RevokedAt != nil) {
+		return nil, nil, ErrInvalidInviteCode
+	}
+	return group, link, nil
+}
+
+// requestToJoin files a join request, or returns the user's pending one.
+// The request takes a use of the link, which a denial gives back.
+func (s *InviteService) requestToJoin(ctx context.Context, group *models.Group, link *models.InviteLink, user *models.User) (*models.JoinRequest, error) {
+	pending, err := s.joinRequestRepo.FindPending(ctx, group.ID, user.ID)
+	if err == nil {
+		return pending, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	if err := s.useLink(ctx, link, time.Now()); err != nil {
+		return nil, err
+	}
+
+	request := &models.JoinRequest{
+		GroupID: group.ID,
+		UserID:  user.ID,
+		Role:    models.RoleMember,
+	}
+	if link != nil {
+		request.Role = link.Role
+		request.InviteLinkID = &link.ID
+	}
+	if err := s.joinRequestRepo.Create(ctx, request); err != nil {
+		s.releaseLink(ctx, request.InviteLinkID)
+		if mongo.IsDuplicateKeyError(err) {
+			return s.joinRequestRepo.FindPending(ctx, group.ID, user.ID)
+		}
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.JoinRequested{
+		GroupName:     group.Name,
+		Request:       *request,
+		ApplicantName: user.DisplayName,
+	})
+	return request, nil
+}
+
+// ListJoinRequests returns the group's join requests with a status,
+// pending if empty, oldest first
+func (s *InviteService) ListJoinRequests(ctx context.Context, groupID string, user *models.User, status models.JoinRequestStatus) ([]models.JoinRequestResponse, error) {
+	group, _, err := s.memberGroup(ctx, groupID, user, models.PermApproveJoins)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "":
+		status = models.JoinRequestPending
+	case models.JoinRequestPending, models.JoinRequestApproved, models.JoinRequestDenied:
+	default:
+		return nil, errors.New("status must be pending, approved or denied")
+	}
+
+	requests, err := s.joinRequestRepo.FindByGroupID(ctx, group.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.joinRequestResponses(ctx, requests, map[primitive.ObjectID]string{group.ID: group.Name}), nil
+}
+
+// ListMyJoinRequests returns the user's own join requests, newest first
+func (s *InviteService) ListMyJoinRequests(ctx context.Context, user *models.User) ([]models.JoinRequestResponse, error) {
+	requests, err := s.joinRequestRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNames := make(map[primitive.ObjectID]string)
+	for _, r := range requests {
+		if _, ok := groupNames[r.GroupID]; ok {
+			continue
+		}
+		if group, err := s.groupRepo.FindByID(ctx, r.GroupID); err == nil {
+			groupNames[r.GroupID] = group.Name
+		}
+	}
+	return s.joinRequestResponses(ctx, requests, groupNames), nil
+}
+
+// ApproveJoinRequest adds the applicant to the group with the role of the
+// invite link they used
+func (s *InviteService) ApproveJoinRequest(ctx context.Context, groupID, requestID string, user *models.User) (*models.JoinRequest, error) {
+	group, request, err := s.pendingRequest(ctx, groupID, requestID, user)
+	if err != nil {
+		return nil, err
+	}
+	applicant, err := s.userRepo.FindByID(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.joinRequestRepo.Decide(ctx, request, models.JoinRequestApproved, user.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrJoinRequestDecided
+		}
+		return nil, err
+	}
+
+	member := models.GroupMember{
+		UserID:       applicant.ID,
+		Nickname:     applicant.DisplayName,
+		Role:         request.Role,
+		JoinedAt:     *request.DecidedAt,
+		InviteLinkID: request.InviteLinkID,
+	}
+	err = s.groupRepo.AddMember(ctx, group.ID, member)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// Someone added the applicant meanwhile, so the link was not needed
+		s.releaseLink(ctx, request.InviteLinkID)
+	case err != nil:
+		_ = s.joinRequestRepo.Reopen(ctx, request.ID)
+		return nil, err
+	default:
+		s.publishJoined(ctx, group, applicant, member)
+	}
+
+	s.bus.Publish(ctx, events.JoinRequestDecided{
+		GroupName: group.Name,
+		Request:   *request,
+		DecidedBy: events.ActorOf(user),
+	})
+	return request, nil
+}
+
+// DenyJoinRequest turns a join request down and gives its use back to the
+// invite link
+func (s *InviteService) DenyJoinRequest(ctx context.Context, groupID, requestID string, user *models.User) (*models.JoinRequest, error) {
+	group, request, err := s.pendingRequest(ctx, groupID, requestID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.joinRequestRepo.Decide(ctx, request, models.JoinRequestDenied, user.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrJoinRequestDecided
+		}
+		return nil, err
+	}
+	s.releaseLink(ctx, request.InviteLinkID)
+
+	s.bus.Publish(ctx, events.JoinRequestDecided{
+		GroupName: group.Name,
+		Request:   *request,
+		DecidedBy: events.ActorOf(user),
+	})
+	return request, nil
+}
+
+// pendingRequest loads a pending join request to a group whose joins the
+// user may approve
+func (s *InviteService) pendingRequest(ctx context.Context, groupID, requestID string, user *models.User) (*models.Group, *models.JoinRequest, error) {
+	group, _, err := s.memberGroup(ctx, groupID, user, models.PermApproveJoins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(requestID)
+	if err != nil {
+		return nil, nil, ErrJoinRequestNotFound
+	}
+	request, err := s.joinRequestRepo.FindByID(ctx, group.ID, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrJoinRequestNotFound
+		}
+		return nil, nil, err
+	}
+	if request.Status != models.JoinRequestPending {
+		return nil, nil, ErrJoinRequestDecided
+	}
+	return group, request, nil
+}
+
+// JoinRequestResponse describes a join request, e.g. the one JoinGroup filed
+func (s *InviteService) JoinRequestResponse(ctx context.Context, request *models.JoinRequest) *models.JoinRequestResponse {
+	groupNames := make(map[primitive.ObjectID]string)
+	if group, err := s.groupRepo.FindByID(ctx, request.GroupID); err == nil {
+		groupNames[group.ID] = group.Name
+	}
+	return &s.joinRequestResponses(ctx, []models.JoinRequest{*request}, groupNames)[0]
+}
+
+func (s *InviteService) joinRequestResponses(ctx context.Context, requests []models.JoinRequest, groupNames map[primitive.ObjectID]string) []models.JoinRequestResponse {
+	ids := make([]primitive.ObjectID, len(requests))
+	for i, r := range requests {
+		ids[i] = r.UserID
+	}
+	applicants := make(map[primitive.ObjectID]*models.User)
+	if users, err := s.userRepo.FindByIDs(ctx, ids); err == nil {
+		for i := range users {
+			applicants[users[i].ID] = &users[i]
+		}
+	}
+
+	resp := make([]models.JoinRequestResponse, len(requests))
+	for i, r := range requests {
+		resp[i] = models.JoinRequestResponse{JoinRequest: r, GroupName: groupNames[r.GroupID]}
+		if applicant, ok := applicants[r.UserID]; ok {
+			resp[i].DisplayName = applicant.DisplayName
+			resp[i].AvatarURL = applicant.AvatarURL
+		}
+	}
+	return resp
+}
+
+func (s *InviteService) publishJoined(ctx context.Context, group *models.Group, user *models.User, member models.GroupMember) {
+	s.bus.Publish(ctx, events.MemberJoined{
+		GroupID:    group.ID,
+		GroupName:  group.Name,
+		Member:     member,
+		MemberName: user.DisplayName,
+	})
+}
+
+// useLink counts a join against the invite link, if one was used
+func (s *InviteService) useLink(ctx context.Context, link *models.InviteLink, now time.Time) error {
+	if link == nil {
+		return nil
+	}
+	if err := s.inviteRepo.Use(ctx, link.ID, now); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return s.unusable(ctx, link, now)
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseLink gives back a use of the invite link, if one was used
+func (s *InviteService) releaseLink(ctx context.Context, linkID *primitive.ObjectID) {
+	if linkID != nil {
+		_ = s.inviteRepo.Release(ctx, *linkID)
+	}
+}
+
+// unusable explains why a link could not be used, from its current state
+// since another join may have used it up in the meantime
+func (s *InviteService) unusable(ctx context.Context, link *models.InviteLink, now time.Time) error {
+	if current, err := s.inviteRepo.FindByID(ctx, link.GroupID, link.ID); err == nil {
+		link = current
+	}
+	switch {
+	case link.RevokedAt != nil:
+		return ErrInvalidInviteCode
+	case link.Expired(now):
+		return ErrInviteExpired
+	default:
+		return ErrInviteUsedUp
+	}
+}
+
+// newCode draws an invite code that no group uses, so a link's code never
+// hides a group's own code
+func (s *InviteService) newCode(ctx context.Context) (string, error) {
+	return drawInviteCode(utils.GenerateInviteCode, func(code string) error {
+		_, err := s.groupRepo.FindByInviteCode(ctx, code)
+		return err
+	})
+}
+
+// drawInviteCode draws codes until find reports that no group has one,
+// giving up after inviteCodeAttempts
+func drawInviteCode(generate func() string, find func(code string) error) (string, error) {
+	for attempt := 0; attempt < inviteCodeAttempts; attempt++ {
+		code := generate()
+		err := find(code)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return code, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", ErrNoFreeInviteCode
+}
+
+// memberGroup loads a group the user is in with a role that grants perm
+func (s *InviteService) memberGroup(ctx context.Context, groupID string, user *models.User, perm models.Permission) (*models.Group, *models.GroupMember, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, nil, errors.New("invalid group ID")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrNotGroupMember
+		}
+		return nil, nil, err
+	}
+	if !group.IsActive {
+		return nil, nil, ErrNotGroupMember
+	}
+	if err := requireGroupPermission(group, user.ID, perm); err != nil {
+		return nil, nil, err
+	}
+	return group, group.Member(user.ID), nil
+}
+
+// memberNames returns the display names of the group's members, falling
+// back to their nicknames
+func (s *InviteService) memberNames(ctx context.Context, group *models.Group) map[primitive.ObjectID]string {
+	names := make(map[primitive.ObjectID]string, len(group.Members))
+	ids := make([]primitive.ObjectID, len(group.Members))
+	for i, m := range group.Members {
+		names[m.UserID] = m.Nickname
+		ids[i] = m.UserID
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return names
+	}
+	for _, u := range users {
+		names[u.ID] = u.DisplayName
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestInviteLinkResponsesHideAdminCodes(t *testing.T) {
+	memberLink := models.InviteLink{ID: primitive.NewObjectID(), Code: "MEMBER01", Role: models.RoleMember}
+	adminLink := models.InviteLink{ID: primitive.NewObjectID(), Code: "ADMIN001", Role: models.RoleAdmin}
+	joiner := primitive.NewObjectID()
+	group := &models.Group{Members: []models.GroupMember{
+		{UserID: primitive.NewObjectID(), Role: models.RoleOwner},
+		{UserID: joiner, Role: models.RoleAdmin, InviteLinkID: &adminLink.ID, JoinedAt: time.Now()},
+	}}
+	links := []models.InviteLink{adminLink, memberLink}
+	names := map[primitive.ObjectID]string{joiner: "Bình"}
+
+	tests := []struct {
+		viewer    models.MemberRole
+		wantCodes []string
+	}{
+		{models.RoleOwner, []string{"ADMIN001", "MEMBER01"}},
+		{models.RoleAdmin, []string{"ADMIN001", "MEMBER01"}},
+		{models.RoleMember, []string{"", "MEMBER01"}},
+	}
+	for _, tt := range tests {
+		resp := inviteLinkResponses(group, links, names, tt.viewer)
+		for i, want := range tt.wantCodes {
+			if resp[i].Code != want {
+				t.Errorf("%s sees link %d with code %q, want %q", tt.viewer, i, resp[i].Code, want)
+			}
+		}
+		// Who joined is shown either way
+		if len(resp[0].Joined) != 1 || resp[0].Joined[0].DisplayName != "Bình" || resp[1].Joined == nil {
+			t.Errorf("%s: joined = %+v, %+v", tt.viewer, resp[0].Joined, resp[1].Joined)
+		}
+	}
+
+	if links[0].Code != "ADMIN001" {
+		t.Error("inviteLinkResponses changed the links it was given")
+	}
+}
+
+func TestDrawInviteCode(t *testing.T) {
+	taken := map[string]bool{"TAKEN001": true, "TAKEN002": true}
+	find := func(code string) error {
+		if taken[code] {
+			return nil
+		}
+		return mongo.ErrNoDocuments
+	}
+	sequence := func(codes ...string) func() string {
+		return func() string {
+			code := codes[0]
+			codes = codes[1:]
+			return code
+		}
+	}
+
+	code, err := drawInviteCode(sequence("TAKEN001", "TAKEN002", "FREE0001"), find)
+	if err != nil || code != "FREE0001" {
+		t.Errorf("drawInviteCode = %q, %v, want FREE0001", code, err)
+	}
+
+	// Every attempt drew a code a group uses
+	always := func() string { return "TAKEN001" }
+	if code, err := drawInviteCode(always, find); !errors.Is(err, ErrNoFreeInviteCode) {
+		t.Errorf("drawInviteCode = %q, %v, want ErrNoFreeInviteCode", code, err)
+	}
+
+	// A failed lookup is not taken to mean the code is free, on any attempt
+	down := errors.New("connection reset")
+	calls := 0
+	_, err = drawInviteCode(always, func(string) error {
+		calls++
+		if calls == inviteCodeAttempts {
+			return down
+		}
+		return nil
+	})
+	if !errors.Is(err, down) {
+		t.Errorf("err = %v, want the lookup error", err)
+	}
+}
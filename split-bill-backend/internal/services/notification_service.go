@@ -1,202 +1,590 @@
-package services
-
-import (
-	"context"
-	"fmt"
-
-	"github.com/splitbill/backend/internal/models"
-	"github.com/splitbill/backend/internal/repository"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.uber.org/zap"
-)
-
-// NotificationType represents the type of notification
-type NotificationType string
-
-const (
-	NotifBillCreated       NotificationType = "bill_created"
-	NotifBillSplit         NotificationType = "bill_split"
-	NotifPaymentReceived   NotificationType = "payment_received"
-	NotifPaymentConfirmed  NotificationType = "payment_confirmed"
-	NotifGroupInvite       NotificationType = "group_invite"
-	NotifMemberJoined      NotificationType = "member_joined"
-	NotifSettlementReminder NotificationType = "settlement_reminder"
-)
-
-// Notification represents a notification to be sent
-type Notification struct {
-	Type    NotificationType       `json:"type"`
-	Title   string                 `json:"title"`
-	Body    string                 `json:"body"`
-	Data    map[string]string      `json:"data"`
-	UserIDs []primitive.ObjectID   `json:"user_ids"`
-}
-
-// NotificationService handles push notifications via FCM
-type NotificationService struct {
-	userRepo *repository.UserRepository
-	logger   *zap.Logger
-	// In production, add firebase.App and messaging.Client
-	// fcmClient *messaging.Client
-	enabled bool
-}
-
-// NewNotificationService creates a new notification service
-func NewNotificationService(userRepo *repository.UserRepository, logger *zap.Logger) *NotificationService {
-	return &NotificationService{
-		userRepo: userRepo,
-		logger:   logger,
-		enabled:  false, // Disabled until FCM is configured
-	}
-}
-
-// SendNotification sends a notification to specified users
-func (s *NotificationService) SendNotification(ctx context.Context, notif *Notification) error {
-	if !s.enabled {
-		s.logger.Debug("Notification not sent (FCM disabled)",
-			zap.String("type", string(notif.Type)),
-			zap.String("title", notif.Title),
-			zap.Int("recipients", len(notif.UserIDs)),
-		)
-		return nil
-	}
-
-	// In production:
-	// 1. Get FCM tokens for user IDs from user repository
-	// 2. Build FCM message
-	// 3. Send via Firebase Cloud Messaging
-	//
-	// tokens, err := s.getUserFCMTokens(ctx, notif.UserIDs)
-	// message := &messaging.MulticastMessage{
-	//     Notification: &messaging.Notification{
-	//         Title: notif.Title,
-	//         Body:  notif.Body,
-	//     },
-	//     Data:   notif.Data,
-	//     Tokens: tokens,
-	// }
-	// response, err := s.fcmClient.SendEachForMulticast(ctx, message)
-
-	s.logger.Info("Notification sent",
-		zap.String("type", string(notif.Type)),
-		zap.Int("recipients", len(notif.UserIDs)),
-	)
-
-	return nil
-}
-
-// NotifyBillCreated notifies group members about a new bill
-func (s *NotificationService) NotifyBillCreated(ctx context.Context, bill *models.Bill, creatorName string, groupName string, memberIDs []primitive.ObjectID) error {
-	// Exclude the payer (bill creator) from notifications
-	var recipients []primitive.ObjectID
-	for _, id := range memberIDs {
-		if id != bill.PaidBy {
-			recipients = append(recipients, id)
-		}
-	}
-
-	if len(recipients) == 0 {
-		return nil
-	}
-
-	notif := &Notification{
-		Type:    NotifBillCreated,
-		Title:   fmt.Sprintf("New bill in %s", groupName),
-		Body:    fmt.Sprintf("%s added \"%s\" - %s", creatorName, bill.Title, formatVND(bill.TotalAmount)),
-		Data: map[string]string{
-			"type":     string(NotifBillCreated),
-			"bill_id":  bill.ID.Hex(),
-			"group_id": bill.GroupID.Hex(),
-		},
-		UserIDs: recipients,
-	}
-
-	return s.SendNotification(ctx, notif)
-}
-
-// NotifyPaymentReceived notifies a user they received a payment
-func (s *NotificationService) NotifyPaymentReceived(ctx context.Context, transaction *models.Transaction, fromUserName string) error {
-	notif := &Notification{
-		Type:    NotifPaymentReceived,
-		Title:   "Payment Received",
-		Body:    fmt.Sprintf("%s sent you %s", fromUserName, formatVND(transaction.Amount)),
-		Data: map[string]string{
-			"type":           string(NotifPaymentReceived),
-			"transaction_id": transaction.ID.Hex(),
-			"group_id":       transaction.GroupID.Hex(),
-		},
-		UserIDs: []primitive.ObjectID{transaction.ToUser},
-	}
-
-	return s.SendNotification(ctx, notif)
-}
-
-// NotifyPaymentConfirmed notifies a user their payment was confirmed
-func (s *NotificationService) NotifyPaymentConfirmed(ctx context.Context, transaction *models.Transaction, confirmerName string) error {
-	notif := &Notification{
-		Type:    NotifPaymentConfirmed,
-		Title:   "Payment Confirmed ✓",
-		Body:    fmt.Sprintf("%s confirmed your payment of %s", confirmerName, formatVND(transaction.Amount)),
-		Data: map[string]string{
-			"type":           string(NotifPaymentConfirmed),
-			"transaction_id": transaction.ID.Hex(),
-			"group_id":       transaction.GroupID.Hex(),
-		},
-		UserIDs: []primitive.ObjectID{transaction.FromUser},
-	}
-
-	return s.SendNotification(ctx, notif)
-}
-
-// NotifyMemberJoined notifies group members when someone joins
-func (s *NotificationService) NotifyMemberJoined(ctx context.Context, groupID primitive.ObjectID, groupName string, newMemberName string, memberIDs []primitive.ObjectID, excludeID primitive.ObjectID) error {
-	var recipients []primitive.ObjectID
-	for _, id := range memberIDs {
-		if id != excludeID {
-			recipients = append(recipients, id)
-		}
-	}
-
-	if len(recipients) == 0 {
-		return nil
-	}
-
-	notif := &Notification{
-		Type:    NotifMemberJoined,
-		Title:   groupName,
-		Body:    fmt.Sprintf("%s joined the group", newMemberName),
-		Data: map[string]string{
-			"type":     string(NotifMemberJoined),
-			"group_id": groupID.Hex(),
-		},
-		UserIDs: recipients,
-	}
-
-	return s.SendNotification(ctx, notif)
-}
-
-// NotifySettlementReminder sends a reminder about pending settlements
-func (s *NotificationService) NotifySettlementReminder(ctx context.Context, userID primitive.ObjectID, groupName string, amount float64) error {
-	notif := &Notification{
-		Type:    NotifSettlementReminder,
-		Title:   "Settlement Reminder 💰",
-		Body:    fmt.Sprintf("You owe %s in %s", formatVND(amount), groupName),
-		Data: map[string]string{
-			"type": string(NotifSettlementReminder),
-		},
-		UserIDs: []primitive.ObjectID{userID},
-	}
-
-	return s.SendNotification(ctx, notif)
-}
-
-func formatVND(amount float64) string {
-	if amount >= 1000000 {
-		return fmt.Sprintf("%.1fM₫", amount/1000000)
-	}
-	if amount >= 1000 {
-		return fmt.Sprintf("%.0fK₫", amount/1000)
-	}
-	return fmt.Sprintf("%.0f₫", amount)
-}
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/i18n"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/notify"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// NotificationType represents the type of notification
+type NotificationType string
+
+const (
+	NotifBillCreated       NotificationType = "bill_created"
+	NotifBillSplit         NotificationType = "bill_split"
+	NotifPaymentReceived   NotificationType = "payment_received"
+	NotifPaymentConfirmed  NotificationType = "payment_confirmed"
+	NotifGroupInvite       NotificationType = "group_invite"
+	NotifMemberJoined      NotificationType = "member_joined"
+	NotifJoinRequest       NotificationType = "join_request"
+	NotifJoinRequestResult NotificationType = "join_request_result"
+	NotifSettlementReminder NotificationType = "settlement_reminder"
+	NotifDigest             NotificationType = "digest"
+)
+
+// notificationTypes lists the types users can set preferences for
+var notificationTypes = []NotificationType{
+	NotifBillCreated,
+	NotifBillSplit,
+	NotifPaymentReceived,
+	NotifPaymentConfirmed,
+	NotifGroupInvite,
+	NotifMemberJoined,
+	NotifJoinRequest,
+	NotifJoinRequestResult,
+	NotifSettlementReminder,
+	NotifDigest,
+}
+
+// ErrNotificationNotFound is returned for notifications outside the user's inbox
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// Notification represents a notification to be sent. Key picks the message
+// in the i18n catalog and Params fill it in; it is rendered in each
+// recipient's language.
+type Notification struct {
+	Type    NotificationType       `json:"type"`
+	Key     string                 `json:"key"`
+	Params  i18n.Params            `json:"params"`
+	Data    map[string]string      `json:"data"`
+	HTML    string                 `json:"html,omitempty"` // email body, when richer than Body
+	UserIDs []primitive.ObjectID   `json:"user_ids"`
+}
+
+// NotificationService handles the in-app inbox and routes notifications to push, email, Zalo and webhooks
+type NotificationService struct {
+	userRepo     *repository.UserRepository
+	groupRepo    *repository.GroupRepository
+	notifRepo    *repository.NotificationRepository
+	deferredRepo *repository.DeferredNotificationRepository
+	router       *notify.Router
+	logger       *zap.Logger
+}
+
+// NewNotificationService creates a new notification service. Notifications
+// go to the inbox and to the channels each recipient's preferences allow.
+func NewNotificationService(
+	userRepo *repository.UserRepository,
+	groupRepo *repository.GroupRepository,
+	notifRepo *repository.NotificationRepository,
+	deferredRepo *repository.DeferredNotificationRepository,
+	router *notify.Router,
+	logger *zap.Logger,
+) *NotificationService {
+	return &NotificationService{
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		notifRepo:    notifRepo,
+		deferredRepo: deferredRepo,
+		router:       router,
+		logger:       logger,
+	}
+}
+
+// ErrNotificationNotSent wraps the errors that kept a notification from
+// reaching anyone, so callers know a retry will not send it twice
+var ErrNotificationNotSent = errors.New("notification was not sent")
+
+// SendNotification puts a notification in the inbox of the specified users
+// and routes it to the channels they can be reached on and allow, each in
+// the user's language. Users in quiet hours get it when their quiet hours end.
+// Once it is in the inbox, failing channels are reported but the
+// notification counts as sent.
+func (s *NotificationService) SendNotification(ctx context.Context, notif *Notification) error {
+	users, err := s.userRepo.FindByIDs(ctx, notif.UserIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotificationNotSent, err)
+	}
+
+	// Placeholder members have no one to read their notifications
+	people := users[:0]
+	for _, u := range users {
+		if !u.Placeholder {
+			people = append(people, u)
+		}
+	}
+	users = people
+
+	inbox := make([]models.Notification, len(users))
+	byLang := make(map[i18n.Lang][]notify.Recipient)
+	for i := range users {
+		lang := i18n.ForUser(&users[i])
+		title, body := i18n.NotificationText(lang, notif.Key, notif.Params)
+		inbox[i] = models.Notification{
+			UserID: users[i].ID,
+			Type:   string(notif.Type),
+			Title:  title,
+			Body:   body,
+			Key:    notif.Key,
+			Params: notif.Params,
+			Data:   notif.Data,
+		}
+		byLang[lang] = append(byLang[lang], toRecipient(&users[i]))
+	}
+	if err := s.notifRepo.CreateMany(ctx, inbox); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotificationNotSent, err)
+	}
+
+	deferred := 0
+	for _, lang := range i18n.Supported {
+		recipients := byLang[lang]
+		if len(recipients) == 0 {
+			continue
+		}
+
+		title, body := i18n.NotificationText(lang, notif.Key, notif.Params)
+		msg := toMessage(notif.Type, title, body, notif.Data, notif.HTML)
+		deferrals, routeErr := s.router.Route(ctx, msg, recipients)
+		err = errors.Join(err, routeErr)
+
+		for _, deferral := range deferrals {
+			if deferErr := s.deferNotification(ctx, msg, deferral); deferErr != nil {
+				err = errors.Join(err, deferErr)
+			}
+		}
+		deferred += len(deferrals)
+	}
+
+	s.logger.Info("Notification sent",
+		zap.String("type", string(notif.Type)),
+		zap.Int("recipients", len(users)),
+		zap.Int("deferred", deferred),
+		zap.Bool("all_channels_ok", err == nil),
+	)
+
+	return err
+}
+
+// deferNotification stores a notification until the recipient's quiet hours end
+func (s *NotificationService) deferNotification(ctx context.Context, msg notify.Message, deferral notify.Deferral) error {
+	userID, err := primitive.ObjectIDFromHex(deferral.Recipient.UserID)
+	if err != nil {
+		return err
+	}
+
+	channels := make([]string, len(deferral.Channels))
+	for i, channel := range deferral.Channels {
+		channels[i] = string(channel)
+	}
+
+	return s.deferredRepo.Create(ctx, &models.DeferredNotification{
+		UserID:    userID,
+		Type:      msg.Type,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		Data:      msg.Data,
+		HTML:      msg.HTML,
+		Channels:  channels,
+		DeliverAt: deferral.Until,
+	})
+}
+
+// DeliverDeferred sends the notifications whose quiet hours have ended
+func (s *NotificationService) DeliverDeferred(ctx context.Context, now time.Time) error {
+	for {
+		deferred, err := s.deferredRepo.ClaimDue(ctx, now)
+		if err != nil || deferred == nil {
+			return err
+		}
+
+		user, err := s.userRepo.FindByID(ctx, deferred.UserID)
+		if err != nil {
+			s.logger.Warn("Dropping deferred notification for missing user",
+				zap.String("user_id", deferred.UserID.Hex()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		channels := make([]notify.ChannelName, len(deferred.Channels))
+		for i, channel := range deferred.Channels {
+			channels[i] = notify.ChannelName(channel)
+		}
+
+		msg := toMessage(NotificationType(deferred.Type), deferred.Title, deferred.Body, deferred.Data, deferred.HTML)
+		if err := s.router.Deliver(ctx, msg, toRecipient(user), channels); err != nil {
+			s.logger.Warn("Deferred notification failed",
+				zap.String("type", deferred.Type),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// StartDeferredDelivery delivers deferred notifications every interval until ctx is cancelled
+func (s *NotificationService) StartDeferredDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.DeliverDeferred(ctx, time.Now()); err != nil {
+			s.logger.Warn("Deferred notification run failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// toRecipient collects a user's addresses and preferences for the router
+func toRecipient(user *models.User) notify.Recipient {
+	recipient := notify.Recipient{
+		UserID:      user.ID.Hex(),
+		Name:        user.DisplayName,
+		Email:       user.Email,
+		ZaloUserID:  user.ZaloUserID,
+		WebhookURL:  user.WebhookURL,
+		Preferences: user.NotificationPreferences,
+	}
+	for _, device := range user.Devices {
+		recipient.DeviceTokens = append(recipient.DeviceTokens, device.Token)
+	}
+	return recipient
+}
+
+func toMessage(notifType NotificationType, title, body string, data map[string]string, html string) notify.Message {
+	return notify.Message{
+		Type:    string(notifType),
+		Title:   title,
+		Body:    body,
+		Data:    data,
+		GroupID: data["group_id"],
+		HTML:    html,
+	}
+}
+
+// RegisterEventHandlers notifies the members a group event concerns. They
+// run in the background, so a slow or failing push service never holds up
+// or fails the request that caused the event.
+func (s *NotificationService) RegisterEventHandlers(bus *events.Bus) {
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.BillCreated) error {
+		group, err := s.groupRepo.FindByID(ctx, e.Bill.GroupID)
+		if err != nil {
+			return err
+		}
+		// The creator knows about the bill already
+		var recipients []primitive.ObjectID
+		for _, id := range group.MemberIDs() {
+			if id != e.Actor.ID {
+				recipients = append(recipients, id)
+			}
+		}
+		return s.sent(s.NotifyBillCreated(ctx, &e.Bill, actorName(ctx, s.userRepo, e.Actor), group.Name, recipients))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.TransactionCreated) error {
+		if e.Transaction.Type == models.TransactionReversal {
+			return nil
+		}
+		return s.sent(s.NotifyPaymentReceived(ctx, &e.Transaction, e.Actor.Name))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.TransactionConfirmed) error {
+		if e.Transaction.Type == models.TransactionReversal {
+			return nil
+		}
+		return s.sent(s.NotifyPaymentConfirmed(ctx, &e.Transaction, e.Actor.Name))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.MemberJoined) error {
+		if e.AddedBy != nil {
+			return s.sent(s.NotifyAddedToGroup(ctx, e.GroupID, e.GroupName, e.AddedBy.Name, e.Member.UserID))
+		}
+		group, err := s.groupRepo.FindByID(ctx, e.GroupID)
+		if err != nil {
+			return err
+		}
+		return s.sent(s.NotifyMemberJoined(ctx, e.GroupID, e.GroupName, e.MemberName, group.MemberIDs(), e.Member.UserID))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.JoinRequested) error {
+		group, err := s.groupRepo.FindByID(ctx, e.Request.GroupID)
+		if err != nil {
+			return err
+		}
+		// Only those who can approve the request hear about it
+		var approvers []primitive.ObjectID
+		for _, m := range group.Members {
+			if m.Role.Can(models.PermApproveJoins) {
+				approvers = append(approvers, m.UserID)
+			}
+		}
+		return s.sent(s.NotifyJoinRequested(ctx, &e.Request, e.GroupName, e.ApplicantName, approvers))
+	})
+	events.SubscribeAsync(bus, "notifications", func(ctx context.Context, e events.JoinRequestDecided) error {
+		return s.sent(s.NotifyJoinRequestDecided(ctx, &e.Request, e.GroupName))
+	})
+}
+
+// sent logs a failed send instead of returning it: the notification is in
+// the inbox already and may have reached some channels, so retrying the
+// event would deliver it twice
+func (s *NotificationService) sent(err error) error {
+	if err != nil {
+		s.logger.Warn("Failed to send notification", zap.Error(err))
+	}
+	return nil
+}
+
+// ListNotifications returns a page of the user's inbox, newest first
+func (s *NotificationService) ListNotifications(ctx context.Context, user *models.User, unreadOnly bool, page utils.CursorPage) ([]models.Notification, *utils.Cursor, error) {
+	return s.notifRepo.FindByUserID(ctx, user.ID, unreadOnly, page)
+}
+
+// MarkRead marks one of the user's notifications read
+func (s *NotificationService) MarkRead(ctx context.Context, user *models.User, notificationID string) (*models.Notification, error) {
+	objID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return nil, ErrNotificationNotFound
+	}
+
+	notification, err := s.notifRepo.MarkRead(ctx, user.ID, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks the user's whole inbox read and returns how many were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, user *models.User) (int64, error) {
+	return s.notifRepo.MarkAllRead(ctx, user.ID)
+}
+
+// UnreadCount returns the number for the user's unread badge
+func (s *NotificationService) UnreadCount(ctx context.Context, user *models.User) (int64, error) {
+	return s.notifRepo.CountUnread(ctx, user.ID)
+}
+
+// NotifyBillCreated notifies group members about a new bill
+func (s *NotificationService) NotifyBillCreated(ctx context.Context, bill *models.Bill, creatorName string, groupName string, memberIDs []primitive.ObjectID) error {
+	// Exclude the payer (bill creator) from notifications
+	var recipients []primitive.ObjectID
+	for _, id := range memberIDs {
+		if id != bill.PaidBy {
+			recipients = append(recipients, id)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notif := &Notification{
+		Type:    NotifBillCreated,
+		Key:     string(NotifBillCreated),
+		Params:  i18n.Params{"actor": creatorName, "group": groupName, "bill": bill.Title, "amount": i18n.Amount(bill.TotalAmount)},
+		Data: map[string]string{
+			"type":     string(NotifBillCreated),
+			"bill_id":  bill.ID.Hex(),
+			"group_id": bill.GroupID.Hex(),
+		},
+		UserIDs: recipients,
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyPaymentReceived notifies a user they received a payment
+func (s *NotificationService) NotifyPaymentReceived(ctx context.Context, transaction *models.Transaction, fromUserName string) error {
+	notif := &Notification{
+		Type:    NotifPaymentReceived,
+		Key:     string(NotifPaymentReceived),
+		Params:  i18n.Params{"actor": fromUserName, "amount": i18n.Amount(transaction.Amount)},
+		Data: map[string]string{
+			"type":           string(NotifPaymentReceived),
+			"transaction_id": transaction.ID.Hex(),
+			"group_id":       transaction.GroupID.Hex(),
+		},
+		UserIDs: []primitive.ObjectID{transaction.ToUser},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyPaymentConfirmed notifies a user their payment was confirmed
+func (s *NotificationService) NotifyPaymentConfirmed(ctx context.Context, transaction *models.Transaction, confirmerName string) error {
+	notif := &Notification{
+		Type:    NotifPaymentConfirmed,
+		Key:     string(NotifPaymentConfirmed),
+		Params:  i18n.Params{"actor": confirmerName, "amount": i18n.Amount(transaction.Amount)},
+		Data: map[string]string{
+			"type":           string(NotifPaymentConfirmed),
+			"transaction_id": transaction.ID.Hex(),
+			"group_id":       transaction.GroupID.Hex(),
+		},
+		UserIDs: []primitive.ObjectID{transaction.FromUser},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyMemberJoined notifies group members when someone joins
+func (s *NotificationService) NotifyMemberJoined(ctx context.Context, groupID primitive.ObjectID, groupName string, newMemberName string, memberIDs []primitive.ObjectID, excludeID primitive.ObjectID) error {
+	var recipients []primitive.ObjectID
+	for _, id := range memberIDs {
+		if id != excludeID {
+			recipients = append(recipients, id)
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notif := &Notification{
+		Type:    NotifMemberJoined,
+		Key:     string(NotifMemberJoined),
+		Params:  i18n.Params{"actor": newMemberName, "group": groupName},
+		Data: map[string]string{
+			"type":     string(NotifMemberJoined),
+			"group_id": groupID.Hex(),
+		},
+		UserIDs: recipients,
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyAddedToGroup tells a user someone added them to a group
+func (s *NotificationService) NotifyAddedToGroup(ctx context.Context, groupID primitive.ObjectID, groupName string, adderName string, userID primitive.ObjectID) error {
+	notif := &Notification{
+		Type:  NotifGroupInvite,
+		Key:    string(NotifGroupInvite),
+		Params: i18n.Params{"actor": adderName, "group": groupName},
+		Data: map[string]string{
+			"type":     string(NotifGroupInvite),
+			"group_id": groupID.Hex(),
+		},
+		UserIDs: []primitive.ObjectID{userID},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyJoinRequested tells a group's owners and admins someone asked to join
+func (s *NotificationService) NotifyJoinRequested(ctx context.Context, request *models.JoinRequest, groupName string, applicantName string, approverIDs []primitive.ObjectID) error {
+	if len(approverIDs) == 0 {
+		return nil
+	}
+
+	notif := &Notification{
+		Type:   NotifJoinRequest,
+		Key:    string(NotifJoinRequest),
+		Params: i18n.Params{"actor": applicantName, "group": groupName},
+		Data: map[string]string{
+			"type":       string(NotifJoinRequest),
+			"group_id":   request.GroupID.Hex(),
+			"request_id": request.ID.Hex(),
+		},
+		UserIDs: approverIDs,
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyJoinRequestDecided tells the applicant their join request was
+// approved or denied
+func (s *NotificationService) NotifyJoinRequestDecided(ctx context.Context, request *models.JoinRequest, groupName string) error {
+	notif := &Notification{
+		Type:   NotifJoinRequestResult,
+		Key:    string(NotifJoinRequestResult) + "." + string(request.Status),
+		Params: i18n.Params{"group": groupName},
+		Data: map[string]string{
+			"type":       string(NotifJoinRequestResult),
+			"group_id":   request.GroupID.Hex(),
+			"request_id": request.ID.Hex(),
+			"status":     string(request.Status),
+		},
+		UserIDs: []primitive.ObjectID{request.UserID},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// SettlementReminder describes one reminder to pay a group debt
+type SettlementReminder struct {
+	UserID      primitive.ObjectID
+	GroupID     primitive.ObjectID
+	GroupName   string
+	Amount      float64
+	Level       int    // escalation level, 1 for the first scheduled reminder
+	RequestedBy string // member who pressed "remind", empty for scheduled reminders
+}
+
+// NotifySettlementReminder sends a reminder about pending settlements.
+// The wording gets firmer as the escalation level rises.
+func (s *NotificationService) NotifySettlementReminder(ctx context.Context, reminder SettlementReminder) error {
+	key := string(NotifSettlementReminder)
+	switch {
+	case reminder.RequestedBy != "":
+		key += ".requested"
+	case reminder.Level >= 3:
+		key += ".overdue"
+	case reminder.Level == 2:
+		key += ".unpaid"
+	}
+
+	notif := &Notification{
+		Type:   NotifSettlementReminder,
+		Key:    key,
+		Params: i18n.Params{"actor": reminder.RequestedBy, "group": reminder.GroupName, "amount": i18n.Amount(reminder.Amount)},
+		Data: map[string]string{
+			"type":     string(NotifSettlementReminder),
+			"group_id": reminder.GroupID.Hex(),
+			"level":    fmt.Sprintf("%d", reminder.Level),
+		},
+		UserIDs: []primitive.ObjectID{reminder.UserID},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
+
+// NotifyDigest sends a member the digest of one of their groups. The push
+// text is a one-line summary; emails get the full digest.
+func (s *NotificationService) NotifyDigest(ctx context.Context, userID primitive.ObjectID, digest *models.Digest) error {
+	html, err := notify.RenderDigestHTML(digest)
+	if err != nil {
+		return err
+	}
+
+	params := i18n.Params{
+		"group":       digest.GroupName,
+		"bills":       strconv.Itoa(len(digest.NewBills)),
+		"bills_total": i18n.Amount(digest.NewBillsTotal),
+		"balance":     i18n.Amount(digest.Balance),
+		"to_confirm":  strconv.Itoa(digest.ToConfirm),
+	}
+	if digest.TopCategory != nil {
+		params["top_category"] = digest.TopCategory.Category
+	}
+
+	notif := &Notification{
+		Type:   NotifDigest,
+		Key:    "digest." + string(digest.Frequency),
+		Params: params,
+		HTML:   html,
+		Data: map[string]string{
+			"type":         string(NotifDigest),
+			"group_id":     digest.GroupID,
+			"frequency":    string(digest.Frequency),
+			"period_start": digest.PeriodStart.Format(time.RFC3339),
+		},
+		UserIDs: []primitive.ObjectID{userID},
+	}
+
+	return s.SendNotification(ctx, notif)
+}
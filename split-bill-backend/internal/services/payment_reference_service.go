@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// referenceAttempts bounds retries when a freshly generated code is already taken
+const referenceAttempts = 5
+
+var (
+	ErrPaymentReferenceNotFound = errors.New("payment reference not found")
+	ErrPaymentReferenceInvalid  = errors.New("payment reference does not match this payment")
+)
+
+type PaymentReferenceService struct {
+	referenceRepo   *repository.PaymentReferenceRepository
+	transactionRepo *repository.TransactionRepository
+	groupRepo       *repository.GroupRepository
+}
+
+func NewPaymentReferenceService(
+	referenceRepo *repository.PaymentReferenceRepository,
+	transactionRepo *repository.TransactionRepository,
+	groupRepo *repository.GroupRepository,
+) *PaymentReferenceService {
+	return &PaymentReferenceService{
+		referenceRepo:   referenceRepo,
+		transactionRepo: transactionRepo,
+		groupRepo:       groupRepo,
+	}
+}
+
+// ReserveForTransaction picks the reference for a new transaction, whose ID must
+// already be assigned. If the payer passes the reference of a settle-up item they
+// are paying, that code is claimed so the note they already typed still matches;
+// otherwise a new one is issued.
+func (s *PaymentReferenceService) ReserveForTransaction(ctx context.Context, tx *models.Transaction, requested string) (string, error) {
+	if requested == "" {
+		ref, err := s.create(ctx, &models.PaymentReference{
+			Kind:          models.ReferenceTransaction,
+			GroupID:       tx.GroupID,
+			FromUser:      tx.FromUser,
+			ToUser:        tx.ToUser,
+			Amount:        tx.Amount,
+			TransactionID: tx.ID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return ref.Code, nil
+	}
+
+	ref, err := s.Resolve(ctx, requested)
+	if err != nil {
+		return "", err
+	}
+	if ref.Kind != models.ReferenceSettlement || !ref.TransactionID.IsZero() ||
+		ref.GroupID != tx.GroupID || ref.FromUser != tx.FromUser || ref.ToUser != tx.ToUser {
+		return "", ErrPaymentReferenceInvalid
+	}
+
+	// Claiming is atomic, so two payments cannot take the same settle-up code
+	if err := s.referenceRepo.LinkTransaction(ctx, ref.Code, tx.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", ErrPaymentReferenceInvalid
+		}
+		return "", err
+	}
+	return ref.Code, nil
+}
+
+// Release gives back the reference reserved for a transaction that could
+// not be stored, so a settle-up code the payer typed can be used again
+func (s *PaymentReferenceService) Release(ctx context.Context, tx *models.Transaction) error {
+	if tx.Reference == "" {
+		return nil
+	}
+	return s.referenceRepo.Release(ctx, tx.Reference, tx.ID)
+}
+
+// AttachToSettlements gives every settle-up suggestion a reference. What one
+// member owes another keeps its code across calls, even as the amount
+// changes, until a payment claims it; so there is at most one open code per
+// pair of members.
+func (s *PaymentReferenceService) AttachToSettlements(ctx context.Context, groupID string, settlements []models.Settlement) error {
+	groupObjID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return errors.New("invalid group ID")
+	}
+
+	for i := range settlements {
+		fromUser, err := primitive.ObjectIDFromHex(settlements[i].FromUserID)
+		if err != nil {
+			continue
+		}
+		toUser, err := primitive.ObjectIDFromHex(settlements[i].ToUserID)
+		if err != nil {
+			continue
+		}
+
+		amount := settlements[i].Amount
+		ref, err := withNewReferenceCode(func(code string) (*models.PaymentReference, error) {
+			return s.referenceRepo.UpsertOpenSettlement(ctx, groupObjID, fromUser, toUser, amount, code)
+		})
+		if err != nil {
+			return err
+		}
+		settlements[i].Reference = ref.Code
+	}
+	return nil
+}
+
+// Lookup resolves a reference to what it pays. Only members of the group
+// the payment belongs to can look it up.
+func (s *PaymentReferenceService) Lookup(ctx context.Context, code string, user *models.User) (*models.PaymentReferenceResponse, error) {
+	ref, err := s.Resolve(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.groupRepo.IsMember(ctx, ref.GroupID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		// Do not reveal that the code exists
+		return nil, ErrPaymentReferenceNotFound
+	}
+
+	response := ref.ToResponse()
+	if !ref.TransactionID.IsZero() {
+		tx, err := s.transactionRepo.FindByID(ctx, ref.TransactionID)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		if tx != nil {
+			txResponse := tx.ToResponse()
+			response.Transaction = &txResponse
+		}
+	}
+	return &response, nil
+}
+
+// Resolve finds a reference by code without any access check, for internal
+// callers such as reconciliation and payment webhooks
+func (s *PaymentReferenceService) Resolve(ctx context.Context, code string) (*models.PaymentReference, error) {
+	code = utils.NormalizePaymentReference(code)
+	if !utils.IsPaymentReference(code) {
+		return nil, ErrPaymentReferenceNotFound
+	}
+
+	ref, err := s.referenceRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrPaymentReferenceNotFound
+		}
+		return nil, err
+	}
+	return ref, nil
+}
+
+// create stores a reference under a fresh code, retrying on the rare collision
+func (s *PaymentReferenceService) create(ctx context.Context, ref *models.PaymentReference) (*models.PaymentReference, error) {
+	return withNewReferenceCode(func(code string) (*models.PaymentReference, error) {
+		ref.Code = code
+		if err := s.referenceRepo.Create(ctx, ref); err != nil {
+			return nil, err
+		}
+		return ref, nil
+	})
+}
+
+// withNewReferenceCode calls store with freshly generated codes until it
+// does not fail with a duplicate key
+func withNewReferenceCode(store func(code string) (*models.PaymentReference, error)) (*models.PaymentReference, error) {
+	for attempt := 0; attempt < referenceAttempts; attempt++ {
+		ref, err := store(utils.GeneratePaymentReference())
+		if !mongo.IsDuplicateKeyError(err) {
+			return ref, err
+		}
+	}
+	return nil, errors.New("could not generate a unique payment reference")
+}
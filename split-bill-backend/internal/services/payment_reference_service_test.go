@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/splitbill/backend/internal/models"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errDuplicateKey = mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+
+func TestWithNewReferenceCodeFindsRacingUpsert(t *testing.T) {
+	// Another request hands out a reference for the pair between our
+	// upsert's lookup and its insert, so the insert hits the unique index
+	var open *models.PaymentReference
+	var codes []string
+	upsert := func(code string) (*models.PaymentReference, error) {
+		codes = append(codes, code)
+		if open == nil {
+			open = &models.PaymentReference{Code: "SBRACER1", Kind: models.ReferenceSettlement, Open: true}
+			return nil, errDuplicateKey
+		}
+		open.Amount = 150000
+		return open, nil
+	}
+
+	ref, err := withNewReferenceCode(upsert)
+	if err != nil {
+		t.Fatalf("withNewReferenceCode: %v", err)
+	}
+	if ref.Code != "SBRACER1" || ref.Amount != 150000 {
+		t.Errorf("ref = %+v, want the other request's reference with the current amount", ref)
+	}
+	if len(codes) != 2 || codes[0] == codes[1] {
+		t.Errorf("tried codes %v, want two different ones", codes)
+	}
+}
+
+func TestWithNewReferenceCodeStopsOnOtherErrors(t *testing.T) {
+	down := errors.New("connection reset")
+	calls := 0
+	_, err := withNewReferenceCode(func(string) (*models.PaymentReference, error) {
+		calls++
+		return nil, down
+	})
+	if !errors.Is(err, down) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the error after 1", err, calls)
+	}
+
+	calls = 0
+	_, err = withNewReferenceCode(func(string) (*models.PaymentReference, error) {
+		calls++
+		return nil, errDuplicateKey
+	})
+	if err == nil || calls != referenceAttempts {
+		t.Errorf("err = %v after %d calls, want an error after %d", err, calls, referenceAttempts)
+	}
+}
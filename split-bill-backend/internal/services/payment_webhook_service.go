@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"github.com/splitbill/backend/pkg/bankhook"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// paymentEventStore is what PaymentWebhookService needs of PaymentEventRepository
+type paymentEventStore interface {
+	Create(ctx context.Context, event *models.PaymentEvent) error
+	FindByProviderTxID(ctx context.Context, provider, providerTxID string) (*models.PaymentEvent, error)
+	Resolve(ctx context.Context, event *models.PaymentEvent) error
+}
+
+type PaymentWebhookService struct {
+	eventRepo          paymentEventStore
+	transactionRepo    *repository.TransactionRepository
+	userRepo           *repository.UserRepository
+	referenceService   *PaymentReferenceService
+	transactionService *TransactionService
+
+	// match is matchEvent; tests replace it
+	match func(ctx context.Context, event *models.PaymentEvent) error
+}
+
+func NewPaymentWebhookService(
+	eventRepo *repository.PaymentEventRepository,
+	transactionRepo *repository.TransactionRepository,
+	userRepo *repository.UserRepository,
+	referenceService *PaymentReferenceService,
+	transactionService *TransactionService,
+) *PaymentWebhookService {
+	s := &PaymentWebhookService{
+		eventRepo:          eventRepo,
+		transactionRepo:    transactionRepo,
+		userRepo:           userRepo,
+		referenceService:   referenceService,
+		transactionService: transactionService,
+	}
+	s.match = s.matchEvent
+	return s
+}
+
+// HandleTransfers records the transfers of one webhook delivery and confirms the
+// pending transactions they pay. Transfers the provider already delivered are
+// skipped, except those whose matching failed part way: an incoming event is
+// stored as processing until it is resolved, so the provider's retry after an
+// error matches it again instead of dropping the payment.
+func (s *PaymentWebhookService) HandleTransfers(ctx context.Context, transfers []bankhook.Transfer) (*models.PaymentWebhookResult, error) {
+	result := &models.PaymentWebhookResult{
+		Received: len(transfers),
+		Events:   []models.PaymentEvent{},
+	}
+
+	for _, transfer := range transfers {
+		event := &models.PaymentEvent{
+			Provider:        transfer.Provider,
+			ProviderTxID:    transfer.ID,
+			Incoming:        transfer.Incoming,
+			Amount:          transfer.Amount,
+			Description:     transfer.Description,
+			AccountNumber:   transfer.AccountNumber,
+			Bank:            transfer.Bank,
+			BankReference:   transfer.BankReference,
+			TransactionTime: transfer.Time,
+			Status:          models.PaymentEventProcessing,
+		}
+		if !transfer.Incoming {
+			event.Status = models.PaymentEventIgnored
+		}
+
+		if err := s.eventRepo.Create(ctx, event); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				return nil, err
+			}
+			stored, err := s.eventRepo.FindByProviderTxID(ctx, event.Provider, event.ProviderTxID)
+			if err != nil {
+				return nil, err
+			}
+			if stored.Status != models.PaymentEventProcessing {
+				result.Duplicates++
+				continue
+			}
+			event = stored
+		}
+
+		if event.Status == models.PaymentEventProcessing {
+			event.Status = models.PaymentEventUnmatched
+			if err := s.match(ctx, event); err != nil {
+				return nil, err
+			}
+			if err := s.eventRepo.Resolve(ctx, event); err != nil {
+				return nil, err
+			}
+		}
+
+		switch event.Status {
+		case models.PaymentEventMatched:
+			result.Matched++
+		case models.PaymentEventUnmatched:
+			result.Unmatched++
+		}
+		result.Events = append(result.Events, *event)
+	}
+	return result, nil
+}
+
+// matchEvent confirms the pending transaction whose payment reference appears in
+// the transfer note and whose amount equals the transfer. The account that
+// received the money must be one of the recipient's saved accounts; events that
+// cannot show this stay unmatched for the recipient to confirm by hand.
+func (s *PaymentWebhookService) matchEvent(ctx context.Context, event *models.PaymentEvent) error {
+	codes := utils.FindPaymentReferences(normalizeReference(event.Description))
+	if len(codes) == 0 {
+		event.Reason = "no payment reference in description"
+		return nil
+	}
+
+	event.Reason = "payment reference not found"
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		ref, err := s.referenceService.Resolve(ctx, code)
+		if err != nil {
+			if errors.Is(err, ErrPaymentReferenceNotFound) {
+				continue
+			}
+			return err
+		}
+		event.Reference = ref.Code
+		if ref.TransactionID.IsZero() {
+			event.Reason = "no transaction recorded for this reference"
+			continue
+		}
+
+		tx, err := s.transactionRepo.FindByID(ctx, ref.TransactionID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return err
+		}
+		if tx.Type == models.TransactionReversal || tx.Status != models.TransactionPending {
+			event.Reason = "transaction is not pending"
+			continue
+		}
+		if !sameAmount(*tx, event.Amount) {
+			event.Reason = "amount does not match the transaction"
+			continue
+		}
+
+		recipient, err := s.userRepo.FindByID(ctx, tx.ToUser)
+		if err != nil {
+			return err
+		}
+		if reason := unverifiedAccount(recipient, event.AccountNumber); reason != "" {
+			event.Reason = reason
+			continue
+		}
+
+		if _, err := s.transactionService.ConfirmTransaction(ctx, tx.ID.Hex(), recipient); err != nil {
+			if errors.Is(err, ErrTransactionNotPending) {
+				event.Reason = "transaction is not pending"
+				continue
+			}
+			return err
+		}
+
+		event.Status = models.PaymentEventMatched
+		event.TransactionID = tx.ID
+		event.Reason = ""
+		return nil
+	}
+	return nil
+}
+
+// unverifiedAccount explains why a transfer to accountNumber cannot be shown
+// to have reached the user, or returns "" if it went to one of their saved
+// accounts. The aggregator may watch accounts of other people, so a transfer
+// to an unknown account never counts.
+func unverifiedAccount(user *models.User, accountNumber string) string {
+	accountNumber = strings.ReplaceAll(accountNumber, " ", "")
+	if accountNumber == "" {
+		return "provider did not report the receiving account"
+	}
+	if len(user.BankAccounts) == 0 {
+		return "recipient has no saved bank account to verify against"
+	}
+	for _, account := range user.BankAccounts {
+		if strings.ReplaceAll(account.AccountNumber, " ", "") == accountNumber {
+			return ""
+		}
+	}
+	return "receiving account does not belong to the recipient"
+}
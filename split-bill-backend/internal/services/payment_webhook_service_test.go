@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/pkg/bankhook"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memoryEventStore keeps payment events by provider and transaction ID, like
+// the unique index on payment_events
+type memoryEventStore struct {
+	events map[string]models.PaymentEvent
+}
+
+func newMemoryEventStore() *memoryEventStore {
+	return &memoryEventStore{events: make(map[string]models.PaymentEvent)}
+}
+
+func (m *memoryEventStore) Create(ctx context.Context, event *models.PaymentEvent) error {
+	key := event.Provider + "/" + event.ProviderTxID
+	if _, ok := m.events[key]; ok {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+	}
+	event.ID = primitive.NewObjectID()
+	m.events[key] = *event
+	return nil
+}
+
+func (m *memoryEventStore) FindByProviderTxID(ctx context.Context, provider, providerTxID string) (*models.PaymentEvent, error) {
+	event, ok := m.events[provider+"/"+providerTxID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &event, nil
+}
+
+func (m *memoryEventStore) Resolve(ctx context.Context, event *models.PaymentEvent) error {
+	key := event.Provider + "/" + event.ProviderTxID
+	stored := m.events[key]
+	stored.Status, stored.Reason, stored.TransactionID = event.Status, event.Reason, event.TransactionID
+	m.events[key] = stored
+	return nil
+}
+
+func TestHandleTransfersRetriesFailedMatch(t *testing.T) {
+	store := newMemoryEventStore()
+	txID := primitive.NewObjectID()
+	confirmed := 0
+	attempts := 0
+
+	s := &PaymentWebhookService{eventRepo: store}
+	s.match = func(ctx context.Context, event *models.PaymentEvent) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("connection reset")
+		}
+		confirmed++
+		event.Status = models.PaymentEventMatched
+		event.TransactionID = txID
+		return nil
+	}
+
+	transfer := bankhook.Transfer{
+		Provider:      bankhook.ProviderSePay,
+		ID:            "92704",
+		Incoming:      true,
+		Amount:        150000,
+		Description:   "SB7K2Q9M tra tien an trua",
+		AccountNumber: "0071000123456",
+		Time:          time.Now(),
+	}
+	ctx := context.Background()
+
+	if _, err := s.HandleTransfers(ctx, []bankhook.Transfer{transfer}); err == nil {
+		t.Fatal("first delivery: want the matching error")
+	}
+	stored, _ := store.FindByProviderTxID(ctx, transfer.Provider, transfer.ID)
+	if stored.Status != models.PaymentEventProcessing {
+		t.Fatalf("after failed match, status = %q, want processing", stored.Status)
+	}
+
+	result, err := s.HandleTransfers(ctx, []bankhook.Transfer{transfer})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if result.Matched != 1 || result.Duplicates != 0 || confirmed != 1 {
+		t.Errorf("retry: result = %+v, confirmed %d times", result, confirmed)
+	}
+	stored, _ = store.FindByProviderTxID(ctx, transfer.Provider, transfer.ID)
+	if stored.Status != models.PaymentEventMatched || stored.TransactionID != txID {
+		t.Errorf("after retry, stored = %+v", stored)
+	}
+
+	result, err = s.HandleTransfers(ctx, []bankhook.Transfer{transfer})
+	if err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if result.Duplicates != 1 || result.Matched != 0 || confirmed != 1 {
+		t.Errorf("redelivery: result = %+v, confirmed %d times", result, confirmed)
+	}
+}
+
+func TestHandleTransfersIgnoresOutgoing(t *testing.T) {
+	s := &PaymentWebhookService{eventRepo: newMemoryEventStore()}
+	s.match = func(ctx context.Context, event *models.PaymentEvent) error {
+		t.Errorf("outgoing transfer %s was matched", event.ProviderTxID)
+		return nil
+	}
+
+	result, err := s.HandleTransfers(context.Background(), []bankhook.Transfer{
+		{Provider: bankhook.ProviderCasso, ID: "1", Amount: 50000, Description: "SB7K2Q9M"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 0 || result.Unmatched != 0 || result.Events[0].Status != models.PaymentEventIgnored {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestUnverifiedAccount(t *testing.T) {
+	saved := &models.User{BankAccounts: []models.BankAccount{
+		{BankCode: "VCB", AccountNumber: "0071 0001 23456"},
+	}}
+	tests := []struct {
+		name     string
+		user     *models.User
+		account  string
+		verified bool
+	}{
+		{"saved account", saved, "0071000123456", true},
+		{"saved account with spaces", saved, "0071 000123456", true},
+		{"other account", saved, "1903555555", false},
+		{"account not reported", saved, "", false},
+		{"recipient without accounts", &models.User{}, "0071000123456", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := unverifiedAccount(tt.user, tt.account)
+			if (reason == "") != tt.verified {
+				t.Errorf("unverifiedAccount = %q, want verified %v", reason, tt.verified)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// reconcileWindow is how far a transfer date may be from the day the
+// pending transaction was created and still count as a match
+const reconcileWindow = 3 * 24 * time.Hour
+
+// amountTolerance absorbs rounding in VND amounts
+const amountTolerance = 0.5
+
+var (
+	ErrStatementLineNotFound = errors.New("statement line not found")
+	ErrStatementLineResolved = errors.New("statement line is already resolved")
+	ErrInvalidStatementLine  = errors.New("invalid statement line ID")
+)
+
+type ReconciliationService struct {
+	statementRepo      *repository.StatementRepository
+	transactionRepo    *repository.TransactionRepository
+	groupRepo          *repository.GroupRepository
+	transactionService *TransactionService
+}
+
+func NewReconciliationService(
+	statementRepo *repository.StatementRepository,
+	transactionRepo *repository.TransactionRepository,
+	groupRepo *repository.GroupRepository,
+	transactionService *TransactionService,
+) *ReconciliationService {
+	return &ReconciliationService{
+		statementRepo:      statementRepo,
+		transactionRepo:    transactionRepo,
+		groupRepo:          groupRepo,
+		transactionService: transactionService,
+	}
+}
+
+// ImportStatement parses a bank statement export, stores its incoming transfers
+// and confirms the pending transactions they pay. Lines seen in an earlier
+// import are skipped; lines without a confident match go to the review queue.
+func (s *ReconciliationService) ImportStatement(ctx context.Context, user *models.User, groupID, bank, fileName string, file io.Reader) (*models.StatementImportResult, error) {
+	imp := &models.StatementImport{
+		UserID:   user.ID,
+		Bank:     strings.TrimSpace(bank),
+		FileName: fileName,
+	}
+
+	if groupID != "" {
+		groupObjID, err := primitive.ObjectIDFromHex(groupID)
+		if err != nil {
+			return nil, ErrInvalidGroupID
+		}
+		isMember, err := s.groupRepo.IsMember(ctx, groupObjID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrNotGroupMember
+		}
+		imp.GroupID = groupObjID
+	}
+
+	entries, err := utils.ParseBankStatement(file)
+	if err != nil {
+		return nil, err
+	}
+	imp.TotalLines = len(entries)
+
+	if err := s.statementRepo.CreateImport(ctx, imp); err != nil {
+		return nil, err
+	}
+
+	result := &models.StatementImportResult{
+		Matched:   []models.StatementLine{},
+		Unmatched: []models.StatementLine{},
+	}
+	claimed := make(map[primitive.ObjectID]bool)
+	occurrences := make(map[string]int)
+
+	for _, entry := range entries {
+		// Only money coming in can pay someone's debt to the importer
+		if entry.Amount <= 0 {
+			continue
+		}
+		imp.IncomingLines++
+
+		line := &models.StatementLine{
+			ImportID:      imp.ID,
+			UserID:        user.ID,
+			GroupID:       imp.GroupID,
+			Bank:          imp.Bank,
+			Row:           entry.Row,
+			Date:          entry.Date,
+			Amount:        entry.Amount,
+			Description:   entry.Description,
+			BankReference: entry.Reference,
+			Fingerprint:   statementFingerprint(entry, occurrences),
+			Status:        models.StatementLineUnmatched,
+		}
+		if err := s.statementRepo.CreateLine(ctx, line); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				imp.DuplicateLines++
+				continue
+			}
+			return nil, err
+		}
+
+		if err := s.reconcileLine(ctx, user, line, claimed); err != nil {
+			return nil, err
+		}
+
+		if line.Status == models.StatementLineMatched {
+			imp.MatchedLines++
+			result.Matched = append(result.Matched, *line)
+		} else {
+			imp.UnmatchedLines++
+			result.Unmatched = append(result.Unmatched, *line)
+		}
+	}
+
+	if err := s.statementRepo.UpdateImportCounts(ctx, imp); err != nil {
+		return nil, err
+	}
+	result.Import = *imp
+	return result, nil
+}
+
+// reconcileLine looks for the pending transaction a line pays and confirms it.
+// A line matches when its description carries the transaction's payment reference
+// and the amount is equal, or when it is the only pending transaction with that
+// amount inside the date window. Anything else is left for review with suggestions.
+func (s *ReconciliationService) reconcileLine(ctx context.Context, user *models.User, line *models.StatementLine, claimed map[primitive.ObjectID]bool) error {
+	pending, err := s.transactionRepo.FindPendingToUser(ctx, user.ID, line.GroupID,
+		line.Date.Add(-reconcileWindow), line.Date.Add(reconcileWindow+24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	match, reason, byAmount := matchStatementLine(line, pending, claimed)
+	if match != nil {
+		if _, err := s.transactionService.ConfirmTransaction(ctx, match.ID.Hex(), user); err == nil {
+			claimed[match.ID] = true
+			if err := s.statementRepo.ResolveLine(ctx, line.ID, models.StatementLineMatched, match.ID, reason); err != nil {
+				return err
+			}
+			line.Status = models.StatementLineMatched
+			line.TransactionID = match.ID
+			line.MatchReason = reason
+			return nil
+		} else if !errors.Is(err, ErrTransactionNotPending) {
+			return err
+		}
+	}
+
+	candidates := make([]primitive.ObjectID, 0, len(byAmount))
+	for _, tx := range byAmount {
+		candidates = append(candidates, tx.ID)
+	}
+	if len(candidates) > 0 {
+		if err := s.statementRepo.SetCandidates(ctx, line.ID, candidates); err != nil {
+			return err
+		}
+		line.Candidates = candidates
+	}
+	return nil
+}
+
+// matchStatementLine picks the pending transaction a line certainly pays, if
+// any, and returns every unclaimed transaction with the line's amount as
+// candidates for review
+func matchStatementLine(line *models.StatementLine, pending []models.Transaction, claimed map[primitive.ObjectID]bool) (*models.Transaction, models.StatementMatchReason, []models.Transaction) {
+	description := normalizeReference(line.Description)
+	var byReference, byAmount []models.Transaction
+	mentionsOther := false
+	for _, tx := range pending {
+		referenced := referencedIn(description, tx)
+		// A second transfer naming a transaction an earlier line already
+		// paid is a double payment, not a payment of some other transaction
+		if claimed[tx.ID] || !sameAmount(tx, line.Amount) {
+			mentionsOther = mentionsOther || referenced
+			continue
+		}
+		byAmount = append(byAmount, tx)
+		if referenced {
+			byReference = append(byReference, tx)
+		}
+	}
+
+	switch {
+	case len(byReference) == 1:
+		return &byReference[0], models.MatchByReference, byAmount
+	case len(byReference) == 0 && len(byAmount) == 1 && !mentionsOther:
+		// A reference to a different transaction with another amount
+		// means a partial or wrong transfer, which needs a human
+		return &byAmount[0], models.MatchByAmountDate, byAmount
+	}
+	return nil, "", byAmount
+}
+
+// ListImports returns one page of the user's statement imports
+func (s *ReconciliationService) ListImports(ctx context.Context, user *models.User, pagination *utils.Pagination) ([]models.StatementImport, error) {
+	imports, total, err := s.statementRepo.FindImportsByUser(ctx, user.ID, pagination.Skip(), int64(pagination.Limit))
+	if err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+	return imports, nil
+}
+
+// ListLines returns one page of the user's statement lines in a status;
+// unmatched lines form the review queue
+func (s *ReconciliationService) ListLines(ctx context.Context, user *models.User, status models.StatementLineStatus, pagination *utils.Pagination) ([]models.StatementLine, error) {
+	lines, total, err := s.statementRepo.FindLines(ctx, user.ID, status, pagination.Skip(), int64(pagination.Limit))
+	if err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+	return lines, nil
+}
+
+// LinkLine resolves a review queue line by hand by confirming the given pending transaction
+func (s *ReconciliationService) LinkLine(ctx context.Context, user *models.User, lineID, txID string) (*models.StatementLine, error) {
+	line, err := s.unmatchedLine(ctx, user, lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.transactionService.GetTransaction(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the line first so two concurrent links cannot confirm two transactions
+	if err := s.statementRepo.ResolveLine(ctx, line.ID, models.StatementLineLinked, tx.ID, ""); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrStatementLineResolved
+		}
+		return nil, err
+	}
+
+	if _, err := s.transactionService.ConfirmTransaction(ctx, tx.ID.Hex(), user); err != nil {
+		_ = s.statementRepo.ReopenLine(ctx, line.ID)
+		return nil, err
+	}
+
+	return s.statementRepo.FindLineByID(ctx, line.ID)
+}
+
+// IgnoreLine removes an unrelated transfer from the review queue
+func (s *ReconciliationService) IgnoreLine(ctx context.Context, user *models.User, lineID string) (*models.StatementLine, error) {
+	line, err := s.unmatchedLine(ctx, user, lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.statementRepo.ResolveLine(ctx, line.ID, models.StatementLineIgnored, primitive.NilObjectID, ""); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrStatementLineResolved
+		}
+		return nil, err
+	}
+
+	return s.statementRepo.FindLineByID(ctx, line.ID)
+}
+
+// unmatchedLine loads one of the user's lines that is still in the review queue
+func (s *ReconciliationService) unmatchedLine(ctx context.Context, user *models.User, lineID string) (*models.StatementLine, error) {
+	objID, err := primitive.ObjectIDFromHex(lineID)
+	if err != nil {
+		return nil, ErrInvalidStatementLine
+	}
+
+	line, err := s.statementRepo.FindLineByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrStatementLineNotFound
+		}
+		return nil, err
+	}
+	if line.UserID != user.ID {
+		return nil, ErrStatementLineNotFound
+	}
+	if line.Status != models.StatementLineUnmatched {
+		return nil, ErrStatementLineResolved
+	}
+	return line, nil
+}
+
+// statementFingerprint identifies a bank line so re-importing an overlapping
+// statement does not create duplicates. Identical transfers on the same day
+// are told apart by the running balance after each or, when the export has
+// no balance column, by their order among the identical lines of the file;
+// occurrences counts those lines as the file is read.
+func statementFingerprint(entry utils.StatementEntry, occurrences map[string]int) string {
+	key := fmt.Sprintf("%s|%.2f|%s|%s",
+		entry.Date.Format(time.RFC3339), entry.Amount, entry.Reference, entry.Description)
+	if entry.Balance != nil {
+		key += fmt.Sprintf("|balance %.2f", *entry.Balance)
+	} else {
+		occurrences[key]++
+		key += fmt.Sprintf("|#%d", occurrences[key])
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// sameAmount reports whether a transaction is for the given VND amount
+func sameAmount(tx models.Transaction, amount float64) bool {
+	if tx.Currency != "" && !strings.EqualFold(tx.Currency, "VND") {
+		return false
+	}
+	return math.Abs(tx.Amount-amount) < amountTolerance
+}
+
+// referencedIn reports whether a normalized statement description mentions the
+// transaction by its payment reference or its ID
+func referencedIn(description string, tx models.Transaction) bool {
+	if tx.Reference != "" && strings.Contains(description, tx.Reference) {
+		return true
+	}
+	return strings.Contains(description, strings.ToUpper(tx.ID.Hex()))
+}
+
+// normalizeReference keeps only letters and digits in upper case, since banks
+// drop or replace punctuation and spaces in transfer descriptions
+func normalizeReference(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(utils.NormalizeStatementText(s)) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatchStatementLine(t *testing.T) {
+	lunch := models.Transaction{ID: primitive.NewObjectID(), Amount: 150000, Currency: "VND", Reference: "SB7K2Q9M"}
+	movie := models.Transaction{ID: primitive.NewObjectID(), Amount: 150000, Currency: "VND", Reference: "SB2M4N6P"}
+	rent := models.Transaction{ID: primitive.NewObjectID(), Amount: 3000000, Currency: "VND", Reference: "SB9X8W7V"}
+	dollars := models.Transaction{ID: primitive.NewObjectID(), Amount: 150000, Currency: "USD", Reference: "SB4R5T6Y"}
+	unreferenced := models.Transaction{ID: primitive.NewObjectID(), Amount: 150000, Currency: "VND"}
+
+	tests := []struct {
+		name        string
+		description string
+		amount      float64
+		pending     []models.Transaction
+		claimed     []primitive.ObjectID
+		want        *models.Transaction
+		reason      models.StatementMatchReason
+		candidates  int
+	}{
+		{"reference picks one of two same amounts", "MBVCB.5521.sb7k2q9m.tra tien an", 150000,
+			[]models.Transaction{lunch, movie}, nil, &lunch, models.MatchByReference, 2},
+		{"reference by transaction ID", "CK " + unreferenced.ID.Hex() + " cafe", 150000,
+			[]models.Transaction{unreferenced, movie}, nil, &unreferenced, models.MatchByReference, 2},
+		{"only transaction with the amount", "NGUYEN VAN AN chuyen tien", 150000,
+			[]models.Transaction{lunch, rent}, nil, &lunch, models.MatchByAmountDate, 1},
+		{"two with the amount and no reference", "NGUYEN VAN AN chuyen tien", 150000,
+			[]models.Transaction{lunch, movie}, nil, nil, "", 2},
+		{"reference with a different amount", "SB9X8W7V tra truoc tien nha", 150000,
+			[]models.Transaction{lunch, rent}, nil, nil, "", 1},
+		{"reference with a different amount only", "SB7K2Q9M", 100000,
+			[]models.Transaction{lunch}, nil, nil, "", 0},
+		{"other currency is never the amount", "chuyen tien", 150000,
+			[]models.Transaction{dollars}, nil, nil, "", 0},
+		{"within amount tolerance", "SB7K2Q9M", 150000.4,
+			[]models.Transaction{lunch}, nil, &lunch, models.MatchByReference, 1},
+		{"claimed by an earlier line", "NGUYEN VAN AN chuyen tien", 150000,
+			[]models.Transaction{lunch, movie}, []primitive.ObjectID{lunch.ID}, &movie, models.MatchByAmountDate, 1},
+		{"reference to a claimed transaction", "SB7K2Q9M", 150000,
+			[]models.Transaction{lunch, movie}, []primitive.ObjectID{lunch.ID}, nil, "", 1},
+		{"nothing pending", "SB7K2Q9M", 150000, nil, nil, nil, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claimed := make(map[primitive.ObjectID]bool)
+			for _, id := range tt.claimed {
+				claimed[id] = true
+			}
+			line := &models.StatementLine{Description: tt.description, Amount: tt.amount}
+
+			match, reason, candidates := matchStatementLine(line, tt.pending, claimed)
+			switch {
+			case tt.want == nil && match != nil:
+				t.Errorf("matched %s, want no match", match.Reference)
+			case tt.want != nil && (match == nil || match.ID != tt.want.ID):
+				t.Errorf("match = %v, want %s", match, tt.want.Reference)
+			}
+			if reason != tt.reason {
+				t.Errorf("reason = %q, want %q", reason, tt.reason)
+			}
+			if len(candidates) != tt.candidates {
+				t.Errorf("%d candidates, want %d", len(candidates), tt.candidates)
+			}
+		})
+	}
+}
+
+func TestStatementFingerprint(t *testing.T) {
+	day := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	transfer := utils.StatementEntry{Row: 7, Date: day, Amount: 150000, Description: "NGUYEN VAN AN chuyen tien"}
+	balance := func(v float64) *float64 { return &v }
+
+	t.Run("identical transfers without balance", func(t *testing.T) {
+		occurrences := make(map[string]int)
+		first := statementFingerprint(transfer, occurrences)
+		second := transfer
+		second.Row = 8
+		if statementFingerprint(second, occurrences) == first {
+			t.Error("two identical same-day transfers share a fingerprint")
+		}
+
+		// The same lines in a later export covering the same days, at other rows
+		reimport := make(map[string]int)
+		moved := transfer
+		moved.Row = 3
+		if statementFingerprint(moved, reimport) != first {
+			t.Error("the first transfer changed fingerprint on re-import")
+		}
+	})
+
+	t.Run("identical transfers with balance", func(t *testing.T) {
+		first, second := transfer, transfer
+		first.Balance, second.Balance = balance(1150000), balance(1300000)
+		a := statementFingerprint(first, make(map[string]int))
+		if statementFingerprint(second, make(map[string]int)) == a {
+			t.Error("two transfers with different balances share a fingerprint")
+		}
+		first.Row = 40
+		if statementFingerprint(first, make(map[string]int)) != a {
+			t.Error("fingerprint depends on the row when the balance is known")
+		}
+	})
+
+	t.Run("different transfers", func(t *testing.T) {
+		other := transfer
+		other.Amount = 200000
+		if statementFingerprint(other, make(map[string]int)) == statementFingerprint(transfer, make(map[string]int)) {
+			t.Error("different amounts share a fingerprint")
+		}
+	})
+}
+
+func TestNormalizeReference(t *testing.T) {
+	if got := normalizeReference("MBVCB.5521.sb7k2q9m-Trả tiền"); got != "MBVCB5521SB7K2Q9MTRATIEN" {
+		t.Errorf("normalizeReference = %q", got)
+	}
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultReminderHour is when reminders go out if the group does not choose
+	defaultReminderHour = 9
+
+	// reminderEscalationWindow is how far back earlier reminders count towards escalation
+	reminderEscalationWindow = 30 * 24 * time.Hour
+
+	// maxReminderLevel is the firmest reminder wording
+	maxReminderLevel = 3
+)
+
+// reminderTimezone is used for schedules and the once-a-day limit
+var reminderTimezone = time.FixedZone("ICT", 7*60*60)
+
+var (
+	ErrNotGroupAdmin        = errors.New("only group owners and admins can change reminder settings")
+	ErrNothingOwed          = errors.New("this member does not owe anything in this group")
+	ErrAlreadyRemindedToday = errors.New("this member was already reminded today")
+	ErrRemindersSnoozed     = errors.New("this member has snoozed reminders")
+	ErrRemindPlaceholder    = errors.New("placeholder members have no account to remind")
+)
+
+type ReminderService struct {
+	groupRepo       *repository.GroupRepository
+	billRepo        *repository.BillRepository
+	transactionRepo *repository.TransactionRepository
+	reminderRepo    *repository.ReminderRepository
+	debtService     *DebtService
+	notifService    *NotificationService
+	logger          *zap.Logger
+}
+
+func NewReminderService(
+	groupRepo *repository.GroupRepository,
+	billRepo *repository.BillRepository,
+	transactionRepo *repository.TransactionRepository,
+	reminderRepo *repository.ReminderRepository,
+	debtService *DebtService,
+	notifService *NotificationService,
+	logger *zap.Logger,
+) *ReminderService {
+	return &ReminderService{
+		groupRepo:       groupRepo,
+		billRepo:        billRepo,
+		transactionRepo: transactionRepo,
+		reminderRepo:    reminderRepo,
+		debtService:     debtService,
+		notifService:    notifService,
+		logger:          logger,
+	}
+}
+
+// Start runs the reminder schedule every interval until ctx is cancelled.
+// Every server instance may run it; the delivery log keeps reminders single.
+func (s *ReminderService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx, time.Now()); err != nil {
+			s.logger.Warn("Settlement reminder run failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue sends the scheduled reminders that are due at now
+func (s *ReminderService) RunDue(ctx context.Context, now time.Time) error {
+	groups, err := s.groupRepo.FindWithReminders(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		if err := s.runGroup(ctx, &groups[i], now); err != nil {
+			s.logger.Warn("Settlement reminders failed for group",
+				zap.String("group_id", groups[i].ID.Hex()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// runGroup reminds the members of one group whose reminder slot has come up
+func (s *ReminderService) runGroup(ctx context.Context, group *models.Group, now time.Time) error {
+	settings := group.Reminders
+	local := now.In(reminderTimezone)
+	if local.Hour() < settings.Hour {
+		return nil
+	}
+
+	// slots maps each member to remind to the key of this reminder slot
+	slots := make(map[primitive.ObjectID]string)
+	switch settings.Schedule {
+	case models.ReminderScheduleWeekly:
+		if local.Weekday() != settings.Weekday {
+			return nil
+		}
+		year, week := local.ISOWeek()
+		for _, member := range group.Members {
+			slots[member.UserID] = fmt.Sprintf("scheduled:%s:%s:%d-W%02d", group.ID.Hex(), member.UserID.Hex(), year, week)
+		}
+
+	case models.ReminderScheduleAfterBill:
+		bills, err := s.billRepo.FindActiveByGroupID(ctx, group.ID)
+		if err != nil {
+			return err
+		}
+		today := local.Format("2006-01-02")
+		for _, bill := range bills {
+			due := bill.CreatedAt.AddDate(0, 0, settings.DaysAfterBill).In(reminderTimezone)
+			if due.Format("2006-01-02") != today {
+				continue
+			}
+			for _, split := range bill.Splits {
+				if split.UserID != bill.PaidBy && !split.IsPaid {
+					// One reminder a day even if several bills come due together
+					slots[split.UserID] = fmt.Sprintf("scheduled:%s:%s:%s", group.ID.Hex(), split.UserID.Hex(), today)
+				}
+			}
+		}
+
+	default:
+		return nil
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+
+	owed, err := s.outstandingDebts(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range group.Members {
+		key, ok := slots[member.UserID]
+		amount := owed[member.UserID]
+		if !ok || member.Placeholder || amount <= 0 || amount < settings.MinAmount || isSnoozed(member, now) {
+			continue
+		}
+
+		previous, err := s.reminderRepo.CountSince(ctx, group.ID, member.UserID, models.ReminderKindScheduled, now.Add(-reminderEscalationWindow))
+		if err != nil {
+			return err
+		}
+		level := int(math.Min(float64(previous+1), maxReminderLevel))
+
+		delivery := &models.ReminderDelivery{
+			Key:     key,
+			Kind:    models.ReminderKindScheduled,
+			GroupID: group.ID,
+			UserID:  member.UserID,
+			Amount:  amount,
+			Level:   level,
+		}
+		if err := s.deliver(ctx, group, delivery, ""); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemindMember sends a "remind this person" reminder. Each member can be
+// reminded this way once a day per group, whoever presses the button.
+func (s *ReminderService) RemindMember(ctx context.Context, groupID string, requester *models.User, targetID string) (*models.ReminderDelivery, error) {
+	group, err := s.memberGroup(ctx, groupID, requester)
+	if err != nil {
+		return nil, err
+	}
+	target, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	var member *models.GroupMember
+	for i := range group.Members {
+		if group.Members[i].UserID == target {
+			member = &group.Members[i]
+		}
+	}
+	if member == nil || target == requester.ID {
+		return nil, ErrNothingOwed
+	}
+	if member.Placeholder {
+		return nil, ErrRemindPlaceholder
+	}
+	if isSnoozed(*member, time.Now()) {
+		return nil, ErrRemindersSnoozed
+	}
+
+	owed, err := s.outstandingDebts(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	amount := owed[target]
+	if amount <= 0 {
+		return nil, ErrNothingOwed
+	}
+
+	today := time.Now().In(reminderTimezone).Format("2006-01-02")
+	delivery := &models.ReminderDelivery{
+		Key:         fmt.Sprintf("manual:%s:%s:%s", group.ID.Hex(), target.Hex(), today),
+		Kind:        models.ReminderKindManual,
+		GroupID:     group.ID,
+		UserID:      target,
+		RequestedBy: requester.ID,
+		Amount:      amount,
+		Level:       1,
+	}
+	if err := s.deliver(ctx, group, delivery, requester.DisplayName); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyRemindedToday
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// GetSettings returns a group's reminder schedule, which is off until an admin sets one
+func (s *ReminderService) GetSettings(ctx context.Context, groupID string, user *models.User) (*models.ReminderSettings, error) {
+	group, err := s.memberGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+	if group.Reminders == nil {
+		return &models.ReminderSettings{Schedule: models.ReminderScheduleOff, Hour: defaultReminderHour}, nil
+	}
+	return group.Reminders, nil
+}
+
+// UpdateSettings changes a group's reminder schedule. Only admins can change it.
+func (s *ReminderService) UpdateSettings(ctx context.Context, groupID string, user *models.User, req models.UpdateReminderSettingsRequest) (*models.ReminderSettings, error) {
+	group, err := s.memberGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+	if !isGroupAdmin(group, user.ID) {
+		return nil, ErrNotGroupAdmin
+	}
+	if req.Schedule == models.ReminderScheduleAfterBill && req.DaysAfterBill < 1 {
+		return nil, errors.New("days_after_bill must be at least 1 for the after_bill schedule")
+	}
+
+	settings := &models.ReminderSettings{
+		Schedule:      req.Schedule,
+		Weekday:       time.Weekday(req.Weekday),
+		Hour:          defaultReminderHour,
+		DaysAfterBill: req.DaysAfterBill,
+		MinAmount:     req.MinAmount,
+	}
+	if req.Hour != nil {
+		settings.Hour = *req.Hour
+	}
+
+	if err := s.groupRepo.UpdateReminders(ctx, group.ID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Snooze pauses the user's reminders in a group for a number of days; 0 resumes them.
+// It returns when reminders resume, or nil if they are not snoozed.
+func (s *ReminderService) Snooze(ctx context.Context, groupID string, user *models.User, days int) (*time.Time, error) {
+	group, err := s.memberGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var until *time.Time
+	if days > 0 {
+		t := time.Now().AddDate(0, 0, days)
+		until = &t
+	}
+	if err := s.groupRepo.SnoozeReminders(ctx, group.ID, user.ID, until); err != nil {
+		return nil, err
+	}
+	return until, nil
+}
+
+// deliver claims the reminder slot in the delivery log, then sends the
+// notification. If nothing was sent the slot is given up again, so the next
+// run or press of the button can retry.
+func (s *ReminderService) deliver(ctx context.Context, group *models.Group, delivery *models.ReminderDelivery, requestedBy string) error {
+	if err := s.reminderRepo.CreateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	err := s.notifService.NotifySettlementReminder(ctx, SettlementReminder{
+		UserID:      delivery.UserID,
+		GroupID:     group.ID,
+		GroupName:   group.Name,
+		Amount:      delivery.Amount,
+		Level:       delivery.Level,
+		RequestedBy: requestedBy,
+	})
+	if errors.Is(err, ErrNotificationNotSent) {
+		if releaseErr := s.reminderRepo.DeleteDelivery(ctx, delivery.ID); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+	}
+	return err
+}
+
+// outstandingDebts returns what each member owes in the group, less the
+// payments they have already sent that are waiting to be confirmed
+func (s *ReminderService) outstandingDebts(ctx context.Context, group *models.Group) (map[primitive.ObjectID]float64, error) {
+	balances, err := s.debtService.GetGroupBalances(ctx, group.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.transactionRepo.FindPendingByGroupID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	owed := make(map[primitive.ObjectID]float64)
+	for _, balance := range balances {
+		if balance.Balance >= 0 {
+			continue
+		}
+		userID, err := primitive.ObjectIDFromHex(balance.UserID)
+		if err != nil {
+			continue
+		}
+		owed[userID] = -balance.Balance
+	}
+	for _, tx := range pending {
+		if _, ok := owed[tx.FromUser]; ok {
+			owed[tx.FromUser] -= tx.Amount
+		}
+	}
+	for userID, amount := range owed {
+		if amount < 0.01 {
+			delete(owed, userID)
+		}
+	}
+	return owed, nil
+}
+
+// memberGroup loads a group the user belongs to
+func (s *ReminderService) memberGroup(ctx context.Context, groupID string, user *models.User) (*models.Group, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, errors.New("invalid group ID")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if !isGroupMember(group, user.ID) {
+		return nil, ErrNotGroupMember
+	}
+	return group, nil
+}
+
+// isGroupAdmin reports whether userID may manage the group's settings,
+// which owners and admins can
+func isGroupAdmin(group *models.Group, userID primitive.ObjectID) bool {
+	member := group.Member(userID)
+	return member != nil && member.Role.Can(models.PermManageGroup)
+}
+
+// isSnoozed reports whether the member has paused reminders at now
+func isSnoozed(member models.GroupMember, now time.Time) bool {
+	return member.RemindersSnoozedUntil != nil && member.RemindersSnoozedUntil.After(now)
+}
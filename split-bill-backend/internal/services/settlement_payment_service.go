@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNoDebtToMember = errors.New("you do not owe this member anything in this group")
+
+// SettlementPayment is the settle-up item the caller pays, with the member being paid
+type SettlementPayment struct {
+	Group      *models.Group
+	Settlement models.Settlement
+	Payee      *models.User
+}
+
+type SettlementPaymentService struct {
+	debtService      *DebtService
+	groupRepo        *repository.GroupRepository
+	userRepo         *repository.UserRepository
+	referenceService *PaymentReferenceService
+}
+
+func NewSettlementPaymentService(
+	debtService *DebtService,
+	groupRepo *repository.GroupRepository,
+	userRepo *repository.UserRepository,
+	referenceService *PaymentReferenceService,
+) *SettlementPaymentService {
+	return &SettlementPaymentService{
+		debtService:      debtService,
+		groupRepo:        groupRepo,
+		userRepo:         userRepo,
+		referenceService: referenceService,
+	}
+}
+
+// PrepareSettlementPayment finds what the user owes toUserID in the group's
+// settle-up plan and loads the payee. The payee's bank details are only
+// returned to members the plan tells to pay them.
+func (s *SettlementPaymentService) PrepareSettlementPayment(ctx context.Context, groupID string, user *models.User, toUserID string) (*SettlementPayment, error) {
+	groupObjID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, errors.New("invalid group ID")
+	}
+	payeeID, err := primitive.ObjectIDFromHex(toUserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, groupObjID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if !isGroupMember(group, user.ID) {
+		return nil, ErrNotGroupMember
+	}
+	if payeeID == user.ID || !isGroupMember(group, payeeID) {
+		return nil, ErrNoDebtToMember
+	}
+
+	settlements, err := s.debtService.GetOptimalSettlements(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var owed []models.Settlement
+	for _, settlement := range settlements {
+		if settlement.FromUserID == user.ID.Hex() && settlement.ToUserID == payeeID.Hex() {
+			owed = append(owed, settlement)
+			break
+		}
+	}
+	if len(owed) == 0 {
+		return nil, ErrNoDebtToMember
+	}
+
+	// Same code as the settle-up list shows, so either screen can be used to pay
+	if err := s.referenceService.AttachToSettlements(ctx, groupID, owed); err != nil {
+		return nil, err
+	}
+
+	payee, err := s.userRepo.FindByID(ctx, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SettlementPayment{
+		Group:      group,
+		Settlement: owed[0],
+		Payee:      payee,
+	}, nil
+}
+
+// isGroupMember reports whether userID is in the group's member list
+func isGroupMember(group *models.Group, userID primitive.ObjectID) bool {
+	for _, member := range group.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
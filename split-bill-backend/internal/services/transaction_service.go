@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrTransactionNotPending = errors.New("transaction is no longer pending")
+	ErrTransactionForbidden  = errors.New("you are not allowed to change this transaction")
+	ErrTransactionReversed   = errors.New("transaction already has a reversal")
+	ErrNotGroupMember        = errors.New("you are not a member of this group")
+
+	ErrInvalidToUserID      = errors.New("invalid to_user ID")
+	ErrInvalidGroupID       = errors.New("invalid group ID")
+	ErrInvalidTransactionID = errors.New("invalid transaction ID")
+	ErrReverseReversal      = errors.New("a reversal cannot be reversed")
+	ErrReverseNotConfirmed  = errors.New("only confirmed transactions can be reversed; cancel pending ones instead")
+)
+
+type TransactionService struct {
+	transactionRepo  *repository.TransactionRepository
+	groupRepo        *repository.GroupRepository
+	userRepo         *repository.UserRepository
+	referenceService *PaymentReferenceService
+	bus              *events.Bus
+}
+
+func NewTransactionService(
+	transactionRepo *repository.TransactionRepository,
+	groupRepo *repository.GroupRepository,
+	userRepo *repository.UserRepository,
+	referenceService *PaymentReferenceService,
+	bus *events.Bus,
+) *TransactionService {
+	return &TransactionService{
+		transactionRepo:  transactionRepo,
+		groupRepo:        groupRepo,
+		userRepo:         userRepo,
+		referenceService: referenceService,
+		bus:              bus,
+	}
+}
+
+// CreateTransaction records a pending payment from the current user
+func (s *TransactionService) CreateTransaction(ctx context.Context, fromUser *models.User, req models.CreateTransactionRequest) (*models.Transaction, error) {
+	toUserID, err := primitive.ObjectIDFromHex(req.ToUser)
+	if err != nil {
+		return nil, ErrInvalidToUserID
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(req.GroupID)
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if err := requireGroupPermission(group, fromUser.ID, models.PermRecordExpenses); err != nil {
+		return nil, err
+	}
+
+	tx := &models.Transaction{
+		ID:              primitive.NewObjectID(),
+		GroupID:         groupID,
+		FromUser:        fromUser.ID,
+		ToUser:          toUserID,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		Type:            models.TransactionSettlement,
+		Status:          models.TransactionPending,
+		PaymentMethod:   req.PaymentMethod,
+		PaymentProofURL: req.PaymentProofURL,
+		Note:            req.Note,
+	}
+
+	if req.BillID != "" {
+		billID, err := primitive.ObjectIDFromHex(req.BillID)
+		if err == nil {
+			tx.BillID = billID
+		}
+	}
+
+	tx.Reference, err = s.referenceService.ReserveForTransaction(ctx, tx, req.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		_ = s.referenceService.Release(ctx, tx)
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.TransactionCreated{Transaction: *tx, Actor: events.ActorOf(fromUser)})
+
+	return tx, nil
+}
+
+// GetTransaction gets a transaction by ID
+func (s *TransactionService) GetTransaction(ctx context.Context, txID string) (*models.Transaction, error) {
+	objID, err := primitive.ObjectIDFromHex(txID)
+	if err != nil {
+		return nil, ErrInvalidTransactionID
+	}
+
+	tx, err := s.transactionRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ListGroupTransactions returns one page of a group's transactions for a member of that group
+func (s *TransactionService) ListGroupTransactions(ctx context.Context, groupID string, user *models.User, filter repository.TransactionFilter, pagination *utils.Pagination) ([]models.TransactionResponse, error) {
+	groupObjID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, ErrInvalidGroupID
+	}
+
+	isMember, err := s.groupRepo.IsMember(ctx, groupObjID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, ErrNotGroupMember
+	}
+
+	filter.GroupID = groupObjID
+	return s.listTransactions(ctx, filter, pagination)
+}
+
+// ListUserTransactions returns one page of transactions the user sent or received
+func (s *TransactionService) ListUserTransactions(ctx context.Context, user *models.User, filter repository.TransactionFilter, pagination *utils.Pagination) ([]models.TransactionResponse, error) {
+	filter.MemberID = user.ID
+	return s.listTransactions(ctx, filter, pagination)
+}
+
+func (s *TransactionService) listTransactions(ctx context.Context, filter repository.TransactionFilter, pagination *utils.Pagination) ([]models.TransactionResponse, error) {
+	transactions, total, err := s.transactionRepo.FindPage(ctx, filter, pagination.Skip(), int64(pagination.Limit))
+	if err != nil {
+		return nil, err
+	}
+	pagination.SetTotal(total)
+
+	return s.toResponses(ctx, transactions), nil
+}
+
+// toResponses converts transactions to API responses with the sender and recipient names filled in
+func (s *TransactionService) toResponses(ctx context.Context, transactions []models.Transaction) []models.TransactionResponse {
+	userIDs := make([]primitive.ObjectID, 0, len(transactions)*2)
+	seen := make(map[primitive.ObjectID]bool)
+	for _, tx := range transactions {
+		for _, id := range []primitive.ObjectID{tx.FromUser, tx.ToUser} {
+			if !seen[id] {
+				seen[id] = true
+				userIDs = append(userIDs, id)
+			}
+		}
+	}
+
+	nameMap := make(map[primitive.ObjectID]string)
+	if len(userIDs) > 0 {
+		users, err := s.userRepo.FindByIDs(ctx, userIDs)
+		if err == nil {
+			for _, u := range users {
+				nameMap[u.ID] = u.DisplayName
+			}
+		}
+	}
+
+	responses := make([]models.TransactionResponse, len(transactions))
+	for i, tx := range transactions {
+		responses[i] = tx.ToResponse()
+		responses[i].FromUserName = nameMap[tx.FromUser]
+		responses[i].ToUserName = nameMap[tx.ToUser]
+	}
+	return responses
+}
+
+// UpdateTransaction lets the sender amend a transaction while it is still pending.
+// The previous values are kept in the transaction history.
+func (s *TransactionService) UpdateTransaction(ctx context.Context, txID string, user *models.User, req models.UpdateTransactionRequest) (*models.Transaction, error) {
+	tx, err := s.pendingFromSender(ctx, txID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := bson.M{}
+	if req.Amount != nil && *req.Amount != tx.Amount {
+		changes["amount"] = *req.Amount
+	}
+	if req.Currency != "" && req.Currency != tx.Currency {
+		changes["currency"] = req.Currency
+	}
+	if req.PaymentMethod != nil && *req.PaymentMethod != tx.PaymentMethod {
+		changes["payment_method"] = *req.PaymentMethod
+	}
+	if req.PaymentProofURL != nil && *req.PaymentProofURL != tx.PaymentProofURL {
+		changes["payment_proof_url"] = *req.PaymentProofURL
+	}
+	if req.Note != nil && *req.Note != tx.Note {
+		changes["note"] = *req.Note
+	}
+
+	if len(changes) == 0 {
+		return tx, nil
+	}
+
+	revision := tx.Snapshot(models.RevisionEdited, user.ID, strings.TrimSpace(req.Reason))
+	if err := s.transactionRepo.Amend(ctx, tx.ID, changes, revision); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTransactionNotPending
+		}
+		return nil, err
+	}
+
+	updated, err := s.transactionRepo.FindByID(ctx, tx.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, events.TransactionUpdated{Transaction: *updated, Previous: *tx, Actor: events.ActorOf(user)})
+	return updated, nil
+}
+
+// CancelTransaction lets the sender withdraw a transaction while it is still pending
+func (s *TransactionService) CancelTransaction(ctx context.Context, txID string, user *models.User, reason string) (*models.Transaction, error) {
+	tx, err := s.pendingFromSender(ctx, txID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := tx.Snapshot(models.RevisionCancelled, user.ID, strings.TrimSpace(reason))
+	if err := s.transactionRepo.Cancel(ctx, tx.ID, revision); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTransactionNotPending
+		}
+		return nil, err
+	}
+
+	cancelled, err := s.transactionRepo.FindByID(ctx, tx.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, events.TransactionCancelled{Transaction: *cancelled, Actor: events.ActorOf(user)})
+	return cancelled, nil
+}
+
+// ReverseTransaction asks to undo a confirmed transaction. It creates a pending
+// reversal that moves the amount back and has to be accepted by the counterparty.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, txID string, user *models.User, reason string) (*models.Transaction, error) {
+	original, err := s.GetTransaction(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.FromUser != user.ID {
+		return nil, ErrTransactionForbidden
+	}
+	if original.Type == models.TransactionReversal {
+		return nil, ErrReverseReversal
+	}
+	if original.Status != models.TransactionConfirmed {
+		return nil, ErrReverseNotConfirmed
+	}
+
+	if !original.ReversedBy.IsZero() {
+		return nil, ErrTransactionReversed
+	}
+	if _, err := s.transactionRepo.FindActiveReversal(ctx, original.ID); err == nil {
+		return nil, ErrTransactionReversed
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	reversal := &models.Transaction{
+		ID:         primitive.NewObjectID(),
+		GroupID:    original.GroupID,
+		FromUser:   original.ToUser,
+		ToUser:     original.FromUser,
+		Amount:     original.Amount,
+		Currency:   original.Currency,
+		BillID:     original.BillID,
+		Type:       models.TransactionReversal,
+		Status:     models.TransactionPending,
+		Note:       strings.TrimSpace(reason),
+		ReversalOf: original.ID,
+	}
+
+	reversal.Reference, err = s.referenceService.ReserveForTransaction(ctx, reversal, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// The unique index on active reversals stops a concurrent request
+	// that got past the check above
+	if err := s.transactionRepo.Create(ctx, reversal); err != nil {
+		_ = s.referenceService.Release(ctx, reversal)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrTransactionReversed
+		}
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.TransactionCreated{Transaction: *reversal, Actor: events.ActorOf(user)})
+
+	return reversal, nil
+}
+
+// ConfirmTransaction accepts a pending transaction. Only the approver can confirm:
+// the recipient for payments, the original recipient for reversals.
+func (s *TransactionService) ConfirmTransaction(ctx context.Context, txID string, user *models.User) (*models.Transaction, error) {
+	tx, err := s.pendingForApprover(ctx, txID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactionRepo.Confirm(ctx, tx.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTransactionNotPending
+		}
+		return nil, err
+	}
+
+	if tx.Type == models.TransactionReversal && !tx.ReversalOf.IsZero() {
+		if err := s.transactionRepo.MarkReversed(ctx, tx.ReversalOf, tx.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	confirmed, err := s.transactionRepo.FindByID(ctx, tx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.bus.Publish(ctx, events.TransactionConfirmed{Transaction: *confirmed, Actor: events.ActorOf(user)})
+
+	return confirmed, nil
+}
+
+// RejectTransaction declines a pending transaction. Only the approver can reject.
+func (s *TransactionService) RejectTransaction(ctx context.Context, txID string, user *models.User) (*models.Transaction, error) {
+	tx, err := s.pendingForApprover(ctx, txID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactionRepo.Reject(ctx, tx.ID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrTransactionNotPending
+		}
+		return nil, err
+	}
+
+	rejected, err := s.transactionRepo.FindByID(ctx, tx.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Publish(ctx, events.TransactionRejected{Transaction: *rejected, Actor: events.ActorOf(user)})
+	return rejected, nil
+}
+
+// pendingFromSender loads a transaction the user sent and that can still be changed
+func (s *TransactionService) pendingFromSender(ctx context.Context, txID string, user *models.User) (*models.Transaction, error) {
+	tx, err := s.GetTransaction(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reversals are requested by the original sender, who is their recipient
+	sender := tx.FromUser
+	if tx.Type == models.TransactionReversal {
+		sender = tx.ToUser
+	}
+	if sender != user.ID {
+		return nil, ErrTransactionForbidden
+	}
+	if tx.Status != models.TransactionPending {
+		return nil, ErrTransactionNotPending
+	}
+	return tx, nil
+}
+
+// pendingForApprover loads a pending transaction the user is allowed to accept or decline
+func (s *TransactionService) pendingForApprover(ctx context.Context, txID string, user *models.User) (*models.Transaction, error) {
+	tx, err := s.GetTransaction(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Approver() != user.ID {
+		return nil, ErrTransactionForbidden
+	}
+	if tx.Status != models.TransactionPending {
+		return nil, ErrTransactionNotPending
+	}
+	return tx, nil
+}
@@ -0,0 +1,571 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/splitbill/backend/internal/events"
+	"github.com/splitbill/backend/internal/models"
+	"github.com/splitbill/backend/internal/repository"
+	"github.com/splitbill/backend/internal/utils"
+	"github.com/splitbill/backend/pkg/safehttp"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Headers sent with every webhook delivery. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+const (
+	WebhookEventHeader     = "X-SplitBill-Event"
+	WebhookDeliveryHeader  = "X-SplitBill-Delivery"
+	WebhookTimestampHeader = "X-SplitBill-Timestamp"
+	WebhookSignatureHeader = "X-SplitBill-Signature"
+)
+
+const (
+	// webhookPingEvent is the event of test deliveries
+	webhookPingEvent = "ping"
+
+	// webhookRetryAfter is the wait before the first retry; each later
+	// retry waits twice as long, up to webhookMaxBackoff
+	webhookRetryAfter = 30 * time.Second
+	webhookMaxBackoff = 6 * time.Hour
+
+	// webhookLease is how long a claimed delivery is left alone by other
+	// instances before it counts as abandoned and is tried again
+	webhookLease = 2 * time.Minute
+
+	// webhookMaxPerGroup limits how many webhooks a group can register
+	webhookMaxPerGroup = 10
+
+	webhookResponseLimit = 1024
+)
+
+var (
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrWebhookAdminOnly     = errors.New("only group owners and admins can manage webhooks")
+	ErrWebhookLimit         = errors.New("this group already has the maximum number of webhooks")
+	ErrInvalidWebhookURL    = errors.New("webhook URL must be an http or https URL of a public host")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryStillPending = errors.New("this delivery is still being retried")
+)
+
+// WebhookOptions tunes webhook deliveries
+type WebhookOptions struct {
+	Timeout      time.Duration // per attempt
+	MaxAttempts  int           // attempts before a delivery is dead-lettered
+	AllowPrivate bool          // let webhooks reach private and loopback addresses, for local development
+}
+
+// WebhookService lets group admins send the group's events to their own
+// tools. Every matching event becomes a delivery in the log, which is
+// retried with exponential backoff until the endpoint accepts it or the
+// attempts run out.
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	groupRepo   *repository.GroupRepository
+	client      *http.Client
+	opts        WebhookOptions
+	logger      *zap.Logger
+}
+
+func NewWebhookService(
+	webhookRepo *repository.WebhookRepository,
+	groupRepo *repository.GroupRepository,
+	opts WebhookOptions,
+	logger *zap.Logger,
+) *WebhookService {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		groupRepo:   groupRepo,
+		client:      safehttp.NewClient(opts.Timeout, opts.AllowPrivate),
+		opts:        opts,
+		logger:      logger,
+	}
+}
+
+// RegisterEventHandlers queues a delivery for every webhook of the group
+// that wants the event, and makes the first attempt
+func (s *WebhookService) RegisterEventHandlers(bus *events.Bus) {
+	bus.SubscribeAll("webhooks", true, func(ctx context.Context, event events.Event) error {
+		webhooks, err := s.webhookRepo.FindActiveByGroupID(ctx, event.Group())
+		if err != nil {
+			return err
+		}
+
+		for i := range webhooks {
+			webhook := &webhooks[i]
+			if !webhook.Wants(string(event.Name())) {
+				continue
+			}
+			delivery, err := s.queue(ctx, webhook, string(event.Name()), event, false)
+			if err != nil {
+				s.logger.Warn("Failed to queue webhook delivery",
+					zap.String("webhook_id", webhook.ID.Hex()),
+					zap.String("event", string(event.Name())),
+					zap.Error(err),
+				)
+				continue
+			}
+			s.attempt(ctx, webhook, delivery)
+		}
+		return nil
+	})
+}
+
+// queue stores a delivery, already claimed by the caller for its first attempt
+func (s *WebhookService) queue(ctx context.Context, webhook *models.GroupWebhook, event string, data interface{}, test bool) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		WebhookID: webhook.ID,
+		GroupID:   webhook.GroupID,
+		Event:     event,
+		Test:      test,
+		Status:    models.WebhookDeliveryPending,
+	}
+
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        delivery.ID.Hex(),
+		Event:     event,
+		GroupID:   webhook.GroupID.Hex(),
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = string(payload)
+
+	claimedUntil := time.Now().Add(webhookLease)
+	delivery.NextAttemptAt = &claimedUntil
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// attempt sends a delivery once and records the outcome: delivered,
+// pending with the time of the next attempt, or failed for good
+func (s *WebhookService) attempt(ctx context.Context, webhook *models.GroupWebhook, delivery *models.WebhookDelivery) {
+	started := time.Now()
+	code, body, err := s.send(ctx, webhook, delivery)
+	now := time.Now()
+
+	delivery.Attempts++
+	delivery.DurationMs = now.Sub(started).Milliseconds()
+	delivery.ResponseCode = code
+	delivery.ResponseBody = body
+	delivery.LastError = ""
+	delivery.NextAttemptAt = nil
+
+	maxAttempts := s.opts.MaxAttempts
+	if delivery.Test {
+		maxAttempts = 1 // the admin is watching; retrying would only confuse
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(webhookBackoff(delivery.Attempts))
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	// Record the outcome even if the event handler's time is up
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.webhookRepo.SaveAttempt(saveCtx, delivery); err != nil {
+		s.logger.Warn("Failed to record webhook attempt",
+			zap.String("delivery_id", delivery.ID.Hex()),
+			zap.Error(err),
+		)
+	}
+}
+
+// send posts a delivery's payload with its signature and returns the
+// response status and the start of the response body
+func (s *WebhookService) send(ctx context.Context, webhook *models.GroupWebhook, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SplitBill-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The URL may carry a token of the receiver's, so keep it out of the log
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return 0, "", fmt.Errorf("request failed: %w", urlErr.Err)
+		}
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(snippet), fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, string(snippet), nil
+}
+
+// SignWebhook computes the signature header of a delivery, so receivers
+// can check it the same way
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff is the wait after a delivery failed attempts times
+func webhookBackoff(attempts int) time.Duration {
+	wait := webhookRetryAfter
+	for i := 1; i < attempts && wait < webhookMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > webhookMaxBackoff {
+		wait = webhookMaxBackoff
+	}
+	return wait
+}
+
+// RetryDue makes the next attempt of every delivery that is due at now
+func (s *WebhookService) RetryDue(ctx context.Context, now time.Time) error {
+	for {
+		delivery, err := s.webhookRepo.ClaimDue(ctx, now, webhookLease)
+		if err != nil || delivery == nil {
+			return err
+		}
+
+		webhook, err := s.webhookRepo.FindByID(ctx, delivery.GroupID, delivery.WebhookID)
+		if err == nil && !webhook.Active {
+			err = errors.New("webhook is disabled")
+		}
+		if err != nil {
+			delivery.Status = models.WebhookDeliveryFailed
+			delivery.LastError = err.Error()
+			delivery.NextAttemptAt = nil
+			if saveErr := s.webhookRepo.SaveAttempt(ctx, delivery); saveErr != nil {
+				return saveErr
+			}
+			continue
+		}
+
+		s.attempt(ctx, webhook, delivery)
+	}
+}
+
+// Start retries due deliveries every interval until ctx is cancelled
+func (s *WebhookService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RetryDue(ctx, time.Now()); err != nil {
+			s.logger.Warn("Webhook retry run failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListWebhooks returns the group's webhooks
+func (s *WebhookService) ListWebhooks(ctx context.Context, groupID string, user *models.User) ([]models.GroupWebhook, error) {
+	group, err := s.adminGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindByGroupID(ctx, group.ID)
+}
+
+// CreateWebhook registers a webhook. The response is the only time its
+// signing secret is shown.
+func (s *WebhookService) CreateWebhook(ctx context.Context, groupID string, user *models.User, req models.CreateWebhookRequest) (*models.GroupWebhookWithSecret, error) {
+	group, err := s.adminGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	filter, err := validateWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhookRepo.FindByGroupID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= webhookMaxPerGroup {
+		return nil, ErrWebhookLimit
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.GroupWebhook{
+		GroupID:     group.ID,
+		URL:         req.URL,
+		Description: strings.TrimSpace(req.Description),
+		Events:      filter,
+		Secret:      secret,
+		Active:      true,
+		CreatedBy:   user.ID,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &models.GroupWebhookWithSecret{GroupWebhook: *webhook, Secret: secret}, nil
+}
+
+// UpdateWebhook changes a webhook. The new secret is returned when it was rotated.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, groupID, webhookID string, user *models.User, req models.UpdateWebhookRequest) (*models.GroupWebhookWithSecret, error) {
+	webhook, err := s.adminWebhook(ctx, groupID, webhookID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := s.validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Description != nil {
+		webhook.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Events != nil {
+		filter, err := validateWebhookEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = filter
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	result := &models.GroupWebhookWithSecret{}
+	if req.RotateSecret {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+		result.Secret = secret
+	}
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	result.GroupWebhook = *webhook
+	return result, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, groupID, webhookID string, user *models.User) error {
+	webhook, err := s.adminWebhook(ctx, groupID, webhookID, user)
+	if err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, webhook.GroupID, webhook.ID)
+}
+
+// TestWebhook sends a ping event to the webhook right away and returns the
+// delivery with the endpoint's response. Test deliveries are not retried.
+func (s *WebhookService) TestWebhook(ctx context.Context, groupID, webhookID string, user *models.User) (*models.WebhookDelivery, error) {
+	webhook, err := s.adminWebhook(ctx, groupID, webhookID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.queue(ctx, webhook, webhookPingEvent, map[string]string{
+		"webhook_id":   webhook.ID.Hex(),
+		"requested_by": user.ID.Hex(),
+		"message":      "Test delivery from Split Bill",
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	s.attempt(ctx, webhook, delivery)
+	return delivery, nil
+}
+
+// ListDeliveries returns a page of the webhook's delivery log, newest
+// first. Filter by status failed to see the dead letters.
+func (s *WebhookService) ListDeliveries(ctx context.Context, groupID, webhookID string, user *models.User, status models.WebhookDeliveryStatus, page utils.CursorPage) ([]models.WebhookDelivery, *utils.Cursor, error) {
+	webhook, err := s.adminWebhook(ctx, groupID, webhookID, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.webhookRepo.FindDeliveries(ctx, webhook.ID, status, page)
+}
+
+// Redeliver sends a finished delivery again, with the same ID and payload,
+// and gives it a fresh set of attempts. It is meant for dead letters once
+// the endpoint is fixed.
+func (s *WebhookService) Redeliver(ctx context.Context, groupID, webhookID, deliveryID string, user *models.User) (*models.WebhookDelivery, error) {
+	webhook, err := s.adminWebhook(ctx, groupID, webhookID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	delivery, err := s.webhookRepo.FindDelivery(ctx, webhook.ID, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		return nil, ErrDeliveryStillPending
+	}
+
+	delivery.Attempts = 0
+	delivery.DeliveredAt = nil
+	s.attempt(ctx, webhook, delivery)
+	return delivery, nil
+}
+
+// adminGroup loads a group the user administers
+func (s *WebhookService) adminGroup(ctx context.Context, groupID string, user *models.User) (*models.Group, error) {
+	objID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return nil, errors.New("invalid group ID")
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, objID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if !isGroupMember(group, user.ID) {
+		return nil, ErrNotGroupMember
+	}
+	if !isGroupAdmin(group, user.ID) {
+		return nil, ErrWebhookAdminOnly
+	}
+	return group, nil
+}
+
+// adminWebhook loads a webhook of a group the user administers
+func (s *WebhookService) adminWebhook(ctx context.Context, groupID, webhookID string, user *models.User) (*models.GroupWebhook, error) {
+	group, err := s.adminGroup(ctx, groupID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	webhook, err := s.webhookRepo.FindByID(ctx, group.ID, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// validateURL accepts absolute http(s) URLs, and unless private addresses
+// are allowed, not ones naming localhost or a private IP
+func (s *WebhookService) validateURL(raw string) error {
+	u, err := neturl.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+	if s.opts.AllowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidWebhookURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !safehttp.IsPublicIP(ip) {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// validateWebhookEvents checks an event filter and drops duplicates.
+// An empty filter means every event.
+func validateWebhookEvents(names []string) ([]string, error) {
+	known := map[string]bool{}
+	for _, name := range events.Names() {
+		known[string(name)] = true
+	}
+
+	filter := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			valid := make([]string, 0, len(known))
+			for _, n := range events.Names() {
+				valid = append(valid, string(n))
+			}
+			return nil, fmt.Errorf("unknown event %q, expected one of: %s", name, strings.Join(valid, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			filter = append(filter, name)
+		}
+	}
+	return filter, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
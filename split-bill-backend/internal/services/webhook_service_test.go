@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// Same as: printf '%s' '1700000000.{"event":"ping"}' | openssl dgst -sha256 -hmac whsec_test
+	const want = "sha256=aa8efe37b751e71157c508c5ac4acb1e9fe5225db98355dfc00f4b680afbc447"
+	if got := SignWebhook("whsec_test", "1700000000", []byte(`{"event":"ping"}`)); got != want {
+		t.Errorf("SignWebhook = %s, want %s", got, want)
+	}
+	if SignWebhook("whsec_test", "1700000001", []byte(`{"event":"ping"}`)) == want {
+		t.Error("signature does not cover the timestamp")
+	}
+	if SignWebhook("whsec_other", "1700000000", []byte(`{"event":"ping"}`)) == want {
+		t.Error("signature does not depend on the secret")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{1000, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{"https://hooks.example.com/splitbill", false, false},
+		{"http://203.113.131.1:8080/hook", false, false},
+		{"ftp://hooks.example.com/", false, true},
+		{"hooks.example.com/splitbill", false, true},
+		{"https:///splitbill", false, true},
+		{"https://localhost/hook", false, true},
+		{"https://api.localhost/hook", false, true},
+		{"http://127.0.0.1:3000/hook", false, true},
+		{"http://[::1]/hook", false, true},
+		{"http://10.0.0.5/hook", false, true},
+		{"http://100.64.0.1/hook", false, true},
+		{"http://0.0.0.0:8080/hook", false, true},
+		{"http://169.254.169.254/latest/meta-data", false, true},
+		{"http://localhost:3000/hook", true, false},
+		{"http://10.0.0.5/hook", true, false},
+		{"ftp://localhost/", true, true},
+	}
+	for _, tt := range tests {
+		s := &WebhookService{opts: WebhookOptions{AllowPrivate: tt.allowPrivate}}
+		err := s.validateURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateURL(%q) allowPrivate=%v: err = %v, want error %v", tt.url, tt.allowPrivate, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("validateURL(%q): err = %v, want ErrInvalidWebhookURL", tt.url, err)
+		}
+	}
+}
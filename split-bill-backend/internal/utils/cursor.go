@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned for a cursor this server did not hand out
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list sorted newest first by (created_at, _id).
+// Items added after the first page was read sort before the cursor, so
+// paging on never repeats or skips items.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
+}
+
+// CursorPage holds cursor pagination parameters
+type CursorPage struct {
+	After *Cursor // nil for the first page
+	Limit int
+}
+
+// NewCursor returns the cursor that continues after an item
+func NewCursor(createdAt time.Time, id primitive.ObjectID) *Cursor {
+	return &Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// Encode returns the opaque form sent to clients
+func (c *Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixMilli(), 10) + ":" + c.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	millis, hex, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: time.UnixMilli(ms).UTC(), ID: id}, nil
+}
+
+// ParseCursorPage extracts the cursor and limit query parameters
+func ParseCursorPage(c *gin.Context) (CursorPage, error) {
+	page := CursorPage{Limit: DefaultLimit}
+
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			page.Limit = parsed
+			if page.Limit > MaxLimit {
+				page.Limit = MaxLimit
+			}
+		}
+	}
+
+	if s := c.Query("cursor"); s != "" {
+		cursor, err := DecodeCursor(s)
+		if err != nil {
+			return page, err
+		}
+		page.After = cursor
+	}
+
+	return page, nil
+}
+
+// CursorResponse wraps a page of items with the cursor of the next page
+type CursorResponse struct {
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"` // empty on the last page
+	HasMore    bool        `json:"has_more"`
+}
+
+// RespondCursor sends a cursor paginated JSON response
+func RespondCursor(c *gin.Context, statusCode int, message string, data interface{}, next *Cursor) {
+	response := CursorResponse{Data: data}
+	if next != nil {
+		response.NextCursor = next.Encode()
+		response.HasMore = true
+	}
+	RespondSuccess(c, statusCode, message, response)
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+const (
+	paymentReferencePrefix = "SB"
+	paymentReferenceLength = 6 // random characters after the prefix; 32^6 codes
+)
+
+// paymentReferencePattern finds references in free text such as transfer descriptions
+var paymentReferencePattern = regexp.MustCompile(`SB[ABCDEFGHJKLMNPQRSTUVWXYZ23456789]{6}`)
+
+// GeneratePaymentReference generates a short payment reference such as "SB7K2Q9M".
+// It uses the invite code alphabet so it survives being read aloud or retyped.
+func GeneratePaymentReference() string {
+	var sb strings.Builder
+	sb.WriteString(paymentReferencePrefix)
+	for i := 0; i < paymentReferenceLength; i++ {
+		idx, _ := rand.Int(rand.Reader, big.NewInt(int64(len(inviteCodeChars))))
+		sb.WriteByte(inviteCodeChars[idx.Int64()])
+	}
+	return sb.String()
+}
+
+// NormalizePaymentReference upper-cases a reference and strips surrounding spaces
+func NormalizePaymentReference(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsPaymentReference reports whether code has the shape of a payment reference
+func IsPaymentReference(code string) bool {
+	return len(code) == len(paymentReferencePrefix)+paymentReferenceLength &&
+		paymentReferencePattern.MatchString(code)
+}
+
+// FindPaymentReferences returns the references mentioned in a transfer
+// description or SMS, in order of appearance
+func FindPaymentReferences(text string) []string {
+	return paymentReferencePattern.FindAllString(strings.ToUpper(text), -1)
+}
+
+// WithPaymentReference puts the reference at the start of a transfer note,
+// where banks that truncate long descriptions will keep it
+func WithPaymentReference(note, reference string) string {
+	if reference == "" || strings.Contains(strings.ToUpper(note), reference) {
+		return note
+	}
+	if note == "" {
+		return reference
+	}
+	return reference + " " + note
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneratePaymentReference(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code := GeneratePaymentReference()
+		if !IsPaymentReference(code) {
+			t.Fatalf("GeneratePaymentReference() = %q, not a payment reference", code)
+		}
+		seen[code] = true
+	}
+	if len(seen) < 990 {
+		t.Errorf("1000 codes, only %d distinct", len(seen))
+	}
+}
+
+func TestIsPaymentReference(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"SB7K2Q9M", true},
+		{"SBAAAAAA", true},
+		{"sb7k2q9m", false}, // callers normalize first
+		{"SB7K2Q", false},
+		{"SB7K2Q9MX", false},
+		{"XB7K2Q9M", false},
+		{"SB7K2Q0M", false}, // 0 is not in the alphabet
+		{"SB7K2Q1M", false}, // nor 1
+		{"SB7KIO9M", false}, // nor I and O
+		{" SB7K2Q9M", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsPaymentReference(tt.code); got != tt.want {
+			t.Errorf("IsPaymentReference(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePaymentReference(t *testing.T) {
+	if got := NormalizePaymentReference("  sb7k2q9m\n"); got != "SB7K2Q9M" {
+		t.Errorf("NormalizePaymentReference = %q", got)
+	}
+}
+
+func TestFindPaymentReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"at the start", "SB7K2Q9M tra tien an trua", []string{"SB7K2Q9M"}},
+		{"lower case", "chuyen tien sb7k2q9m", []string{"SB7K2Q9M"}},
+		{"run together with the note", "SB7K2Q9MTRATIEN", []string{"SB7K2Q9M"}},
+		{"bank prefix", "MBVCB.3278907687.SB7K2Q9M.tien an", []string{"SB7K2Q9M"}},
+		{"two codes in order", "SB2M4N6P va SB7K2Q9M", []string{"SB2M4N6P", "SB7K2Q9M"}},
+		{"too short", "SB7K2Q tien an", nil},
+		{"none", "tien an toi", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindPaymentReferences(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindPaymentReferences(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPaymentReference(t *testing.T) {
+	tests := []struct {
+		note, reference, want string
+	}{
+		{"tien an trua", "SB7K2Q9M", "SB7K2Q9M tien an trua"},
+		{"", "SB7K2Q9M", "SB7K2Q9M"},
+		{"tien an", "", "tien an"},
+		{"tien an sb7k2q9m", "SB7K2Q9M", "tien an sb7k2q9m"}, // already there
+	}
+	for _, tt := range tests {
+		if got := WithPaymentReference(tt.note, tt.reference); got != tt.want {
+			t.Errorf("WithPaymentReference(%q, %q) = %q, want %q", tt.note, tt.reference, got, tt.want)
+		}
+	}
+}
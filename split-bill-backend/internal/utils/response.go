@@ -1,57 +1,57 @@
-package utils
-
-import (
-	"net/http"
-
-	"github.com/gin-gonic/gin"
-)
-
-// APIResponse is the standard API response format
-type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-}
-
-// RespondSuccess sends a success response
-func RespondSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
-	c.JSON(statusCode, APIResponse{
-		Success: true,
-		Message: message,
-		Data:    data,
-	})
-}
-
-// RespondError sends an error response
-func RespondError(c *gin.Context, statusCode int, message string) {
-	c.JSON(statusCode, APIResponse{
-		Success: false,
-		Error:   message,
-	})
-}
-
-// RespondBadRequest sends a 400 error response
-func RespondBadRequest(c *gin.Context, message string) {
-	RespondError(c, http.StatusBadRequest, message)
-}
-
-// RespondUnauthorized sends a 401 error response
-func RespondUnauthorized(c *gin.Context, message string) {
-	RespondError(c, http.StatusUnauthorized, message)
-}
-
-// RespondForbidden sends a 403 error response
-func RespondForbidden(c *gin.Context, message string) {
-	RespondError(c, http.StatusForbidden, message)
-}
-
-// RespondNotFound sends a 404 error response
-func RespondNotFound(c *gin.Context, message string) {
-	RespondError(c, http.StatusNotFound, message)
-}
-
-// RespondInternalError sends a 500 error response
-func RespondInternalError(c *gin.Context, message string) {
-	RespondError(c, http.StatusInternalServerError, message)
-}
+package utils
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIResponse is the standard API response format
+type APIResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// RespondSuccess sends a success response
+func RespondSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
+	c.JSON(statusCode, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// RespondError sends an error response
+func RespondError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, APIResponse{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// RespondBadRequest sends a 400 error response
+func RespondBadRequest(c *gin.Context, message string) {
+	RespondError(c, http.StatusBadRequest, message)
+}
+
+// RespondUnauthorized sends a 401 error response
+func RespondUnauthorized(c *gin.Context, message string) {
+	RespondError(c, http.StatusUnauthorized, message)
+}
+
+// RespondForbidden sends a 403 error response
+func RespondForbidden(c *gin.Context, message string) {
+	RespondError(c, http.StatusForbidden, message)
+}
+
+// RespondNotFound sends a 404 error response
+func RespondNotFound(c *gin.Context, message string) {
+	RespondError(c, http.StatusNotFound, message)
+}
+
+// RespondInternalError sends a 500 error response
+func RespondInternalError(c *gin.Context, message string) {
+	RespondError(c, http.StatusInternalServerError, message)
+}
@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// StatementEntry is a single line of a bank statement.
+// Amount is positive for money in and negative for money out.
+type StatementEntry struct {
+	Row         int
+	Date        time.Time
+	Amount      float64
+	Description string
+	Reference   string
+	// Balance is the account balance after the line, nil when the export has no balance column
+	Balance *float64
+}
+
+// statementColumn identifies the meaning of a statement CSV column
+type statementColumn int
+
+const (
+	columnUnknown statementColumn = iota
+	columnDate
+	columnCredit
+	columnDebit
+	columnAmount
+	columnDescription
+	columnReference
+	columnBalance
+)
+
+// statementHeaders maps normalized header names used by Vietcombank, Techcombank,
+// MB, ACB, VPBank and similar exports to their column meaning.
+// Text in parentheses is dropped before lookup, so "Ghi có (Credit)" becomes "ghi co".
+var statementHeaders = map[string]statementColumn{
+	"ngay giao dich":        columnDate,
+	"ngay gd":               columnDate,
+	"ngay hach toan":        columnDate,
+	"ngay hieu luc":         columnDate,
+	"ngay thuc hien":        columnDate,
+	"ngay":                  columnDate,
+	"thoi gian":             columnDate,
+	"date":                  columnDate,
+	"transaction date":      columnDate,
+	"posting date":          columnDate,
+	"value date":            columnDate,
+	"so tien ghi co":        columnCredit,
+	"ghi co":                columnCredit,
+	"co":                    columnCredit,
+	"phat sinh co":          columnCredit,
+	"tien vao":              columnCredit,
+	"so tien vao":           columnCredit,
+	"credit":                columnCredit,
+	"credit amount":         columnCredit,
+	"so tien ghi no":        columnDebit,
+	"ghi no":                columnDebit,
+	"no":                    columnDebit,
+	"phat sinh no":          columnDebit,
+	"tien ra":               columnDebit,
+	"so tien ra":            columnDebit,
+	"debit":                 columnDebit,
+	"debit amount":          columnDebit,
+	"so tien":               columnAmount,
+	"so tien giao dich":     columnAmount,
+	"amount":                columnAmount,
+	"mo ta":                 columnDescription,
+	"dien giai":             columnDescription,
+	"noi dung":              columnDescription,
+	"noi dung chi tiet":     columnDescription,
+	"noi dung giao dich":    columnDescription,
+	"chi tiet giao dich":    columnDescription,
+	"description":           columnDescription,
+	"details":               columnDescription,
+	"transaction details":   columnDescription,
+	"remark":                columnDescription,
+	"remarks":               columnDescription,
+	"so tham chieu":         columnReference,
+	"so but toan":           columnReference,
+	"so giao dich":          columnReference,
+	"ma giao dich":          columnReference,
+	"so chung tu":           columnReference,
+	"so ct":                 columnReference,
+	"reference":             columnReference,
+	"reference no":          columnReference,
+	"ref no":                columnReference,
+	"transaction no":        columnReference,
+	"transaction reference": columnReference,
+	"so du":                 columnBalance,
+	"so du cuoi":            columnBalance,
+	"so du sau giao dich":   columnBalance,
+	"balance":               columnBalance,
+	"running balance":       columnBalance,
+}
+
+// statementDateLayouts are the date formats seen in Vietnamese bank exports
+var statementDateLayouts = []string{
+	"02/01/2006",
+	"2/1/2006",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"2/1/2006 15:04:05",
+	"02-01-2006",
+	"02-01-2006 15:04:05",
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"02/01/06",
+}
+
+// statementTimezone is used for statement dates, which banks export in local time
+var statementTimezone = time.FixedZone("ICT", 7*60*60)
+
+// statementHeaderScanRows limits how far down the file we look for the header row,
+// since exports start with account details and a title
+const statementHeaderScanRows = 40
+
+var (
+	parenthesesPattern = regexp.MustCompile(`\([^)]*\)`)
+	nonAmountPattern   = regexp.MustCompile(`[^\d.,+-]`)
+
+	// ErrStatementHeaderNotFound is returned when no recognizable header row exists
+	ErrStatementHeaderNotFound = errors.New("could not find date, description and amount columns in statement")
+)
+
+// ParseBankStatement reads a CSV bank statement export. It locates the header row,
+// detects the delimiter and returns every line that has a valid date and a non-zero amount.
+func ParseBankStatement(r io.Reader) ([]StatementEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	headerRow, columns := findStatementHeader(rows)
+	if headerRow < 0 {
+		return nil, ErrStatementHeaderNotFound
+	}
+
+	var entries []StatementEntry
+	for i := headerRow + 1; i < len(rows); i++ {
+		entry, ok := parseStatementRow(rows[i], columns)
+		if !ok {
+			continue
+		}
+		entry.Row = i + 1
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NormalizeStatementText lowercases text and strips Vietnamese diacritics
+func NormalizeStatementText(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		result = s
+	}
+	result = strings.NewReplacer("đ", "d", "Đ", "D").Replace(result)
+	return strings.Join(strings.Fields(strings.ToLower(result)), " ")
+}
+
+// detectDelimiter picks the most common of comma, semicolon and tab in the first lines
+func detectDelimiter(data []byte) rune {
+	lines := bytes.SplitN(data, []byte("\n"), 20)
+	best, bestCount := ',', 0
+	for _, d := range []rune{',', ';', '\t'} {
+		count := 0
+		for _, line := range lines {
+			count += bytes.Count(line, []byte(string(d)))
+		}
+		if count > bestCount {
+			best, bestCount = d, count
+		}
+	}
+	return best
+}
+
+// findStatementHeader returns the index of the header row and the column mapping
+func findStatementHeader(rows [][]string) (int, map[statementColumn]int) {
+	for i := 0; i < len(rows) && i < statementHeaderScanRows; i++ {
+		columns := make(map[statementColumn]int)
+		for j, cell := range rows[i] {
+			name := NormalizeStatementText(parenthesesPattern.ReplaceAllString(cell, " "))
+			name = strings.Trim(name, " :.*")
+			if col, ok := statementHeaders[name]; ok {
+				if _, seen := columns[col]; !seen {
+					columns[col] = j
+				}
+			}
+		}
+
+		_, hasDate := columns[columnDate]
+		_, hasDescription := columns[columnDescription]
+		_, hasCredit := columns[columnCredit]
+		_, hasAmount := columns[columnAmount]
+		if hasDate && hasDescription && (hasCredit || hasAmount) {
+			return i, columns
+		}
+	}
+	return -1, nil
+}
+
+// parseStatementRow converts a data row; rows without a date or amount (totals, footers) are skipped
+func parseStatementRow(row []string, columns map[statementColumn]int) (StatementEntry, bool) {
+	cell := func(col statementColumn) string {
+		idx, ok := columns[col]
+		if !ok || idx >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[idx])
+	}
+
+	date, ok := parseStatementDate(cell(columnDate))
+	if !ok {
+		return StatementEntry{}, false
+	}
+
+	var amount float64
+	if _, ok := columns[columnCredit]; ok {
+		amount = parseStatementAmount(cell(columnCredit))
+		if amount == 0 {
+			amount = -parseStatementAmount(cell(columnDebit))
+		}
+	} else {
+		amount = parseStatementAmount(cell(columnAmount))
+	}
+	if amount == 0 {
+		return StatementEntry{}, false
+	}
+
+	entry := StatementEntry{
+		Date:        date,
+		Amount:      amount,
+		Description: strings.Join(strings.Fields(cell(columnDescription)), " "),
+		Reference:   cell(columnReference),
+	}
+	if balance := cell(columnBalance); balance != "" {
+		value := parseStatementAmount(balance)
+		entry.Balance = &value
+	}
+	return entry, true
+}
+
+// parseStatementDate tries the known layouts, also accepting a date followed by other text
+func parseStatementDate(s string) (time.Time, bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return time.Time{}, false
+	}
+
+	candidates := []string{s}
+	if fields := strings.Fields(s); len(fields) > 1 {
+		candidates = append(candidates, fields[0])
+	}
+
+	for _, candidate := range candidates {
+		for _, layout := range statementDateLayouts {
+			if t, err := time.ParseInLocation(layout, candidate, statementTimezone); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseStatementAmount parses an amount cell, ignoring currency symbols and labels
+func parseStatementAmount(s string) float64 {
+	s = nonAmountPattern.ReplaceAllString(s, "")
+	if s == "" || s == "-" || s == "+" {
+		return 0
+	}
+	return parseAmount(s)
+}
@@ -0,0 +1,216 @@
+// Package bankhook parses bank transfer notifications pushed by Vietnamese
+// payment aggregators such as Casso and SePay, and verifies their signatures.
+//
+// Both services watch a bank account and POST a JSON document for every
+// transaction on it. The shapes differ, so Parse detects the provider and
+// returns the transfers in one common form.
+package bankhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Providers whose payload shapes Parse understands
+const (
+	ProviderCasso = "casso"
+	ProviderSePay = "sepay"
+)
+
+// timezone is used for transaction times, which aggregators send in local time
+var timezone = time.FixedZone("ICT", 7*60*60)
+
+// timeLayouts are the transaction time formats aggregators send
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	"2006-01-02",
+}
+
+var (
+	ErrUnknownPayload = errors.New("bankhook: unrecognized payload")
+	ErrMissingID      = errors.New("bankhook: transaction has no ID")
+)
+
+// Transfer is one bank transaction reported by an aggregator
+type Transfer struct {
+	Provider      string    // ProviderCasso or ProviderSePay
+	ID            string    // provider's transaction ID, unique per provider
+	Incoming      bool      // money in to the watched account
+	Amount        float64   // always positive; see Incoming for the direction
+	Description   string    // transfer note as the bank delivered it
+	AccountNumber string    // the watched account
+	Bank          string    // bank of the watched account, if the provider says
+	BankReference string    // bank's own transaction reference
+	Time          time.Time // when the bank booked the transaction
+}
+
+// cassoTransaction covers both Casso webhook versions: v1 sends "tid", "when"
+// and "bank_sub_acc_id", v2 sends "reference", "transactionDateTime" and "accountNumber".
+// Outgoing transactions have a negative amount.
+type cassoTransaction struct {
+	ID                  flexString `json:"id"`
+	TID                 string     `json:"tid"`
+	Reference           string     `json:"reference"`
+	Description         string     `json:"description"`
+	Amount              float64    `json:"amount"`
+	When                string     `json:"when"`
+	TransactionDateTime string     `json:"transactionDateTime"`
+	BankSubAccID        string     `json:"bank_sub_acc_id"`
+	AccountNumber       string     `json:"accountNumber"`
+	BankAbbreviation    string     `json:"bankAbbreviation"`
+	BankName            string     `json:"bankName"`
+}
+
+// sepayTransaction is the SePay webhook body, one transaction per request
+type sepayTransaction struct {
+	ID              flexString `json:"id"`
+	Gateway         string     `json:"gateway"`
+	TransactionDate string     `json:"transactionDate"`
+	AccountNumber   string     `json:"accountNumber"`
+	Code            *string    `json:"code"`
+	Content         string     `json:"content"`
+	TransferType    string     `json:"transferType"`
+	TransferAmount  float64    `json:"transferAmount"`
+	ReferenceCode   string     `json:"referenceCode"`
+	Description     string     `json:"description"`
+}
+
+// Parse detects the provider of a webhook body and returns its transfers.
+// Casso may batch several transactions in one request; SePay sends one.
+func Parse(body []byte) ([]Transfer, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, ErrUnknownPayload
+	}
+
+	switch {
+	case probe["data"] != nil:
+		return parseCasso(probe["data"])
+	case probe["transferType"] != nil:
+		return parseSePay(body)
+	default:
+		return nil, ErrUnknownPayload
+	}
+}
+
+func parseCasso(data json.RawMessage) ([]Transfer, error) {
+	var batch []cassoTransaction
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, ErrUnknownPayload
+		}
+	} else {
+		var single cassoTransaction
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, ErrUnknownPayload
+		}
+		batch = append(batch, single)
+	}
+
+	transfers := make([]Transfer, 0, len(batch))
+	for _, tx := range batch {
+		if tx.ID == "" {
+			return nil, ErrMissingID
+		}
+		bank := tx.BankAbbreviation
+		if bank == "" {
+			bank = tx.BankName
+		}
+		transfers = append(transfers, Transfer{
+			Provider:      ProviderCasso,
+			ID:            string(tx.ID),
+			Incoming:      tx.Amount > 0,
+			Amount:        abs(tx.Amount),
+			Description:   tx.Description,
+			AccountNumber: firstNonEmpty(tx.AccountNumber, tx.BankSubAccID),
+			Bank:          bank,
+			BankReference: firstNonEmpty(tx.Reference, tx.TID),
+			Time:          parseTime(firstNonEmpty(tx.TransactionDateTime, tx.When)),
+		})
+	}
+	return transfers, nil
+}
+
+func parseSePay(body []byte) ([]Transfer, error) {
+	var tx sepayTransaction
+	if err := json.Unmarshal(body, &tx); err != nil {
+		return nil, ErrUnknownPayload
+	}
+	if tx.ID == "" {
+		return nil, ErrMissingID
+	}
+
+	// SePay puts the note in "content"; "description" is the full bank SMS text
+	description := tx.Content
+	if description == "" {
+		description = tx.Description
+	}
+	if tx.Code != nil && *tx.Code != "" && !strings.Contains(description, *tx.Code) {
+		description = *tx.Code + " " + description
+	}
+
+	return []Transfer{{
+		Provider:      ProviderSePay,
+		ID:            string(tx.ID),
+		Incoming:      strings.EqualFold(tx.TransferType, "in"),
+		Amount:        abs(tx.TransferAmount),
+		Description:   description,
+		AccountNumber: tx.AccountNumber,
+		Bank:          tx.Gateway,
+		BankReference: tx.ReferenceCode,
+		Time:          parseTime(tx.TransactionDate),
+	}}, nil
+}
+
+// parseTime reads a transaction time, falling back to now when it is missing or unreadable
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, timezone); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// flexString accepts IDs sent either as JSON numbers or strings
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = ""
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*f = flexString(n.String())
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,214 @@
+package bankhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-secret"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Transfer
+	}{
+		{
+			name: "casso_v1_batch",
+			body: `{"error":0,"data":[
+				{"id":123456,"tid":"FT23094123","description":"SB7K2Q tra tien an","amount":150000,"cusum_balance":9000000,"when":"2024-03-05 12:30:00","bank_sub_acc_id":"0011001234567","subAccId":"0011001234567"},
+				{"id":123457,"tid":"FT23094124","description":"Rut tien","amount":-50000,"when":"2024-03-05 13:00:00","bank_sub_acc_id":"0011001234567"}
+			]}`,
+			want: []Transfer{
+				{Provider: ProviderCasso, ID: "123456", Incoming: true, Amount: 150000, Description: "SB7K2Q tra tien an", AccountNumber: "0011001234567", BankReference: "FT23094123", Time: time.Date(2024, 3, 5, 12, 30, 0, 0, timezone)},
+				{Provider: ProviderCasso, ID: "123457", Incoming: false, Amount: 50000, Description: "Rut tien", AccountNumber: "0011001234567", BankReference: "FT23094124", Time: time.Date(2024, 3, 5, 13, 0, 0, 0, timezone)},
+			},
+		},
+		{
+			name: "casso_v2_single",
+			body: `{"error":0,"data":{"id":"88","reference":"MBVCB.1234","description":"SB2M4N chuyen tien","amount":25000,"runningBalance":100000,"transactionDateTime":"2024-03-06 08:15:00","accountNumber":"0123456789","bankName":"MB Bank","bankAbbreviation":"MB"}}`,
+			want: []Transfer{
+				{Provider: ProviderCasso, ID: "88", Incoming: true, Amount: 25000, Description: "SB2M4N chuyen tien", AccountNumber: "0123456789", Bank: "MB", BankReference: "MBVCB.1234", Time: time.Date(2024, 3, 6, 8, 15, 0, 0, timezone)},
+			},
+		},
+		{
+			name: "sepay",
+			body: `{"id":92704,"gateway":"Vietcombank","transactionDate":"2024-03-25 14:02:37","accountNumber":"0123499999","code":"SB9ABC","content":"tien an toi","transferType":"in","transferAmount":2277000,"accumulated":19077000,"subAccount":null,"referenceCode":"MBVCB.3278907687","description":""}`,
+			want: []Transfer{
+				{Provider: ProviderSePay, ID: "92704", Incoming: true, Amount: 2277000, Description: "SB9ABC tien an toi", AccountNumber: "0123499999", Bank: "Vietcombank", BankReference: "MBVCB.3278907687", Time: time.Date(2024, 3, 25, 14, 2, 37, 0, timezone)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d transfers, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !got[i].Time.Equal(tt.want[i].Time) {
+					t.Errorf("transfer %d time = %v, want %v", i, got[i].Time, tt.want[i].Time)
+				}
+				got[i].Time, tt.want[i].Time = time.Time{}, time.Time{}
+				if got[i] != tt.want[i] {
+					t.Errorf("transfer %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		want error
+	}{
+		"not_json":       {`hello`, ErrUnknownPayload},
+		"unknown_shape":  {`{"foo":1}`, ErrUnknownPayload},
+		"casso_no_id":    {`{"error":0,"data":[{"amount":1000,"description":"x"}]}`, ErrMissingID},
+		"sepay_no_id":    {`{"transferType":"in","transferAmount":1000}`, ErrMissingID},
+		"casso_bad_data": {`{"error":0,"data":"oops"}`, ErrUnknownPayload},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.body)); !errors.Is(err, tt.want) {
+				t.Errorf("Parse error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"error":0,"data":[]}`)
+	signature := Sign(testSecret, body)
+
+	if err := Verify(testSecret, body, signature); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	if err := Verify(testSecret, body, signature[len(signaturePrefix):]); err != nil {
+		t.Errorf("bare hex signature rejected: %v", err)
+	}
+
+	rejected := map[string]struct {
+		secret, signature string
+		body              []byte
+	}{
+		"wrong_secret":  {"other", signature, body},
+		"tampered_body": {testSecret, signature, []byte(`{"error":0,"data":[{}]}`)},
+		"missing":       {testSecret, "", body},
+		"not_hex":       {testSecret, "sha256=zz", body},
+		"empty_secret":  {"", Sign("", body), body},
+	}
+	for name, tt := range rejected {
+		t.Run(name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.body, tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Verify error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+// receiver mimics the webhook endpoint: it verifies, parses and de-duplicates
+type receiver struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	received []Transfer
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(testSecret, body, req.Header.Get(SignatureHeader)); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	transfers, err := Parse(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range transfers {
+		key := t.Provider + "/" + t.ID
+		if !r.seen[key] {
+			r.seen[key] = true
+			r.received = append(r.received, t)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestFakeSenderRoundTrip(t *testing.T) {
+	for _, provider := range []string{ProviderCasso, ProviderSePay} {
+		t.Run(provider, func(t *testing.T) {
+			recv := &receiver{seen: make(map[string]bool)}
+			server := httptest.NewServer(recv)
+			defer server.Close()
+
+			sender := NewFakeSender(server.URL, testSecret, provider)
+			sent := Transfer{
+				Incoming:      true,
+				Amount:        150000,
+				Description:   "SB7K2Q tra tien an",
+				AccountNumber: "0011001234567",
+				Bank:          "VCB",
+				BankReference: "FT001",
+				Time:          time.Date(2024, 3, 5, 12, 30, 0, 0, timezone),
+			}
+
+			status, _, err := sender.Send(context.Background(), sent)
+			if err != nil || status != http.StatusOK {
+				t.Fatalf("Send = %d, %v", status, err)
+			}
+
+			// Redelivery of the same provider transaction is dropped by the receiver
+			sent.ID = "1"
+			if status, _, err := sender.Send(context.Background(), sent); err != nil || status != http.StatusOK {
+				t.Fatalf("resend = %d, %v", status, err)
+			}
+
+			if len(recv.received) != 1 {
+				t.Fatalf("received %d transfers, want 1", len(recv.received))
+			}
+			got := recv.received[0]
+			if got.Provider != provider || got.ID != "1" || !got.Incoming || got.Amount != sent.Amount ||
+				got.Description != sent.Description || got.AccountNumber != sent.AccountNumber ||
+				got.BankReference != sent.BankReference || !got.Time.Equal(sent.Time) {
+				t.Errorf("received %+v, sent %+v", got, sent)
+			}
+		})
+	}
+}
+
+func TestFakeSenderBadSignatureRejected(t *testing.T) {
+	server := httptest.NewServer(&receiver{seen: make(map[string]bool)})
+	defer server.Close()
+
+	sender := NewFakeSender(server.URL, "wrong-secret", ProviderCasso)
+	status, _, err := sender.Send(context.Background(), Transfer{Incoming: true, Amount: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+func TestFakeSenderSePaySingleTransfer(t *testing.T) {
+	sender := NewFakeSender("http://unused", testSecret, ProviderSePay)
+	if _, err := sender.Body(Transfer{ID: "1"}, Transfer{ID: "2"}); err == nil {
+		t.Error("expected an error for two SePay transfers in one request")
+	}
+}
@@ -0,0 +1,133 @@
+package bankhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// FakeSender posts signed notifications in a provider's shape, standing in
+// for Casso or SePay in tests and local development
+type FakeSender struct {
+	URL      string
+	Secret   string
+	Provider string // ProviderCasso (default) or ProviderSePay
+	Client   *http.Client
+
+	nextID int64
+}
+
+// NewFakeSender creates a sender posting to url, signed with secret
+func NewFakeSender(url, secret, provider string) *FakeSender {
+	return &FakeSender{
+		URL:      url,
+		Secret:   secret,
+		Provider: provider,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send posts the transfers and returns the response status and body.
+// Transfers without an ID get a sequential one; SePay only takes one transfer per request.
+func (f *FakeSender) Send(ctx context.Context, transfers ...Transfer) (int, []byte, error) {
+	transfers = append([]Transfer(nil), transfers...)
+	for i := range transfers {
+		if transfers[i].ID == "" {
+			f.nextID++
+			transfers[i].ID = strconv.FormatInt(f.nextID, 10)
+		}
+	}
+
+	body, err := f.Body(transfers...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return f.Post(ctx, body)
+}
+
+// Body encodes transfers the way the provider would
+func (f *FakeSender) Body(transfers ...Transfer) ([]byte, error) {
+	if f.Provider == ProviderSePay {
+		if len(transfers) != 1 {
+			return nil, fmt.Errorf("bankhook: SePay sends exactly one transfer per request, got %d", len(transfers))
+		}
+		return json.Marshal(sepayBody(transfers[0]))
+	}
+
+	data := make([]cassoTransaction, 0, len(transfers))
+	for _, t := range transfers {
+		data = append(data, cassoBody(t))
+	}
+	return json.Marshal(map[string]interface{}{"error": 0, "data": data})
+}
+
+// Post sends a raw body with a valid signature
+func (f *FakeSender) Post(ctx context.Context, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(f.Secret, body))
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func cassoBody(t Transfer) cassoTransaction {
+	amount := t.Amount
+	if !t.Incoming {
+		amount = -amount
+	}
+	return cassoTransaction{
+		ID:                  flexString(t.ID),
+		Reference:           t.BankReference,
+		Description:         t.Description,
+		Amount:              amount,
+		TransactionDateTime: transferTime(t).Format("2006-01-02 15:04:05"),
+		AccountNumber:       t.AccountNumber,
+		BankAbbreviation:    t.Bank,
+	}
+}
+
+func sepayBody(t Transfer) sepayTransaction {
+	transferType := "in"
+	if !t.Incoming {
+		transferType = "out"
+	}
+	return sepayTransaction{
+		ID:              flexString(t.ID),
+		Gateway:         t.Bank,
+		TransactionDate: transferTime(t).Format("2006-01-02 15:04:05"),
+		AccountNumber:   t.AccountNumber,
+		Content:         t.Description,
+		TransferType:    transferType,
+		TransferAmount:  t.Amount,
+		ReferenceCode:   t.BankReference,
+	}
+}
+
+func transferTime(t Transfer) time.Time {
+	if t.Time.IsZero() {
+		return time.Now().In(timezone)
+	}
+	return t.Time.In(timezone)
+}
@@ -0,0 +1,47 @@
+package bankhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the raw request body,
+// written as "sha256=<hex>". A bare hex digest is accepted too.
+const SignatureHeader = "X-Webhook-Signature"
+
+const signaturePrefix = "sha256="
+
+var ErrInvalidSignature = errors.New("bankhook: invalid signature")
+
+// Sign returns the signature header value for a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value against the body in constant time
+func Verify(secret string, body []byte, signature string) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+
+	signature = strings.TrimSpace(signature)
+	if len(signature) >= len(signaturePrefix) && strings.EqualFold(signature[:len(signaturePrefix)], signaturePrefix) {
+		signature = signature[len(signaturePrefix):]
+	}
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(given, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package banks is a directory of Vietnamese banks participating in NAPAS 247.
+//
+// The bundled dataset maps each bank's NAPAS BIN to the names and codes apps
+// show, the logo key used by the VietQR image CDN, the deeplink scheme of its
+// mobile app and the shape of its account numbers.
+package banks
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:embed napas_banks.json
+var napasBanks []byte
+
+var (
+	ErrUnknownBank          = errors.New("unknown bank")
+	ErrInvalidAccountNumber = errors.New("invalid account number")
+)
+
+// AccountRules describes what a bank's account numbers look like
+type AccountRules struct {
+	MinLength int  `json:"min_length"`
+	MaxLength int  `json:"max_length"`
+	Numeric   bool `json:"numeric"` // digits only; otherwise letters are allowed too
+}
+
+// Bank is one entry in the NAPAS bank directory
+type Bank struct {
+	BIN            string       `json:"bin"`                       // 6 digit NAPAS bank identification number
+	Code           string       `json:"code"`                      // canonical short code, e.g. VCB
+	ShortName      string       `json:"short_name"`                // name users know, e.g. Vietcombank
+	Name           string       `json:"name"`                      // full legal name
+	Aliases        []string     `json:"aliases,omitempty"`         // other codes seen in the wild, e.g. CTG for VietinBank
+	LogoKey        string       `json:"logo_key"`                  // image name on the VietQR logo CDN
+	DeeplinkScheme string       `json:"deeplink_scheme,omitempty"` // URL scheme of the bank's app, if it accepts transfer links
+	Color          string       `json:"color,omitempty"`           // brand color for the bank picker
+	Account        AccountRules `json:"account"`
+}
+
+// Registry looks up banks by BIN, code, short name or alias
+type Registry struct {
+	banks []Bank
+	index map[string]*Bank
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default returns the registry built from the bundled dataset
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		registry, err := Load(bytes.NewReader(napasBanks))
+		if err != nil {
+			panic("banks: invalid bundled dataset: " + err.Error())
+		}
+		defaultRegistry = registry
+	})
+	return defaultRegistry
+}
+
+// Load builds a registry from a JSON array of banks
+func Load(r io.Reader) (*Registry, error) {
+	var banks []Bank
+	if err := json.NewDecoder(r).Decode(&banks); err != nil {
+		return nil, err
+	}
+
+	registry := &Registry{
+		banks: banks,
+		index: make(map[string]*Bank, len(banks)*3),
+	}
+	for i := range registry.banks {
+		bank := &registry.banks[i]
+		if len(bank.BIN) != 6 || !isDigits(bank.BIN) {
+			return nil, fmt.Errorf("bank %q: BIN must be 6 digits", bank.Code)
+		}
+		if bank.Code == "" || bank.ShortName == "" {
+			return nil, fmt.Errorf("bank %s: code and short name are required", bank.BIN)
+		}
+		if bank.Account.MinLength <= 0 || bank.Account.MaxLength < bank.Account.MinLength {
+			return nil, fmt.Errorf("bank %s: invalid account length rule", bank.Code)
+		}
+
+		keys := append([]string{bank.BIN, bank.Code, bank.ShortName}, bank.Aliases...)
+		for _, key := range keys {
+			key = normalizeKey(key)
+			if existing, ok := registry.index[key]; ok && existing != bank {
+				return nil, fmt.Errorf("bank key %q is used by both %s and %s", key, existing.Code, bank.Code)
+			}
+			registry.index[key] = bank
+		}
+	}
+	return registry, nil
+}
+
+// All returns every bank in dataset order
+func (r *Registry) All() []Bank {
+	banks := make([]Bank, len(r.banks))
+	copy(banks, r.banks)
+	return banks
+}
+
+// Lookup finds a bank by BIN, code, short name or alias, ignoring case and spaces
+func (r *Registry) Lookup(key string) (Bank, bool) {
+	bank, ok := r.index[normalizeKey(key)]
+	if !ok {
+		return Bank{}, false
+	}
+	return *bank, true
+}
+
+// ValidateAccount checks an account number against the bank's rules and
+// returns the bank with the account number stripped of spaces and dashes
+func (r *Registry) ValidateAccount(bankKey, accountNumber string) (Bank, string, error) {
+	bank, ok := r.Lookup(bankKey)
+	if !ok {
+		return Bank{}, "", fmt.Errorf("%w: %s", ErrUnknownBank, bankKey)
+	}
+
+	account := strings.NewReplacer(" ", "", "-", "", ".", "").Replace(accountNumber)
+	if err := bank.Account.check(account); err != nil {
+		return Bank{}, "", fmt.Errorf("%w for %s: %s", ErrInvalidAccountNumber, bank.ShortName, err.Error())
+	}
+	return bank, account, nil
+}
+
+func (rules AccountRules) check(account string) error {
+	if rules.Numeric && !isDigits(account) {
+		return errors.New("must contain only digits")
+	}
+	if !rules.Numeric && !isAlphanumeric(account) {
+		return errors.New("must contain only letters and digits")
+	}
+	if len(account) < rules.MinLength || len(account) > rules.MaxLength {
+		if rules.MinLength == rules.MaxLength {
+			return fmt.Errorf("must be %d characters long", rules.MinLength)
+		}
+		return fmt.Errorf("must be %d to %d characters long", rules.MinLength, rules.MaxLength)
+	}
+	return nil
+}
+
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(key), ""))
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return s != ""
+}
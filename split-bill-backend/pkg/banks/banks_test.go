@@ -0,0 +1,148 @@
+package banks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLookup(t *testing.T) {
+	tests := []struct {
+		key      string
+		wantCode string
+	}{
+		// By BIN
+		{"970436", "VCB"},
+		{"970415", "ICB"},
+		{"970422", "MB"},
+		{"546034", "CAKE"},
+		// By code, any case
+		{"VCB", "VCB"},
+		{"tcb", "TCB"},
+		{"Bidv", "BIDV"},
+		// By short name, ignoring spaces
+		{"Vietcombank", "VCB"},
+		{"VietinBank", "ICB"},
+		{"MB Bank", "MB"},
+		{"mbbank", "MB"},
+		{" Agribank ", "VBA"},
+		// By alias
+		{"CTG", "ICB"},
+		{"MBB", "MB"},
+	}
+	registry := Default()
+	for _, tt := range tests {
+		bank, ok := registry.Lookup(tt.key)
+		if !ok || bank.Code != tt.wantCode {
+			t.Errorf("Lookup(%q) = %s, %v, want %s", tt.key, bank.Code, ok, tt.wantCode)
+		}
+	}
+
+	for _, key := range []string{"", "970499", "VIETCOMBANKK", "Ngân hàng"} {
+		if bank, ok := registry.Lookup(key); ok {
+			t.Errorf("Lookup(%q) = %s, want not found", key, bank.Code)
+		}
+	}
+}
+
+func TestDefaultDataset(t *testing.T) {
+	all := Default().All()
+	if len(all) < 40 {
+		t.Fatalf("bundled dataset has %d banks, want the NAPAS 247 members", len(all))
+	}
+
+	vcb, _ := Default().Lookup("VCB")
+	if vcb.BIN != "970436" || vcb.ShortName != "Vietcombank" || vcb.LogoKey == "" {
+		t.Errorf("VCB = %+v", vcb)
+	}
+	for _, bank := range all {
+		if bank.Name == "" || bank.LogoKey == "" {
+			t.Errorf("bank %s is missing its name or logo key", bank.Code)
+		}
+	}
+
+	// All returns a copy
+	all[0].Code = "XXX"
+	if Default().All()[0].Code == "XXX" {
+		t.Error("All exposes the registry's slice")
+	}
+}
+
+func TestValidateAccount(t *testing.T) {
+	tests := []struct {
+		bank, account string
+		wantCode      string
+		wantAccount   string
+		wantErr       error
+	}{
+		{"VCB", "0071000123456", "VCB", "0071000123456", nil},
+		{"970436", "0071 0001 23456", "VCB", "0071000123456", nil},
+		{"CTG", "1010-2345-6789", "ICB", "101023456789", nil},
+		{"MB Bank", "0123.456.789", "MB", "0123456789", nil},
+		{"VBA", "1234567890123", "VBA", "1234567890123", nil},
+		{"VBA", "123456789012", "", "", ErrInvalidAccountNumber},   // Agribank is exactly 13
+		{"VCB", "00710001", "", "", ErrInvalidAccountNumber},       // too short
+		{"VCB", "00710001234567", "", "", ErrInvalidAccountNumber}, // too long
+		{"TCB", "1903ABC4567", "", "", ErrInvalidAccountNumber},    // letters
+		{"TCB", "", "", "", ErrInvalidAccountNumber},
+		{"XYZ", "0071000123456", "", "", ErrUnknownBank},
+		{"", "0071000123456", "", "", ErrUnknownBank},
+	}
+	for _, tt := range tests {
+		bank, account, err := Default().ValidateAccount(tt.bank, tt.account)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ValidateAccount(%q, %q): err = %v, want %v", tt.bank, tt.account, err, tt.wantErr)
+			continue
+		}
+		if bank.Code != tt.wantCode || account != tt.wantAccount {
+			t.Errorf("ValidateAccount(%q, %q) = %s, %q, want %s, %q", tt.bank, tt.account, bank.Code, account, tt.wantCode, tt.wantAccount)
+		}
+	}
+}
+
+func TestValidateAccountMessages(t *testing.T) {
+	_, _, err := Default().ValidateAccount("VBA", "123")
+	if err == nil || !strings.Contains(err.Error(), "Agribank") || !strings.Contains(err.Error(), "must be 13 characters long") {
+		t.Errorf("err = %v", err)
+	}
+	_, _, err = Default().ValidateAccount("VCB", "123")
+	if err == nil || !strings.Contains(err.Error(), "must be 9 to 13 characters long") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestAlphanumericAccounts(t *testing.T) {
+	registry, err := Load(strings.NewReader(`[{"bin":"970499","code":"TEST","short_name":"Test Bank","name":"Test",
+		"logo_key":"TEST","account":{"min_length":6,"max_length":10,"numeric":false}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, account, err := registry.ValidateAccount("TEST", "ab-12 cd"); err != nil || account != "ab12cd" {
+		t.Errorf("ValidateAccount = %q, %v", account, err)
+	}
+	if _, _, err := registry.ValidateAccount("TEST", "ab12_cd"); !errors.Is(err, ErrInvalidAccountNumber) {
+		t.Errorf("err = %v, want ErrInvalidAccountNumber", err)
+	}
+}
+
+func TestLoadRejectsBadDatasets(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"short BIN", `[{"bin":"97043","code":"A","short_name":"A","account":{"min_length":6,"max_length":10,"numeric":true}}]`},
+		{"letters in BIN", `[{"bin":"97043X","code":"A","short_name":"A","account":{"min_length":6,"max_length":10,"numeric":true}}]`},
+		{"no code", `[{"bin":"970436","short_name":"A","account":{"min_length":6,"max_length":10,"numeric":true}}]`},
+		{"no length rule", `[{"bin":"970436","code":"A","short_name":"A"}]`},
+		{"max below min", `[{"bin":"970436","code":"A","short_name":"A","account":{"min_length":10,"max_length":6,"numeric":true}}]`},
+		{"shared key", `[
+			{"bin":"970436","code":"A","short_name":"Alpha","account":{"min_length":6,"max_length":10,"numeric":true}},
+			{"bin":"970437","code":"B","short_name":"Beta","aliases":["alpha"],"account":{"min_length":6,"max_length":10,"numeric":true}}]`},
+		{"not JSON", `{`},
+	}
+	for _, tt := range tests {
+		if _, err := Load(strings.NewReader(tt.json)); err == nil {
+			t.Errorf("%s: Load succeeded, want an error", tt.name)
+		}
+	}
+}
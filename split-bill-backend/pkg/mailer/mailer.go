@@ -0,0 +1,138 @@
+// Package mailer sends multipart text/HTML email over SMTP.
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoRecipient = errors.New("mailer: no recipient")
+
+// Config holds the SMTP server settings. Without a username the server is
+// used unauthenticated, which is what local sinks like MailHog expect.
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // "Name <address>" or a bare address
+}
+
+// Email is one message to one or more recipients
+type Email struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string // optional; sent as the preferred alternative
+}
+
+// Mailer sends email through one SMTP server
+type Mailer struct {
+	cfg Config
+}
+
+// New creates a mailer for the SMTP server in cfg
+func New(cfg Config) (*Mailer, error) {
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("mailer: invalid from address: %w", err)
+	}
+	return &Mailer{cfg: cfg}, nil
+}
+
+// Send delivers an email. STARTTLS is used when the server offers it.
+func (m *Mailer) Send(email Email) error {
+	if len(email.To) == 0 {
+		return ErrNoRecipient
+	}
+
+	from, _ := mail.ParseAddress(m.cfg.From)
+	msg, err := m.build(from, email)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := smtp.SendMail(addr, auth, from.Address, email.To, msg); err != nil {
+		return fmt.Errorf("mailer: send: %w", err)
+	}
+	return nil
+}
+
+// build writes the MIME message
+func (m *Mailer) build(from *mail.Address, email Email) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", strings.Join(email.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomID()+"@"+domainOf(from.Address)+">")
+	header("MIME-Version", "1.0")
+
+	if email.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		return buf.Bytes(), writeQuotedPrintable(&buf, email.Text)
+	}
+
+	boundary := "sb-" + randomID()
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		buf.WriteString("--" + boundary + "\r\n")
+		header("Content-Type", part.contentType+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--\r\n")
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, body string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func domainOf(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return address[at+1:]
+	}
+	return "localhost"
+}
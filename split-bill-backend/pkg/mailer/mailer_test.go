@@ -0,0 +1,171 @@
+package mailer
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// sink is a minimal local SMTP server that keeps the messages it receives
+type sink struct {
+	listener net.Listener
+	messages chan received
+}
+
+type received struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSink(t *testing.T) *sink {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &sink{listener: listener, messages: make(chan received, 1)}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *sink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *sink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *sink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var msg received
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			msg.data = data.String()
+			s.messages <- msg
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSendMultipart(t *testing.T) {
+	s := newSink(t)
+	m, err := New(Config{Host: "127.0.0.1", Port: s.port(), From: "Split Bill <no-reply@splitbill.test>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := Email{
+		To:      []string{"an@example.com"},
+		Subject: "Hóa đơn mới trong Đà Lạt",
+		Text:    "Bình đã thêm \"Lẩu\" - 450K₫",
+		HTML:    "<p>Bình đã thêm <b>Lẩu</b> - 450K₫</p>",
+	}
+	if err := m.Send(email); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := <-s.messages
+	if got.from != "no-reply@splitbill.test" || len(got.to) != 1 || got.to[0] != "an@example.com" {
+		t.Fatalf("envelope = %s -> %v", got.from, got.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != email.Subject {
+		t.Errorf("subject = %q, %v", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, %v", mediaType, err)
+	}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	want := map[string]string{"text/plain": email.Text, "text/html": email.HTML}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(part) // quoted-printable is decoded by the reader
+		if string(body) != want[partType] {
+			t.Errorf("%s part = %q, want %q", partType, body, want[partType])
+		}
+		delete(want, partType)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing parts: %v", want)
+	}
+}
+
+func TestSendRequiresRecipient(t *testing.T) {
+	m, err := New(Config{Host: "127.0.0.1", Port: 1, From: "no-reply@splitbill.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(Email{Subject: "x"}); err != ErrNoRecipient {
+		t.Errorf("Send error = %v, want ErrNoRecipient", err)
+	}
+}
+
+func TestNewRejectsBadFrom(t *testing.T) {
+	if _, err := New(Config{From: "not an address"}); err == nil {
+		t.Error("expected an error for an invalid from address")
+	}
+}
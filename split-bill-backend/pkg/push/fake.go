@@ -0,0 +1,61 @@
+package push
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeSender keeps messages in memory instead of sending them. Tokens marked
+// invalid are reported back the way FCM reports unregistered devices.
+type FakeSender struct {
+	mu      sync.Mutex
+	sent    []Message
+	invalid map[string]bool
+}
+
+// NewFakeSender creates an empty in-memory sender
+func NewFakeSender() *FakeSender {
+	return &FakeSender{invalid: make(map[string]bool)}
+}
+
+// MarkInvalid makes later sends to these tokens fail as unregistered
+func (s *FakeSender) MarkInvalid(tokens ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range tokens {
+		s.invalid[token] = true
+	}
+}
+
+// Send records msg with only the tokens that are still valid
+func (s *FakeSender) Send(ctx context.Context, msg Message) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &Result{}
+	delivered := msg
+	delivered.Tokens = nil
+	for _, token := range msg.Tokens {
+		if s.invalid[token] {
+			result.InvalidTokens = append(result.InvalidTokens, token)
+			continue
+		}
+		delivered.Tokens = append(delivered.Tokens, token)
+	}
+	result.Sent = len(delivered.Tokens)
+	if result.Sent > 0 {
+		s.sent = append(s.sent, delivered)
+	}
+	return result, nil
+}
+
+// Sent returns the messages delivered so far
+func (s *FakeSender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
@@ -0,0 +1,77 @@
+package push
+
+import (
+	"context"
+	"fmt"
+
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"google.golang.org/api/option"
+)
+
+// fcmBatchSize is the most tokens FCM accepts in one multicast request
+const fcmBatchSize = 500
+
+// FCMSender sends messages through Firebase Cloud Messaging
+type FCMSender struct {
+	client *messaging.Client
+}
+
+// NewFCMSender creates an FCM sender from a Firebase service account file
+func NewFCMSender(ctx context.Context, credentialsFile string) (*FCMSender, error) {
+	return newFCMSender(ctx, nil, option.WithCredentialsFile(credentialsFile))
+}
+
+func newFCMSender(ctx context.Context, config *firebase.Config, opts ...option.ClientOption) (*FCMSender, error) {
+	app, err := firebase.NewApp(ctx, config, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("push: init firebase: %w", err)
+	}
+	client, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("push: messaging client: %w", err)
+	}
+	return &FCMSender{client: client}, nil
+}
+
+// Send delivers msg to every token, in batches FCM accepts
+func (s *FCMSender) Send(ctx context.Context, msg Message) (*Result, error) {
+	result := &Result{}
+	for start := 0; start < len(msg.Tokens); start += fcmBatchSize {
+		end := start + fcmBatchSize
+		if end > len(msg.Tokens) {
+			end = len(msg.Tokens)
+		}
+		tokens := msg.Tokens[start:end]
+
+		response, err := s.client.SendEachForMulticast(ctx, &messaging.MulticastMessage{
+			Tokens: tokens,
+			Notification: &messaging.Notification{
+				Title: msg.Title,
+				Body:  msg.Body,
+			},
+			Data: msg.Data,
+		})
+		if err != nil {
+			if result.Sent > 0 {
+				return result, nil
+			}
+			return nil, fmt.Errorf("push: fcm send: %w", err)
+		}
+
+		result.Sent += response.SuccessCount
+		for i, r := range response.Responses {
+			if r.Error != nil && staleToken(r.Error) {
+				result.InvalidTokens = append(result.InvalidTokens, tokens[i])
+			}
+		}
+	}
+	return result, nil
+}
+
+// staleToken reports whether FCM refused a token because the device is gone.
+// INVALID_ARGUMENT is not enough: FCM also returns it for a message that is
+// too large or malformed, which says nothing about the devices.
+func staleToken(err error) bool {
+	return messaging.IsUnregistered(err) || messaging.IsSenderIDMismatch(err)
+}
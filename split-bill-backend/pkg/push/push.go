@@ -0,0 +1,27 @@
+// Package push delivers notifications to mobile devices.
+package push
+
+import "context"
+
+// Message is one notification sent to a set of device tokens
+type Message struct {
+	Tokens []string
+	Title  string
+	Body   string
+	Data   map[string]string
+}
+
+// Result reports how a message was delivered
+type Result struct {
+	Sent int
+
+	// InvalidTokens are no longer registered with the push service and
+	// should be removed so they are not tried again
+	InvalidTokens []string
+}
+
+// Sender delivers messages to devices. An error means nothing could be sent;
+// failures for individual tokens are reported in the Result.
+type Sender interface {
+	Send(ctx context.Context, msg Message) (*Result, error)
+}
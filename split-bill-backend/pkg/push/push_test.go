@@ -0,0 +1,148 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	firebase "firebase.google.com/go/v4"
+	"google.golang.org/api/option"
+)
+
+// fcmErrors are FCM v1 error responses by the token they are returned for
+var fcmErrors = map[string]struct {
+	status int
+	body   string
+}{
+	"gone": {http.StatusNotFound, `{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",
+		"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`},
+	"other-project": {http.StatusForbidden, `{"error":{"code":403,"message":"SenderId mismatch","status":"PERMISSION_DENIED",
+		"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"SENDER_ID_MISMATCH"}]}}`},
+	"apns-broken": {http.StatusUnauthorized, `{"error":{"code":401,"message":"Auth error from APNS or Web Push Service","status":"UNAUTHENTICATED",
+		"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"THIRD_PARTY_AUTH_ERROR"}]}}`},
+}
+
+// payloadTooLarge is what FCM answers for a message it cannot accept at all
+const payloadTooLarge = `{"error":{"code":400,"message":"Request contains an invalid argument.","status":"INVALID_ARGUMENT",
+	"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"INVALID_ARGUMENT"}]}}`
+
+// fcmServer answers FCM v1 sends, failing tokens listed in fcmErrors and,
+// if badPayload is set, every message
+func fcmServer(t *testing.T, badPayload bool) (*FCMSender, *[]string) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/splitbill-test/messages:send" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Message struct {
+				Token string `json:"token"`
+			} `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		received = append(received, req.Message.Token)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if badPayload {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(payloadTooLarge))
+			return
+		}
+		if e, ok := fcmErrors[req.Message.Token]; ok {
+			w.WriteHeader(e.status)
+			w.Write([]byte(e.body))
+			return
+		}
+		w.Write([]byte(`{"name":"projects/splitbill-test/messages/1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	sender, err := newFCMSender(context.Background(),
+		&firebase.Config{ProjectID: "splitbill-test"},
+		option.WithEndpoint(server.URL),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("newFCMSender: %v", err)
+	}
+	return sender, &received
+}
+
+func TestFCMSenderPrunesGoneDevices(t *testing.T) {
+	sender, received := fcmServer(t, false)
+
+	result, err := sender.Send(context.Background(), Message{
+		Tokens: []string{"phone", "gone", "tablet", "other-project", "apns-broken"},
+		Title:  "Nhắc thanh toán",
+		Body:   "Bạn còn nợ 150.000₫",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	sort.Strings(result.InvalidTokens)
+	if result.Sent != 2 || !reflect.DeepEqual(result.InvalidTokens, []string{"gone", "other-project"}) {
+		t.Errorf("result = %+v, want 2 sent and gone, other-project pruned", result)
+	}
+	if len(*received) != 5 {
+		t.Errorf("FCM received %d messages, want 5", len(*received))
+	}
+}
+
+func TestFCMSenderKeepsTokensOnBadPayload(t *testing.T) {
+	sender, _ := fcmServer(t, true)
+
+	result, err := sender.Send(context.Background(), Message{
+		Tokens: []string{"phone", "tablet"},
+		Title:  "Nhắc thanh toán",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Sent != 0 || len(result.InvalidTokens) != 0 {
+		t.Errorf("result = %+v, want nothing sent and no token pruned", result)
+	}
+}
+
+func TestFakeSender(t *testing.T) {
+	sender := NewFakeSender()
+	sender.MarkInvalid("gone")
+
+	result, err := sender.Send(context.Background(), Message{Tokens: []string{"phone", "gone"}, Title: "Hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent != 1 || !reflect.DeepEqual(result.InvalidTokens, []string{"gone"}) {
+		t.Errorf("result = %+v", result)
+	}
+
+	// Nothing is recorded when no token is valid
+	if _, err := sender.Send(context.Background(), Message{Tokens: []string{"gone"}, Title: "Again"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := sender.Sent()
+	if len(sent) != 1 || !reflect.DeepEqual(sent[0].Tokens, []string{"phone"}) || sent[0].Title != "Hi" {
+		t.Errorf("Sent() = %+v", sent)
+	}
+}
+
+func TestFakeSenderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewFakeSender().Send(ctx, Message{Tokens: []string{"phone"}}); err == nil {
+		t.Error("Send with a cancelled context: want an error")
+	}
+}
@@ -0,0 +1,76 @@
+// Package safehttp builds HTTP clients for URLs that users choose, such as
+// webhook endpoints, so they cannot be pointed at the server's own network:
+// loopback, private ranges, link-local cloud metadata and the like.
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a client refuses to connect to a host
+// because it resolves to an address that is not public
+var ErrPrivateAddress = errors.New("host resolves to a private address")
+
+// nonPublicNetworks are the IPv4 ranges net.IP has no predicate for
+var nonPublicNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "this network", which Linux routes to the host itself
+		"100.64.0.0/10", // carrier-grade NAT, used by some clouds for internal services
+		"198.18.0.0/15", // benchmarking
+		"240.0.0.0/4",   // reserved, including broadcast
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewClient returns a client that refuses to connect to addresses that are
+// not public unless allowPrivate is set, checked when dialing so DNS tricks
+// cannot get around it, and does not follow redirects
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
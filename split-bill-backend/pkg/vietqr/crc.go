@@ -0,0 +1,18 @@
+package vietqr
+
+// CRC16 computes the CRC16-CCITT (FALSE) checksum used by EMVCo QR codes:
+// polynomial 0x1021, initial value 0xFFFF, no reflection, no final XOR.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,54 @@
+package vietqr
+
+import (
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// DefaultSize is the default edge length of rendered QR images in pixels
+const DefaultSize = 512
+
+// PNG renders a payload as a PNG image of size x size pixels
+func PNG(payload string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// SVG renders a payload as a scalable SVG image with the given nominal size
+func SVG(payload string, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	qr, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := qr.Bitmap() // includes the quiet zone
+	modules := len(bitmap)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, modules, modules)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	b.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// Merge horizontal runs of dark modules into one rectangle
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,280 @@
+// Package vietqr builds and parses NAPAS VietQR payloads.
+//
+// A VietQR payload is an EMVCo merchant-presented QR string: a sequence of
+// TLV fields (two digit ID, two digit length, value) ending with a
+// CRC16-CCITT checksum. Banking apps scan it to prefill a transfer.
+package vietqr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Top level field IDs
+const (
+	idPayloadFormat      = "00"
+	idInitiationMethod   = "01"
+	idMerchantAccount    = "38"
+	idCurrency           = "53"
+	idAmount             = "54"
+	idCountry            = "58"
+	idAdditionalData     = "62"
+	idCRC                = "63"
+	payloadFormatVersion = "01"
+)
+
+// Merchant account information (field 38) sub-field IDs
+const (
+	idGUID        = "00"
+	idBeneficiary = "01"
+	idService     = "02"
+	idBankBIN     = "00"
+	idAccount     = "01"
+)
+
+// Additional data (field 62) sub-field IDs
+const (
+	idBillNumber = "01"
+	idPurpose    = "08"
+)
+
+const (
+	// NapasGUID identifies the NAPAS VietQR scheme
+	NapasGUID = "A000000727"
+
+	// CurrencyVND is the ISO 4217 numeric code for Vietnamese dong
+	CurrencyVND = "704"
+
+	// CountryVN is the ISO 3166 country code for Vietnam
+	CountryVN = "VN"
+
+	// InitiationStatic marks a reusable QR without an amount
+	InitiationStatic = "11"
+
+	// InitiationDynamic marks a one-off QR with an amount
+	InitiationDynamic = "12"
+)
+
+// Service codes for the NAPAS 247 transfer
+const (
+	ServiceAccount = "QRIBFTTA" // transfer to a bank account number
+	ServiceCard    = "QRIBFTTC" // transfer to a card number
+)
+
+// MaxPurposeLength keeps the transfer note within what banking apps accept
+const MaxPurposeLength = 50
+
+var (
+	ErrInvalidBIN     = errors.New("vietqr: bank BIN must be 6 digits")
+	ErrInvalidAccount = errors.New("vietqr: account number must be 1-19 letters or digits")
+	ErrInvalidAmount  = errors.New("vietqr: amount must not be negative")
+	ErrInvalidCRC     = errors.New("vietqr: checksum mismatch")
+	ErrMalformed      = errors.New("vietqr: malformed payload")
+)
+
+// Payload holds the fields of a VietQR transfer
+type Payload struct {
+	BankBIN       string  // 6 digit NAPAS bank identification number, e.g. 970436
+	AccountNumber string  // beneficiary account or card number
+	Service       string  // ServiceAccount (default) or ServiceCard
+	Amount        float64 // in VND; 0 produces a static QR without an amount
+	Purpose       string  // transfer note, shown to the payer
+	BillNumber    string  // optional reference for the merchant
+}
+
+// Encode builds the payload string, including its checksum.
+// Purpose is folded to ASCII since banks reject Vietnamese diacritics.
+func Encode(p Payload) (string, error) {
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	service := p.Service
+	if service == "" {
+		service = ServiceAccount
+	}
+	dynamic := math.Round(p.Amount) > 0
+
+	beneficiary := tlv(idBankBIN, p.BankBIN) + tlv(idAccount, p.AccountNumber)
+	merchant := tlv(idGUID, NapasGUID) + tlv(idBeneficiary, beneficiary) + tlv(idService, service)
+
+	var b strings.Builder
+	b.WriteString(tlv(idPayloadFormat, payloadFormatVersion))
+	if dynamic {
+		b.WriteString(tlv(idInitiationMethod, InitiationDynamic))
+	} else {
+		b.WriteString(tlv(idInitiationMethod, InitiationStatic))
+	}
+	b.WriteString(tlv(idMerchantAccount, merchant))
+	b.WriteString(tlv(idCurrency, CurrencyVND))
+	if dynamic {
+		b.WriteString(tlv(idAmount, formatAmount(p.Amount)))
+	}
+	b.WriteString(tlv(idCountry, CountryVN))
+
+	additional := ""
+	if bill := asciiText(p.BillNumber, 25); bill != "" {
+		additional += tlv(idBillNumber, bill)
+	}
+	if purpose := asciiText(p.Purpose, MaxPurposeLength); purpose != "" {
+		additional += tlv(idPurpose, purpose)
+	}
+	if additional != "" {
+		b.WriteString(tlv(idAdditionalData, additional))
+	}
+
+	// The checksum covers everything up to and including its own ID and length
+	b.WriteString(idCRC + "04")
+	b.WriteString(fmt.Sprintf("%04X", CRC16(b.String())))
+	return b.String(), nil
+}
+
+// Decode parses a payload string and verifies its checksum
+func Decode(payload string) (*Payload, error) {
+	if len(payload) < 8 {
+		return nil, ErrMalformed
+	}
+	body, checksum := payload[:len(payload)-4], payload[len(payload)-4:]
+	if !strings.HasSuffix(body, idCRC+"04") {
+		return nil, ErrMalformed
+	}
+	if fmt.Sprintf("%04X", CRC16(body)) != strings.ToUpper(checksum) {
+		return nil, ErrInvalidCRC
+	}
+
+	fields, err := parseTLV(body[:len(body)-4])
+	if err != nil {
+		return nil, err
+	}
+	if fields[idPayloadFormat] != payloadFormatVersion {
+		return nil, ErrMalformed
+	}
+
+	merchant, err := parseTLV(fields[idMerchantAccount])
+	if err != nil {
+		return nil, err
+	}
+	if merchant[idGUID] != NapasGUID {
+		return nil, errors.New("vietqr: not a NAPAS VietQR payload")
+	}
+	beneficiary, err := parseTLV(merchant[idBeneficiary])
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payload{
+		BankBIN:       beneficiary[idBankBIN],
+		AccountNumber: beneficiary[idAccount],
+		Service:       merchant[idService],
+	}
+
+	if amount := fields[idAmount]; amount != "" {
+		p.Amount, err = strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+	}
+
+	if data := fields[idAdditionalData]; data != "" {
+		additional, err := parseTLV(data)
+		if err != nil {
+			return nil, err
+		}
+		p.BillNumber = additional[idBillNumber]
+		p.Purpose = additional[idPurpose]
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p Payload) validate() error {
+	if len(p.BankBIN) != 6 || !isDigits(p.BankBIN) {
+		return ErrInvalidBIN
+	}
+	if len(p.AccountNumber) == 0 || len(p.AccountNumber) > 19 || !isAlphanumeric(p.AccountNumber) {
+		return ErrInvalidAccount
+	}
+	if p.Amount < 0 {
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// tlv encodes one field. Values are ASCII, so byte length equals character count.
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// parseTLV splits a TLV sequence into a map of ID to value
+func parseTLV(s string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for i := 0; i < len(s); {
+		if i+4 > len(s) {
+			return nil, ErrMalformed
+		}
+		id := s[i : i+2]
+		length, err := strconv.Atoi(s[i+2 : i+4])
+		if err != nil || i+4+length > len(s) {
+			return nil, ErrMalformed
+		}
+		fields[id] = s[i+4 : i+4+length]
+		i += 4 + length
+	}
+	return fields, nil
+}
+
+// formatAmount writes a whole number of dong, since VND has no minor unit
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount), 'f', 0, 64)
+}
+
+// asciiText removes diacritics and characters banks do not accept, then truncates
+func asciiText(s string, max int) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	folded = strings.NewReplacer("đ", "d", "Đ", "D").Replace(folded)
+
+	var b strings.Builder
+	for _, r := range folded {
+		if r >= 0x20 && r < 0x7F {
+			b.WriteRune(r)
+		}
+	}
+	result := strings.Join(strings.Fields(b.String()), " ")
+	if len(result) > max {
+		result = strings.TrimSpace(result[:max])
+	}
+	return result
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') {
+			return false
+		}
+	}
+	return true
+}
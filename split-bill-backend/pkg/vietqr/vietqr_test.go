@@ -0,0 +1,174 @@
+package vietqr
+
+import (
+	"bytes"
+	"flag"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+var goldenCases = []struct {
+	name    string
+	payload Payload
+	want    Payload // what Decode should return; differs from payload when Encode normalizes
+}{
+	{
+		name:    "static_account",
+		payload: Payload{BankBIN: "970436", AccountNumber: "0011001234567"},
+		want:    Payload{BankBIN: "970436", AccountNumber: "0011001234567", Service: ServiceAccount},
+	},
+	{
+		name:    "dynamic_with_reference",
+		payload: Payload{BankBIN: "970422", AccountNumber: "0123456789", Amount: 150000, Purpose: "SB7K2Q tien an toi"},
+		want:    Payload{BankBIN: "970422", AccountNumber: "0123456789", Service: ServiceAccount, Amount: 150000, Purpose: "SB7K2Q tien an toi"},
+	},
+	{
+		name:    "vietnamese_purpose_folded",
+		payload: Payload{BankBIN: "970407", AccountNumber: "19031234567012", Amount: 99999.6, Purpose: "SB9ABC Đi chợ cuối tuần"},
+		want:    Payload{BankBIN: "970407", AccountNumber: "19031234567012", Service: ServiceAccount, Amount: 100000, Purpose: "SB9ABC Di cho cuoi tuan"},
+	},
+	{
+		name:    "card_with_bill_number",
+		payload: Payload{BankBIN: "970415", AccountNumber: "9704150000000001", Service: ServiceCard, Amount: 25000, BillNumber: "SB2M4N", Purpose: "Cafe"},
+		want:    Payload{BankBIN: "970415", AccountNumber: "9704150000000001", Service: ServiceCard, Amount: 25000, BillNumber: "SB2M4N", Purpose: "Cafe"},
+	},
+}
+
+func TestEncodeGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Encode(tc.payload)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			path := filepath.Join("testdata", tc.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create): %v", err)
+			}
+			if want := strings.TrimSpace(string(golden)); got != want {
+				t.Errorf("payload mismatch\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", tc.name+".golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Decode(strings.TrimSpace(string(golden)))
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if *got != tc.want {
+				t.Errorf("decoded %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeStructure(t *testing.T) {
+	payload, err := Encode(Payload{BankBIN: "970436", AccountNumber: "0011001234567"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields, err := parseTLV(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"00": "01",
+		"01": InitiationStatic,
+		"38": "0010A00000072701270006970436011300110012345670208QRIBFTTA",
+		"53": "704",
+		"58": "VN",
+	}
+	for id, want := range expect {
+		if fields[id] != want {
+			t.Errorf("field %s = %q, want %q", id, fields[id], want)
+		}
+	}
+	if _, ok := fields["54"]; ok {
+		t.Error("static QR must not carry an amount")
+	}
+}
+
+func TestCRC16KnownVector(t *testing.T) {
+	// Standard check value for CRC-16/CCITT-FALSE
+	if got := CRC16("123456789"); got != 0x29B1 {
+		t.Fatalf("CRC16 = %04X, want 29B1", got)
+	}
+}
+
+func TestDecodeRejectsTampering(t *testing.T) {
+	payload, err := Encode(Payload{BankBIN: "970422", AccountNumber: "0123456789", Amount: 150000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := strings.Replace(payload, "150000", "950000", 1)
+	if _, err := Decode(tampered); err != ErrInvalidCRC {
+		t.Fatalf("Decode(tampered) error = %v, want %v", err, ErrInvalidCRC)
+	}
+	if _, err := Decode("not a payload"); err == nil {
+		t.Fatal("Decode accepted garbage")
+	}
+}
+
+func TestEncodeValidation(t *testing.T) {
+	cases := []Payload{
+		{BankBIN: "97043", AccountNumber: "123"},
+		{BankBIN: "970436", AccountNumber: ""},
+		{BankBIN: "970436", AccountNumber: "12-34"},
+		{BankBIN: "970436", AccountNumber: "123", Amount: -1},
+	}
+	for _, p := range cases {
+		if _, err := Encode(p); err == nil {
+			t.Errorf("Encode(%+v) succeeded, want error", p)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	payload, err := Encode(Payload{BankBIN: "970436", AccountNumber: "0011001234567", Amount: 50000, Purpose: "SB7K2Q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pngData, err := PNG(payload, 256)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		t.Fatalf("rendered PNG does not decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Errorf("PNG size = %dx%d, want 256x256", b.Dx(), b.Dy())
+	}
+
+	svg, err := SVG(payload, 256)
+	if err != nil {
+		t.Fatalf("SVG: %v", err)
+	}
+	if !bytes.HasPrefix(svg, []byte("<svg")) || !bytes.HasSuffix(svg, []byte("</svg>")) {
+		t.Errorf("SVG is not a complete document: %.60s...", svg)
+	}
+}
@@ -0,0 +1,104 @@
+// Package zalo sends text messages to followers of a Zalo Official Account.
+package zalo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Zalo OA Open API
+const DefaultBaseURL = "https://openapi.zalo.me"
+
+// maxTextLength is the longest text message Zalo accepts
+const maxTextLength = 2000
+
+// APIError is an error reported by the Zalo API
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("zalo: error %d: %s", e.Code, e.Message)
+}
+
+// Client sends customer service messages as an Official Account.
+// BaseURL can point at a stub server in tests and local development.
+type Client struct {
+	BaseURL     string
+	AccessToken string
+	HTTPClient  *http.Client
+}
+
+// NewClient creates a client for the OA the access token belongs to
+func NewClient(accessToken string) *Client {
+	return &Client{
+		BaseURL:     DefaultBaseURL,
+		AccessToken: accessToken,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type sendRequest struct {
+	Recipient struct {
+		UserID string `json:"user_id"`
+	} `json:"recipient"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+type sendResponse struct {
+	Error   int    `json:"error"`
+	Message string `json:"message"`
+	Data    struct {
+		MessageID string `json:"message_id"`
+	} `json:"data"`
+}
+
+// SendText sends a text message to a follower and returns its message ID.
+// Longer texts are cut to the length Zalo accepts.
+func (c *Client) SendText(ctx context.Context, userID, text string) (string, error) {
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = strings.TrimSpace(string(runes[:maxTextLength-1])) + "…"
+	}
+
+	var req sendRequest
+	req.Recipient.UserID = userID
+	req.Message.Text = text
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(c.BaseURL, "/")+"/v3.0/oa/message/cs", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("access_token", c.AccessToken)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("zalo: send: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &APIError{Code: resp.StatusCode, Message: resp.Status}
+	}
+
+	var result sendResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("zalo: decode response: %w", err)
+	}
+	if result.Error != 0 {
+		return "", &APIError{Code: result.Error, Message: result.Message}
+	}
+	return result.Data.MessageID, nil
+}
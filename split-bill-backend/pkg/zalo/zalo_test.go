@@ -0,0 +1,71 @@
+package zalo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSendText(t *testing.T) {
+	var got sendRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3.0/oa/message/cs" || r.Header.Get("access_token") != "token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"error":0,"message":"Success","data":{"message_id":"m1","user_id":"u1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+
+	id, err := client.SendText(context.Background(), "u1", "Bạn còn nợ 150K₫")
+	if err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if id != "m1" || got.Recipient.UserID != "u1" || got.Message.Text != "Bạn còn nợ 150K₫" {
+		t.Errorf("id = %q, request = %+v", id, got)
+	}
+}
+
+func TestSendTextAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"error":-213,"message":"User has not followed OA"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+
+	_, err := client.SendText(context.Background(), "u1", "hi")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != -213 {
+		t.Errorf("error = %v, want API error -213", err)
+	}
+}
+
+func TestSendTextTruncates(t *testing.T) {
+	var got sendRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"error":0,"data":{"message_id":"m2"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.BaseURL = server.URL
+
+	if _, err := client.SendText(context.Background(), "u1", strings.Repeat("đ", 3000)); err != nil {
+		t.Fatal(err)
+	}
+	if n := utf8.RuneCountInString(got.Message.Text); n != maxTextLength {
+		t.Errorf("sent %d characters, want %d", n, maxTextLength)
+	}
+}
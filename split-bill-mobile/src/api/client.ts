@@ -1,65 +1,70 @@
-import axios, { AxiosInstance, AxiosRequestConfig } from 'axios';
-import { useAuthStore } from '../store/useAuthStore';
-
-const BASE_URL = __DEV__
-  ? 'http://10.0.2.2:8080/api/v1' // Android emulator
-  : 'https://api.splitbill.app/api/v1';
-
-class ApiClient {
-  private client: AxiosInstance;
-
-  constructor() {
-    this.client = axios.create({
-      baseURL: BASE_URL,
-      timeout: 15000,
-      headers: {
-        'Content-Type': 'application/json',
-      },
-    });
-
-    // Request interceptor - add auth token
-    this.client.interceptors.request.use(
-      async (config) => {
-        const token = useAuthStore.getState().token;
-        if (token) {
-          config.headers.Authorization = `Bearer ${token}`;
-        }
-        return config;
-      },
-      (error) => Promise.reject(error),
-    );
-
-    // Response interceptor - handle errors
-    this.client.interceptors.response.use(
-      (response) => response,
-      (error) => {
-        if (error.response?.status === 401) {
-          useAuthStore.getState().logout();
-        }
-        return Promise.reject(error);
-      },
-    );
-  }
-
-  async get<T>(url: string, config?: AxiosRequestConfig) {
-    const response = await this.client.get<T>(url, config);
-    return response.data;
-  }
-
-  async post<T>(url: string, data?: any, config?: AxiosRequestConfig) {
-    const response = await this.client.post<T>(url, data, config);
-    return response.data;
-  }
-
-  async put<T>(url: string, data?: any, config?: AxiosRequestConfig) {
-    const response = await this.client.put<T>(url, data, config);
-    return response.data;
-  }
-
-  async delete<T>(url: string, config?: AxiosRequestConfig) {
-    const response = await this.client.delete<T>(url, config);
-    return response.data;
-  }
-}
-
-export const api = new ApiClient();
+import axios, { AxiosInstance, AxiosRequestConfig } from 'axios';
+import { useAuthStore } from '../store/useAuthStore';
+
+export const BASE_URL = __DEV__
+  ? 'http://10.0.2.2:8080/api/v1' // Android emulator
+  : 'https://api.splitbill.app/api/v1';
+
+const deviceLocale = Intl.DateTimeFormat().resolvedOptions().locale || 'vi-VN';
+
+class ApiClient {
+  private client: AxiosInstance;
+
+  constructor() {
+    this.client = axios.create({
+      baseURL: BASE_URL,
+      timeout: 15000,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+    });
+
+    // Request interceptor - add auth token
+    this.client.interceptors.request.use(
+      async (config) => {
+        const token = useAuthStore.getState().token;
+        if (token) {
+          config.headers.Authorization = `Bearer ${token}`;
+        }
+        // Activities and notifications come back in this language unless
+        // the profile picks one
+        config.headers['Accept-Language'] = deviceLocale;
+        return config;
+      },
+      (error) => Promise.reject(error),
+    );
+
+    // Response interceptor - handle errors
+    this.client.interceptors.response.use(
+      (response) => response,
+      (error) => {
+        if (error.response?.status === 401) {
+          useAuthStore.getState().logout();
+        }
+        return Promise.reject(error);
+      },
+    );
+  }
+
+  async get<T>(url: string, config?: AxiosRequestConfig) {
+    const response = await this.client.get<T>(url, config);
+    return response.data;
+  }
+
+  async post<T>(url: string, data?: any, config?: AxiosRequestConfig) {
+    const response = await this.client.post<T>(url, data, config);
+    return response.data;
+  }
+
+  async put<T>(url: string, data?: any, config?: AxiosRequestConfig) {
+    const response = await this.client.put<T>(url, data, config);
+    return response.data;
+  }
+
+  async delete<T>(url: string, config?: AxiosRequestConfig) {
+    const response = await this.client.delete<T>(url, config);
+    return response.data;
+  }
+}
+
+export const api = new ApiClient();
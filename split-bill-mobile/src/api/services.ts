@@ -1,182 +1,364 @@
-import { api } from './client';
-import {
-  APIResponse,
-  Group,
-  CreateGroupRequest,
-  Bill,
-  CreateBillRequest,
-  Balance,
-  Settlement,
-  Transaction,
-  CreateTransactionRequest,
-  User,
-  OCRResult,
-  ScanReceiptRequest,
-  ScanReceiptBase64Request,
-  ConfirmOCRRequest,
-  ImageUploadResponse,
-  Activity,
-  PaymentDeeplinkRequest,
-  PaymentDeeplinkResponse,
-  VietQRRequest,
-  VietQRResponse,
-  BankInfo,
-  UserPaymentInfo,
-  GroupStats,
-  UserOverallStats,
-  CategoryInfo,
-} from '../types';
-
-// ===== Auth API =====
-export const authAPI = {
-  verifyToken: () => api.post<APIResponse<User>>('/auth/verify-token'),
-
-  getMe: () => api.get<APIResponse<User>>('/auth/me'),
-
-  updateProfile: (data: Partial<User>) =>
-    api.put<APIResponse<User>>('/auth/profile', data),
-};
-
-// ===== Group API =====
-export const groupAPI = {
-  create: (data: CreateGroupRequest) =>
-    api.post<APIResponse<Group>>('/groups', data),
-
-  list: () => api.get<APIResponse<Group[]>>('/groups'),
-
-  getById: (id: string) => api.get<APIResponse<Group>>(`/groups/${id}`),
-
-  update: (id: string, data: Partial<CreateGroupRequest>) =>
-    api.put<APIResponse<Group>>(`/groups/${id}`, data),
-
-  delete: (id: string) => api.delete<APIResponse<null>>(`/groups/${id}`),
-
-  addMember: (groupId: string, userId: string, nickname?: string) =>
-    api.post<APIResponse<null>>(`/groups/${groupId}/members`, {
-      user_id: userId,
-      nickname,
-    }),
-
-  removeMember: (groupId: string, userId: string) =>
-    api.delete<APIResponse<null>>(`/groups/${groupId}/members/${userId}`),
-
-  join: (inviteCode: string) =>
-    api.post<APIResponse<Group>>('/groups/join', {invite_code: inviteCode}),
-};
-
-// ===== Bill API =====
-export const billAPI = {
-  create: (groupId: string, data: CreateBillRequest) =>
-    api.post<APIResponse<Bill>>(`/groups/${groupId}/bills`, data),
-
-  listByGroup: (groupId: string) =>
-    api.get<APIResponse<Bill[]>>(`/groups/${groupId}/bills`),
-
-  getById: (id: string) => api.get<APIResponse<Bill>>(`/bills/${id}`),
-
-  update: (id: string, data: Partial<Bill>) =>
-    api.put<APIResponse<Bill>>(`/bills/${id}`, data),
-
-  delete: (id: string) => api.delete<APIResponse<null>>(`/bills/${id}`),
-
-  getBalances: (groupId: string) =>
-    api.get<APIResponse<Balance[]>>(`/groups/${groupId}/balances`),
-
-  getSettlements: (groupId: string) =>
-    api.get<APIResponse<Settlement[]>>(`/groups/${groupId}/settlements`),
-};
-
-// ===== Transaction API =====
-export const transactionAPI = {
-  create: (data: CreateTransactionRequest) =>
-    api.post<APIResponse<Transaction>>('/transactions', data),
-
-  confirm: (id: string) =>
-    api.put<APIResponse<null>>(`/transactions/${id}/confirm`),
-
-  getMyDebts: () =>
-    api.get<APIResponse<Transaction[]>>('/users/me/debts'),
-};
-
-// ===== OCR API (Phase 2) =====
-export const ocrAPI = {
-  scanReceipt: (data: ScanReceiptRequest) =>
-    api.post<APIResponse<OCRResult>>('/ocr/scan', data),
-
-  scanReceiptBase64: (data: ScanReceiptBase64Request) =>
-    api.post<APIResponse<OCRResult>>('/ocr/scan-base64', data),
-
-  getResult: (id: string) =>
-    api.get<APIResponse<OCRResult>>(`/ocr/${id}/result`),
-
-  confirm: (id: string, data: ConfirmOCRRequest) =>
-    api.post<APIResponse<Bill>>(`/ocr/${id}/confirm`, data),
-
-  getPending: () =>
-    api.get<APIResponse<OCRResult[]>>('/ocr/pending'),
-};
-
-// ===== Image Upload API (Phase 2) =====
-export const uploadAPI = {
-  uploadImage: (formData: any) =>
-    api.post<APIResponse<ImageUploadResponse>>('/upload/image', formData, {
-      headers: { 'Content-Type': 'multipart/form-data' },
-    }),
-
-  uploadBase64: (image: string, fileName?: string) =>
-    api.post<APIResponse<ImageUploadResponse>>('/upload/image-base64', {
-      image,
-      file_name: fileName,
-    }),
-};
-
-// ===== Payment API (Phase 4) =====
-export const paymentAPI = {
-  generateDeeplink: (data: PaymentDeeplinkRequest) =>
-    api.post<APIResponse<PaymentDeeplinkResponse>>('/payment/deeplink', data),
-
-  generateVietQR: (data: VietQRRequest) =>
-    api.post<APIResponse<VietQRResponse>>('/payment/vietqr', data),
-
-  getUserPaymentInfo: (userId: string) =>
-    api.get<APIResponse<UserPaymentInfo>>(`/payment/user/${userId}`),
-
-  getSupportedBanks: () =>
-    api.get<APIResponse<BankInfo[]>>('/payment/banks'),
-};
-
-// ===== Activity API (Phase 4) =====
-export const activityAPI = {
-  getGroupActivities: (groupId: string, limit?: number) =>
-    api.get<APIResponse<Activity[]>>(
-      `/groups/${groupId}/activities${limit ? `?limit=${limit}` : ''}`,
-    ),
-
-  getMyActivities: (limit?: number) =>
-    api.get<APIResponse<Activity[]>>(
-      `/activities/me${limit ? `?limit=${limit}` : ''}`,
-    ),
-};
-
-// ===== Stats API (Phase 5) =====
-export const statsAPI = {
-  getGroupStats: (groupId: string) =>
-    api.get<APIResponse<GroupStats>>(`/groups/${groupId}/stats`),
-
-  getGroupCategoryStats: (groupId: string) =>
-    api.get<APIResponse<{categories: any[]; monthly_trend: any[]; total_spent: number}>>(
-      `/groups/${groupId}/stats/categories`,
-    ),
-
-  getUserStats: () =>
-    api.get<APIResponse<UserOverallStats>>('/stats/me'),
-
-  exportGroupSummary: (groupId: string, format?: string) =>
-    api.get<any>(
-      `/groups/${groupId}/export${format ? `?format=${format}` : ''}`,
-      { responseType: format === 'text' ? ('text' as any) : 'json' },
-    ),
-
-  getCategories: () =>
-    api.get<APIResponse<CategoryInfo[]>>('/categories'),
-};
+import { api, BASE_URL } from './client';
+import {
+  APIResponse,
+  Group,
+  GroupMember,
+  CreateGroupRequest,
+  UpdateGroupRequest,
+  PermissionMatrix,
+  InviteLink,
+  InviteLinkWithJoins,
+  CreateInviteLinkRequest,
+  JoinRequest,
+  JoinRequestStatus,
+  Bill,
+  CreateBillRequest,
+  Balance,
+  Settlement,
+  SettlementPayment,
+  ReminderSettings,
+  ReminderDelivery,
+  Digest,
+  DigestFrequency,
+  Transaction,
+  TransactionListParams,
+  Paginated,
+  CursorPage,
+  CursorParams,
+  GroupWebhook,
+  CreateWebhookRequest,
+  UpdateWebhookRequest,
+  WebhookDelivery,
+  WebhookDeliveryStatus,
+  AppNotification,
+  NotificationListParams,
+  CreateTransactionRequest,
+  User,
+  Device,
+  RegisterDeviceRequest,
+  OCRResult,
+  ScanReceiptRequest,
+  ScanReceiptBase64Request,
+  ConfirmOCRRequest,
+  ImageUploadResponse,
+  Activity,
+  ActivityQuery,
+  PaymentDeeplinkRequest,
+  PaymentDeeplinkResponse,
+  VietQRRequest,
+  VietQRResponse,
+  BankInfo,
+  UserPaymentInfo,
+  GroupStats,
+  UserOverallStats,
+  CategoryInfo,
+  StatementImport,
+  StatementImportResult,
+  StatementLine,
+  StatementLineStatus,
+  PaymentReferenceLookup,
+} from '../types';
+
+// ===== Auth API =====
+export const authAPI = {
+  verifyToken: () => api.post<APIResponse<User>>('/auth/verify-token'),
+
+  getMe: () => api.get<APIResponse<User>>('/auth/me'),
+
+  updateProfile: (data: Partial<User>) =>
+    api.put<APIResponse<User>>('/auth/profile', data),
+
+  registerDevice: (data: RegisterDeviceRequest) =>
+    api.post<APIResponse<Device>>('/users/me/devices', data),
+
+  unregisterDevice: (token: string) =>
+    api.delete<APIResponse<null>>(`/users/me/devices/${encodeURIComponent(token)}`),
+};
+
+// ===== Group API =====
+export const groupAPI = {
+  create: (data: CreateGroupRequest) =>
+    api.post<APIResponse<Group>>('/groups', data),
+
+  list: () => api.get<APIResponse<Group[]>>('/groups'),
+
+  getById: (id: string) => api.get<APIResponse<Group>>(`/groups/${id}`),
+
+  update: (id: string, data: UpdateGroupRequest) =>
+    api.put<APIResponse<Group>>(`/groups/${id}`, data),
+
+  delete: (id: string) => api.delete<APIResponse<null>>(`/groups/${id}`),
+
+  addMember: (groupId: string, userId: string, nickname?: string) =>
+    api.post<APIResponse<null>>(`/groups/${groupId}/members`, {
+      user_id: userId,
+      nickname,
+    }),
+
+  addPlaceholder: (groupId: string, name: string) =>
+    api.post<APIResponse<GroupMember>>(`/groups/${groupId}/placeholders`, {
+      name,
+    }),
+
+  removeMember: (groupId: string, userId: string) =>
+    api.delete<APIResponse<null>>(`/groups/${groupId}/members/${userId}`),
+
+  promoteMember: (groupId: string, userId: string) =>
+    api.post<APIResponse<Group>>(`/groups/${groupId}/members/${userId}/promote`),
+
+  demoteMember: (groupId: string, userId: string) =>
+    api.post<APIResponse<Group>>(`/groups/${groupId}/members/${userId}/demote`),
+
+  transferOwnership: (groupId: string, userId: string) =>
+    api.post<APIResponse<Group>>(`/groups/${groupId}/transfer-ownership`, {
+      user_id: userId,
+    }),
+
+  getPermissions: () =>
+    api.get<APIResponse<PermissionMatrix>>('/groups/permissions'),
+
+  // 200 with the group, or 202 with a pending request if the group
+  // requires approval
+  join: (inviteCode: string) =>
+    api.post<APIResponse<Group | JoinRequest>>('/groups/join', {invite_code: inviteCode}),
+
+  getReminderSettings: (groupId: string) =>
+    api.get<APIResponse<ReminderSettings>>(`/groups/${groupId}/reminders`),
+
+  updateReminderSettings: (groupId: string, data: ReminderSettings) =>
+    api.put<APIResponse<ReminderSettings>>(`/groups/${groupId}/reminders`, data),
+
+  snoozeReminders: (groupId: string, days: number) =>
+    api.put<APIResponse<{snoozed_until: string | null}>>(`/groups/${groupId}/reminders/snooze`, {days}),
+
+  remindMember: (groupId: string, userId: string) =>
+    api.post<APIResponse<ReminderDelivery>>(`/groups/${groupId}/members/${userId}/remind`),
+
+  // Server-sent events URL; open it with the auth header (see GroupEvent)
+  eventsUrl: (groupId: string) => `${BASE_URL}/groups/${groupId}/events`,
+
+  getDigest: (groupId: string, frequency?: Exclude<DigestFrequency, 'off'>) =>
+    api.get<APIResponse<Digest>>(`/groups/${groupId}/digest`, {params: {frequency}}),
+};
+
+// ===== Invite API =====
+// Joining with a link's code goes through groupAPI.join
+export const inviteAPI = {
+  regenerateCode: (groupId: string) =>
+    api.post<APIResponse<{invite_code: string}>>(`/groups/${groupId}/invite-code/regenerate`),
+
+  list: (groupId: string) =>
+    api.get<APIResponse<InviteLinkWithJoins[]>>(`/groups/${groupId}/invites`),
+
+  create: (groupId: string, data: CreateInviteLinkRequest) =>
+    api.post<APIResponse<InviteLink>>(`/groups/${groupId}/invites`, data),
+
+  revoke: (groupId: string, inviteId: string) =>
+    api.delete<APIResponse<null>>(`/groups/${groupId}/invites/${inviteId}`),
+
+  // Join requests to groups that require approval
+  myJoinRequests: () =>
+    api.get<APIResponse<JoinRequest[]>>('/groups/join-requests'),
+
+  listJoinRequests: (groupId: string, status?: JoinRequestStatus) =>
+    api.get<APIResponse<JoinRequest[]>>(`/groups/${groupId}/join-requests`, {params: {status}}),
+
+  approveJoinRequest: (groupId: string, requestId: string) =>
+    api.post<APIResponse<JoinRequest>>(`/groups/${groupId}/join-requests/${requestId}/approve`),
+
+  denyJoinRequest: (groupId: string, requestId: string) =>
+    api.post<APIResponse<JoinRequest>>(`/groups/${groupId}/join-requests/${requestId}/deny`),
+};
+
+// ===== Group Webhook API (admins only) =====
+export const webhookAPI = {
+  list: (groupId: string) =>
+    api.get<APIResponse<GroupWebhook[]>>(`/groups/${groupId}/webhooks`),
+
+  create: (groupId: string, data: CreateWebhookRequest) =>
+    api.post<APIResponse<GroupWebhook>>(`/groups/${groupId}/webhooks`, data),
+
+  update: (groupId: string, webhookId: string, data: UpdateWebhookRequest) =>
+    api.put<APIResponse<GroupWebhook>>(`/groups/${groupId}/webhooks/${webhookId}`, data),
+
+  delete: (groupId: string, webhookId: string) =>
+    api.delete<APIResponse<null>>(`/groups/${groupId}/webhooks/${webhookId}`),
+
+  test: (groupId: string, webhookId: string) =>
+    api.post<APIResponse<WebhookDelivery>>(`/groups/${groupId}/webhooks/${webhookId}/test`),
+
+  deliveries: (groupId: string, webhookId: string, params?: CursorParams & {status?: WebhookDeliveryStatus}) =>
+    api.get<APIResponse<CursorPage<WebhookDelivery>>>(`/groups/${groupId}/webhooks/${webhookId}/deliveries`, {params}),
+
+  redeliver: (groupId: string, webhookId: string, deliveryId: string) =>
+    api.post<APIResponse<WebhookDelivery>>(
+      `/groups/${groupId}/webhooks/${webhookId}/deliveries/${deliveryId}/redeliver`,
+    ),
+};
+
+// ===== Bill API =====
+export const billAPI = {
+  create: (groupId: string, data: CreateBillRequest) =>
+    api.post<APIResponse<Bill>>(`/groups/${groupId}/bills`, data),
+
+  listByGroup: (groupId: string) =>
+    api.get<APIResponse<Bill[]>>(`/groups/${groupId}/bills`),
+
+  getById: (id: string) => api.get<APIResponse<Bill>>(`/bills/${id}`),
+
+  update: (id: string, data: Partial<Bill>) =>
+    api.put<APIResponse<Bill>>(`/bills/${id}`, data),
+
+  delete: (id: string) => api.delete<APIResponse<null>>(`/bills/${id}`),
+
+  getBalances: (groupId: string) =>
+    api.get<APIResponse<Balance[]>>(`/groups/${groupId}/balances`),
+
+  getSettlements: (groupId: string) =>
+    api.get<APIResponse<Settlement[]>>(`/groups/${groupId}/settlements`),
+
+  getSettlementPayment: (groupId: string, toUserId: string) =>
+    api.get<APIResponse<SettlementPayment>>(`/groups/${groupId}/settlements/${toUserId}/pay`),
+
+  getTransactions: (groupId: string, params?: TransactionListParams) =>
+    api.get<APIResponse<Paginated<Transaction>>>(`/groups/${groupId}/transactions`, { params }),
+};
+
+// ===== Transaction API =====
+export const transactionAPI = {
+  create: (data: CreateTransactionRequest) =>
+    api.post<APIResponse<Transaction>>('/transactions', data),
+
+  confirm: (id: string) =>
+    api.put<APIResponse<null>>(`/transactions/${id}/confirm`),
+
+  getMyDebts: (params?: TransactionListParams) =>
+    api.get<APIResponse<Paginated<Transaction>>>('/users/me/debts', { params }),
+};
+
+// ===== Bank Statement API =====
+export const statementAPI = {
+  import: (formData: any) =>
+    api.post<APIResponse<StatementImportResult>>('/statements/import', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    }),
+
+  listImports: (page?: number) =>
+    api.get<APIResponse<Paginated<StatementImport>>>('/statements/imports', {
+      params: { page },
+    }),
+
+  listLines: (status: StatementLineStatus = 'unmatched', page?: number) =>
+    api.get<APIResponse<Paginated<StatementLine>>>('/statements/lines', {
+      params: { status, page },
+    }),
+
+  linkLine: (lineId: string, transactionId: string) =>
+    api.post<APIResponse<StatementLine>>(`/statements/lines/${lineId}/link`, {
+      transaction_id: transactionId,
+    }),
+
+  ignoreLine: (lineId: string) =>
+    api.put<APIResponse<StatementLine>>(`/statements/lines/${lineId}/ignore`),
+};
+
+// ===== OCR API (Phase 2) =====
+export const ocrAPI = {
+  scanReceipt: (data: ScanReceiptRequest) =>
+    api.post<APIResponse<OCRResult>>('/ocr/scan', data),
+
+  scanReceiptBase64: (data: ScanReceiptBase64Request) =>
+    api.post<APIResponse<OCRResult>>('/ocr/scan-base64', data),
+
+  getResult: (id: string) =>
+    api.get<APIResponse<OCRResult>>(`/ocr/${id}/result`),
+
+  confirm: (id: string, data: ConfirmOCRRequest) =>
+    api.post<APIResponse<Bill>>(`/ocr/${id}/confirm`, data),
+
+  getPending: () =>
+    api.get<APIResponse<OCRResult[]>>('/ocr/pending'),
+};
+
+// ===== Image Upload API (Phase 2) =====
+export const uploadAPI = {
+  uploadImage: (formData: any) =>
+    api.post<APIResponse<ImageUploadResponse>>('/upload/image', formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    }),
+
+  uploadBase64: (image: string, fileName?: string) =>
+    api.post<APIResponse<ImageUploadResponse>>('/upload/image-base64', {
+      image,
+      file_name: fileName,
+    }),
+};
+
+// ===== Payment API (Phase 4) =====
+export const paymentAPI = {
+  generateDeeplink: (data: PaymentDeeplinkRequest) =>
+    api.post<APIResponse<PaymentDeeplinkResponse>>('/payment/deeplink', data),
+
+  generateVietQR: (data: VietQRRequest) =>
+    api.post<APIResponse<VietQRResponse>>('/payment/vietqr', data),
+
+  getUserPaymentInfo: (userId: string) =>
+    api.get<APIResponse<UserPaymentInfo>>(`/payment/user/${userId}`),
+
+  getSupportedBanks: () =>
+    api.get<APIResponse<BankInfo[]>>('/payment/banks'),
+
+  lookupReference: (code: string) =>
+    api.get<APIResponse<PaymentReferenceLookup>>(`/payment/references/${code}`),
+};
+
+// ===== Notification API =====
+export const notificationAPI = {
+  list: (params?: NotificationListParams) =>
+    api.get<APIResponse<CursorPage<AppNotification>>>('/notifications', { params }),
+
+  getUnreadCount: () =>
+    api.get<APIResponse<{unread: number}>>('/notifications/unread-count'),
+
+  markRead: (id: string) =>
+    api.put<APIResponse<AppNotification>>(`/notifications/${id}/read`),
+
+  markAllRead: () =>
+    api.put<APIResponse<{updated: number}>>('/notifications/read-all'),
+};
+
+// ===== Activity API (Phase 4) =====
+export const activityAPI = {
+  getGroupActivities: (groupId: string, params?: ActivityQuery) =>
+    api.get<APIResponse<CursorPage<Activity>>>(`/groups/${groupId}/activities`, {params}),
+
+  getMyActivities: (params?: ActivityQuery) =>
+    api.get<APIResponse<CursorPage<Activity>>>('/activities/me', {params}),
+
+  // Only what the current user did
+  getOwnActivities: (params?: Omit<ActivityQuery, 'actor'>) =>
+    api.get<APIResponse<CursorPage<Activity>>>('/activities/me/own', {params}),
+};
+
+// ===== Stats API (Phase 5) =====
+export const statsAPI = {
+  getGroupStats: (groupId: string) =>
+    api.get<APIResponse<GroupStats>>(`/groups/${groupId}/stats`),
+
+  getGroupCategoryStats: (groupId: string) =>
+    api.get<APIResponse<{categories: any[]; monthly_trend: any[]; total_spent: number}>>(
+      `/groups/${groupId}/stats/categories`,
+    ),
+
+  getUserStats: () =>
+    api.get<APIResponse<UserOverallStats>>('/stats/me'),
+
+  exportGroupSummary: (groupId: string, format?: string) =>
+    api.get<any>(
+      `/groups/${groupId}/export${format ? `?format=${format}` : ''}`,
+      { responseType: format === 'text' ? ('text' as any) : 'json' },
+    ),
+
+  getCategories: () =>
+    api.get<APIResponse<CategoryInfo[]>>('/categories'),
+};
@@ -1,213 +1,213 @@
-import React from 'react';
-import {
-  TouchableOpacity,
-  Text,
-  StyleSheet,
-  ActivityIndicator,
-  ViewStyle,
-  TextStyle,
-} from 'react-native';
-import {colors, spacing, borderRadius, fontSize} from '../../theme';
-
-interface ButtonProps {
-  title: string;
-  onPress: () => void;
-  variant?: 'primary' | 'secondary' | 'outline' | 'danger' | 'ghost';
-  size?: 'sm' | 'md' | 'lg';
-  loading?: boolean;
-  disabled?: boolean;
-  icon?: React.ReactNode;
-  style?: ViewStyle;
-  textStyle?: TextStyle;
-  fullWidth?: boolean;
-}
-
-const Button: React.FC<ButtonProps> = ({
-  title,
-  onPress,
-  variant = 'primary',
-  size = 'md',
-  loading = false,
-  disabled = false,
-  icon,
-  style,
-  textStyle,
-  fullWidth = true,
-}) => {
-  const getContainerStyle = (): ViewStyle[] => {
-    const base: ViewStyle[] = [styles.base];
-
-    // Size
-    switch (size) {
-      case 'sm':
-        base.push(styles.sizeSm);
-        break;
-      case 'lg':
-        base.push(styles.sizeLg);
-        break;
-      default:
-        base.push(styles.sizeMd);
-    }
-
-    // Variant
-    switch (variant) {
-      case 'secondary':
-        base.push(styles.secondary);
-        break;
-      case 'outline':
-        base.push(styles.outline);
-        break;
-      case 'danger':
-        base.push(styles.danger);
-        break;
-      case 'ghost':
-        base.push(styles.ghost);
-        break;
-      default:
-        base.push(styles.primary);
-    }
-
-    if (fullWidth) {
-      base.push(styles.fullWidth);
-    }
-
-    if (disabled || loading) {
-      base.push(styles.disabled);
-    }
-
-    if (style) {
-      base.push(style);
-    }
-
-    return base;
-  };
-
-  const getTextStyle = (): TextStyle[] => {
-    const base: TextStyle[] = [styles.text];
-
-    switch (size) {
-      case 'sm':
-        base.push(styles.textSm);
-        break;
-      case 'lg':
-        base.push(styles.textLg);
-        break;
-      default:
-        base.push(styles.textMd);
-    }
-
-    switch (variant) {
-      case 'outline':
-        base.push(styles.textOutline);
-        break;
-      case 'ghost':
-        base.push(styles.textGhost);
-        break;
-      case 'secondary':
-        base.push(styles.textSecondary);
-        break;
-      default:
-        base.push(styles.textPrimary);
-    }
-
-    if (textStyle) {
-      base.push(textStyle);
-    }
-
-    return base;
-  };
-
-  return (
-    <TouchableOpacity
-      style={getContainerStyle()}
-      onPress={onPress}
-      disabled={disabled || loading}
-      activeOpacity={0.7}>
-      {loading ? (
-        <ActivityIndicator
-          size="small"
-          color={variant === 'outline' || variant === 'ghost' ? colors.primary : '#FFFFFF'}
-        />
-      ) : (
-        <>
-          {icon}
-          <Text style={getTextStyle()}>{title}</Text>
-        </>
-      )}
-    </TouchableOpacity>
-  );
-};
-
-const styles = StyleSheet.create({
-  base: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    justifyContent: 'center',
-    gap: spacing.sm,
-    borderRadius: borderRadius.md,
-  },
-  fullWidth: {
-    width: '100%',
-  },
-  // Sizes
-  sizeSm: {
-    paddingVertical: spacing.xs,
-    paddingHorizontal: spacing.md,
-  },
-  sizeMd: {
-    paddingVertical: spacing.sm + 2,
-    paddingHorizontal: spacing.lg,
-  },
-  sizeLg: {
-    paddingVertical: spacing.md,
-    paddingHorizontal: spacing.xl,
-  },
-  // Variants
-  primary: {
-    backgroundColor: colors.primary,
-  },
-  secondary: {
-    backgroundColor: colors.primaryLight,
-  },
-  outline: {
-    backgroundColor: 'transparent',
-    borderWidth: 1.5,
-    borderColor: colors.primary,
-  },
-  danger: {
-    backgroundColor: colors.error,
-  },
-  ghost: {
-    backgroundColor: 'transparent',
-  },
-  disabled: {
-    opacity: 0.5,
-  },
-  // Text
-  text: {
-    fontWeight: '600',
-  },
-  textSm: {
-    fontSize: fontSize.sm,
-  },
-  textMd: {
-    fontSize: fontSize.md,
-  },
-  textLg: {
-    fontSize: fontSize.lg,
-  },
-  textPrimary: {
-    color: '#FFFFFF',
-  },
-  textSecondary: {
-    color: colors.primary,
-  },
-  textOutline: {
-    color: colors.primary,
-  },
-  textGhost: {
-    color: colors.primary,
-  },
-});
-
-export default Button;
+import React from 'react';
+import {
+  TouchableOpacity,
+  Text,
+  StyleSheet,
+  ActivityIndicator,
+  ViewStyle,
+  TextStyle,
+} from 'react-native';
+import {colors, spacing, borderRadius, fontSize} from '../../theme';
+
+interface ButtonProps {
+  title: string;
+  onPress: () => void;
+  variant?: 'primary' | 'secondary' | 'outline' | 'danger' | 'ghost';
+  size?: 'sm' | 'md' | 'lg';
+  loading?: boolean;
+  disabled?: boolean;
+  icon?: React.ReactNode;
+  style?: ViewStyle;
+  textStyle?: TextStyle;
+  fullWidth?: boolean;
+}
+
+const Button: React.FC<ButtonProps> = ({
+  title,
+  onPress,
+  variant = 'primary',
+  size = 'md',
+  loading = false,
+  disabled = false,
+  icon,
+  style,
+  textStyle,
+  fullWidth = true,
+}) => {
+  const getContainerStyle = (): ViewStyle[] => {
+    const base: ViewStyle[] = [styles.base];
+
+    // Size
+    switch (size) {
+      case 'sm':
+        base.push(styles.sizeSm);
+        break;
+      case 'lg':
+        base.push(styles.sizeLg);
+        break;
+      default:
+        base.push(styles.sizeMd);
+    }
+
+    // Variant
+    switch (variant) {
+      case 'secondary':
+        base.push(styles.secondary);
+        break;
+      case 'outline':
+        base.push(styles.outline);
+        break;
+      case 'danger':
+        base.push(styles.danger);
+        break;
+      case 'ghost':
+        base.push(styles.ghost);
+        break;
+      default:
+        base.push(styles.primary);
+    }
+
+    if (fullWidth) {
+      base.push(styles.fullWidth);
+    }
+
+    if (disabled || loading) {
+      base.push(styles.disabled);
+    }
+
+    if (style) {
+      base.push(style);
+    }
+
+    return base;
+  };
+
+  const getTextStyle = (): TextStyle[] => {
+    const base: TextStyle[] = [styles.text];
+
+    switch (size) {
+      case 'sm':
+        base.push(styles.textSm);
+        break;
+      case 'lg':
+        base.push(styles.textLg);
+        break;
+      default:
+        base.push(styles.textMd);
+    }
+
+    switch (variant) {
+      case 'outline':
+        base.push(styles.textOutline);
+        break;
+      case 'ghost':
+        base.push(styles.textGhost);
+        break;
+      case 'secondary':
+        base.push(styles.textSecondary);
+        break;
+      default:
+        base.push(styles.textPrimary);
+    }
+
+    if (textStyle) {
+      base.push(textStyle);
+    }
+
+    return base;
+  };
+
+  return (
+    <TouchableOpacity
+      style={getContainerStyle()}
+      onPress={onPress}
+      disabled={disabled || loading}
+      activeOpacity={0.7}>
+      {loading ? (
+        <ActivityIndicator
+          size="small"
+          color={variant === 'outline' || variant === 'ghost' ? colors.primary : '#FFFFFF'}
+        />
+      ) : (
+        <>
+          {icon}
+          <Text style={getTextStyle()}>{title}</Text>
+        </>
+      )}
+    </TouchableOpacity>
+  );
+};
+
+const styles = StyleSheet.create({
+  base: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    justifyContent: 'center',
+    gap: spacing.sm,
+    borderRadius: borderRadius.md,
+  },
+  fullWidth: {
+    width: '100%',
+  },
+  // Sizes
+  sizeSm: {
+    paddingVertical: spacing.xs,
+    paddingHorizontal: spacing.md,
+  },
+  sizeMd: {
+    paddingVertical: spacing.sm + 2,
+    paddingHorizontal: spacing.lg,
+  },
+  sizeLg: {
+    paddingVertical: spacing.md,
+    paddingHorizontal: spacing.xl,
+  },
+  // Variants
+  primary: {
+    backgroundColor: colors.primary,
+  },
+  secondary: {
+    backgroundColor: colors.primaryLight,
+  },
+  outline: {
+    backgroundColor: 'transparent',
+    borderWidth: 1.5,
+    borderColor: colors.primary,
+  },
+  danger: {
+    backgroundColor: colors.error,
+  },
+  ghost: {
+    backgroundColor: 'transparent',
+  },
+  disabled: {
+    opacity: 0.5,
+  },
+  // Text
+  text: {
+    fontWeight: '600',
+  },
+  textSm: {
+    fontSize: fontSize.sm,
+  },
+  textMd: {
+    fontSize: fontSize.md,
+  },
+  textLg: {
+    fontSize: fontSize.lg,
+  },
+  textPrimary: {
+    color: '#FFFFFF',
+  },
+  textSecondary: {
+    color: colors.primary,
+  },
+  textOutline: {
+    color: colors.primary,
+  },
+  textGhost: {
+    color: colors.primary,
+  },
+});
+
+export default Button;
@@ -1,76 +1,76 @@
-import React from 'react';
-import {View, Text, StyleSheet} from 'react-native';
-import Icon from 'react-native-vector-icons/MaterialCommunityIcons';
-import {colors, spacing, fontSize} from '../../theme';
-import Button from './Button';
-
-interface EmptyStateProps {
-  icon: string;
-  title: string;
-  subtitle?: string;
-  actionLabel?: string;
-  onAction?: () => void;
-}
-
-const EmptyState: React.FC<EmptyStateProps> = ({
-  icon,
-  title,
-  subtitle,
-  actionLabel,
-  onAction,
-}) => {
-  return (
-    <View style={styles.container}>
-      <View style={styles.iconContainer}>
-        <Icon name={icon} size={56} color={colors.textSecondary} />
-      </View>
-      <Text style={styles.title}>{title}</Text>
-      {subtitle && <Text style={styles.subtitle}>{subtitle}</Text>}
-      {actionLabel && onAction && (
-        <Button
-          title={actionLabel}
-          onPress={onAction}
-          variant="outline"
-          size="sm"
-          fullWidth={false}
-          style={styles.button}
-        />
-      )}
-    </View>
-  );
-};
-
-const styles = StyleSheet.create({
-  container: {
-    alignItems: 'center',
-    paddingVertical: spacing.xxl,
-    paddingHorizontal: spacing.lg,
-  },
-  iconContainer: {
-    width: 100,
-    height: 100,
-    borderRadius: 50,
-    backgroundColor: `${colors.textSecondary}10`,
-    justifyContent: 'center',
-    alignItems: 'center',
-    marginBottom: spacing.lg,
-  },
-  title: {
-    fontSize: fontSize.lg,
-    fontWeight: '700',
-    color: colors.text,
-    textAlign: 'center',
-  },
-  subtitle: {
-    fontSize: fontSize.sm,
-    color: colors.textSecondary,
-    textAlign: 'center',
-    marginTop: spacing.xs,
-    lineHeight: 20,
-  },
-  button: {
-    marginTop: spacing.lg,
-  },
-});
-
-export default EmptyState;
+import React from 'react';
+import {View, Text, StyleSheet} from 'react-native';
+import Icon from 'react-native-vector-icons/MaterialCommunityIcons';
+import {colors, spacing, fontSize} from '../../theme';
+import Button from './Button';
+
+interface EmptyStateProps {
+  icon: string;
+  title: string;
+  subtitle?: string;
+  actionLabel?: string;
+  onAction?: () => void;
+}
+
+const EmptyState: React.FC<EmptyStateProps> = ({
+  icon,
+  title,
+  subtitle,
+  actionLabel,
+  onAction,
+}) => {
+  return (
+    <View style={styles.container}>
+      <View style={styles.iconContainer}>
+        <Icon name={icon} size={56} color={colors.textSecondary} />
+      </View>
+      <Text style={styles.title}>{title}</Text>
+      {subtitle && <Text style={styles.subtitle}>{subtitle}</Text>}
+      {actionLabel && onAction && (
+        <Button
+          title={actionLabel}
+          onPress={onAction}
+          variant="outline"
+          size="sm"
+          fullWidth={false}
+          style={styles.button}
+        />
+      )}
+    </View>
+  );
+};
+
+const styles = StyleSheet.create({
+  container: {
+    alignItems: 'center',
+    paddingVertical: spacing.xxl,
+    paddingHorizontal: spacing.lg,
+  },
+  iconContainer: {
+    width: 100,
+    height: 100,
+    borderRadius: 50,
+    backgroundColor: `${colors.textSecondary}10`,
+    justifyContent: 'center',
+    alignItems: 'center',
+    marginBottom: spacing.lg,
+  },
+  title: {
+    fontSize: fontSize.lg,
+    fontWeight: '700',
+    color: colors.text,
+    textAlign: 'center',
+  },
+  subtitle: {
+    fontSize: fontSize.sm,
+    color: colors.textSecondary,
+    textAlign: 'center',
+    marginTop: spacing.xs,
+    lineHeight: 20,
+  },
+  button: {
+    marginTop: spacing.lg,
+  },
+});
+
+export default EmptyState;
@@ -1,55 +1,55 @@
-import React from 'react';
-import {View, Text, StyleSheet, ActivityIndicator, Modal} from 'react-native';
-import {colors, fontSize, spacing} from '../../theme';
-
-interface LoadingOverlayProps {
-  visible: boolean;
-  message?: string;
-}
-
-const LoadingOverlay: React.FC<LoadingOverlayProps> = ({
-  visible,
-  message = 'Loading...',
-}) => {
-  if (!visible) return null;
-
-  return (
-    <Modal transparent animationType="fade" visible={visible}>
-      <View style={styles.overlay}>
-        <View style={styles.container}>
-          <ActivityIndicator size="large" color={colors.primary} />
-          <Text style={styles.message}>{message}</Text>
-        </View>
-      </View>
-    </Modal>
-  );
-};
-
-const styles = StyleSheet.create({
-  overlay: {
-    flex: 1,
-    backgroundColor: 'rgba(0,0,0,0.4)',
-    justifyContent: 'center',
-    alignItems: 'center',
-  },
-  container: {
-    backgroundColor: colors.surface,
-    padding: spacing.xl,
-    borderRadius: 16,
-    alignItems: 'center',
-    minWidth: 150,
-    elevation: 5,
-    shadowColor: '#000',
-    shadowOffset: {width: 0, height: 2},
-    shadowOpacity: 0.25,
-    shadowRadius: 8,
-  },
-  message: {
-    marginTop: spacing.md,
-    fontSize: fontSize.md,
-    color: colors.text,
-    fontWeight: '500',
-  },
-});
-
-export default LoadingOverlay;
+import React from 'react';
+import {View, Text, StyleSheet, ActivityIndicator, Modal} from 'react-native';
+import {colors, fontSize, spacing} from '../../theme';
+
+interface LoadingOverlayProps {
+  visible: boolean;
+  message?: string;
+}
+
+const LoadingOverlay: React.FC<LoadingOverlayProps> = ({
+  visible,
+  message = 'Loading...',
+}) => {
+  if (!visible) return null;
+
+  return (
+    <Modal transparent animationType="fade" visible={visible}>
+      <View style={styles.overlay}>
+        <View style={styles.container}>
+          <ActivityIndicator size="large" color={colors.primary} />
+          <Text style={styles.message}>{message}</Text>
+        </View>
+      </View>
+    </Modal>
+  );
+};
+
+const styles = StyleSheet.create({
+  overlay: {
+    flex: 1,
+    backgroundColor: 'rgba(0,0,0,0.4)',
+    justifyContent: 'center',
+    alignItems: 'center',
+  },
+  container: {
+    backgroundColor: colors.surface,
+    padding: spacing.xl,
+    borderRadius: 16,
+    alignItems: 'center',
+    minWidth: 150,
+    elevation: 5,
+    shadowColor: '#000',
+    shadowOffset: {width: 0, height: 2},
+    shadowOpacity: 0.25,
+    shadowRadius: 8,
+  },
+  message: {
+    marginTop: spacing.md,
+    fontSize: fontSize.md,
+    color: colors.text,
+    fontWeight: '500',
+  },
+});
+
+export default LoadingOverlay;
@@ -1,3 +1,3 @@
-export {default as Button} from './common/Button';
-export {default as LoadingOverlay} from './common/LoadingOverlay';
-export {default as EmptyState} from './common/EmptyState';
+export {default as Button} from './common/Button';
+export {default as LoadingOverlay} from './common/LoadingOverlay';
+export {default as EmptyState} from './common/EmptyState';
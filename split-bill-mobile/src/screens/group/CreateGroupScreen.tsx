@@ -1,108 +1,108 @@
-import React, {useState} from 'react';
-import {
-  View,
-  Text,
-  TextInput,
-  TouchableOpacity,
-  StyleSheet,
-  SafeAreaView,
-  Alert,
-} from 'react-native';
-import {useNavigation} from '@react-navigation/native';
-import {colors, spacing, fontSize, borderRadius} from '../../theme';
-import {useGroupStore} from '../../store/useGroupStore';
-
-export default function CreateGroupScreen() {
-  const navigation = useNavigation();
-  const {createGroup, isLoading} = useGroupStore();
-  const [name, setName] = useState('');
-  const [description, setDescription] = useState('');
-
-  const handleCreate = async () => {
-    if (!name.trim()) {
-      Alert.alert('Lỗi', 'Vui lòng nhập tên nhóm');
-      return;
-    }
-    try {
-      await createGroup({name: name.trim(), description: description.trim()});
-      Alert.alert('Thành công', 'Nhóm đã được tạo!');
-      navigation.goBack();
-    } catch (error) {
-      Alert.alert('Lỗi', 'Không thể tạo nhóm. Vui lòng thử lại.');
-    }
-  };
-
-  return (
-    <SafeAreaView style={styles.container}>
-      <View style={styles.form}>
-        <Text style={styles.label}>Tên nhóm *</Text>
-        <TextInput
-          style={styles.input}
-          placeholder="VD: Đi ăn team, Trip Đà Lạt..."
-          value={name}
-          onChangeText={setName}
-          maxLength={100}
-        />
-
-        <Text style={styles.label}>Mô tả (tùy chọn)</Text>
-        <TextInput
-          style={[styles.input, styles.textArea]}
-          placeholder="Mô tả ngắn về nhóm..."
-          value={description}
-          onChangeText={setDescription}
-          multiline
-          numberOfLines={3}
-          maxLength={500}
-        />
-
-        <TouchableOpacity
-          style={[styles.button, isLoading && styles.buttonDisabled]}
-          onPress={handleCreate}
-          disabled={isLoading}>
-          <Text style={styles.buttonText}>
-            {isLoading ? 'Đang tạo...' : 'Tạo Nhóm'}
-          </Text>
-        </TouchableOpacity>
-      </View>
-    </SafeAreaView>
-  );
-}
-
-const styles = StyleSheet.create({
-  container: {flex: 1, backgroundColor: colors.background},
-  form: {padding: spacing.lg},
-  label: {
-    fontSize: fontSize.md,
-    fontWeight: '600',
-    color: colors.text,
-    marginBottom: spacing.sm,
-    marginTop: spacing.md,
-  },
-  input: {
-    backgroundColor: colors.surface,
-    borderRadius: borderRadius.sm,
-    borderWidth: 1,
-    borderColor: colors.border,
-    paddingHorizontal: spacing.md,
-    paddingVertical: spacing.md,
-    fontSize: fontSize.md,
-    color: colors.text,
-  },
-  textArea: {
-    height: 80,
-    textAlignVertical: 'top',
-  },
-  button: {
-    backgroundColor: colors.primary,
-    borderRadius: borderRadius.sm,
-    paddingVertical: spacing.md,
-    alignItems: 'center',
-    marginTop: spacing.xl,
-  },
-  buttonDisabled: {opacity: 0.6},
-  buttonText: {
-    color: colors.textInverse,
-    fontSize: fontSize.lg,
-    fontWeight: '600',
-  },
-});
+import React, {useState} from 'react';
+import {
+  View,
+  Text,
+  TextInput,
+  TouchableOpacity,
+  StyleSheet,
+  SafeAreaView,
+  Alert,
+} from 'react-native';
+import {useNavigation} from '@react-navigation/native';
+import {colors, spacing, fontSize, borderRadius} from '../../theme';
+import {useGroupStore} from '../../store/useGroupStore';
+
+export default function CreateGroupScreen() {
+  const navigation = useNavigation();
+  const {createGroup, isLoading} = useGroupStore();
+  const [name, setName] = useState('');
+  const [description, setDescription] = useState('');
+
+  const handleCreate = async () => {
+    if (!name.trim()) {
+      Alert.alert('Lỗi', 'Vui lòng nhập tên nhóm');
+      return;
+    }
+    try {
+      await createGroup({name: name.trim(), description: description.trim()});
+      Alert.alert('Thành công', 'Nhóm đã được tạo!');
+      navigation.goBack();
+    } catch (error) {
+      Alert.alert('Lỗi', 'Không thể tạo nhóm. Vui lòng thử lại.');
+    }
+  };
+
+  return (
+    <SafeAreaView style={styles.container}>
+      <View style={styles.form}>
+        <Text style={styles.label}>Tên nhóm *</Text>
+        <TextInput
+          style={styles.input}
+          placeholder="VD: Đi ăn team, Trip Đà Lạt..."
+          value={name}
+          onChangeText={setName}
+          maxLength={100}
+        />
+
+        <Text style={styles.label}>Mô tả (tùy chọn)</Text>
+        <TextInput
+          style={[styles.input, styles.textArea]}
+          placeholder="Mô tả ngắn về nhóm..."
+          value={description}
+          onChangeText={setDescription}
+          multiline
+          numberOfLines={3}
+          maxLength={500}
+        />
+
+        <TouchableOpacity
+          style={[styles.button, isLoading && styles.buttonDisabled]}
+          onPress={handleCreate}
+          disabled={isLoading}>
+          <Text style={styles.buttonText}>
+            {isLoading ? 'Đang tạo...' : 'Tạo Nhóm'}
+          </Text>
+        </TouchableOpacity>
+      </View>
+    </SafeAreaView>
+  );
+}
+
+const styles = StyleSheet.create({
+  container: {flex: 1, backgroundColor: colors.background},
+  form: {padding: spacing.lg},
+  label: {
+    fontSize: fontSize.md,
+    fontWeight: '600',
+    color: colors.text,
+    marginBottom: spacing.sm,
+    marginTop: spacing.md,
+  },
+  input: {
+    backgroundColor: colors.surface,
+    borderRadius: borderRadius.sm,
+    borderWidth: 1,
+    borderColor: colors.border,
+    paddingHorizontal: spacing.md,
+    paddingVertical: spacing.md,
+    fontSize: fontSize.md,
+    color: colors.text,
+  },
+  textArea: {
+    height: 80,
+    textAlignVertical: 'top',
+  },
+  button: {
+    backgroundColor: colors.primary,
+    borderRadius: borderRadius.sm,
+    paddingVertical: spacing.md,
+    alignItems: 'center',
+    marginTop: spacing.xl,
+  },
+  buttonDisabled: {opacity: 0.6},
+  buttonText: {
+    color: colors.textInverse,
+    fontSize: fontSize.lg,
+    fontWeight: '600',
+  },
+});
@@ -1,492 +1,492 @@
-import React, {useState} from 'react';
-import {
-  View,
-  Text,
-  StyleSheet,
-  ScrollView,
-  TouchableOpacity,
-  TextInput,
-  Alert,
-  ActivityIndicator,
-  Switch,
-} from 'react-native';
-import Icon from 'react-native-vector-icons/MaterialCommunityIcons';
-import {colors, spacing, borderRadius, fontSize} from '../../theme';
-import {useAuthStore} from '../../store/useAuthStore';
-
-interface ProfileScreenProps {
-  navigation: any;
-}
-
-const ProfileScreen: React.FC<ProfileScreenProps> = ({navigation}) => {
-  const {user, loading, updateProfile, logout} = useAuthStore();
-  const [isEditing, setIsEditing] = useState(false);
-  const [displayName, setDisplayName] = useState(user?.display_name || '');
-  const [bankName, setBankName] = useState(
-    user?.bank_accounts?.[0]?.bank_name || '',
-  );
-  const [accountNumber, setAccountNumber] = useState(
-    user?.bank_accounts?.[0]?.account_number || '',
-  );
-  const [accountName, setAccountName] = useState(
-    user?.bank_accounts?.[0]?.account_name || '',
-  );
-  const [notificationsEnabled, setNotificationsEnabled] = useState(true);
-
-  const handleSave = async () => {
-    if (!displayName.trim()) {
-      Alert.alert('Error', 'Display name is required');
-      return;
-    }
-
-    try {
-      const bankAccounts =
-        bankName.trim() && accountNumber.trim()
-          ? [
-              {
-                bank_name: bankName.trim(),
-                account_number: accountNumber.trim(),
-                account_name: accountName.trim(),
-              },
-            ]
-          : undefined;
-
-      await updateProfile({
-        display_name: displayName.trim(),
-        bank_accounts: bankAccounts,
-      });
-
-      setIsEditing(false);
-      Alert.alert('Success', 'Profile updated successfully!');
-    } catch (error: any) {
-      Alert.alert('Error', error.message || 'Failed to update profile');
-    }
-  };
-
-  const handleLogout = () => {
-    Alert.alert('Logout', 'Are you sure you want to logout?', [
-      {text: 'Cancel', style: 'cancel'},
-      {
-        text: 'Logout',
-        style: 'destructive',
-        onPress: () => logout(),
-      },
-    ]);
-  };
-
-  const getInitials = (): string => {
-    if (user?.display_name) {
-      return user.display_name
-        .split(' ')
-        .map(n => n[0])
-        .join('')
-        .toUpperCase()
-        .slice(0, 2);
-    }
-    return 'U';
-  };
-
-  return (
-    <ScrollView style={styles.container} showsVerticalScrollIndicator={false}>
-      {/* Profile Header */}
-      <View style={styles.headerCard}>
-        <View style={styles.avatarContainer}>
-          <View style={styles.avatar}>
-            <Text style={styles.avatarText}>{getInitials()}</Text>
-          </View>
-          {isEditing && (
-            <TouchableOpacity style={styles.editAvatarButton}>
-              <Icon name="camera" size={16} color="#FFFFFF" />
-            </TouchableOpacity>
-          )}
-        </View>
-        <Text style={styles.userName}>
-          {user?.display_name || 'Set your name'}
-        </Text>
-        <Text style={styles.userPhone}>{user?.phone_number || 'No phone'}</Text>
-        <Text style={styles.userEmail}>{user?.email || ''}</Text>
-
-        {!isEditing && (
-          <TouchableOpacity
-            style={styles.editButton}
-            onPress={() => setIsEditing(true)}>
-            <Icon name="pencil" size={16} color={colors.primary} />
-            <Text style={styles.editButtonText}>Edit Profile</Text>
-          </TouchableOpacity>
-        )}
-      </View>
-
-      {/* Edit Form */}
-      {isEditing && (
-        <View style={styles.sectionCard}>
-          <Text style={styles.sectionTitle}>Personal Information</Text>
-
-          <View style={styles.inputGroup}>
-            <Text style={styles.inputLabel}>Display Name</Text>
-            <TextInput
-              style={styles.input}
-              value={displayName}
-              onChangeText={setDisplayName}
-              placeholder="Enter your name"
-              placeholderTextColor={colors.textSecondary}
-            />
-          </View>
-
-          <Text style={[styles.sectionTitle, {marginTop: spacing.lg}]}>
-            Bank Account (for receiving payments)
-          </Text>
-
-          <View style={styles.inputGroup}>
-            <Text style={styles.inputLabel}>Bank Name</Text>
-            <TextInput
-              style={styles.input}
-              value={bankName}
-              onChangeText={setBankName}
-              placeholder="e.g., Vietcombank, TPBank..."
-              placeholderTextColor={colors.textSecondary}
-            />
-          </View>
-
-          <View style={styles.inputGroup}>
-            <Text style={styles.inputLabel}>Account Number</Text>
-            <TextInput
-              style={styles.input}
-              value={accountNumber}
-              onChangeText={setAccountNumber}
-              placeholder="Enter account number"
-              placeholderTextColor={colors.textSecondary}
-              keyboardType="numeric"
-            />
-          </View>
-
-          <View style={styles.inputGroup}>
-            <Text style={styles.inputLabel}>Account Holder Name</Text>
-            <TextInput
-              style={styles.input}
-              value={accountName}
-              onChangeText={setAccountName}
-              placeholder="Enter account holder name"
-              placeholderTextColor={colors.textSecondary}
-              autoCapitalize="characters"
-            />
-          </View>
-
-          <View style={styles.editActions}>
-            <TouchableOpacity
-              style={styles.cancelButton}
-              onPress={() => {
-                setIsEditing(false);
-                setDisplayName(user?.display_name || '');
-                setBankName(user?.bank_accounts?.[0]?.bank_name || '');
-                setAccountNumber(
-                  user?.bank_accounts?.[0]?.account_number || '',
-                );
-                setAccountName(user?.bank_accounts?.[0]?.account_name || '');
-              }}>
-              <Text style={styles.cancelButtonText}>Cancel</Text>
-            </TouchableOpacity>
-            <TouchableOpacity
-              style={styles.saveButton}
-              onPress={handleSave}
-              disabled={loading}>
-              {loading ? (
-                <ActivityIndicator size="small" color="#FFFFFF" />
-              ) : (
-                <Text style={styles.saveButtonText}>Save Changes</Text>
-              )}
-            </TouchableOpacity>
-          </View>
-        </View>
-      )}
-
-      {/* Settings */}
-      <View style={styles.sectionCard}>
-        <Text style={styles.sectionTitle}>Settings</Text>
-
-        <View style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="bell-outline" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Notifications</Text>
-          </View>
-          <Switch
-            value={notificationsEnabled}
-            onValueChange={setNotificationsEnabled}
-            trackColor={{false: colors.border, true: colors.primaryLight}}
-            thumbColor={notificationsEnabled ? colors.primary : '#f4f3f4'}
-          />
-        </View>
-
-        <TouchableOpacity style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="translate" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Language</Text>
-          </View>
-          <View style={styles.settingRight}>
-            <Text style={styles.settingValue}>Tiếng Việt</Text>
-            <Icon
-              name="chevron-right"
-              size={20}
-              color={colors.textSecondary}
-            />
-          </View>
-        </TouchableOpacity>
-
-        <TouchableOpacity style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="currency-usd" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Currency</Text>
-          </View>
-          <View style={styles.settingRight}>
-            <Text style={styles.settingValue}>VND</Text>
-            <Icon
-              name="chevron-right"
-              size={20}
-              color={colors.textSecondary}
-            />
-          </View>
-        </TouchableOpacity>
-      </View>
-
-      {/* About & Support */}
-      <View style={styles.sectionCard}>
-        <Text style={styles.sectionTitle}>About & Support</Text>
-
-        <TouchableOpacity style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="help-circle-outline" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Help & FAQ</Text>
-          </View>
-          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
-        </TouchableOpacity>
-
-        <TouchableOpacity style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="shield-check-outline" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Privacy Policy</Text>
-          </View>
-          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
-        </TouchableOpacity>
-
-        <TouchableOpacity style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon
-              name="file-document-outline"
-              size={22}
-              color={colors.text}
-            />
-            <Text style={styles.settingLabel}>Terms of Service</Text>
-          </View>
-          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
-        </TouchableOpacity>
-
-        <View style={styles.settingRow}>
-          <View style={styles.settingLeft}>
-            <Icon name="information-outline" size={22} color={colors.text} />
-            <Text style={styles.settingLabel}>Version</Text>
-          </View>
-          <Text style={styles.settingValue}>1.0.0 (MVP)</Text>
-        </View>
-      </View>
-
-      {/* Logout */}
-      <TouchableOpacity style={styles.logoutButton} onPress={handleLogout}>
-        <Icon name="logout" size={20} color={colors.error} />
-        <Text style={styles.logoutText}>Logout</Text>
-      </TouchableOpacity>
-
-      <View style={{height: spacing.xxl}} />
-    </ScrollView>
-  );
-};
-
-const styles = StyleSheet.create({
-  container: {
-    flex: 1,
-    backgroundColor: colors.background,
-  },
-  headerCard: {
-    backgroundColor: colors.primary,
-    paddingTop: spacing.xxl,
-    paddingBottom: spacing.xl,
-    paddingHorizontal: spacing.lg,
-    alignItems: 'center',
-    borderBottomLeftRadius: borderRadius.xl,
-    borderBottomRightRadius: borderRadius.xl,
-  },
-  avatarContainer: {
-    position: 'relative',
-    marginBottom: spacing.md,
-  },
-  avatar: {
-    width: 80,
-    height: 80,
-    borderRadius: 40,
-    backgroundColor: 'rgba(255,255,255,0.2)',
-    justifyContent: 'center',
-    alignItems: 'center',
-    borderWidth: 3,
-    borderColor: 'rgba(255,255,255,0.4)',
-  },
-  avatarText: {
-    fontSize: 28,
-    fontWeight: '700',
-    color: '#FFFFFF',
-  },
-  editAvatarButton: {
-    position: 'absolute',
-    bottom: 0,
-    right: 0,
-    width: 28,
-    height: 28,
-    borderRadius: 14,
-    backgroundColor: colors.secondary,
-    justifyContent: 'center',
-    alignItems: 'center',
-    borderWidth: 2,
-    borderColor: colors.primary,
-  },
-  userName: {
-    fontSize: fontSize.xxl,
-    fontWeight: '700',
-    color: '#FFFFFF',
-  },
-  userPhone: {
-    fontSize: fontSize.md,
-    color: 'rgba(255,255,255,0.8)',
-    marginTop: spacing.xs,
-  },
-  userEmail: {
-    fontSize: fontSize.sm,
-    color: 'rgba(255,255,255,0.6)',
-    marginTop: 2,
-  },
-  editButton: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    gap: spacing.xs,
-    backgroundColor: '#FFFFFF',
-    paddingHorizontal: spacing.md,
-    paddingVertical: spacing.sm,
-    borderRadius: borderRadius.full,
-    marginTop: spacing.md,
-  },
-  editButtonText: {
-    fontSize: fontSize.sm,
-    fontWeight: '600',
-    color: colors.primary,
-  },
-  sectionCard: {
-    backgroundColor: colors.surface,
-    marginHorizontal: spacing.md,
-    marginTop: spacing.md,
-    padding: spacing.lg,
-    borderRadius: borderRadius.lg,
-    elevation: 1,
-    shadowColor: '#000',
-    shadowOffset: {width: 0, height: 1},
-    shadowOpacity: 0.05,
-    shadowRadius: 2,
-  },
-  sectionTitle: {
-    fontSize: fontSize.lg,
-    fontWeight: '700',
-    color: colors.text,
-    marginBottom: spacing.md,
-  },
-  inputGroup: {
-    marginBottom: spacing.md,
-  },
-  inputLabel: {
-    fontSize: fontSize.sm,
-    fontWeight: '500',
-    color: colors.textSecondary,
-    marginBottom: spacing.xs,
-  },
-  input: {
-    backgroundColor: colors.background,
-    borderRadius: borderRadius.md,
-    paddingHorizontal: spacing.md,
-    paddingVertical: spacing.sm,
-    fontSize: fontSize.md,
-    color: colors.text,
-    borderWidth: 1,
-    borderColor: colors.border,
-  },
-  editActions: {
-    flexDirection: 'row',
-    gap: spacing.sm,
-    marginTop: spacing.md,
-  },
-  cancelButton: {
-    flex: 1,
-    alignItems: 'center',
-    paddingVertical: spacing.md,
-    borderRadius: borderRadius.md,
-    backgroundColor: colors.background,
-    borderWidth: 1,
-    borderColor: colors.border,
-  },
-  cancelButtonText: {
-    fontSize: fontSize.md,
-    fontWeight: '600',
-    color: colors.textSecondary,
-  },
-  saveButton: {
-    flex: 1,
-    alignItems: 'center',
-    paddingVertical: spacing.md,
-    borderRadius: borderRadius.md,
-    backgroundColor: colors.primary,
-  },
-  saveButtonText: {
-    fontSize: fontSize.md,
-    fontWeight: '600',
-    color: '#FFFFFF',
-  },
-  settingRow: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    justifyContent: 'space-between',
-    paddingVertical: spacing.sm,
-    borderBottomWidth: 1,
-    borderBottomColor: colors.border,
-  },
-  settingLeft: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    gap: spacing.sm,
-  },
-  settingLabel: {
-    fontSize: fontSize.md,
-    color: colors.text,
-  },
-  settingRight: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    gap: spacing.xs,
-  },
-  settingValue: {
-    fontSize: fontSize.sm,
-    color: colors.textSecondary,
-  },
-  logoutButton: {
-    flexDirection: 'row',
-    alignItems: 'center',
-    justifyContent: 'center',
-    gap: spacing.sm,
-    marginHorizontal: spacing.md,
-    marginTop: spacing.lg,
-    paddingVertical: spacing.md,
-    borderRadius: borderRadius.lg,
-    backgroundColor: colors.surface,
-    borderWidth: 1,
-    borderColor: colors.error,
-  },
-  logoutText: {
-    fontSize: fontSize.md,
-    fontWeight: '600',
-    color: colors.error,
-  },
-});
-
-export default ProfileScreen;
+import React, {useState} from 'react';
+import {
+  View,
+  Text,
+  StyleSheet,
+  ScrollView,
+  TouchableOpacity,
+  TextInput,
+  Alert,
+  ActivityIndicator,
+  Switch,
+} from 'react-native';
+import Icon from 'react-native-vector-icons/MaterialCommunityIcons';
+import {colors, spacing, borderRadius, fontSize} from '../../theme';
+import {useAuthStore} from '../../store/useAuthStore';
+
+interface ProfileScreenProps {
+  navigation: any;
+}
+
+const ProfileScreen: React.FC<ProfileScreenProps> = ({navigation}) => {
+  const {user, loading, updateProfile, logout} = useAuthStore();
+  const [isEditing, setIsEditing] = useState(false);
+  const [displayName, setDisplayName] = useState(user?.display_name || '');
+  const [bankName, setBankName] = useState(
+    user?.bank_accounts?.[0]?.bank_name || '',
+  );
+  const [accountNumber, setAccountNumber] = useState(
+    user?.bank_accounts?.[0]?.account_number || '',
+  );
+  const [accountName, setAccountName] = useState(
+    user?.bank_accounts?.[0]?.account_name || '',
+  );
+  const [notificationsEnabled, setNotificationsEnabled] = useState(true);
+
+  const handleSave = async () => {
+    if (!displayName.trim()) {
+      Alert.alert('Error', 'Display name is required');
+      return;
+    }
+
+    try {
+      const bankAccounts =
+        bankName.trim() && accountNumber.trim()
+          ? [
+              {
+                bank_name: bankName.trim(),
+                account_number: accountNumber.trim(),
+                account_name: accountName.trim(),
+              },
+            ]
+          : undefined;
+
+      await updateProfile({
+        display_name: displayName.trim(),
+        bank_accounts: bankAccounts,
+      });
+
+      setIsEditing(false);
+      Alert.alert('Success', 'Profile updated successfully!');
+    } catch (error: any) {
+      Alert.alert('Error', error.message || 'Failed to update profile');
+    }
+  };
+
+  const handleLogout = () => {
+    Alert.alert('Logout', 'Are you sure you want to logout?', [
+      {text: 'Cancel', style: 'cancel'},
+      {
+        text: 'Logout',
+        style: 'destructive',
+        onPress: () => logout(),
+      },
+    ]);
+  };
+
+  const getInitials = (): string => {
+    if (user?.display_name) {
+      return user.display_name
+        .split(' ')
+        .map(n => n[0])
+        .join('')
+        .toUpperCase()
+        .slice(0, 2);
+    }
+    return 'U';
+  };
+
+  return (
+    <ScrollView style={styles.container} showsVerticalScrollIndicator={false}>
+      {/* Profile Header */}
+      <View style={styles.headerCard}>
+        <View style={styles.avatarContainer}>
+          <View style={styles.avatar}>
+            <Text style={styles.avatarText}>{getInitials()}</Text>
+          </View>
+          {isEditing && (
+            <TouchableOpacity style={styles.editAvatarButton}>
+              <Icon name="camera" size={16} color="#FFFFFF" />
+            </TouchableOpacity>
+          )}
+        </View>
+        <Text style={styles.userName}>
+          {user?.display_name || 'Set your name'}
+        </Text>
+        <Text style={styles.userPhone}>{user?.phone_number || 'No phone'}</Text>
+        <Text style={styles.userEmail}>{user?.email || ''}</Text>
+
+        {!isEditing && (
+          <TouchableOpacity
+            style={styles.editButton}
+            onPress={() => setIsEditing(true)}>
+            <Icon name="pencil" size={16} color={colors.primary} />
+            <Text style={styles.editButtonText}>Edit Profile</Text>
+          </TouchableOpacity>
+        )}
+      </View>
+
+      {/* Edit Form */}
+      {isEditing && (
+        <View style={styles.sectionCard}>
+          <Text style={styles.sectionTitle}>Personal Information</Text>
+
+          <View style={styles.inputGroup}>
+            <Text style={styles.inputLabel}>Display Name</Text>
+            <TextInput
+              style={styles.input}
+              value={displayName}
+              onChangeText={setDisplayName}
+              placeholder="Enter your name"
+              placeholderTextColor={colors.textSecondary}
+            />
+          </View>
+
+          <Text style={[styles.sectionTitle, {marginTop: spacing.lg}]}>
+            Bank Account (for receiving payments)
+          </Text>
+
+          <View style={styles.inputGroup}>
+            <Text style={styles.inputLabel}>Bank Name</Text>
+            <TextInput
+              style={styles.input}
+              value={bankName}
+              onChangeText={setBankName}
+              placeholder="e.g., Vietcombank, TPBank..."
+              placeholderTextColor={colors.textSecondary}
+            />
+          </View>
+
+          <View style={styles.inputGroup}>
+            <Text style={styles.inputLabel}>Account Number</Text>
+            <TextInput
+              style={styles.input}
+              value={accountNumber}
+              onChangeText={setAccountNumber}
+              placeholder="Enter account number"
+              placeholderTextColor={colors.textSecondary}
+              keyboardType="numeric"
+            />
+          </View>
+
+          <View style={styles.inputGroup}>
+            <Text style={styles.inputLabel}>Account Holder Name</Text>
+            <TextInput
+              style={styles.input}
+              value={accountName}
+              onChangeText={setAccountName}
+              placeholder="Enter account holder name"
+              placeholderTextColor={colors.textSecondary}
+              autoCapitalize="characters"
+            />
+          </View>
+
+          <View style={styles.editActions}>
+            <TouchableOpacity
+              style={styles.cancelButton}
+              onPress={() => {
+                setIsEditing(false);
+                setDisplayName(user?.display_name || '');
+                setBankName(user?.bank_accounts?.[0]?.bank_name || '');
+                setAccountNumber(
+                  user?.bank_accounts?.[0]?.account_number || '',
+                );
+                setAccountName(user?.bank_accounts?.[0]?.account_name || '');
+              }}>
+              <Text style={styles.cancelButtonText}>Cancel</Text>
+            </TouchableOpacity>
+            <TouchableOpacity
+              style={styles.saveButton}
+              onPress={handleSave}
+              disabled={loading}>
+              {loading ? (
+                <ActivityIndicator size="small" color="#FFFFFF" />
+              ) : (
+                <Text style={styles.saveButtonText}>Save Changes</Text>
+              )}
+            </TouchableOpacity>
+          </View>
+        </View>
+      )}
+
+      {/* Settings */}
+      <View style={styles.sectionCard}>
+        <Text style={styles.sectionTitle}>Settings</Text>
+
+        <View style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="bell-outline" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Notifications</Text>
+          </View>
+          <Switch
+            value={notificationsEnabled}
+            onValueChange={setNotificationsEnabled}
+            trackColor={{false: colors.border, true: colors.primaryLight}}
+            thumbColor={notificationsEnabled ? colors.primary : '#f4f3f4'}
+          />
+        </View>
+
+        <TouchableOpacity style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="translate" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Language</Text>
+          </View>
+          <View style={styles.settingRight}>
+            <Text style={styles.settingValue}>Tiếng Việt</Text>
+            <Icon
+              name="chevron-right"
+              size={20}
+              color={colors.textSecondary}
+            />
+          </View>
+        </TouchableOpacity>
+
+        <TouchableOpacity style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="currency-usd" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Currency</Text>
+          </View>
+          <View style={styles.settingRight}>
+            <Text style={styles.settingValue}>VND</Text>
+            <Icon
+              name="chevron-right"
+              size={20}
+              color={colors.textSecondary}
+            />
+          </View>
+        </TouchableOpacity>
+      </View>
+
+      {/* About & Support */}
+      <View style={styles.sectionCard}>
+        <Text style={styles.sectionTitle}>About & Support</Text>
+
+        <TouchableOpacity style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="help-circle-outline" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Help & FAQ</Text>
+          </View>
+          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
+        </TouchableOpacity>
+
+        <TouchableOpacity style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="shield-check-outline" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Privacy Policy</Text>
+          </View>
+          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
+        </TouchableOpacity>
+
+        <TouchableOpacity style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon
+              name="file-document-outline"
+              size={22}
+              color={colors.text}
+            />
+            <Text style={styles.settingLabel}>Terms of Service</Text>
+          </View>
+          <Icon name="chevron-right" size={20} color={colors.textSecondary} />
+        </TouchableOpacity>
+
+        <View style={styles.settingRow}>
+          <View style={styles.settingLeft}>
+            <Icon name="information-outline" size={22} color={colors.text} />
+            <Text style={styles.settingLabel}>Version</Text>
+          </View>
+          <Text style={styles.settingValue}>1.0.0 (MVP)</Text>
+        </View>
+      </View>
+
+      {/* Logout */}
+      <TouchableOpacity style={styles.logoutButton} onPress={handleLogout}>
+        <Icon name="logout" size={20} color={colors.error} />
+        <Text style={styles.logoutText}>Logout</Text>
+      </TouchableOpacity>
+
+      <View style={{height: spacing.xxl}} />
+    </ScrollView>
+  );
+};
+
+const styles = StyleSheet.create({
+  container: {
+    flex: 1,
+    backgroundColor: colors.background,
+  },
+  headerCard: {
+    backgroundColor: colors.primary,
+    paddingTop: spacing.xxl,
+    paddingBottom: spacing.xl,
+    paddingHorizontal: spacing.lg,
+    alignItems: 'center',
+    borderBottomLeftRadius: borderRadius.xl,
+    borderBottomRightRadius: borderRadius.xl,
+  },
+  avatarContainer: {
+    position: 'relative',
+    marginBottom: spacing.md,
+  },
+  avatar: {
+    width: 80,
+    height: 80,
+    borderRadius: 40,
+    backgroundColor: 'rgba(255,255,255,0.2)',
+    justifyContent: 'center',
+    alignItems: 'center',
+    borderWidth: 3,
+    borderColor: 'rgba(255,255,255,0.4)',
+  },
+  avatarText: {
+    fontSize: 28,
+    fontWeight: '700',
+    color: '#FFFFFF',
+  },
+  editAvatarButton: {
+    position: 'absolute',
+    bottom: 0,
+    right: 0,
+    width: 28,
+    height: 28,
+    borderRadius: 14,
+    backgroundColor: colors.secondary,
+    justifyContent: 'center',
+    alignItems: 'center',
+    borderWidth: 2,
+    borderColor: colors.primary,
+  },
+  userName: {
+    fontSize: fontSize.xxl,
+    fontWeight: '700',
+    color: '#FFFFFF',
+  },
+  userPhone: {
+    fontSize: fontSize.md,
+    color: 'rgba(255,255,255,0.8)',
+    marginTop: spacing.xs,
+  },
+  userEmail: {
+    fontSize: fontSize.sm,
+    color: 'rgba(255,255,255,0.6)',
+    marginTop: 2,
+  },
+  editButton: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    gap: spacing.xs,
+    backgroundColor: '#FFFFFF',
+    paddingHorizontal: spacing.md,
+    paddingVertical: spacing.sm,
+    borderRadius: borderRadius.full,
+    marginTop: spacing.md,
+  },
+  editButtonText: {
+    fontSize: fontSize.sm,
+    fontWeight: '600',
+    color: colors.primary,
+  },
+  sectionCard: {
+    backgroundColor: colors.surface,
+    marginHorizontal: spacing.md,
+    marginTop: spacing.md,
+    padding: spacing.lg,
+    borderRadius: borderRadius.lg,
+    elevation: 1,
+    shadowColor: '#000',
+    shadowOffset: {width: 0, height: 1},
+    shadowOpacity: 0.05,
+    shadowRadius: 2,
+  },
+  sectionTitle: {
+    fontSize: fontSize.lg,
+    fontWeight: '700',
+    color: colors.text,
+    marginBottom: spacing.md,
+  },
+  inputGroup: {
+    marginBottom: spacing.md,
+  },
+  inputLabel: {
+    fontSize: fontSize.sm,
+    fontWeight: '500',
+    color: colors.textSecondary,
+    marginBottom: spacing.xs,
+  },
+  input: {
+    backgroundColor: colors.background,
+    borderRadius: borderRadius.md,
+    paddingHorizontal: spacing.md,
+    paddingVertical: spacing.sm,
+    fontSize: fontSize.md,
+    color: colors.text,
+    borderWidth: 1,
+    borderColor: colors.border,
+  },
+  editActions: {
+    flexDirection: 'row',
+    gap: spacing.sm,
+    marginTop: spacing.md,
+  },
+  cancelButton: {
+    flex: 1,
+    alignItems: 'center',
+    paddingVertical: spacing.md,
+    borderRadius: borderRadius.md,
+    backgroundColor: colors.background,
+    borderWidth: 1,
+    borderColor: colors.border,
+  },
+  cancelButtonText: {
+    fontSize: fontSize.md,
+    fontWeight: '600',
+    color: colors.textSecondary,
+  },
+  saveButton: {
+    flex: 1,
+    alignItems: 'center',
+    paddingVertical: spacing.md,
+    borderRadius: borderRadius.md,
+    backgroundColor: colors.primary,
+  },
+  saveButtonText: {
+    fontSize: fontSize.md,
+    fontWeight: '600',
+    color: '#FFFFFF',
+  },
+  settingRow: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    justifyContent: 'space-between',
+    paddingVertical: spacing.sm,
+    borderBottomWidth: 1,
+    borderBottomColor: colors.border,
+  },
+  settingLeft: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    gap: spacing.sm,
+  },
+  settingLabel: {
+    fontSize: fontSize.md,
+    color: colors.text,
+  },
+  settingRight: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    gap: spacing.xs,
+  },
+  settingValue: {
+    fontSize: fontSize.sm,
+    color: colors.textSecondary,
+  },
+  logoutButton: {
+    flexDirection: 'row',
+    alignItems: 'center',
+    justifyContent: 'center',
+    gap: spacing.sm,
+    marginHorizontal: spacing.md,
+    marginTop: spacing.lg,
+    paddingVertical: spacing.md,
+    borderRadius: borderRadius.lg,
+    backgroundColor: colors.surface,
+    borderWidth: 1,
+    borderColor: colors.error,
+  },
+  logoutText: {
+    fontSize: fontSize.md,
+    fontWeight: '600',
+    color: colors.error,
+  },
+});
+
+export default ProfileScreen;
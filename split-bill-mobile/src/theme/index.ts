@@ -1,63 +1,63 @@
-export const colors = {
-  primary: '#6C5CE7',
-  primaryLight: '#A29BFE',
-  primaryDark: '#4834D4',
-  secondary: '#00CEC9',
-  secondaryLight: '#81ECEC',
-  accent: '#FD79A8',
-
-  success: '#00B894',
-  warning: '#FDCB6E',
-  error: '#E17055',
-  info: '#74B9FF',
-
-  background: '#F8F9FA',
-  surface: '#FFFFFF',
-  card: '#FFFFFF',
-
-  text: '#2D3436',
-  textSecondary: '#636E72',
-  textLight: '#B2BEC3',
-  textInverse: '#FFFFFF',
-
-  border: '#DFE6E9',
-  borderLight: '#F0F0F0',
-
-  positive: '#00B894',
-  negative: '#E17055',
-  neutral: '#636E72',
-};
-
-export const spacing = {
-  xs: 4,
-  sm: 8,
-  md: 16,
-  lg: 24,
-  xl: 32,
-  xxl: 48,
-};
-
-export const borderRadius = {
-  sm: 8,
-  md: 12,
-  lg: 16,
-  xl: 24,
-  full: 999,
-};
-
-export const fontSize = {
-  xs: 10,
-  sm: 12,
-  md: 14,
-  lg: 16,
-  xl: 18,
-  xxl: 24,
-  xxxl: 32,
-};
-
-export const fontWeight = {
-  regular: '400' as const,
-  medium: '500' as const,
-  semibold: '600' as const,
-  bold: '700' as const,
-};
+export const colors = {
+  primary: '#6C5CE7',
+  primaryLight: '#A29BFE',
+  primaryDark: '#4834D4',
+  secondary: '#00CEC9',
+  secondaryLight: '#81ECEC',
+  accent: '#FD79A8',
+
+  success: '#00B894',
+  warning: '#FDCB6E',
+  error: '#E17055',
+  info: '#74B9FF',
+
+  background: '#F8F9FA',
+  surface: '#FFFFFF',
+  card: '#FFFFFF',
+
+  text: '#2D3436',
+  textSecondary: '#636E72',
+  textLight: '#B2BEC3',
+  textInverse: '#FFFFFF',
+
+  border: '#DFE6E9',
+  borderLight: '#F0F0F0',
+
+  positive: '#00B894',
+  negative: '#E17055',
+  neutral: '#636E72',
+};
+
+export const spacing = {
+  xs: 4,
+  sm: 8,
+  md: 16,
+  lg: 24,
+  xl: 32,
+  xxl: 48,
+};
+
+export const borderRadius = {
+  sm: 8,
+  md: 12,
+  lg: 16,
+  xl: 24,
+  full: 999,
+};
+
+export const fontSize = {
+  xs: 10,
+  sm: 12,
+  md: 14,
+  lg: 16,
+  xl: 18,
+  xxl: 24,
+  xxxl: 32,
+};
+
+export const fontWeight = {
+  regular: '400' as const,
+  medium: '500' as const,
+  semibold: '600' as const,
+  bold: '700' as const,
+};
@@ -1,387 +1,809 @@
-// User types
-export interface BankAccount {
-  bank_code: string;
-  account_number: string;
-  account_name: string;
-}
-
-export interface User {
-  id: string;
-  phone: string;
-  display_name: string;
-  avatar_url: string;
-  bank_accounts: BankAccount[];
-  preferred_payment: string;
-  created_at: string;
-}
-
-// Group types
-export type MemberRole = 'admin' | 'member';
-
-export interface GroupMember {
-  user_id: string;
-  nickname: string;
-  display_name: string;
-  avatar_url: string;
-  role: MemberRole;
-  joined_at: string;
-}
-
-export interface Group {
-  id: string;
-  name: string;
-  description: string;
-  avatar_url: string;
-  created_by: string;
-  members: GroupMember[];
-  invite_code: string;
-  is_active: boolean;
-  created_at: string;
-}
-
-// Bill types
-export type SplitType = 'equal' | 'by_item' | 'by_percentage' | 'by_amount';
-export type BillStatus = 'pending' | 'settled' | 'cancelled';
-
-export interface BillItem {
-  id: string;
-  name: string;
-  quantity: number;
-  unit_price: number;
-  total_price: number;
-  assigned_to: string[];
-}
-
-export interface ExtraCharges {
-  tax: number;
-  service_charge: number;
-  tip: number;
-  discount: number;
-}
-
-export interface BillSplit {
-  user_id: string;
-  display_name: string;
-  amount: number;
-  is_paid: boolean;
-  paid_at?: string;
-}
-
-export interface Bill {
-  id: string;
-  group_id: string;
-  title: string;
-  description: string;
-  category: string;
-  receipt_image_url: string;
-  total_amount: number;
-  currency: string;
-  paid_by: string;
-  paid_by_name: string;
-  split_type: SplitType;
-  items: BillItem[];
-  extra_charges: ExtraCharges;
-  splits: BillSplit[];
-  status: BillStatus;
-  created_at: string;
-}
-
-// Transaction types
-export type TransactionStatus = 'pending' | 'confirmed' | 'rejected';
-
-export interface Transaction {
-  id: string;
-  group_id: string;
-  from_user: string;
-  from_user_name: string;
-  to_user: string;
-  to_user_name: string;
-  amount: number;
-  currency: string;
-  bill_id?: string;
-  type: 'payment' | 'settlement';
-  status: TransactionStatus;
-  payment_method: string;
-  note: string;
-  created_at: string;
-  confirmed_at?: string;
-}
-
-// Settlement types
-export interface Settlement {
-  from_user_id: string;
-  from_user_name: string;
-  to_user_id: string;
-  to_user_name: string;
-  amount: number;
-}
-
-export interface Balance {
-  user_id: string;
-  display_name: string;
-  balance: number;
-}
-
-// API Response
-export interface APIResponse<T> {
-  success: boolean;
-  message: string;
-  data: T;
-  error?: string;
-}
-
-// Create requests
-export interface CreateGroupRequest {
-  name: string;
-  description?: string;
-  avatar_url?: string;
-}
-
-export interface CreateBillRequest {
-  title: string;
-  description?: string;
-  category?: string;
-  total_amount: number;
-  currency: string;
-  paid_by: string;
-  split_type: SplitType;
-  items?: CreateBillItemRequest[];
-  extra_charges?: ExtraCharges;
-  split_among?: string[];
-}
-
-// ===== OCR Types (Phase 2) =====
-export type OCRStatus = 'processing' | 'completed' | 'failed' | 'confirmed';
-
-export interface ParsedItem {
-  name: string;
-  quantity: number;
-  unit_price: number;
-  total_price: number;
-  confidence: number;
-}
-
-export interface OCRResult {
-  id: string;
-  image_url: string;
-  raw_text: string;
-  parsed_items: ParsedItem[];
-  parsed_total: number;
-  parsed_tax: number;
-  parsed_service_fee: number;
-  parsed_discount: number;
-  confidence_score: number;
-  processing_time_ms: number;
-  status: OCRStatus;
-  created_at: string;
-}
-
-export interface ScanReceiptRequest {
-  group_id: string;
-  image_url: string;
-}
-
-export interface ScanReceiptBase64Request {
-  group_id: string;
-  image_base64: string;
-  file_name?: string;
-}
-
-export interface ConfirmOCRRequest {
-  title: string;
-  items: ParsedItem[];
-  total: number;
-  tax: number;
-  service_fee: number;
-  discount: number;
-  paid_by: string;
-  split_type: SplitType;
-  split_among?: string[];
-}
-
-export interface ImageUploadResponse {
-  url: string;
-  file_name: string;
-  size: number;
-}
-
-export interface CreateBillItemRequest {
-  name: string;
-  quantity: number;
-  unit_price: number;
-  total_price?: number;
-  assigned_to?: string[];
-}
-
-export interface CreateTransactionRequest {
-  group_id: string;
-  to_user: string;
-  amount: number;
-  currency: string;
-  bill_id?: string;
-  payment_method?: string;
-  note?: string;
-}
-
-// ===== Payment Types (Phase 4) =====
-export type ActivityType =
-  | 'bill_created'
-  | 'bill_deleted'
-  | 'bill_updated'
-  | 'member_joined'
-  | 'member_left'
-  | 'payment_sent'
-  | 'payment_confirmed'
-  | 'payment_rejected'
-  | 'group_created'
-  | 'settlement_created';
-
-export interface Activity {
-  id: string;
-  group_id: string;
-  group_name?: string;
-  user_id: string;
-  user_name: string;
-  user_avatar?: string;
-  type: ActivityType;
-  title: string;
-  detail: string;
-  amount?: number;
-  ref_id?: string;
-  created_at: string;
-  time_ago: string;
-}
-
-export interface BankingDeeplink {
-  app_name: string;
-  scheme: string;
-  color: string;
-  icon_name: string;
-}
-
-export interface PaymentDeeplinkRequest {
-  bank_code: string;
-  account_number: string;
-  account_name?: string;
-  amount: number;
-  note?: string;
-}
-
-export interface PaymentDeeplinkResponse {
-  amount: number;
-  note: string;
-  deeplinks: BankingDeeplink[];
-  vietqr_url: string;
-}
-
-export interface VietQRRequest {
-  bank_id: string;
-  account_no: string;
-  account_name?: string;
-  amount: number;
-  description?: string;
-  template?: string;
-}
-
-export interface VietQRResponse {
-  qr_url: string;
-  bank_id: string;
-  account_number: string;
-  account_name: string;
-  amount: number;
-  description: string;
-}
-
-export interface BankInfo {
-  id: string;
-  name: string;
-  code: string;
-  short_name: string;
-  logo: string;
-  color: string;
-}
-
-export interface UserPaymentInfo {
-  user_id: string;
-  display_name: string;
-  bank_accounts: BankAccount[];
-  preferred_payment: string;
-}
-
-// ===== Statistics Types (Phase 5) =====
-
-export interface BillSummary {
-  id: string;
-  title: string;
-  amount: number;
-  category: string;
-  paid_by_name: string;
-  created_at: string;
-}
-
-export interface MemberSpendStats {
-  user_id: string;
-  display_name: string;
-  avatar_url: string;
-  total_paid: number;
-  total_owed: number;
-  net_balance: number;
-  bill_count: number;
-  percentage: number;
-}
-
-export interface CategoryStat {
-  category: string;
-  total: number;
-  count: number;
-  percentage: number;
-  icon: string;
-  color: string;
-}
-
-export interface MonthlySpend {
-  month: string;
-  year: number;
-  month_num: number;
-  total: number;
-  bill_count: number;
-}
-
-export interface GroupSpendInfo {
-  group_id: string;
-  group_name: string;
-  total: number;
-  bill_count: number;
-}
-
-export interface GroupStats {
-  group_id: string;
-  group_name: string;
-  total_spent: number;
-  total_bills: number;
-  total_members: number;
-  average_bill: number;
-  largest_bill?: BillSummary;
-  smallest_bill?: BillSummary;
-  member_stats: MemberSpendStats[];
-  category_stats: CategoryStat[];
-  monthly_trend: MonthlySpend[];
-  recent_bills: BillSummary[];
-}
-
-export interface UserOverallStats {
-  total_groups: number;
-  total_spent: number;
-  total_owed: number;
-  total_bills: number;
-  top_groups: GroupSpendInfo[];
-  category_stats: CategoryStat[];
-  monthly_trend: MonthlySpend[];
-}
-
-export interface CategoryInfo {
-  key: string;
-  label: string;
-  icon: string;
-  color: string;
-}
+// User types
+export interface BankAccount {
+  bank_code: string;
+  account_number: string;
+  account_name: string;
+}
+
+export interface User {
+  id: string;
+  phone: string;
+  display_name: string;
+  avatar_url: string;
+  bank_accounts: BankAccount[];
+  preferred_payment: string;
+  email?: string;
+  zalo_user_id?: string;
+  webhook_url?: string;
+  notification_preferences?: NotificationPreferences;
+  language?: Language; // unset = follow the device
+  created_at: string;
+}
+
+// Languages the server renders activities and notifications in
+export type Language = 'vi' | 'en';
+
+// Notification preferences
+export type NotificationChannel = 'push' | 'email' | 'zalo' | 'webhook';
+
+export interface NotificationPreferences {
+  channels?: Partial<Record<NotificationChannel, boolean>>;
+  types?: Record<string, {off?: boolean; channels?: NotificationChannel[]}>;
+  groups?: Record<string, {muted?: boolean; channels?: NotificationChannel[]}>;
+  quiet_hours?: {start: string; end: string}; // "HH:MM" local time
+  timezone?: string; // IANA name, defaults to Asia/Ho_Chi_Minh
+  digest?: DigestFrequency; // group summaries, off by default
+}
+
+// Group digests
+export type DigestFrequency = 'off' | 'weekly' | 'monthly';
+
+export interface Digest {
+  group_id: string;
+  group_name: string;
+  frequency: DigestFrequency;
+  period_start: string;
+  period_end: string; // exclusive
+  new_bills: {
+    id: string;
+    title: string;
+    amount: number;
+    category: string;
+    paid_by_name: string;
+    created_at: string;
+  }[];
+  new_bills_total: number;
+  top_category?: {category: string; total: number; percentage: number};
+  balance: number; // positive = owed money, negative = owes money
+  to_confirm: number;
+  to_confirm_amount: number;
+  awaiting_confirm: number;
+  awaiting_amount: number;
+  payments_confirmed: number;
+  members_joined: number;
+}
+
+export type DevicePlatform = 'ios' | 'android' | 'web';
+
+export interface RegisterDeviceRequest {
+  token: string;
+  platform: DevicePlatform;
+  app_version?: string;
+}
+
+export interface Device {
+  token: string;
+  platform: DevicePlatform;
+  app_version?: string;
+  created_at: string;
+  last_seen_at: string;
+}
+
+// Group types
+export type MemberRole = 'owner' | 'admin' | 'member' | 'viewer';
+
+export type GroupPermission =
+  | 'view_group'
+  | 'record_expenses'
+  | 'edit_own_bills'
+  | 'edit_others_bills'
+  | 'add_members'
+  | 'approve_joins'
+  | 'remove_members'
+  | 'manage_group'
+  | 'manage_roles'
+  | 'transfer_ownership'
+  | 'delete_group';
+
+export type PermissionMatrix = Record<MemberRole, GroupPermission[]>;
+
+export interface GroupMember {
+  user_id: string;
+  nickname: string;
+  display_name: string;
+  avatar_url: string;
+  role: MemberRole;
+  joined_at: string;
+  reminders_snoozed_until?: string;
+  // Invite link the member joined with, if any
+  invite_link_id?: string;
+  // Added by name, without an account, until someone claims them
+  placeholder?: boolean;
+}
+
+export interface Group {
+  id: string;
+  name: string;
+  description: string;
+  avatar_url: string;
+  created_by: string;
+  members: GroupMember[];
+  invite_code: string;
+  is_active: boolean;
+  reminders?: ReminderSettings;
+  created_at: string;
+  // Joining with an invite code files a join request for admins to approve
+  join_approval_required: boolean;
+}
+
+// Invite links: extra codes that can expire, allow a limited number of
+// joins (max_uses 0 means no limit) and give a role other than member
+export interface InviteLink {
+  id: string;
+  group_id: string;
+  code: string; // empty in lists for links with a role you cannot invite as
+  role: Exclude<MemberRole, 'owner'>;
+  max_uses: number;
+  uses: number;
+  expires_at?: string;
+  revoked_at?: string;
+  created_by: string;
+  created_at: string;
+  // Set on claim links, which let one person take this placeholder's place
+  placeholder_id?: string;
+}
+
+export interface InviteJoin {
+  user_id: string;
+  display_name: string;
+  joined_at: string;
+}
+
+export interface InviteLinkWithJoins extends InviteLink {
+  joined: InviteJoin[];
+}
+
+export interface CreateInviteLinkRequest {
+  role?: Exclude<MemberRole, 'owner'>;
+  max_uses?: number;
+  expires_in_hours?: number;
+  placeholder_id?: string; // makes a single-use claim link
+}
+
+export type JoinRequestStatus = 'pending' | 'approved' | 'denied';
+
+export interface JoinRequest {
+  id: string;
+  group_id: string;
+  user_id: string;
+  role: Exclude<MemberRole, 'owner'>;
+  invite_link_id?: string;
+  status: JoinRequestStatus;
+  decided_by?: string;
+  decided_at?: string;
+  created_at: string;
+  group_name: string;
+  display_name: string;
+  avatar_url: string;
+}
+
+// Realtime group events, streamed from GET /groups/:id/events as server-sent
+// events. Resume by sending the last event id as the Last-Event-ID header.
+export type GroupEventType =
+  | 'bill.created'
+  | 'bill.updated'
+  | 'bill.deleted'
+  | 'transaction.created'
+  | 'transaction.updated'
+  | 'transaction.confirmed'
+  | 'transaction.rejected'
+  | 'transaction.cancelled'
+  | 'member.joined'
+  | 'member.left'
+  | 'member.claimed' // { placeholder_id, user_id }: reload the group
+  | 'balances.changed' // refetch balances
+  | 'resync'; // missed events are gone, reload the group
+
+export interface GroupEvent<T = unknown> {
+  id?: string;
+  type: GroupEventType;
+  data: T;
+}
+
+// Outgoing group webhooks (admins only). Deliveries are JSON POSTs signed
+// with X-SplitBill-Signature: sha256=HMAC(secret, timestamp + "." + body).
+export type WebhookEvent =
+  | Exclude<GroupEventType, 'balances.changed' | 'resync'>
+  | 'join_request.created'
+  | 'join_request.decided';
+
+export interface GroupWebhook {
+  id: string;
+  group_id: string;
+  url: string;
+  description?: string;
+  events: WebhookEvent[]; // empty = all events
+  active: boolean;
+  secret?: string; // only when created or rotated
+  created_by: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CreateWebhookRequest {
+  url: string;
+  description?: string;
+  events?: WebhookEvent[];
+}
+
+export interface UpdateWebhookRequest {
+  url?: string;
+  description?: string;
+  events?: WebhookEvent[];
+  active?: boolean;
+  rotate_secret?: boolean;
+}
+
+export type WebhookDeliveryStatus = 'pending' | 'delivered' | 'failed';
+
+export interface WebhookDelivery {
+  id: string;
+  webhook_id: string;
+  group_id: string;
+  event: WebhookEvent | 'ping';
+  payload: string;
+  test?: boolean;
+  status: WebhookDeliveryStatus;
+  attempts: number;
+  next_attempt_at?: string;
+  response_code?: number;
+  response_body?: string;
+  last_error?: string;
+  duration_ms?: number;
+  delivered_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+// Reminder types
+export type ReminderSchedule = 'off' | 'weekly' | 'after_bill';
+
+export interface ReminderSettings {
+  schedule: ReminderSchedule;
+  weekday: number; // 0 = Sunday
+  hour: number;
+  days_after_bill: number;
+  min_amount: number;
+}
+
+export interface ReminderDelivery {
+  id: string;
+  kind: 'scheduled' | 'manual';
+  group_id: string;
+  user_id: string;
+  requested_by?: string;
+  amount: number;
+  level: number;
+  created_at: string;
+}
+
+// Bill types
+export type SplitType = 'equal' | 'by_item' | 'by_percentage' | 'by_amount';
+export type BillStatus = 'pending' | 'settled' | 'cancelled';
+
+export interface BillItem {
+  id: string;
+  name: string;
+  quantity: number;
+  unit_price: number;
+  total_price: number;
+  assigned_to: string[];
+}
+
+export interface ExtraCharges {
+  tax: number;
+  service_charge: number;
+  tip: number;
+  discount: number;
+}
+
+export interface BillSplit {
+  user_id: string;
+  display_name: string;
+  amount: number;
+  is_paid: boolean;
+  paid_at?: string;
+}
+
+export interface Bill {
+  id: string;
+  group_id: string;
+  title: string;
+  description: string;
+  category: string;
+  receipt_image_url: string;
+  total_amount: number;
+  currency: string;
+  paid_by: string;
+  paid_by_name: string;
+  created_by: string;
+  split_type: SplitType;
+  items: BillItem[];
+  extra_charges: ExtraCharges;
+  splits: BillSplit[];
+  status: BillStatus;
+  created_at: string;
+}
+
+// Transaction types
+export type TransactionStatus = 'pending' | 'confirmed' | 'rejected' | 'cancelled';
+
+export interface Transaction {
+  id: string;
+  group_id: string;
+  from_user: string;
+  from_user_name: string;
+  to_user: string;
+  to_user_name: string;
+  amount: number;
+  currency: string;
+  bill_id?: string;
+  type: 'payment' | 'settlement' | 'reversal';
+  status: TransactionStatus;
+  payment_method: string;
+  note: string;
+  reference?: string;
+  created_at: string;
+  confirmed_at?: string;
+}
+
+export interface TransactionListParams {
+  status?: TransactionStatus;
+  type?: Transaction['type'];
+  member?: string;
+  from?: string;
+  to?: string;
+  page?: number;
+  limit?: number;
+}
+
+// Settlement types
+export interface Settlement {
+  from_user_id: string;
+  from_user_name: string;
+  to_user_id: string;
+  to_user_name: string;
+  amount: number;
+  reference?: string;
+}
+
+export interface Balance {
+  user_id: string;
+  display_name: string;
+  balance: number;
+}
+
+// API Response
+export interface APIResponse<T> {
+  success: boolean;
+  message: string;
+  data: T;
+  error?: string;
+}
+
+export interface Pagination {
+  page: number;
+  limit: number;
+  total: number;
+  last_page: number;
+}
+
+export interface Paginated<T> {
+  data: T[];
+  pagination: Pagination;
+}
+
+export interface CursorPage<T> {
+  data: T[];
+  next_cursor?: string;
+  has_more: boolean;
+}
+
+export interface CursorParams {
+  cursor?: string;
+  limit?: number;
+}
+
+// Notification inbox types
+export interface AppNotification {
+  id: string;
+  type: string;
+  title: string;
+  body: string;
+  data?: Record<string, string>;
+  read_at?: string;
+  created_at: string;
+}
+
+export interface NotificationListParams extends CursorParams {
+  unread?: boolean;
+}
+
+// Create requests
+export interface CreateGroupRequest {
+  name: string;
+  description?: string;
+  avatar_url?: string;
+}
+
+export interface UpdateGroupRequest extends Partial<CreateGroupRequest> {
+  join_approval_required?: boolean;
+}
+
+export interface CreateBillRequest {
+  title: string;
+  description?: string;
+  category?: string;
+  total_amount: number;
+  currency: string;
+  paid_by: string;
+  split_type: SplitType;
+  items?: CreateBillItemRequest[];
+  extra_charges?: ExtraCharges;
+  split_among?: string[];
+}
+
+// ===== OCR Types (Phase 2) =====
+export type OCRStatus = 'processing' | 'completed' | 'failed' | 'confirmed';
+
+export interface ParsedItem {
+  name: string;
+  quantity: number;
+  unit_price: number;
+  total_price: number;
+  confidence: number;
+}
+
+export interface OCRResult {
+  id: string;
+  image_url: string;
+  raw_text: string;
+  parsed_items: ParsedItem[];
+  parsed_total: number;
+  parsed_tax: number;
+  parsed_service_fee: number;
+  parsed_discount: number;
+  confidence_score: number;
+  processing_time_ms: number;
+  status: OCRStatus;
+  created_at: string;
+}
+
+export interface ScanReceiptRequest {
+  group_id: string;
+  image_url: string;
+}
+
+export interface ScanReceiptBase64Request {
+  group_id: string;
+  image_base64: string;
+  file_name?: string;
+}
+
+export interface ConfirmOCRRequest {
+  title: string;
+  items: ParsedItem[];
+  total: number;
+  tax: number;
+  service_fee: number;
+  discount: number;
+  paid_by: string;
+  split_type: SplitType;
+  split_among?: string[];
+}
+
+export interface ImageUploadResponse {
+  url: string;
+  file_name: string;
+  size: number;
+}
+
+export interface CreateBillItemRequest {
+  name: string;
+  quantity: number;
+  unit_price: number;
+  total_price?: number;
+  assigned_to?: string[];
+}
+
+export interface CreateTransactionRequest {
+  group_id: string;
+  to_user: string;
+  amount: number;
+  currency: string;
+  bill_id?: string;
+  payment_method?: string;
+  note?: string;
+  reference?: string;
+}
+
+// ===== Payment Types (Phase 4) =====
+export type ActivityType =
+  | 'bill_created'
+  | 'bill_deleted'
+  | 'bill_updated'
+  | 'member_joined'
+  | 'member_left'
+  | 'payment_sent'
+  | 'payment_confirmed'
+  | 'payment_rejected'
+  | 'group_created'
+  | 'settlement_created'
+  | 'receipt_confirmed';
+
+export interface ActivityRef {
+  kind: 'user' | 'group' | 'bill' | 'transaction' | 'receipt';
+  id: string;
+  name?: string; // as it was when the activity happened
+}
+
+export interface ActivityChange {
+  field: string; // e.g. 'title', 'total_amount', 'paid_by'
+  old?: string | number | boolean; // absent = was empty
+  new?: string | number | boolean;
+}
+
+export interface ActivityPayload {
+  actor: ActivityRef;
+  target: ActivityRef;
+  counterparty?: ActivityRef; // other member of a payment
+  source?: ActivityRef; // scanned receipt a bill came from
+  group?: ActivityRef;
+  amount?: number;
+  currency?: string;
+  changes?: ActivityChange[];
+}
+
+// Filters and page of an activity feed. type is a comma separated list of
+// ActivityType; from/to are YYYY-MM-DD or RFC3339.
+export interface ActivityQuery extends CursorParams {
+  type?: string;
+  actor?: string;
+  from?: string;
+  to?: string;
+}
+
+export interface Activity {
+  id: string;
+  group_id: string;
+  group_name?: string;
+  user_id: string;
+  user_name: string;
+  user_avatar?: string;
+  type: ActivityType;
+  title: string;
+  detail: string;
+  amount?: number;
+  ref_id?: string;
+  payload?: ActivityPayload;
+  created_at: string;
+  time_ago: string;
+}
+
+export interface BankingDeeplink {
+  app_name: string;
+  scheme: string;
+  color: string;
+  icon_name: string;
+}
+
+export interface PaymentDeeplinkRequest {
+  bank_code: string;
+  account_number: string;
+  account_name?: string;
+  amount: number;
+  note?: string;
+  reference?: string;
+}
+
+export interface PaymentDeeplinkResponse {
+  amount: number;
+  note: string;
+  reference?: string;
+  deeplinks: BankingDeeplink[];
+  vietqr_url?: string;
+  vietqr_payload?: string;
+}
+
+export interface PaymentPayee {
+  user_id: string;
+  display_name: string;
+  avatar_url: string;
+  bank_accounts: BankAccount[] | null;
+  preferred_payment: string;
+}
+
+export interface SettlementPayment {
+  group_id: string;
+  payee: PaymentPayee;
+  amount: number;
+  currency: string;
+  reference: string;
+  note: string;
+  bank_account?: BankAccount;
+  deeplinks: BankingDeeplink[];
+  vietqr_url?: string;
+  vietqr_payload?: string;
+  transaction_draft: CreateTransactionRequest;
+}
+
+export interface VietQRRequest {
+  bank_id: string;
+  account_no: string;
+  account_name?: string;
+  amount: number;
+  description?: string;
+  reference?: string;
+  template?: string;
+}
+
+export interface VietQRResponse {
+  payload: string;
+  qr_url: string;
+  qr_svg_url: string;
+  bank_id: string;
+  account_number: string;
+  account_name: string;
+  amount: number;
+  description: string;
+  reference?: string;
+}
+
+export interface BankAccountRules {
+  min_length: number;
+  max_length: number;
+  numeric: boolean;
+}
+
+export interface BankInfo {
+  id: string;
+  name: string;
+  code: string;
+  short_name: string;
+  full_name?: string;
+  aliases?: string[] | null;
+  logo: string;
+  logo_key?: string;
+  color: string;
+  deeplink_scheme?: string;
+  account_rules?: BankAccountRules;
+}
+
+export interface UserPaymentInfo {
+  user_id: string;
+  display_name: string;
+  bank_accounts: BankAccount[];
+  preferred_payment: string;
+}
+
+// ===== Statistics Types (Phase 5) =====
+
+export interface BillSummary {
+  id: string;
+  title: string;
+  amount: number;
+  category: string;
+  paid_by_name: string;
+  created_at: string;
+}
+
+export interface MemberSpendStats {
+  user_id: string;
+  display_name: string;
+  avatar_url: string;
+  total_paid: number;
+  total_owed: number;
+  net_balance: number;
+  bill_count: number;
+  percentage: number;
+}
+
+export interface CategoryStat {
+  category: string;
+  total: number;
+  count: number;
+  percentage: number;
+  icon: string;
+  color: string;
+}
+
+export interface MonthlySpend {
+  month: string;
+  year: number;
+  month_num: number;
+  total: number;
+  bill_count: number;
+}
+
+export interface GroupSpendInfo {
+  group_id: string;
+  group_name: string;
+  total: number;
+  bill_count: number;
+}
+
+export interface GroupStats {
+  group_id: string;
+  group_name: string;
+  total_spent: number;
+  total_bills: number;
+  total_members: number;
+  average_bill: number;
+  largest_bill?: BillSummary;
+  smallest_bill?: BillSummary;
+  member_stats: MemberSpendStats[];
+  category_stats: CategoryStat[];
+  monthly_trend: MonthlySpend[];
+  recent_bills: BillSummary[];
+}
+
+export interface UserOverallStats {
+  total_groups: number;
+  total_spent: number;
+  total_owed: number;
+  total_bills: number;
+  top_groups: GroupSpendInfo[];
+  category_stats: CategoryStat[];
+  monthly_trend: MonthlySpend[];
+}
+
+export interface CategoryInfo {
+  key: string;
+  label: string;
+  icon: string;
+  color: string;
+}
+
+// ===== Bank Statement Reconciliation =====
+export type StatementLineStatus = 'unmatched' | 'matched' | 'linked' | 'ignored';
+
+export interface StatementImport {
+  id: string;
+  user_id: string;
+  group_id?: string;
+  bank: string;
+  file_name: string;
+  total_lines: number;
+  incoming_lines: number;
+  duplicate_lines: number;
+  matched_lines: number;
+  unmatched_lines: number;
+  created_at: string;
+}
+
+export interface StatementLine {
+  id: string;
+  import_id: string;
+  user_id: string;
+  group_id?: string;
+  bank: string;
+  row: number;
+  date: string;
+  amount: number;
+  description: string;
+  bank_reference?: string;
+  status: StatementLineStatus;
+  transaction_id?: string;
+  match_reason?: 'reference' | 'amount_date';
+  candidates?: string[];
+  created_at: string;
+  resolved_at?: string;
+}
+
+export interface StatementImportResult {
+  import: StatementImport;
+  matched: StatementLine[];
+  unmatched: StatementLine[];
+}
+
+// ===== Payment References =====
+export interface PaymentReferenceLookup {
+  code: string;
+  kind: 'transaction' | 'settlement';
+  group_id: string;
+  from_user: string;
+  to_user: string;
+  amount: number;
+  transaction?: Transaction;
+  created_at: string;
+}